@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// IsAutoscalingEnabled returns whether the number of active ranks of the filesystem is autoscaled
+func (m *MetadataServerSpec) IsAutoscalingEnabled() bool {
+	return m.Autoscaling != nil && m.Autoscaling.Enabled
+}
+
+// MaxActiveCount returns the highest number of active ranks of the filesystem
+func (m *MetadataServerSpec) MaxActiveCount() int32 {
+	if m.IsAutoscalingEnabled() {
+		return m.Autoscaling.MaxActiveCount
+	}
+	return m.ActiveCount
+}
+
+// DesiredActiveCount returns the number of active ranks the filesystem must run. When the autoscaling is enabled it
+// is the count chosen by the autoscaler, ActiveCount until it made a decision, within the min and max counts.
+func (f *CephFilesystem) DesiredActiveCount() int32 {
+	mds := f.Spec.MetadataServer
+	if !mds.IsAutoscalingEnabled() {
+		return mds.ActiveCount
+	}
+
+	count := mds.ActiveCount
+	if f.Status != nil && f.Status.MDSStatus != nil && f.Status.MDSStatus.DesiredActiveCount > 0 {
+		count = f.Status.MDSStatus.DesiredActiveCount
+	}
+	if count < mds.Autoscaling.MinActiveCount {
+		return mds.Autoscaling.MinActiveCount
+	}
+	if count > mds.Autoscaling.MaxActiveCount {
+		return mds.Autoscaling.MaxActiveCount
+	}
+	return count
+}
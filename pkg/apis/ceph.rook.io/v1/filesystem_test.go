@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesiredActiveCount(t *testing.T) {
+	fs := &CephFilesystem{Spec: FilesystemSpec{MetadataServer: MetadataServerSpec{ActiveCount: 2}}}
+	assert.Equal(t, int32(2), fs.DesiredActiveCount())
+	assert.Equal(t, int32(2), fs.Spec.MetadataServer.MaxActiveCount())
+
+	// the active count is used until the autoscaler made a decision
+	fs.Spec.MetadataServer.Autoscaling = &MDSAutoscalingSpec{Enabled: true, MinActiveCount: 1, MaxActiveCount: 4}
+	assert.Equal(t, int32(2), fs.DesiredActiveCount())
+	assert.Equal(t, int32(4), fs.Spec.MetadataServer.MaxActiveCount())
+
+	fs.Status = &CephFilesystemStatus{MDSStatus: &FilesystemMDSStatus{DesiredActiveCount: 3}}
+	assert.Equal(t, int32(3), fs.DesiredActiveCount())
+
+	// the count is kept within the bounds
+	fs.Spec.MetadataServer.Autoscaling.MaxActiveCount = 2
+	assert.Equal(t, int32(2), fs.DesiredActiveCount())
+	fs.Spec.MetadataServer.ActiveCount = 1
+	fs.Status = nil
+	fs.Spec.MetadataServer.Autoscaling.MinActiveCount = 2
+	assert.Equal(t, int32(2), fs.DesiredActiveCount())
+
+	// the decision of the autoscaler is ignored once disabled
+	fs.Status = &CephFilesystemStatus{MDSStatus: &FilesystemMDSStatus{DesiredActiveCount: 3}}
+	fs.Spec.MetadataServer.Autoscaling.Enabled = false
+	assert.Equal(t, int32(1), fs.DesiredActiveCount())
+	assert.Equal(t, int32(1), fs.Spec.MetadataServer.MaxActiveCount())
+}
@@ -20,6 +20,11 @@ func (s *ObjectStoreSpec) IsMultisite() bool {
 	return s.Zone.Name != ""
 }
 
+// IsSharedPools returns whether the object store uses pools shared with other object stores
+func (s *ObjectStoreSpec) IsSharedPools() bool {
+	return s.SharedPools.MetadataPoolName != "" || s.SharedPools.DataPoolName != ""
+}
+
 func (s *ObjectRealmSpec) IsPullRealm() bool {
 	return s.Pull.Endpoint != ""
 }
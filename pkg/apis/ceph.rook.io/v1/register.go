@@ -68,6 +68,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephObjectStoreList{},
 		&CephObjectStoreUser{},
 		&CephObjectStoreUserList{},
+		&CephBucketTopic{},
+		&CephBucketTopicList{},
+		&CephBucketNotification{},
+		&CephBucketNotificationList{},
 		&CephObjectRealm{},
 		&CephObjectRealmList{},
 		&CephObjectZoneGroup{},
@@ -76,6 +80,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephObjectZoneList{},
 		&CephRBDMirror{},
 		&CephRBDMirrorList{},
+		&CephFilesystemMirror{},
+		&CephFilesystemMirrorList{},
+		&CephFilesystemSubVolumeGroup{},
+		&CephFilesystemSubVolumeGroupList{},
+		&CephNFSExport{},
+		&CephNFSExportList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -21,6 +21,7 @@ import (
 
 	rookv1 "github.com/rook/rook/pkg/apis/rook.io/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -242,6 +243,10 @@ const (
 	ConditionFailure     ConditionType = "Failure"
 	ConditionUpgrading   ConditionType = "Upgrading"
 	ConditionDeleting    ConditionType = "Deleting"
+	// ConditionDataPoolRemovalBlocked reports the data pools removed from a filesystem spec that are still in use
+	ConditionDataPoolRemovalBlocked ConditionType = "DataPoolRemovalBlocked"
+	// ConditionDegraded reports an object store whose synthetic probes exceed their latency thresholds
+	ConditionDegraded ConditionType = "Degraded"
 )
 
 type ClusterState string
@@ -307,6 +312,14 @@ const (
 	DefaultCRUSHRoot = "default"
 )
 
+// NamedPoolSpec represents the spec of a named ceph pool
+type NamedPoolSpec struct {
+	// Name of the pool, the pools of a filesystem are named after the filesystem and this name
+	Name string `json:"name,omitempty"`
+	// PoolSpec represents the spec of ceph pool
+	PoolSpec `json:",inline"`
+}
+
 // PoolSpec represents the spec of ceph pool
 type PoolSpec struct {
 	// The failure domain: osd/host/(region or zone if available) - technically also any type in the crush map
@@ -345,9 +358,10 @@ type MirrorHealthCheckSpec struct {
 }
 
 type CephBlockPoolStatus struct {
-	Phase           ConditionType        `json:"phase,omitempty"`
-	MirroringStatus *MirroringStatusSpec `json:"mirroringStatus,omitempty"`
-	MirroringInfo   *MirroringInfoSpec   `json:"mirroringInfo,omitempty"`
+	Phase                  ConditionType               `json:"phase,omitempty"`
+	MirroringStatus        *MirroringStatusSpec        `json:"mirroringStatus,omitempty"`
+	MirroringInfo          *MirroringInfoSpec          `json:"mirroringInfo,omitempty"`
+	SnapshotScheduleStatus *SnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
 	// Use only info and put mirroringStatus in it?
 	Info map[string]string `json:"info,omitempty"`
 }
@@ -358,6 +372,14 @@ type MirroringStatusSpec struct {
 	LastChecked string      `json:"lastChecked,omitempty"`
 	LastChanged string      `json:"lastChanged,omitempty"`
 	Details     string      `json:"details,omitempty"`
+	// Role is the last mirroring role applied to the pool images
+	Role MirroringRole `json:"role,omitempty"`
+	// LastRoleTransition is the time of the last promotion or demotion of the pool images
+	LastRoleTransition string `json:"lastRoleTransition,omitempty"`
+	// PendingAcknowledgement is true until the peers acknowledge the last role transition
+	PendingAcknowledgement bool `json:"pendingAcknowledgement,omitempty"`
+	// SplitBrainImages are the images found in split-brain after the last role transition
+	SplitBrainImages []string `json:"splitBrainImages,omitempty"`
 }
 
 type SummarySpec map[string]interface{}
@@ -370,6 +392,30 @@ type MirroringInfoSpec struct {
 	Details     string      `json:"details,omitempty"`
 }
 
+// SnapshotScheduleStatusSpec is the status of the snapshot schedules of a mirrored pool
+type SnapshotScheduleStatusSpec struct {
+	SnapshotSchedules []SnapshotSchedulesSpec `json:"snapshotSchedules,omitempty"`
+	ScheduledImages   []ScheduledImageSpec    `json:"scheduledImages,omitempty"`
+	LastChecked       string                  `json:"lastChecked,omitempty"`
+	LastChanged       string                  `json:"lastChanged,omitempty"`
+	Details           string                  `json:"details,omitempty"`
+}
+
+// SnapshotSchedulesSpec is the list of snapshot schedules configured for a pool, namespace or image
+type SnapshotSchedulesSpec struct {
+	Pool      string                 `json:"pool,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Image     string                 `json:"image,omitempty"`
+	Items     []SnapshotScheduleSpec `json:"items,omitempty"`
+}
+
+// ScheduledImageSpec is the snapshot scheduling state of a mirrored image
+type ScheduledImageSpec struct {
+	Image            string `json:"image,omitempty"`
+	NextSnapshotTime string `json:"nextSnapshotTime,omitempty"`
+	LastSnapshotTime string `json:"lastSnapshotTime,omitempty"`
+}
+
 type Status struct {
 	Phase string `json:"phase,omitempty"`
 }
@@ -399,6 +445,38 @@ type MirroringSpec struct {
 
 	// Mode is the mirroring mode: either "pool" or "image"
 	Mode string `json:"mode,omitempty"`
+
+	// SnapshotSchedules is the scheduling of snapshot for mirrored images/pools
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+
+	// Role is the mirroring role of the pool images on this site: either "primary" or "secondary"
+	// Changing it promotes or demotes all the mirrored images of the pool
+	Role MirroringRole `json:"role,omitempty"`
+
+	// Force promotes the images even if the peer site is not reachable, used for unplanned failover
+	Force bool `json:"force,omitempty"`
+}
+
+// MirroringRole is the role of the mirrored images of a pool
+type MirroringRole string
+
+const (
+	// MirroringRolePrimary means the pool images are primary and replicated to the peers
+	MirroringRolePrimary MirroringRole = "primary"
+	// MirroringRoleSecondary means the pool images are non-primary and replicated from a peer
+	MirroringRoleSecondary MirroringRole = "secondary"
+)
+
+// SnapshotScheduleSpec represents the snapshot scheduling settings of a mirrored pool
+type SnapshotScheduleSpec struct {
+	// Namespace is the RADOS namespace the snapshot schedule applies to, the whole pool if empty
+	Namespace string `json:"namespace,omitempty"`
+
+	// Interval represent the periodicity of the snapshot, e.g: 30m, 1h, 1d
+	Interval string `json:"interval,omitempty"`
+
+	// StartTime indicates when to start the snapshot, in ISO 8601 format
+	StartTime string `json:"startTime,omitempty"`
 }
 
 // ErasureCodeSpec represents the spec for erasure code in a pool
@@ -420,8 +498,8 @@ type ErasureCodedSpec struct {
 type CephFilesystem struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              FilesystemSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              FilesystemSpec        `json:"spec"`
+	Status            *CephFilesystemStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -438,13 +516,266 @@ type FilesystemSpec struct {
 	MetadataPool PoolSpec `json:"metadataPool,omitempty"`
 
 	// The data pool settings
-	DataPools []PoolSpec `json:"dataPools,omitempty"`
+	DataPools []NamedPoolSpec `json:"dataPools,omitempty"`
 
 	// Preserve pools on filesystem deletion
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete"`
 
 	// The mds pod info
 	MetadataServer MetadataServerSpec `json:"metadataServer"`
+
+	// The mirroring settings
+	Mirroring *FSMirroringSpec `json:"mirroring,omitempty"`
+
+	// SnapshotSchedules is the list of snapshot schedules of the filesystem directories
+	SnapshotSchedules []FilesystemSnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+}
+
+// FilesystemSnapshotScheduleSpec represents the snapshot schedule of a directory
+type FilesystemSnapshotScheduleSpec struct {
+	// Path is the absolute path of the directory to snapshot
+	Path string `json:"path"`
+
+	// Interval represents the periodicity of the snapshot, e.g. 1h, 1d, 1w
+	Interval string `json:"interval"`
+
+	// StartTime indicates when to start the snapshot, in ISO 8601 format
+	StartTime string `json:"startTime,omitempty"`
+
+	// Retention is the number of snapshots to keep for the directory
+	Retention FilesystemSnapshotRetentionSpec `json:"retention,omitempty"`
+}
+
+// FilesystemSnapshotRetentionSpec is the number of snapshots to keep per period
+type FilesystemSnapshotRetentionSpec struct {
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
+// FSMirroringSpec represents the setting for a mirrored filesystem
+type FSMirroringSpec struct {
+	// Enabled whether this filesystem is mirrored or not
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Peers represents the peers spec
+	Peers MirroringPeerSpec `json:"peers,omitempty"`
+
+	// Directories is the list of directories to mirror to the peers
+	Directories []string `json:"directories,omitempty"`
+
+	// StatusCheck sets up the directories sync status check
+	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
+}
+
+// MirroringPeerSpec represents the specification of a mirror peer
+type MirroringPeerSpec struct {
+	// SecretNames represents the Kubernetes Secret names to add mirror peers
+	SecretNames []string `json:"secretNames,omitempty"`
+}
+
+// CephFilesystemStatus represents the status of a Ceph Filesystem
+type CephFilesystemStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// MirroringStatus is the filesystem mirroring status
+	MirroringStatus *FilesystemMirroringStatus `json:"mirroringStatus,omitempty"`
+	// SnapshotScheduleStatus is the status of the snapshot schedules
+	SnapshotScheduleStatus *FilesystemSnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
+	// Conditions reports the data pools whose removal is blocked
+	Conditions []Condition `json:"conditions,omitempty"`
+	// DataPools is the list of the data pools created for the filesystem, they are removed by name once not in the spec anymore
+	DataPools []string `json:"dataPools,omitempty"`
+	// MDSStatus is the state of the MDS ranks and daemons of the filesystem
+	MDSStatus *FilesystemMDSStatus `json:"mdsStatus,omitempty"`
+}
+
+// FilesystemMDSStatus represents the state of the MDS ranks and daemons of a filesystem
+type FilesystemMDSStatus struct {
+	// Ranks is the list of the ranks of the filesystem and the daemons holding them
+	Ranks []MDSRankStatus `json:"ranks,omitempty"`
+	// Standby is the list of the standby daemons of the filesystem
+	Standby []string `json:"standby,omitempty"`
+	// Clients is the number of clients connected to the filesystem
+	Clients int `json:"clients"`
+	// DesiredActiveCount is the number of active ranks chosen by the autoscaler
+	DesiredActiveCount int32 `json:"desiredActiveCount,omitempty"`
+	// LastScaleTime is the last time the autoscaler changed the number of active ranks
+	LastScaleTime string `json:"lastScaleTime,omitempty"`
+	LastChecked   string `json:"lastChecked,omitempty"`
+	// Details contains the errors of the last status check or scaling
+	Details string `json:"details,omitempty"`
+}
+
+// MDSRankStatus represents the state of an MDS rank
+type MDSRankStatus struct {
+	Rank int `json:"rank"`
+	// Daemon is the name of the daemon holding the rank
+	Daemon string `json:"daemon,omitempty"`
+	// State is the state of the daemon holding the rank (active, replay, rejoin...) or failed/damaged
+	State string `json:"state"`
+	// StandbyReplay is the name of the standby-replay daemon following the rank
+	StandbyReplay string `json:"standbyReplay,omitempty"`
+	// RequestRate is the number of client requests per second served by the rank
+	RequestRate int `json:"requestRate"`
+	// Caps is the number of capabilities held by the clients of the rank
+	Caps   int `json:"caps"`
+	Inodes int `json:"inodes"`
+}
+
+// FilesystemSnapshotScheduleStatusSpec is the status of the snapshot schedules of a filesystem
+type FilesystemSnapshotScheduleStatusSpec struct {
+	// SnapshotSchedules is the list of snapshot schedules configured on the filesystem
+	SnapshotSchedules []FilesystemSnapshotSchedulesSpec `json:"snapshotSchedules,omitempty"`
+	LastChecked       string                            `json:"lastChecked,omitempty"`
+	// Details contains the errors of the last schedules reconcile or status check
+	Details string `json:"details,omitempty"`
+}
+
+// FilesystemSnapshotSchedulesSpec is the status of a snapshot schedule
+type FilesystemSnapshotSchedulesSpec struct {
+	Path      string                          `json:"path,omitempty"`
+	Interval  string                          `json:"interval,omitempty"`
+	StartTime string                          `json:"startTime,omitempty"`
+	Retention FilesystemSnapshotRetentionSpec `json:"retention,omitempty"`
+	Active    bool                            `json:"active,omitempty"`
+	// LastSnapshot is the time of the last snapshot taken by the schedule
+	LastSnapshot string `json:"lastSnapshot,omitempty"`
+	// SnapshotsCreated is the number of snapshots taken by the schedule
+	SnapshotsCreated int `json:"snapshotsCreated,omitempty"`
+	// SnapshotsPruned is the number of snapshots removed by the retention policy
+	SnapshotsPruned int `json:"snapshotsPruned,omitempty"`
+}
+
+// FilesystemMirroringStatus represents the mirroring status of a filesystem
+type FilesystemMirroringStatus struct {
+	// Peers is the list of configured peers
+	Peers []FilesystemMirrorPeerStatus `json:"peers,omitempty"`
+	// Directories is the list of mirrored directories and their sync status
+	Directories []FilesystemMirrorDirectoryStatus `json:"directories,omitempty"`
+	LastChecked string                            `json:"lastChecked,omitempty"`
+	Details     string                            `json:"details,omitempty"`
+}
+
+// FilesystemMirrorPeerStatus represents the status of a filesystem mirror peer
+type FilesystemMirrorPeerStatus struct {
+	// SecretName is the name of the Kubernetes Secret the peer was added from
+	SecretName string `json:"secretName,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	SiteName   string `json:"siteName,omitempty"`
+	// FSName is the name of the remote filesystem
+	FSName string `json:"fsName,omitempty"`
+	// FailureCount is the number of failed synchronizations reported by the mirror daemons
+	FailureCount int    `json:"failureCount,omitempty"`
+	Health       string `json:"health,omitempty"`
+}
+
+// FilesystemMirrorDirectoryStatus represents the sync status of a mirrored directory
+type FilesystemMirrorDirectoryStatus struct {
+	Path string `json:"path,omitempty"`
+	// State is the state of the directory mapping to a mirror daemon
+	State string `json:"state,omitempty"`
+	// InstanceID is the id of the mirror daemon instance syncing the directory
+	InstanceID   string `json:"instanceID,omitempty"`
+	LastShuffled string `json:"lastShuffled,omitempty"`
+	Details      string `json:"details,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephFilesystemMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              FilesystemMirroringSpec `json:"spec"`
+	Status            *Status                 `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CephFilesystemMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemMirror `json:"items"`
+}
+
+// FilesystemMirroringSpec is the filesystem mirroring daemon spec
+type FilesystemMirroringSpec struct {
+	// The affinity to place the cephfs-mirror pods (default is to place on any available node)
+	Placement rookv1.Placement `json:"placement"`
+
+	// The annotations-related configuration to add/set on each Pod related object.
+	Annotations rookv1.Annotations `json:"annotations,omitempty"`
+
+	// The labels-related configuration to add/set on each Pod related object.
+	Labels rookv1.Labels `json:"labels,omitempty"`
+
+	// The resource requirements for the cephfs-mirror pods
+	Resources v1.ResourceRequirements `json:"resources"`
+
+	// PriorityClassName sets priority class on the cephfs-mirror pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroup represents a subvolume group of a Ceph Filesystem
+type CephFilesystemSubVolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              CephFilesystemSubVolumeGroupSpec    `json:"spec"`
+	Status            *CephFilesystemSubVolumeGroupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephFilesystemSubVolumeGroupList represents a list of Ceph Filesystem SubVolumeGroups
+type CephFilesystemSubVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephFilesystemSubVolumeGroup `json:"items"`
+}
+
+// CephFilesystemSubVolumeGroupSpec represents the specification of a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupSpec struct {
+	// FilesystemName is the name of the CephFilesystem the group belongs to, in the same namespace
+	FilesystemName string `json:"filesystemName"`
+
+	// Quota is the maximum size of the group, no quota if not set
+	Quota *resource.Quantity `json:"quota,omitempty"`
+
+	// DataPoolName is the name of the data pool of the filesystem the group layout points to, the default data pool if empty
+	DataPoolName string `json:"dataPoolName,omitempty"`
+
+	// Mode is the octal permission mode of the group directory, only applied on creation
+	Mode string `json:"mode,omitempty"`
+
+	// ExportPin is the MDS rank the group is pinned to (ceph.dir.pin), -1 removes the pinning
+	ExportPin *int `json:"exportPin,omitempty"`
+}
+
+// CephFilesystemSubVolumeGroupStatus represents the status of a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// Info holds the "clusterID" to set in the StorageClasses provisioning volumes in the group
+	Info map[string]string `json:"info,omitempty"`
+	// Usage is the usage of the group reported by ceph
+	Usage *SubVolumeGroupUsageStatus `json:"usage,omitempty"`
+}
+
+// SubVolumeGroupUsageStatus represents the usage and layout of a subvolume group
+type SubVolumeGroupUsageStatus struct {
+	BytesUsed uint64 `json:"bytesUsed"`
+	// BytesQuota is the quota of the group in bytes, 0 if the group has no quota
+	BytesQuota uint64 `json:"bytesQuota,omitempty"`
+	// UsedPercent is the percentage of the quota in use, "undefined" without quota
+	UsedPercent string `json:"usedPercent,omitempty"`
+	DataPool    string `json:"dataPool,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	LastChecked string `json:"lastChecked,omitempty"`
+	Details     string `json:"details,omitempty"`
 }
 
 type MetadataServerSpec struct {
@@ -469,6 +800,35 @@ type MetadataServerSpec struct {
 
 	// PriorityClassName sets priority classes on components
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Autoscaling changes the number of active MDS ranks depending on the load of the filesystem
+	// +optional
+	Autoscaling *MDSAutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// MDSAutoscalingSpec represents the policy scaling the number of active MDS ranks
+type MDSAutoscalingSpec struct {
+	// Enabled whether the number of active ranks is managed by the autoscaler
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinActiveCount is the lowest number of active ranks
+	MinActiveCount int32 `json:"minActiveCount"`
+
+	// MaxActiveCount is the highest number of active ranks, the MDS daemons are deployed for this count
+	MaxActiveCount int32 `json:"maxActiveCount"`
+
+	// TargetRequestRate is the average number of client requests per second per active rank above which a rank is added,
+	// a rank is removed when the average rate falls below half of it. 0 disables the request rate policy.
+	// +optional
+	TargetRequestRate int `json:"targetRequestRate,omitempty"`
+
+	// ScaleOnCachePressure adds a rank while the MDS daemons report an oversized cache
+	// +optional
+	ScaleOnCachePressure bool `json:"scaleOnCachePressure,omitempty"`
+
+	// Interval is how often the state and the load of the ranks are checked, 1m by default
+	// +optional
+	Interval string `json:"interval,omitempty"`
 }
 
 // +genclient
@@ -498,6 +858,14 @@ type ObjectStoreSpec struct {
 	// The data pool settings
 	DataPool PoolSpec `json:"dataPool"`
 
+	// The placement targets of the object store with their storage classes, in addition to the default placement
+	// target whose STANDARD storage class is the data pool
+	PlacementTargets []ObjectPlacementTargetSpec `json:"placementTargets,omitempty"`
+
+	// The pools shared with other object stores instead of the metadata and data pools of the object store, the
+	// object store is isolated in its own RADOS namespaces of the shared pools
+	SharedPools ObjectSharedPoolsSpec `json:"sharedPools,omitempty"`
+
 	// Preserve pools on object store deletion
 	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete"`
 
@@ -509,11 +877,238 @@ type ObjectStoreSpec struct {
 
 	// The rgw Bucket healthchecks and liveness probe
 	HealthCheck BucketHealthCheckSpec `json:"healthCheck"`
+
+	// Security represents the security settings of the object store
+	Security ObjectStoreSecuritySpec `json:"security,omitempty"`
+
+	// Auth represents the external authentication of the users of the object store
+	Auth ObjectStoreAuthSpec `json:"auth,omitempty"`
+
+	// Metrics represents the usage metrics of the buckets and users of the object store
+	Metrics ObjectStoreMetricsSpec `json:"metrics,omitempty"`
+}
+
+// ObjectStoreMetricsSpec represents the Prometheus metrics of the usage of an object store, published by the operator
+type ObjectStoreMetricsSpec struct {
+	// Enabled publishes the usage and quota utilization of the buckets and users of the object store
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval is the interval of the collection of the usage, 5m by default
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// ServiceMonitor creates a Prometheus ServiceMonitor scraping the metrics of the operator
+	// +optional
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
+}
+
+// ObjectStoreAuthSpec represents the external authentication services of an object store
+type ObjectStoreAuthSpec struct {
+	// Keystone authenticates the users with OpenStack Keystone
+	// +optional
+	Keystone *ObjectStoreKeystoneSpec `json:"keystone,omitempty"`
+
+	// LDAP authenticates the S3 users with an LDAP directory
+	// +optional
+	LDAP *ObjectStoreLDAPSpec `json:"ldap,omitempty"`
+
+	// STS enables the Secure Token Service of the object store
+	// +optional
+	STS *ObjectStoreSTSSpec `json:"sts,omitempty"`
+}
+
+// ObjectStoreKeystoneSpec represents the OpenStack Keystone authentication of an object store
+type ObjectStoreKeystoneSpec struct {
+	// URL is the URL of the Keystone server
+	URL string `json:"url"`
+
+	// AdminUser is the Keystone user of the object store
+	AdminUser string `json:"adminUser"`
+
+	// AdminProject is the project of the Keystone user
+	AdminProject string `json:"adminProject"`
+
+	// AdminDomain is the domain of the Keystone user
+	AdminDomain string `json:"adminDomain"`
+
+	// PasswordSecretName is the name of the secret with the password of the Keystone user in its "password" entry
+	PasswordSecretName string `json:"passwordSecretName"`
+
+	// AcceptedRoles are the Keystone roles of the users allowed to access the object store
+	AcceptedRoles []string `json:"acceptedRoles"`
+
+	// ImplicitTenants creates the users of the object store in their own tenant
+	// +optional
+	ImplicitTenants bool `json:"implicitTenants,omitempty"`
+
+	// EnableS3 authenticates the S3 requests with Keystone in addition to the Swift requests
+	// +optional
+	EnableS3 bool `json:"enableS3,omitempty"`
+}
+
+// ObjectStoreLDAPSpec represents the LDAP authentication of an object store
+type ObjectStoreLDAPSpec struct {
+	// URI is the URI of the LDAP server, e.g. ldaps://ldap.example.com
+	URI string `json:"uri"`
+
+	// BindDN is the DN of the user of the object store searching the directory
+	BindDN string `json:"bindDN"`
+
+	// PasswordSecretName is the name of the secret with the password of the bind user in its "password" entry
+	PasswordSecretName string `json:"passwordSecretName"`
+
+	// SearchDN is the base DN of the search of the users
+	SearchDN string `json:"searchDN"`
+
+	// UIDAttribute is the attribute of the users matching the user name, "uid" by default
+	// +optional
+	UIDAttribute string `json:"uidAttribute,omitempty"`
+
+	// SearchFilter is an additional LDAP filter of the users, e.g. (objectclass=inetorgperson)
+	// +optional
+	SearchFilter string `json:"searchFilter,omitempty"`
+}
+
+// ObjectStoreSTSSpec represents the Secure Token Service of an object store
+type ObjectStoreSTSSpec struct {
+	// KeySecretName is the name of the secret with the 16 characters key encrypting the session tokens in its "key" entry
+	KeySecretName string `json:"keySecretName"`
+
+	// WebIdentity enables AssumeRoleWithWebIdentity with the tokens of an OpenID Connect provider
+	// +optional
+	WebIdentity *ObjectStoreWebIdentitySpec `json:"webIdentity,omitempty"`
+}
+
+// ObjectStoreWebIdentitySpec represents the OpenID Connect provider validating the web identity tokens
+type ObjectStoreWebIdentitySpec struct {
+	// IntrospectionURL is the token introspection endpoint of the provider
+	IntrospectionURL string `json:"introspectionURL"`
+
+	// ClientID is the client of the object store in the provider
+	ClientID string `json:"clientID"`
+
+	// ClientSecretName is the name of the secret with the secret of the client in its "secret" entry
+	ClientSecretName string `json:"clientSecretName"`
+}
+
+// ObjectStoreSecuritySpec represents the security settings of an object store
+type ObjectStoreSecuritySpec struct {
+	// KMS is the key management service of the server side encryption with KMS managed keys (SSE-KMS)
+	KMS *ObjectStoreKMSSpec `json:"kms,omitempty"`
+}
+
+// ObjectStoreKMSSpec represents the key management service of the server side encryption of an object store
+type ObjectStoreKMSSpec struct {
+	// Vault stores the encryption keys in Vault
+	Vault *ObjectStoreVaultSpec `json:"vault,omitempty"`
+
+	// TestingKeysSecretName is the name of the secret with the static keys of the testing backend, for development only.
+	// Its "keys" entry lists the keys as space separated "<key id>=<base64 encoded 256 bit key>".
+	TestingKeysSecretName string `json:"testingKeysSecretName,omitempty"`
+
+	// HealthCheckKeyID is the id of the key used by the bucket health check to verify the encryption, the encryption
+	// is not checked if it is not set
+	HealthCheckKeyID string `json:"healthCheckKeyID,omitempty"`
+
+	// AllowInsecureEncryption lets RGW accept the encryption requests over plain HTTP when the gateway has no SSL
+	// certificate. This is insecure, the encryption keys of the SSE-C requests are sent in clear text.
+	AllowInsecureEncryption bool `json:"allowInsecureEncryption,omitempty"`
+}
+
+// ObjectStoreVaultSpec represents the Vault backend of the server side encryption of an object store
+type ObjectStoreVaultSpec struct {
+	// Address is the URL of the Vault server
+	Address string `json:"address"`
+
+	// SecretEngine is the Vault secret engine of the keys, "kv" or "transit"
+	SecretEngine string `json:"secretEngine"`
+
+	// Prefix is the path of the secret engine in Vault, e.g. /v1/secret/data or /v1/transit/export/encryption-key
+	Prefix string `json:"prefix,omitempty"`
+
+	// Namespace is the Vault Enterprise namespace of the secret engine
+	Namespace string `json:"namespace,omitempty"`
+
+	// TokenSecretName is the name of the secret with the Vault token in its "token" entry
+	TokenSecretName string `json:"tokenSecretName"`
+}
+
+// ObjectSharedPoolsSpec represents the pools shared by several object stores
+type ObjectSharedPoolsSpec struct {
+	// MetadataPoolName is the name of the pool shared by the metadata and the bucket indexes of the object stores
+	MetadataPoolName string `json:"metadataPoolName,omitempty"`
+
+	// DataPoolName is the name of the pool shared by the data of the object stores
+	DataPoolName string `json:"dataPoolName,omitempty"`
+
+	// PreserveRadosNamespaceDataOnDelete keeps the objects of the RADOS namespaces of the object store in the shared
+	// pools when the object store is deleted
+	PreserveRadosNamespaceDataOnDelete bool `json:"preserveRadosNamespaceDataOnDelete,omitempty"`
+}
+
+// ObjectPlacementTargetSpec represents an rgw placement target with its own pools
+type ObjectPlacementTargetSpec struct {
+	// Name is the placement id of the target, "default-placement" adds storage classes to the default placement target
+	Name string `json:"name"`
+
+	// MetadataPool is the settings of the bucket index and non-ec pools of the placement target
+	MetadataPool PoolSpec `json:"metadataPool,omitempty"`
+
+	// DataPool is the settings of the pool of the STANDARD storage class of the placement target
+	DataPool PoolSpec `json:"dataPool,omitempty"`
+
+	// StorageClasses are the other storage classes of the placement target
+	StorageClasses []ObjectStorageClassSpec `json:"storageClasses,omitempty"`
+}
+
+// ObjectStorageClassSpec represents an rgw storage class with its own data pool
+type ObjectStorageClassSpec struct {
+	// Name is the name of the storage class requested by the clients, e.g. COLD
+	Name string `json:"name"`
+
+	// DataPool is the settings of the data pool of the storage class
+	DataPool PoolSpec `json:"dataPool"`
 }
 
 type BucketHealthCheckSpec struct {
 	Bucket        HealthCheckSpec   `json:"bucket,omitempty"`
 	LivenessProbe *rookv1.ProbeSpec `json:"livenessProbe,omitempty"`
+	// Probes are the synthetic probes run with the bucket health check to measure the latency of the object store
+	// +optional
+	Probes *ObjectProbesSpec `json:"probes,omitempty"`
+}
+
+// ObjectProbesSpec represents the workload of the synthetic probes of an object store
+type ObjectProbesSpec struct {
+	// ObjectSizes are the sizes of the objects put, read, listed and deleted by each probe, 4Ki by default
+	// +optional
+	ObjectSizes []string `json:"objectSizes,omitempty"`
+
+	// MultipartSize is the size of the object uploaded in parts of 5Mi by each probe, no multipart upload if not set
+	// +optional
+	MultipartSize string `json:"multipartSize,omitempty"`
+
+	// Samples is the number of the last latencies of each operation the percentiles are computed from, 100 by default
+	// +optional
+	Samples int `json:"samples,omitempty"`
+
+	// LatencyThresholds are the latencies above which the object store is degraded
+	// +optional
+	LatencyThresholds []ObjectProbeLatencyThreshold `json:"latencyThresholds,omitempty"`
+}
+
+// ObjectProbeLatencyThreshold represents the maximum latency of an operation of the probes at a percentile
+type ObjectProbeLatencyThreshold struct {
+	// Operation is the operation of the probes: put, get, list, delete or multipart
+	Operation string `json:"operation"`
+
+	// Percentile of the latencies of the operation compared to the threshold, 99 by default
+	// +optional
+	Percentile int `json:"percentile,omitempty"`
+
+	// Latency is the maximum latency of the operation, for example 500ms
+	Latency string `json:"latency"`
 }
 
 type HealthCheckSpec struct {
@@ -555,6 +1150,82 @@ type GatewaySpec struct {
 
 	// ExternalRgwEndpoints points to external rgw endpoint(s)
 	ExternalRgwEndpoints []v1.EndpointAddress `json:"externalRgwEndpoints,omitempty"`
+
+	// Service represents the settings of the service of the rgw pods
+	// +optional
+	Service *RGWServiceSpec `json:"service,omitempty"`
+
+	// Ingress creates an ingress exposing the rgw service
+	// +optional
+	Ingress *RGWIngressSpec `json:"ingress,omitempty"`
+
+	// Autoscaling scales the rgw pods with a horizontal pod autoscaler, instances is ignored when it is set
+	// +optional
+	Autoscaling *RGWAutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// RGWAutoscalingSpec represents the horizontal autoscaling of the rgw pods
+type RGWAutoscalingSpec struct {
+	// MinInstances is the minimum number of rgw pods
+	MinInstances int32 `json:"minInstances"`
+
+	// MaxInstances is the maximum number of rgw pods
+	MaxInstances int32 `json:"maxInstances"`
+
+	// TargetCPUUtilizationPercentage is the average CPU usage of the rgw pods in percent of their CPU requests
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetRequestsPerSecond is the average number of requests per second of the rgw pods. The metric must be
+	// served for the pods by a custom metrics API, e.g. the Prometheus adapter.
+	// +optional
+	TargetRequestsPerSecond *int32 `json:"targetRequestsPerSecond,omitempty"`
+
+	// RequestsPerSecondMetric is the name of the pod metric of the requests per second, rgw_requests_per_second by default
+	// +optional
+	RequestsPerSecondMetric string `json:"requestsPerSecondMetric,omitempty"`
+}
+
+// RGWServiceSpec represents the settings of the service of the rgw pods
+type RGWServiceSpec struct {
+	// Type is the type of the service, ClusterIP by default
+	// +optional
+	Type v1.ServiceType `json:"type,omitempty"`
+
+	// Annotations are added to the service, e.g. to configure a load balancer
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// LoadBalancerIP is the IP requested for a service of type LoadBalancer
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// ExternalTrafficPolicy is the external traffic policy of a service of type NodePort or LoadBalancer
+	// +optional
+	ExternalTrafficPolicy v1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
+	// NodePort is the node port of the http port of a service of type NodePort or LoadBalancer
+	// +optional
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	// SecureNodePort is the node port of the https port of a service of type NodePort or LoadBalancer
+	// +optional
+	SecureNodePort int32 `json:"secureNodePort,omitempty"`
+}
+
+// RGWIngressSpec represents the ingress of the rgw service
+type RGWIngressSpec struct {
+	// Host is the DNS name of the object store, e.g. s3.example.com. The buckets are also served on the subdomains
+	// of the host, e.g. mybucket.s3.example.com
+	Host string `json:"host"`
+
+	// TLSSecretName is the name of the secret with the TLS certificate of the host and its subdomains
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations are added to the ingress, e.g. to select the ingress class
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type ZoneSpec struct {
@@ -567,6 +1238,19 @@ type ObjectStoreStatus struct {
 	Message      string            `json:"message,omitempty"`
 	BucketStatus *BucketStatus     `json:"bucketStatus,omitempty"`
 	Info         map[string]string `json:"info,omitempty"`
+	// Certificate is the state of the SSL certificate of the gateway
+	// +optional
+	Certificate *CertificateStatus `json:"certificate,omitempty"`
+}
+
+// CertificateStatus represents the SSL certificate served by the gateway
+type CertificateStatus struct {
+	// SecretName is the name of the secret of the certificate
+	SecretName string `json:"secretName"`
+	// Subject is the distinguished name of the subject of the certificate
+	Subject string `json:"subject,omitempty"`
+	// NotAfter is the expiry date of the certificate
+	NotAfter string `json:"notAfter"`
 }
 
 type BucketStatus struct {
@@ -574,6 +1258,23 @@ type BucketStatus struct {
 	Details     string        `json:"details,omitempty"`
 	LastChecked string        `json:"lastChecked,omitempty"`
 	LastChanged string        `json:"lastChanged,omitempty"`
+	// Probes are the latencies of the operations of the synthetic probes
+	// +optional
+	Probes []ObjectProbeStatus `json:"probes,omitempty"`
+}
+
+// ObjectProbeStatus represents the latency percentiles of an operation of the synthetic probes
+type ObjectProbeStatus struct {
+	// Operation is put, get, list, delete or multipart
+	Operation string `json:"operation"`
+	// ObjectSize is the size of the objects of the operation
+	// +optional
+	ObjectSize string `json:"objectSize,omitempty"`
+	// Samples is the number of latencies the percentiles are computed from
+	Samples int    `json:"samples"`
+	P50     string `json:"p50"`
+	P90     string `json:"p90"`
+	P99     string `json:"p99"`
 }
 
 // +genclient
@@ -590,6 +1291,39 @@ type CephObjectStoreUser struct {
 type ObjectStoreUserStatus struct {
 	Phase string            `json:"phase,omitempty"`
 	Info  map[string]string `json:"info"`
+	// Usage is the usage of the user refreshed periodically
+	Usage *ObjectUserUsageStatus `json:"usage,omitempty"`
+	// KeyRotation is the state of the rotation of the s3 keys of the user
+	KeyRotation *ObjectUserKeyRotationStatus `json:"keyRotation,omitempty"`
+	// Details is the error that prevented the reconcile of the user
+	Details string `json:"details,omitempty"`
+}
+
+// ObjectUserUsageStatus represents the usage of an object store user
+type ObjectUserUsageStatus struct {
+	// Buckets is the number of buckets owned by the user
+	Buckets int `json:"buckets"`
+	// SizeBytes is the size of the objects of the user
+	SizeBytes int64 `json:"sizeBytes"`
+	// Objects is the number of objects of the user
+	Objects     int64  `json:"objects"`
+	LastChecked string `json:"lastChecked,omitempty"`
+}
+
+// ObjectUserKeyRotationStatus represents the state of the rotation of the s3 keys of an object store user
+type ObjectUserKeyRotationStatus struct {
+	// AccessKey is the access key of the user stored in the secret
+	AccessKey string `json:"accessKey,omitempty"`
+	// LastRotation is the value of the rotation annotation that triggered the last rotation
+	LastRotation string `json:"lastRotation,omitempty"`
+	// RotationTime is the time of the last rotation
+	RotationTime string `json:"rotationTime,omitempty"`
+	// RetiredAccessKey is the previous access key of the user, it is removed at the RetireTime
+	RetiredAccessKey string `json:"retiredAccessKey,omitempty"`
+	RetireTime       string `json:"retireTime,omitempty"`
+	// PendingRotation is the value of the rotation annotation of a rotation whose new key may be created but not
+	// recorded yet, the new key is reused rather than creating another key when the rotation is retried
+	PendingRotation string `json:"pendingRotation,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -606,6 +1340,217 @@ type ObjectStoreUserSpec struct {
 	Store string `json:"store,omitempty"`
 	//The display name for the ceph users
 	DisplayName string `json:"displayName,omitempty"`
+	// Quotas of the user, an empty quota removes the quota
+	Quotas *ObjectQuotaSpec `json:"quotas,omitempty"`
+	// BucketQuotas are the quotas of each bucket of the user, an empty quota removes the quota
+	BucketQuotas *ObjectQuotaSpec `json:"bucketQuotas,omitempty"`
+	// MaxBuckets is the maximum number of buckets of the user, 0 for unlimited
+	MaxBuckets *int `json:"maxBuckets,omitempty"`
+	// Capabilities are the admin capabilities of the user
+	Capabilities *ObjectUserCapSpec `json:"capabilities,omitempty"`
+	// SubUsers are the Swift subusers of the user
+	SubUsers []ObjectUserSubUserSpec `json:"subUsers,omitempty"`
+	// KeyRotationGracePeriod is how long the previous key of the user remains valid after a rotation of its keys
+	KeyRotationGracePeriod string `json:"keyRotationGracePeriod,omitempty"`
+}
+
+// ObjectQuotaSpec represents the quota of an object store user or bucket
+type ObjectQuotaSpec struct {
+	// MaxSize is the maximum size of the objects
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// MaxObjects is the maximum number of objects
+	MaxObjects *int64 `json:"maxObjects,omitempty"`
+}
+
+// ObjectUserCapSpec represents the admin capabilities of an object store user, each capability is one of "*",
+// "read", "write" or "read, write"
+type ObjectUserCapSpec struct {
+	User     string `json:"user,omitempty"`
+	Bucket   string `json:"bucket,omitempty"`
+	Metadata string `json:"metadata,omitempty"`
+	Usage    string `json:"usage,omitempty"`
+}
+
+// ObjectUserSubUserSpec represents a Swift subuser of an object store user
+type ObjectUserSubUserSpec struct {
+	// Name of the subuser, the Swift user is "<user>:<name>"
+	Name string `json:"name"`
+	// Access of the subuser: read, write, readwrite or full
+	Access string `json:"access"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopic represents a Ceph Object Topic receiving the bucket notifications
+type CephBucketTopic struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketTopicSpec    `json:"spec"`
+	Status            *BucketTopicStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketTopicList represents a list of Ceph Object Topics
+type CephBucketTopicList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketTopic `json:"items"`
+}
+
+// BucketTopicSpec represents the spec of a bucket topic
+type BucketTopicSpec struct {
+	// ObjectStoreName is the name of the object store the topic is created in
+	ObjectStoreName string `json:"objectStoreName"`
+	// OpaqueData is added to the notifications sent to the topic
+	// +optional
+	OpaqueData string `json:"opaqueData,omitempty"`
+	// Persistent sends the notifications asynchronously, they are kept until delivered to the endpoint
+	// +optional
+	Persistent bool `json:"persistent,omitempty"`
+	// Endpoint is the endpoint the notifications are pushed to
+	Endpoint TopicEndpointSpec `json:"endpoint"`
+}
+
+// TopicEndpointSpec contains exactly one of the supported endpoints
+type TopicEndpointSpec struct {
+	// +optional
+	HTTP *HTTPEndpointSpec `json:"http,omitempty"`
+	// +optional
+	AMQP *AMQPEndpointSpec `json:"amqp,omitempty"`
+	// +optional
+	Kafka *KafkaEndpointSpec `json:"kafka,omitempty"`
+}
+
+// HTTPEndpointSpec represents an HTTP endpoint the notifications are pushed to
+type HTTPEndpointSpec struct {
+	// URI of the endpoint, http[s]://<fqdn>[:<port>][/<resource>]
+	URI string `json:"uri"`
+	// DisableVerifySSL disables the verification of the server certificate
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// SendCloudEvents sends the notifications in the CloudEvents format
+	// +optional
+	SendCloudEvents bool `json:"sendCloudEvents,omitempty"`
+}
+
+// AMQPEndpointSpec represents an AMQP 0.9.1 endpoint the notifications are pushed to
+type AMQPEndpointSpec struct {
+	// URI of the endpoint, amqp[s]://<fqdn>[:<port>][/<vhost>]
+	URI string `json:"uri"`
+	// Exchange is the name of the exchange the notifications are sent to
+	Exchange string `json:"exchange"`
+	// AckLevel is when the notification is acknowledged: none, broker (default) or routable
+	// +optional
+	AckLevel string `json:"ackLevel,omitempty"`
+	// DisableVerifySSL disables the verification of the server certificate
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// CredentialsSecretRef is the Secret holding the "user" and "password" of the broker
+	// +optional
+	CredentialsSecretRef *v1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// KafkaEndpointSpec represents a Kafka endpoint the notifications are pushed to
+type KafkaEndpointSpec struct {
+	// URI of the endpoint, kafka://<fqdn>[:<port>]
+	URI string `json:"uri"`
+	// UseSSL connects to the broker with TLS, it is required to send credentials
+	// +optional
+	UseSSL bool `json:"useSSL,omitempty"`
+	// DisableVerifySSL disables the verification of the server certificate
+	// +optional
+	DisableVerifySSL bool `json:"disableVerifySSL,omitempty"`
+	// AckLevel is when the notification is acknowledged: none or broker (default)
+	// +optional
+	AckLevel string `json:"ackLevel,omitempty"`
+	// CredentialsSecretRef is the Secret holding the "user" and "password" of the broker
+	// +optional
+	CredentialsSecretRef *v1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// BucketTopicStatus represents the status of a bucket topic
+type BucketTopicStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// ARN is the Amazon Resource Name of the topic, referenced by the bucket notifications
+	ARN string `json:"arn,omitempty"`
+	// Details contains the error of the last reconcile of the topic
+	Details string `json:"details,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotification represents the notifications sent by a bucket to a topic
+type CephBucketNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              BucketNotificationSpec    `json:"spec"`
+	Status            *BucketNotificationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephBucketNotificationList represents a list of bucket notifications
+type CephBucketNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephBucketNotification `json:"items"`
+}
+
+// BucketNotificationSpec represents the spec of a bucket notification
+type BucketNotificationSpec struct {
+	// Topic is the name of the CephBucketTopic the notifications are sent to
+	Topic string `json:"topic"`
+	// ObjectBucketClaim is the claim of the bucket sending the notifications
+	// +optional
+	ObjectBucketClaim *ObjectBucketClaimReference `json:"objectBucketClaim,omitempty"`
+	// BucketName is the name of the bucket sending the notifications, in the object store of the topic
+	// +optional
+	BucketName string `json:"bucketName,omitempty"`
+	// Events is the list of the S3 events notified, all the events if empty
+	// +optional
+	Events []string `json:"events,omitempty"`
+	// Filter selects the objects notified by their key
+	// +optional
+	Filter *NotificationFilterSpec `json:"filter,omitempty"`
+}
+
+// ObjectBucketClaimReference references an ObjectBucketClaim
+type ObjectBucketClaimReference struct {
+	Name string `json:"name"`
+	// Namespace of the claim, the namespace of the notification if empty
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NotificationFilterSpec represents the filter of the objects notified
+type NotificationFilterSpec struct {
+	// KeyFilters is the list of the filters on the object key
+	// +optional
+	KeyFilters []NotificationKeyFilterRule `json:"keyFilters,omitempty"`
+}
+
+// NotificationKeyFilterRule represents a filter rule on the object key
+type NotificationKeyFilterRule struct {
+	// Name of the rule: prefix, suffix or regex
+	Name string `json:"name"`
+	// Value the key is matched with
+	Value string `json:"value"`
+}
+
+// BucketNotificationStatus represents the status of a bucket notification
+type BucketNotificationStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// BucketName is the name of the bucket sending the notifications
+	BucketName string `json:"bucketName,omitempty"`
+	// TopicARN is the ARN of the topic the notifications are sent to
+	TopicARN string `json:"topicARN,omitempty"`
+	// Details contains the error of the last reconcile of the notification, the delivery errors are not reported
+	Details string `json:"details,omitempty"`
 }
 
 // +genclient
@@ -643,8 +1588,8 @@ type PullSpec struct {
 type CephObjectZoneGroup struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectZoneGroupSpec `json:"spec"`
-	Status            *Status             `json:"status"`
+	Spec              ObjectZoneGroupSpec    `json:"spec"`
+	Status            *ObjectZoneGroupStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -659,6 +1604,60 @@ type CephObjectZoneGroupList struct {
 type ObjectZoneGroupSpec struct {
 	//The display name for the ceph users
 	Realm string `json:"realm"`
+
+	// SyncPolicy restricts the replication between the zones of the zone group to the buckets and zones of its groups.
+	// The sync policy of the zone group is not managed if it is not set.
+	SyncPolicy *ObjectSyncPolicySpec `json:"syncPolicy,omitempty"`
+}
+
+// ObjectZoneGroupStatus represents the status of an ObjectZoneGroup
+type ObjectZoneGroupStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// SyncPolicy is the effective sync policy of the zone group and of the buckets of the spec
+	SyncPolicy []ObjectSyncGroupSpec `json:"syncPolicy,omitempty"`
+}
+
+// ObjectSyncPolicySpec represents the multisite sync policy of a zone group and of its buckets
+type ObjectSyncPolicySpec struct {
+	Groups []ObjectSyncGroupSpec `json:"groups,omitempty"`
+}
+
+// ObjectSyncGroupSpec represents a sync policy group with its data flows and pipes
+type ObjectSyncGroupSpec struct {
+	// ID is the name of the group
+	ID string `json:"id"`
+	// Bucket applies the group to a bucket instead of the zone group
+	Bucket string `json:"bucket,omitempty"`
+	// Status is "enabled", "allowed" or "forbidden"
+	Status string `json:"status"`
+	// Flows are the zones allowed to replicate between each other
+	Flows []ObjectSyncFlowSpec `json:"flows,omitempty"`
+	// Pipes are the buckets replicated between the zones
+	Pipes []ObjectSyncPipeSpec `json:"pipes,omitempty"`
+}
+
+// ObjectSyncFlowSpec represents a data flow of a sync policy group
+type ObjectSyncFlowSpec struct {
+	ID string `json:"id"`
+	// Type is "symmetrical" between the zones or "directional" from the source zone to the destination zone
+	Type string `json:"type"`
+	// Zones are the zones of a symmetrical flow
+	Zones []string `json:"zones,omitempty"`
+	// SourceZone is the source zone of a directional flow
+	SourceZone string `json:"sourceZone,omitempty"`
+	// DestZone is the destination zone of a directional flow
+	DestZone string `json:"destZone,omitempty"`
+}
+
+// ObjectSyncPipeSpec represents a pipe of a sync policy group. The zones and buckets default to all zones and buckets.
+type ObjectSyncPipeSpec struct {
+	ID           string   `json:"id"`
+	SourceZones  []string `json:"sourceZones,omitempty"`
+	SourceBucket string   `json:"sourceBucket,omitempty"`
+	DestZones    []string `json:"destZones,omitempty"`
+	DestBucket   string   `json:"destBucket,omitempty"`
+	// Prefix only replicates the objects whose name starts with the prefix
+	Prefix string `json:"prefix,omitempty"`
 }
 
 // +genclient
@@ -668,8 +1667,8 @@ type ObjectZoneGroupSpec struct {
 type CephObjectZone struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              ObjectZoneSpec `json:"spec"`
-	Status            *Status        `json:"status"`
+	Spec              ObjectZoneSpec    `json:"spec"`
+	Status            *ObjectZoneStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -690,6 +1689,57 @@ type ObjectZoneSpec struct {
 
 	// The data pool settings
 	DataPool PoolSpec `json:"dataPool"`
+
+	// Master promotes the zone to the master zone of its zone group, for example to fail over from an unavailable
+	// master zone. The former master zone is not demoted by unsetting it.
+	Master bool `json:"master,omitempty"`
+
+	// Archive creates the zone as an archive zone that keeps all the versions of the objects of the other zones. An
+	// archive zone cannot be the master zone and an existing zone cannot be converted.
+	Archive bool `json:"archive,omitempty"`
+}
+
+// ObjectZoneStatus represents the status of an ObjectZone
+type ObjectZoneStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// Master is whether the zone is the master zone of its zone group
+	Master bool `json:"master,omitempty"`
+	// MasterZoneGroup is whether the zone group of the zone is the master zone group of the realm
+	MasterZoneGroup bool `json:"masterZoneGroup,omitempty"`
+	// MasterPromoted is whether the zone was promoted to master since master was set in its spec
+	MasterPromoted bool `json:"masterPromoted,omitempty"`
+	// SyncStatus is the replication status reported by `radosgw-admin sync status`
+	SyncStatus *ObjectZoneSyncStatus `json:"syncStatus,omitempty"`
+}
+
+// ObjectZoneSyncStatus represents the metadata and data replication status of a zone
+type ObjectZoneSyncStatus struct {
+	Metadata    ObjectSyncStatus       `json:"metadata,omitempty"`
+	Data        []ObjectDataSyncStatus `json:"data,omitempty"`
+	LastChecked string                 `json:"lastChecked,omitempty"`
+}
+
+// ObjectSyncStatus represents the replication status of the metadata or of the data from a source zone
+type ObjectSyncStatus struct {
+	// State is the sync state, e.g. "syncing" or "no sync (zone is master)"
+	State string `json:"state,omitempty"`
+	// CaughtUp is whether the zone is caught up with the master zone or the source zone
+	CaughtUp bool `json:"caughtUp,omitempty"`
+	// ShardsBehind is the number of log shards with changes that are not applied yet
+	ShardsBehind int `json:"shardsBehind,omitempty"`
+	// RecoveringShards is the number of log shards recovering from sync errors
+	RecoveringShards int `json:"recoveringShards,omitempty"`
+	// OldestChange is the time of the oldest change that is not applied yet
+	OldestChange string `json:"oldestChange,omitempty"`
+	// Errors are the sync errors
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ObjectDataSyncStatus represents the data replication status from a source zone
+type ObjectDataSyncStatus struct {
+	// Source is the name of the source zone
+	Source           string `json:"source"`
+	ObjectSyncStatus `json:",inline"`
 }
 
 // +genclient
@@ -746,6 +1796,85 @@ type GaneshaServerSpec struct {
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephNFSExport represents an export of a CephFS path by the Ganesha servers of a CephNFS
+type CephNFSExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              CephNFSExportSpec    `json:"spec"`
+	Status            *CephNFSExportStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephNFSExportList represents a list of Ceph NFS exports
+type CephNFSExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephNFSExport `json:"items"`
+}
+
+// CephNFSExportSpec represents the specification of a Ceph NFS export
+type CephNFSExportSpec struct {
+	// NFSName is the name of the CephNFS serving the export, in the same namespace
+	NFSName string `json:"nfsName"`
+
+	// ExportID is the Ganesha export id, unique among the exports of the CephNFS
+	ExportID int `json:"exportID"`
+
+	// FilesystemName is the name of the CephFilesystem of the export, in the same namespace
+	FilesystemName string `json:"filesystemName"`
+
+	// Path is the exported path of the filesystem, "/" by default. It cannot be set with a subvolume.
+	Path string `json:"path,omitempty"`
+
+	// SubVolume is the name of the subvolume whose path is exported
+	SubVolume string `json:"subVolume,omitempty"`
+
+	// SubVolumeGroup is the group of the subvolume, the default group if empty
+	SubVolumeGroup string `json:"subVolumeGroup,omitempty"`
+
+	// PseudoPath is the path of the export in the NFSv4 pseudo filesystem of the servers
+	PseudoPath string `json:"pseudoPath"`
+
+	// AccessType is the access of the clients: RW, RO or None, RW by default
+	AccessType string `json:"accessType,omitempty"`
+
+	// Squash is the squashing of the users of the clients: None, Root, RootId or All, None by default
+	Squash string `json:"squash,omitempty"`
+
+	// Clients are the groups of clients whose access and squashing override the ones of the export
+	Clients []NFSExportClientSpec `json:"clients,omitempty"`
+
+	// User is the name of the cephx user of the export, without the "client." prefix, restricted to the exported
+	// path. It is created by the operator and must start with "nfs-export.", "nfs-export.<nfsName>.<name>" by default.
+	User string `json:"user,omitempty"`
+}
+
+// NFSExportClientSpec represents the access of a group of clients to an NFS export
+type NFSExportClientSpec struct {
+	// Addresses are the IP addresses, networks in CIDR notation or host names of the clients
+	Addresses []string `json:"addresses"`
+
+	// AccessType of the clients, the one of the export if empty
+	AccessType string `json:"accessType,omitempty"`
+
+	// Squash of the clients, the one of the export if empty
+	Squash string `json:"squash,omitempty"`
+}
+
+// CephNFSExportStatus represents the status of a Ceph NFS export
+type CephNFSExportStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// Path is the exported path of the filesystem
+	Path string `json:"path,omitempty"`
+	// User is the cephx user of the export created by the operator
+	User string `json:"user,omitempty"`
+}
+
 // NetworkSpec for Ceph includes backward compatibility code
 type NetworkSpec struct {
 	rookv1.NetworkSpec `json:",inline"`
@@ -824,7 +1953,7 @@ type CephRBDMirror struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 	Spec              RBDMirroringSpec `json:"spec"`
-	Status            *Status          `json:"status"`
+	Status            *RBDMirrorStatus `json:"status"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -856,6 +1985,9 @@ type RBDMirroringSpec struct {
 
 	// PriorityClassName sets priority class on the rbd mirror pods
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// StatusCheck sets up the rbd mirror daemon and peers status check
+	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
 }
 
 type RBDMirroringPeerSpec struct {
@@ -863,6 +1995,40 @@ type RBDMirroringPeerSpec struct {
 	SecretNames []string `json:"secretNames,omitempty"`
 }
 
+// RBDMirrorStatus represents the status of the rbd mirror daemons and their peers
+type RBDMirrorStatus struct {
+	Phase string `json:"phase,omitempty"`
+	// DaemonHealth is the overall health of the rbd mirror daemons
+	DaemonHealth string `json:"daemonHealth,omitempty"`
+	// Daemons is the list of rbd mirror daemons seen by the mirrored pools
+	Daemons []RBDMirrorDaemonStatus `json:"daemons,omitempty"`
+	// Peers is the list of configured peers
+	Peers       []RBDMirrorPeerStatus `json:"peers,omitempty"`
+	LastChecked string                `json:"lastChecked,omitempty"`
+	Details     string                `json:"details,omitempty"`
+}
+
+// RBDMirrorDaemonStatus represents the status of an rbd mirror daemon
+type RBDMirrorDaemonStatus struct {
+	ServiceID string `json:"serviceID,omitempty"`
+	Hostname  string `json:"hostname,omitempty"`
+	Leader    bool   `json:"leader,omitempty"`
+	Health    string `json:"health,omitempty"`
+}
+
+// RBDMirrorPeerStatus represents the status of an rbd mirror peer
+type RBDMirrorPeerStatus struct {
+	// SecretName is the name of the Kubernetes Secret the peer was added from
+	SecretName string `json:"secretName,omitempty"`
+	Pool       string `json:"pool,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	SiteName   string `json:"siteName,omitempty"`
+	Direction  string `json:"direction,omitempty"`
+	// LastSync is the most recent image status update received from the peer
+	LastSync string `json:"lastSync,omitempty"`
+	Health   string `json:"health,omitempty"`
+}
+
 // IPFamilyType represents the single stack Ipv4 or Ipv6 protocol.
 type IPFamilyType string
 
@@ -0,0 +1,174 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	scheme "github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CephBucketTopicsGetter has a method to return a CephBucketTopicInterface.
+// A group's client should implement this interface.
+type CephBucketTopicsGetter interface {
+	CephBucketTopics(namespace string) CephBucketTopicInterface
+}
+
+// CephBucketTopicInterface has methods to work with CephBucketTopic resources.
+type CephBucketTopicInterface interface {
+	Create(*v1.CephBucketTopic) (*v1.CephBucketTopic, error)
+	Update(*v1.CephBucketTopic) (*v1.CephBucketTopic, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.CephBucketTopic, error)
+	List(opts metav1.ListOptions) (*v1.CephBucketTopicList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CephBucketTopic, err error)
+	CephBucketTopicExpansion
+}
+
+// cephBucketTopics implements CephBucketTopicInterface
+type cephBucketTopics struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCephBucketTopics returns a CephBucketTopics
+func newCephBucketTopics(c *CephV1Client, namespace string) *cephBucketTopics {
+	return &cephBucketTopics{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cephBucketTopic, and returns the corresponding cephBucketTopic object, and an error if there is any.
+func (c *cephBucketTopics) Get(name string, options metav1.GetOptions) (result *v1.CephBucketTopic, err error) {
+	result = &v1.CephBucketTopic{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CephBucketTopics that match those selectors.
+func (c *cephBucketTopics) List(opts metav1.ListOptions) (result *v1.CephBucketTopicList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CephBucketTopicList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cephBucketTopics.
+func (c *cephBucketTopics) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a cephBucketTopic and creates it.  Returns the server's representation of the cephBucketTopic, and an error, if there is any.
+func (c *cephBucketTopics) Create(cephBucketTopic *v1.CephBucketTopic) (result *v1.CephBucketTopic, err error) {
+	result = &v1.CephBucketTopic{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		Body(cephBucketTopic).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cephBucketTopic and updates it. Returns the server's representation of the cephBucketTopic, and an error, if there is any.
+func (c *cephBucketTopics) Update(cephBucketTopic *v1.CephBucketTopic) (result *v1.CephBucketTopic, err error) {
+	result = &v1.CephBucketTopic{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		Name(cephBucketTopic.Name).
+		Body(cephBucketTopic).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the cephBucketTopic and deletes it. Returns an error if one occurs.
+func (c *cephBucketTopics) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cephBucketTopics) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched cephBucketTopic.
+func (c *cephBucketTopics) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CephBucketTopic, err error) {
+	result = &v1.CephBucketTopic{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cephbuckettopics").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
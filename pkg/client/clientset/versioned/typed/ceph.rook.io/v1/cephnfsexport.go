@@ -0,0 +1,174 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	scheme "github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CephNFSExportsGetter has a method to return a CephNFSExportInterface.
+// A group's client should implement this interface.
+type CephNFSExportsGetter interface {
+	CephNFSExports(namespace string) CephNFSExportInterface
+}
+
+// CephNFSExportInterface has methods to work with CephNFSExport resources.
+type CephNFSExportInterface interface {
+	Create(*v1.CephNFSExport) (*v1.CephNFSExport, error)
+	Update(*v1.CephNFSExport) (*v1.CephNFSExport, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.CephNFSExport, error)
+	List(opts metav1.ListOptions) (*v1.CephNFSExportList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CephNFSExport, err error)
+	CephNFSExportExpansion
+}
+
+// cephNFSExports implements CephNFSExportInterface
+type cephNFSExports struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCephNFSExports returns a CephNFSExports
+func newCephNFSExports(c *CephV1Client, namespace string) *cephNFSExports {
+	return &cephNFSExports{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cephNFSExport, and returns the corresponding cephNFSExport object, and an error if there is any.
+func (c *cephNFSExports) Get(name string, options metav1.GetOptions) (result *v1.CephNFSExport, err error) {
+	result = &v1.CephNFSExport{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CephNFSExports that match those selectors.
+func (c *cephNFSExports) List(opts metav1.ListOptions) (result *v1.CephNFSExportList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CephNFSExportList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cephNFSExports.
+func (c *cephNFSExports) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a cephNFSExport and creates it.  Returns the server's representation of the cephNFSExport, and an error, if there is any.
+func (c *cephNFSExports) Create(cephNFSExport *v1.CephNFSExport) (result *v1.CephNFSExport, err error) {
+	result = &v1.CephNFSExport{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		Body(cephNFSExport).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cephNFSExport and updates it. Returns the server's representation of the cephNFSExport, and an error, if there is any.
+func (c *cephNFSExports) Update(cephNFSExport *v1.CephNFSExport) (result *v1.CephNFSExport, err error) {
+	result = &v1.CephNFSExport{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		Name(cephNFSExport.Name).
+		Body(cephNFSExport).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the cephNFSExport and deletes it. Returns an error if one occurs.
+func (c *cephNFSExports) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cephNFSExports) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched cephNFSExport.
+func (c *cephNFSExports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.CephNFSExport, err error) {
+	result = &v1.CephNFSExport{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cephnfsexports").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCephNFSExports implements CephNFSExportInterface
+type FakeCephNFSExports struct {
+	Fake *FakeCephV1
+	ns   string
+}
+
+var cephnfsexportsResource = schema.GroupVersionResource{Group: "ceph.rook.io", Version: "v1", Resource: "cephnfsexports"}
+
+var cephnfsexportsKind = schema.GroupVersionKind{Group: "ceph.rook.io", Version: "v1", Kind: "CephNFSExport"}
+
+// Get takes name of the cephNFSExport, and returns the corresponding cephNFSExport object, and an error if there is any.
+func (c *FakeCephNFSExports) Get(name string, options v1.GetOptions) (result *cephrookiov1.CephNFSExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cephnfsexportsResource, c.ns, name), &cephrookiov1.CephNFSExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephNFSExport), err
+}
+
+// List takes label and field selectors, and returns the list of CephNFSExports that match those selectors.
+func (c *FakeCephNFSExports) List(opts v1.ListOptions) (result *cephrookiov1.CephNFSExportList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cephnfsexportsResource, cephnfsexportsKind, c.ns, opts), &cephrookiov1.CephNFSExportList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &cephrookiov1.CephNFSExportList{ListMeta: obj.(*cephrookiov1.CephNFSExportList).ListMeta}
+	for _, item := range obj.(*cephrookiov1.CephNFSExportList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cephNFSExports.
+func (c *FakeCephNFSExports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cephnfsexportsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cephNFSExport and creates it.  Returns the server's representation of the cephNFSExport, and an error, if there is any.
+func (c *FakeCephNFSExports) Create(cephNFSExport *cephrookiov1.CephNFSExport) (result *cephrookiov1.CephNFSExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cephnfsexportsResource, c.ns, cephNFSExport), &cephrookiov1.CephNFSExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephNFSExport), err
+}
+
+// Update takes the representation of a cephNFSExport and updates it. Returns the server's representation of the cephNFSExport, and an error, if there is any.
+func (c *FakeCephNFSExports) Update(cephNFSExport *cephrookiov1.CephNFSExport) (result *cephrookiov1.CephNFSExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cephnfsexportsResource, c.ns, cephNFSExport), &cephrookiov1.CephNFSExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephNFSExport), err
+}
+
+// Delete takes name of the cephNFSExport and deletes it. Returns an error if one occurs.
+func (c *FakeCephNFSExports) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cephnfsexportsResource, c.ns, name), &cephrookiov1.CephNFSExport{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCephNFSExports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cephnfsexportsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &cephrookiov1.CephNFSExportList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cephNFSExport.
+func (c *FakeCephNFSExports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *cephrookiov1.CephNFSExport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cephnfsexportsResource, c.ns, name, pt, data, subresources...), &cephrookiov1.CephNFSExport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephNFSExport), err
+}
@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CephNFSExportLister helps list CephNFSExports.
+type CephNFSExportLister interface {
+	// List lists all CephNFSExports in the indexer.
+	List(selector labels.Selector) (ret []*v1.CephNFSExport, err error)
+	// CephNFSExports returns an object that can list and get CephNFSExports.
+	CephNFSExports(namespace string) CephNFSExportNamespaceLister
+	CephNFSExportListerExpansion
+}
+
+// cephNFSExportLister implements the CephNFSExportLister interface.
+type cephNFSExportLister struct {
+	indexer cache.Indexer
+}
+
+// NewCephNFSExportLister returns a new CephNFSExportLister.
+func NewCephNFSExportLister(indexer cache.Indexer) CephNFSExportLister {
+	return &cephNFSExportLister{indexer: indexer}
+}
+
+// List lists all CephNFSExports in the indexer.
+func (s *cephNFSExportLister) List(selector labels.Selector) (ret []*v1.CephNFSExport, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephNFSExport))
+	})
+	return ret, err
+}
+
+// CephNFSExports returns an object that can list and get CephNFSExports.
+func (s *cephNFSExportLister) CephNFSExports(namespace string) CephNFSExportNamespaceLister {
+	return cephNFSExportNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CephNFSExportNamespaceLister helps list and get CephNFSExports.
+type CephNFSExportNamespaceLister interface {
+	// List lists all CephNFSExports in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.CephNFSExport, err error)
+	// Get retrieves the CephNFSExport from the indexer for a given namespace and name.
+	Get(name string) (*v1.CephNFSExport, error)
+	CephNFSExportNamespaceListerExpansion
+}
+
+// cephNFSExportNamespaceLister implements the CephNFSExportNamespaceLister
+// interface.
+type cephNFSExportNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CephNFSExports in the indexer for a given namespace.
+func (s cephNFSExportNamespaceLister) List(selector labels.Selector) (ret []*v1.CephNFSExport, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephNFSExport))
+	})
+	return ret, err
+}
+
+// Get retrieves the CephNFSExport from the indexer for a given namespace and name.
+func (s cephNFSExportNamespaceLister) Get(name string) (*v1.CephNFSExport, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cephnfsexport"), name)
+	}
+	return obj.(*v1.CephNFSExport), nil
+}
@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -109,6 +112,42 @@ func GetFilesystem(context *clusterd.Context, clusterInfo *ClusterInfo, fsName s
 	return &fs, nil
 }
 
+// FilesystemStatus is a representation of the json structure returned by 'ceph fs status'
+type FilesystemStatus struct {
+	Clients []struct {
+		Clients int    `json:"clients"`
+		FS      string `json:"fs"`
+	} `json:"clients"`
+	MDSMap []FilesystemStatusMDS `json:"mdsmap"`
+}
+
+// FilesystemStatusMDS is the load of an mds daemon returned by 'ceph fs status'
+type FilesystemStatusMDS struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Rank  int     `json:"rank"`
+	Rate  float64 `json:"rate"`
+	Caps  int     `json:"caps"`
+	Inos  int     `json:"inos"`
+}
+
+// GetFilesystemStatus gets the load of the mds daemons and the number of clients of a Ceph filesystem.
+func GetFilesystemStatus(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) (*FilesystemStatus, error) {
+	args := []string{"fs", "status", fsName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status of filesystem %q", fsName)
+	}
+
+	var status FilesystemStatus
+	err = json.Unmarshal(buf, &status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return &status, nil
+}
+
 // AllowStandbyReplay gets detailed status information about a Ceph filesystem.
 func AllowStandbyReplay(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, allowStandbyReplay bool) error {
 	logger.Infof("setting allow_standby_replay for filesystem %q", fsName)
@@ -165,6 +204,142 @@ func CreateFilesystem(context *clusterd.Context, clusterInfo *ClusterInfo, name,
 	return nil
 }
 
+// RemoveFilesystemDataPool detaches a data pool from a filesystem, ceph refuses to remove the default data pool
+func RemoveFilesystemDataPool(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, poolName string) error {
+	logger.Infof("removing data pool %q from filesystem %q", poolName, fsName)
+	args := []string{"fs", "rm_data_pool", fsName, poolName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove data pool %q from filesystem %q. %s", poolName, fsName, output)
+	}
+
+	return nil
+}
+
+// MDSCachedInode is an inode of the cache of an mds returned by 'ceph tell mds.<fs>:<rank> dump tree'
+type MDSCachedInode struct {
+	Path   string `json:"path"`
+	Layout struct {
+		// PoolID is the data pool of a file or the pool set by the ceph.dir.layout of a directory, -1 if none
+		PoolID int `json:"pool_id"`
+	} `json:"layout"`
+}
+
+// GetMDSCachedTree returns the inodes of the filesystem tree held in the cache of an active rank
+func GetMDSCachedTree(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, rank int) ([]MDSCachedInode, error) {
+	args := []string{"tell", fmt.Sprintf("mds.%s:%d", fsName, rank), "dump", "tree", "/"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dump the cache of rank %d of filesystem %q. %s", rank, fsName, string(buf))
+	}
+
+	var inodes []MDSCachedInode
+	if err := json.Unmarshal(buf, &inodes); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+	return inodes, nil
+}
+
+// SubVolumeGroupInfo is a representation of the json structure returned by 'ceph fs subvolumegroup info'
+type SubVolumeGroupInfo struct {
+	BytesPercent string `json:"bytes_pcent"`
+	// BytesQuota is either a number of bytes or "infinite"
+	BytesQuota json.RawMessage `json:"bytes_quota"`
+	BytesUsed  uint64          `json:"bytes_used"`
+	DataPool   string          `json:"data_pool"`
+	Mode       int             `json:"mode"`
+	UID        int             `json:"uid"`
+	GID        int             `json:"gid"`
+}
+
+// Quota returns the quota of the subvolume group in bytes, 0 means no quota
+func (i *SubVolumeGroupInfo) Quota() uint64 {
+	var quota uint64
+	if err := json.Unmarshal(i.BytesQuota, &quota); err != nil {
+		return 0
+	}
+	return quota
+}
+
+// CreateFilesystemSubVolumeGroup creates a subvolume group in a filesystem, creating an existing group is a no-op
+// An empty poolLayout or mode keeps the ceph defaults
+func CreateFilesystemSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName, poolLayout, mode string) error {
+	logger.Infof("creating subvolume group %q in filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "create", fsName, groupName}
+	if poolLayout != "" {
+		args = append(args, "--pool_layout", poolLayout)
+	}
+	if mode != "" {
+		args = append(args, "--mode", mode)
+	}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subvolume group %q in filesystem %q. %s", groupName, fsName, output)
+	}
+
+	return nil
+}
+
+// ResizeFilesystemSubVolumeGroup sets the quota of a subvolume group, a zero size removes the quota
+func ResizeFilesystemSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string, size uint64) error {
+	newSize := "inf"
+	if size > 0 {
+		newSize = strconv.FormatUint(size, 10)
+	}
+	logger.Infof("setting quota of subvolume group %q in filesystem %q to %q", groupName, fsName, newSize)
+	args := []string{"fs", "subvolumegroup", "resize", fsName, groupName, newSize}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to resize subvolume group %q in filesystem %q. %s", groupName, fsName, output)
+	}
+
+	return nil
+}
+
+// PinFilesystemSubVolumeGroup pins a subvolume group to an MDS rank, a rank of -1 removes the pinning
+func PinFilesystemSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string, rank int) error {
+	logger.Infof("pinning subvolume group %q in filesystem %q to mds rank %d", groupName, fsName, rank)
+	args := []string{"fs", "subvolumegroup", "pin", fsName, groupName, "export", strconv.Itoa(rank)}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to pin subvolume group %q in filesystem %q. %s", groupName, fsName, output)
+	}
+
+	return nil
+}
+
+// GetFilesystemSubVolumeGroupInfo returns the layout and usage of a subvolume group
+func GetFilesystemSubVolumeGroupInfo(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) (*SubVolumeGroupInfo, error) {
+	args := []string{"fs", "subvolumegroup", "info", fsName, groupName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get subvolume group %q info in filesystem %q", groupName, fsName)
+	}
+
+	var info SubVolumeGroupInfo
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed raw buffer response %s", string(buf))
+	}
+
+	return &info, nil
+}
+
+// DeleteFilesystemSubVolumeGroup removes a subvolume group from a filesystem, ceph refuses to remove a group with subvolumes
+func DeleteFilesystemSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, groupName string) error {
+	logger.Infof("deleting subvolume group %q from filesystem %q", groupName, fsName)
+	args := []string{"fs", "subvolumegroup", "rm", fsName, groupName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("subvolume group %q of filesystem %q does not exist", groupName, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete subvolume group %q from filesystem %q. %s", groupName, fsName, output)
+	}
+
+	return nil
+}
+
 // IsMultiFSEnabled returns true if ROOK_ALLOW_MULTIPLE_FILESYSTEMS is set to "true", allowing
 // Rook to create multiple Ceph filesystems. False if Rook is not allowed to do so.
 func IsMultiFSEnabled() bool {
@@ -318,3 +493,23 @@ func deleteFSPool(context *clusterd.Context, clusterInfo *ClusterInfo, poolNames
 	}
 	return DeletePool(context, clusterInfo, name)
 }
+
+// GetFilesystemSubVolumePath returns the path of a subvolume in a filesystem, an empty group is the default group
+func GetFilesystemSubVolumePath(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, subVolumeName, groupName string) (string, error) {
+	args := []string{"fs", "subvolume", "getpath", fsName, subVolumeName}
+	if groupName != "" {
+		args = append(args, "--group_name", groupName)
+	}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	buf, err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get path of subvolume %q in filesystem %q. %s", subVolumeName, fsName, string(buf))
+	}
+
+	path := strings.TrimSpace(string(buf))
+	if !strings.HasPrefix(path, "/") {
+		return "", errors.Errorf("invalid path %q of subvolume %q in filesystem %q", path, subVolumeName, fsName)
+	}
+	return path, nil
+}
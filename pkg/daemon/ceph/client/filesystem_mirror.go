@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// FilesystemMirroringModule is the name of the mgr module handling the filesystem mirroring
+	FilesystemMirroringModule = "mirroring"
+)
+
+// FilesystemMirrorPeerToken is the content of a filesystem mirroring bootstrap peer token
+type FilesystemMirrorPeerToken struct {
+	FSID       string `json:"fsid"`
+	Filesystem string `json:"filesystem"`
+	User       string `json:"user"`
+	SiteName   string `json:"site_name"`
+	Key        string `json:"key"`
+	MonHost    string `json:"mon_host"`
+}
+
+// FilesystemMirrorPeer is a peer of a mirrored filesystem as returned by 'ceph fs snapshot mirror peer_list'
+type FilesystemMirrorPeer struct {
+	ClientName string `json:"client_name"`
+	SiteName   string `json:"site_name"`
+	FSName     string `json:"fs_name"`
+}
+
+// FilesystemMirrorDirMap is the mapping of a mirrored directory as returned by 'ceph fs snapshot mirror dirmap'
+type FilesystemMirrorDirMap struct {
+	InstanceID   string  `json:"instance_id"`
+	LastShuffled float64 `json:"last_shuffled"`
+	State        string  `json:"state"`
+}
+
+// FilesystemMirrorDaemonStatus is the status of a cephfs-mirror daemon as returned by 'ceph fs snapshot mirror daemon status'
+type FilesystemMirrorDaemonStatus struct {
+	DaemonID    int                                `json:"daemon_id"`
+	Filesystems []FilesystemMirrorDaemonFilesystem `json:"filesystems"`
+}
+
+// FilesystemMirrorDaemonFilesystem is a filesystem handled by a cephfs-mirror daemon
+type FilesystemMirrorDaemonFilesystem struct {
+	FilesystemID   int                          `json:"filesystem_id"`
+	Name           string                       `json:"name"`
+	DirectoryCount int                          `json:"directory_count"`
+	Peers          []FilesystemMirrorDaemonPeer `json:"peers"`
+}
+
+// FilesystemMirrorDaemonPeer is a filesystem peer as seen by a cephfs-mirror daemon
+type FilesystemMirrorDaemonPeer struct {
+	UUID   string `json:"uuid"`
+	Remote struct {
+		ClientName  string `json:"client_name"`
+		ClusterName string `json:"cluster_name"`
+		FSName      string `json:"fs_name"`
+	} `json:"remote"`
+	Stats struct {
+		FailureCount  int `json:"failure_count"`
+		RecoveryCount int `json:"recovery_count"`
+	} `json:"stats"`
+}
+
+// EnableFilesystemSnapshotMirror enables the snapshot mirroring of a filesystem
+func EnableFilesystemSnapshotMirror(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) error {
+	logger.Infof("enabling snapshot mirroring for filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "enable", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable snapshot mirroring for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// DisableFilesystemSnapshotMirror disables the snapshot mirroring of a filesystem
+func DisableFilesystemSnapshotMirror(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) error {
+	logger.Infof("disabling snapshot mirroring for filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "disable", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable snapshot mirroring for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// ImportFilesystemMirrorBootstrapPeer adds a mirror peer to a filesystem from a bootstrap token
+func ImportFilesystemMirrorBootstrapPeer(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, token string) error {
+	logger.Infof("add filesystem mirror bootstrap peer token for filesystem %q", fsName)
+	args := []string{"fs", "snapshot", "mirror", "peer_bootstrap", "import", fsName, token}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add filesystem mirror peer token for filesystem %q. %s", fsName, output)
+	}
+
+	return nil
+}
+
+// ListFilesystemMirrorPeers returns the mirror peers of a filesystem indexed by their uuid
+func ListFilesystemMirrorPeers(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) (map[string]FilesystemMirrorPeer, error) {
+	args := []string{"fs", "snapshot", "mirror", "peer_list", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list mirror peers of filesystem %q", fsName)
+	}
+
+	peers := make(map[string]FilesystemMirrorPeer)
+	if len(output) == 0 {
+		return peers, nil
+	}
+	if err := json.Unmarshal(output, &peers); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal mirror peers of filesystem %q", fsName)
+	}
+
+	return peers, nil
+}
+
+// RemoveFilesystemMirrorPeer removes a mirror peer from a filesystem
+func RemoveFilesystemMirrorPeer(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, peerUUID string) error {
+	logger.Infof("removing mirror peer %q from filesystem %q", peerUUID, fsName)
+	args := []string{"fs", "snapshot", "mirror", "peer_remove", fsName, peerUUID}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove mirror peer %q from filesystem %q. %s", peerUUID, fsName, output)
+	}
+
+	return nil
+}
+
+// AddFilesystemMirrorDirectory adds a directory to the list of directories mirrored to the peers
+func AddFilesystemMirrorDirectory(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) error {
+	logger.Infof("adding directory %q to the mirrored directories of filesystem %q", path, fsName)
+	args := []string{"fs", "snapshot", "mirror", "add", fsName, path}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.EEXIST) {
+			logger.Debugf("directory %q is already mirrored for filesystem %q", path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add mirrored directory %q to filesystem %q. %s", path, fsName, output)
+	}
+
+	return nil
+}
+
+// RemoveFilesystemMirrorDirectory stops mirroring a directory
+func RemoveFilesystemMirrorDirectory(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) error {
+	logger.Infof("removing directory %q from the mirrored directories of filesystem %q", path, fsName)
+	args := []string{"fs", "snapshot", "mirror", "remove", fsName, path}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("directory %q is not mirrored for filesystem %q", path, fsName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove mirrored directory %q from filesystem %q. %s", path, fsName, output)
+	}
+
+	return nil
+}
+
+// GetFilesystemMirrorDirMap returns the mirror daemon a directory is mapped to
+func GetFilesystemMirrorDirMap(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) (*FilesystemMirrorDirMap, error) {
+	args := []string{"fs", "snapshot", "mirror", "dirmap", fsName, path}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get mirrored directory %q mapping for filesystem %q", path, fsName)
+	}
+
+	var dirMap FilesystemMirrorDirMap
+	if err := json.Unmarshal(output, &dirMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal mirrored directory %q mapping", path)
+	}
+
+	return &dirMap, nil
+}
+
+// GetFilesystemMirrorDaemonStatus returns the status of the cephfs-mirror daemons for a filesystem
+func GetFilesystemMirrorDaemonStatus(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) ([]FilesystemMirrorDaemonStatus, error) {
+	args := []string{"fs", "snapshot", "mirror", "daemon", "status", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get filesystem %q mirror daemons status", fsName)
+	}
+
+	var status []FilesystemMirrorDaemonStatus
+	if err := json.Unmarshal(output, &status); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal filesystem mirror daemons status")
+	}
+
+	return status, nil
+}
+
+// DecodeFilesystemMirrorPeerToken decodes a filesystem mirroring bootstrap peer token
+func DecodeFilesystemMirrorPeerToken(token string) (*FilesystemMirrorPeerToken, error) {
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode filesystem mirror peer token")
+	}
+
+	var peerToken FilesystemMirrorPeerToken
+	if err := json.Unmarshal(decoded, &peerToken); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal filesystem mirror peer token")
+	}
+
+	return &peerToken, nil
+}
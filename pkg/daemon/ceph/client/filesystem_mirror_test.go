@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fsMirrorPeerList     = `{"e8bb9b1c-3bc9-4b6d-a1b3-c5b7e1d9e1a3": {"client_name": "client.mirror_remote", "site_name": "site-b", "fs_name": "myfs", "mon_host": "[v2:10.0.0.1:3300,v1:10.0.0.1:6789]"}}`
+	fsMirrorDaemonStatus = `[{"daemon_id": 284167, "filesystems": [{"filesystem_id": 1, "name": "myfs", "directory_count": 1, "peers": [{"uuid": "e8bb9b1c-3bc9-4b6d-a1b3-c5b7e1d9e1a3", "remote": {"client_name": "client.mirror_remote", "cluster_name": "site-b", "fs_name": "myfs"}, "stats": {"failure_count": 2, "recovery_count": 1}}]}]}]`
+	// base64 of {"fsid": "0f5b0d4f-2b5c-4a8b-a8a5-9e6b1b7b1c1a", "filesystem": "myfs", "user": "client.mirror_remote", "site_name": "site-b", "key": "AQCvzWBeIV9lFRAAninzm+8XFxbSfTiPwoX50g==", "mon_host": "10.0.0.1"}
+	fsMirrorPeerToken = "eyJmc2lkIjogIjBmNWIwZDRmLTJiNWMtNGE4Yi1hOGE1LTllNmIxYjdiMWMxYSIsICJmaWxlc3lzdGVtIjogIm15ZnMiLCAidXNlciI6ICJjbGllbnQubWlycm9yX3JlbW90ZSIsICJzaXRlX25hbWUiOiAic2l0ZS1iIiwgImtleSI6ICJBUUN2eldCZUlWOWxGUkFBbmluem0rOFhGeGJTZlRpUHdvWDUwZz09IiwgIm1vbl9ob3N0IjogIjEwLjAuMC4xIn0="
+)
+
+func TestListFilesystemMirrorPeers(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfileArg string, args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snapshot" && args[2] == "mirror" && args[3] == "peer_list" {
+			assert.Equal(t, "myfs", args[4])
+			return fsMirrorPeerList, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	peers, err := ListFilesystemMirrorPeers(context, AdminClusterInfo("mycluster"), "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, "site-b", peers["e8bb9b1c-3bc9-4b6d-a1b3-c5b7e1d9e1a3"].SiteName)
+	assert.Equal(t, "client.mirror_remote", peers["e8bb9b1c-3bc9-4b6d-a1b3-c5b7e1d9e1a3"].ClientName)
+}
+
+func TestGetFilesystemMirrorDaemonStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfileArg string, args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snapshot" && args[2] == "mirror" && args[3] == "daemon" && args[4] == "status" {
+			return fsMirrorDaemonStatus, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	status, err := GetFilesystemMirrorDaemonStatus(context, AdminClusterInfo("mycluster"), "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(status))
+	assert.Equal(t, "myfs", status[0].Filesystems[0].Name)
+	assert.Equal(t, 2, status[0].Filesystems[0].Peers[0].Stats.FailureCount)
+	assert.Equal(t, "myfs", status[0].Filesystems[0].Peers[0].Remote.FSName)
+}
+
+func TestAddFilesystemMirrorDirectory(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfileArg string, args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snapshot" && args[2] == "mirror" && args[3] == "add" && args[4] == "myfs" {
+			assert.Equal(t, "/volumes/new", args[5])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := AddFilesystemMirrorDirectory(context, AdminClusterInfo("mycluster"), "myfs", "/volumes/new")
+	assert.NoError(t, err)
+
+	err = AddFilesystemMirrorDirectory(context, AdminClusterInfo("mycluster"), "otherfs", "/volumes/new")
+	assert.Error(t, err)
+}
+
+func TestDecodeFilesystemMirrorPeerToken(t *testing.T) {
+	token, err := DecodeFilesystemMirrorPeerToken(fsMirrorPeerToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "site-b", token.SiteName)
+	assert.Equal(t, "myfs", token.Filesystem)
+	assert.Equal(t, "client.mirror_remote", token.User)
+
+	_, err = DecodeFilesystemMirrorPeerToken("not a token")
+	assert.Error(t, err)
+}
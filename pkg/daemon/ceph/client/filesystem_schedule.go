@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// SnapshotScheduleModule is the name of the mgr module handling the filesystem snapshot schedules
+	SnapshotScheduleModule = "snap_schedule"
+)
+
+// filesystemScheduleIntervalRegex matches the filesystem snapshot schedule intervals of the snap_schedule module
+var filesystemScheduleIntervalRegex = regexp.MustCompile(`^(\d+)([hdwMy])$`)
+
+// FilesystemSnapshotSchedule is a snapshot schedule as returned by 'ceph fs snap-schedule status'
+type FilesystemSnapshotSchedule struct {
+	FS           string         `json:"fs"`
+	Path         string         `json:"path"`
+	Schedule     string         `json:"schedule"`
+	Retention    map[string]int `json:"retention"`
+	Start        string         `json:"start"`
+	Created      string         `json:"created"`
+	First        string         `json:"first"`
+	Last         string         `json:"last"`
+	LastPruned   string         `json:"last_pruned"`
+	CreatedCount int            `json:"created_count"`
+	PrunedCount  int            `json:"pruned_count"`
+	Active       bool           `json:"active"`
+}
+
+// AddFilesystemSnapshotSchedule adds a snapshot schedule to a directory of a filesystem
+func AddFilesystemSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, interval, startTime string) error {
+	logger.Infof("adding snapshot schedule %q to path %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "add", path, interval}
+	if startTime != "" {
+		args = append(args, startTime)
+	}
+	args = append(args, "--fs", fsName)
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot schedule %q to path %q of filesystem %q. %s", interval, path, fsName, output)
+	}
+
+	return nil
+}
+
+// RemoveFilesystemSnapshotSchedule removes a snapshot schedule from a directory of a filesystem
+// An empty interval removes all the schedules of the directory
+func RemoveFilesystemSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, interval, startTime string) error {
+	logger.Infof("removing snapshot schedule %q from path %q of filesystem %q", interval, path, fsName)
+	args := []string{"fs", "snap-schedule", "remove", path}
+	if interval != "" {
+		args = append(args, interval)
+		if startTime != "" {
+			args = append(args, startTime)
+		}
+	}
+	args = append(args, "--fs", fsName)
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot schedule %q of path %q does not exist", interval, path)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot schedule %q from path %q of filesystem %q. %s", interval, path, fsName, output)
+	}
+
+	return nil
+}
+
+// AddFilesystemSnapshotRetention keeps count snapshots of the given period ("h", "d", "w"...) for a directory of a filesystem
+func AddFilesystemSnapshotRetention(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, period string, count int) error {
+	logger.Infof("adding snapshot retention %d%s to path %q of filesystem %q", count, period, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "add", path, period, strconv.Itoa(count), "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot retention %d%s to path %q of filesystem %q. %s", count, period, path, fsName, output)
+	}
+
+	return nil
+}
+
+// RemoveFilesystemSnapshotRetention removes a snapshot retention policy from a directory of a filesystem
+func RemoveFilesystemSnapshotRetention(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path, period string, count int) error {
+	logger.Infof("removing snapshot retention %d%s from path %q of filesystem %q", count, period, path, fsName)
+	args := []string{"fs", "snap-schedule", "retention", "remove", path, period, strconv.Itoa(count), "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot retention %d%s from path %q of filesystem %q. %s", count, period, path, fsName, output)
+	}
+
+	return nil
+}
+
+// GetFilesystemSnapshotSchedules returns the snapshot schedules of a directory of a filesystem
+func GetFilesystemSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) ([]FilesystemSnapshotSchedule, error) {
+	args := []string{"fs", "snap-schedule", "status", path, "--fs", fsName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		// A directory without any schedule is reported as not found
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			return []FilesystemSnapshotSchedule{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get snapshot schedules of path %q of filesystem %q", path, fsName)
+	}
+
+	schedules := []FilesystemSnapshotSchedule{}
+	if len(output) == 0 {
+		return schedules, nil
+	}
+	if err := json.Unmarshal(output, &schedules); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal snapshot schedules of path %q", path)
+	}
+
+	return schedules, nil
+}
+
+// SameFilesystemSnapshotSchedule returns whether a schedule reported by ceph has the interval and the start time, the
+// equivalent intervals and start times compare equal, e.g. "24h" and "1d". An empty start time matches any start time.
+func SameFilesystemSnapshotSchedule(schedule FilesystemSnapshotSchedule, interval, startTime string) bool {
+	if normalizeFilesystemScheduleInterval(schedule.Schedule) != normalizeFilesystemScheduleInterval(interval) {
+		return false
+	}
+	return startTime == "" || normalizeFilesystemScheduleStartTime(schedule.Start) == normalizeFilesystemScheduleStartTime(startTime)
+}
+
+// normalizeFilesystemScheduleInterval returns the interval in the largest exact unit of hours, days and weeks, the
+// months and years are calendar periods and are only stripped of their leading zeros
+func normalizeFilesystemScheduleInterval(interval string) string {
+	match := filesystemScheduleIntervalRegex.FindStringSubmatch(interval)
+	if match == nil {
+		return interval
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return interval
+	}
+	hours := count
+	switch match[2] {
+	case "M", "y":
+		return fmt.Sprintf("%d%s", count, match[2])
+	case "d":
+		hours *= 24
+	case "w":
+		hours *= 24 * 7
+	}
+
+	switch {
+	case hours%(24*7) == 0:
+		return fmt.Sprintf("%dw", hours/(24*7))
+	case hours%24 == 0:
+		return fmt.Sprintf("%dd", hours/24)
+	default:
+		return fmt.Sprintf("%dh", hours)
+	}
+}
+
+// normalizeFilesystemScheduleStartTime returns the start time the way the snap_schedule module reports it, e.g.
+// "2020-11-11T00:00" is reported as "2020-11-11T00:00:00" and the times with a zone are converted to UTC
+func normalizeFilesystemScheduleStartTime(startTime string) string {
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, startTime); err == nil {
+			return t.Format("2006-01-02T15:04:05")
+		}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, startTime); err == nil {
+			return t.UTC().Format("2006-01-02T15:04:05")
+		}
+	}
+	return startTime
+}
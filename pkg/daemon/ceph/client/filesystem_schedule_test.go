@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const fsSnapshotSchedules = `[{"fs": "myfs", "subvol": null, "path": "/volumes", "rel_path": "/volumes", "schedule": "1h", "retention": {"h": 24, "d": 7}, "start": "2020-11-12T00:00:00", "created": "2020-11-12T08:53:47", "first": "2020-11-12T09:00:00", "last": "2020-11-12T11:00:00", "last_pruned": null, "created_count": 3, "pruned_count": 0, "active": true}]`
+
+func TestGetFilesystemSnapshotSchedules(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfileArg string, args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snap-schedule" && args[2] == "status" {
+			assert.Equal(t, "/volumes", args[3])
+			assert.Equal(t, "--fs", args[4])
+			assert.Equal(t, "myfs", args[5])
+			return fsSnapshotSchedules, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	schedules, err := GetFilesystemSnapshotSchedules(context, AdminClusterInfo("mycluster"), "myfs", "/volumes")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, "1h", schedules[0].Schedule)
+	assert.Equal(t, 24, schedules[0].Retention["h"])
+	assert.Equal(t, 7, schedules[0].Retention["d"])
+	assert.Equal(t, "2020-11-12T11:00:00", schedules[0].Last)
+	assert.Equal(t, "", schedules[0].LastPruned)
+	assert.Equal(t, 3, schedules[0].CreatedCount)
+	assert.True(t, schedules[0].Active)
+}
+
+func TestAddFilesystemSnapshotSchedule(t *testing.T) {
+	var lastArgs []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutputFile = func(command, outfileArg string, args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snap-schedule" {
+			lastArgs = args
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := AddFilesystemSnapshotSchedule(context, AdminClusterInfo("mycluster"), "myfs", "/", "1d", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "add", "/", "1d", "--fs", "myfs"}, lastArgs[:7])
+
+	err = AddFilesystemSnapshotSchedule(context, AdminClusterInfo("mycluster"), "myfs", "/", "1d", "2020-11-12T14:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "add", "/", "1d", "2020-11-12T14:00:00", "--fs", "myfs"}, lastArgs[:8])
+
+	err = AddFilesystemSnapshotRetention(context, AdminClusterInfo("mycluster"), "myfs", "/", "w", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "snap-schedule", "retention", "add", "/", "w", "4", "--fs", "myfs"}, lastArgs[:9])
+}
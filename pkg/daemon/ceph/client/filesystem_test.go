@@ -166,3 +166,103 @@ func TestFilesystemRemove(t *testing.T) {
 	assert.True(t, dataDeleted)
 	assert.True(t, crushDeleted)
 }
+
+func TestGetFilesystemStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		assert.Equal(t, []string{"fs", "status", "myfs"}, args[:3])
+		return `{"clients":[{"clients":2,"fs":"myfs"}],"mdsmap":[{"caps":5,"dirs":12,"dns":10,"inos":13,"name":"myfs-a","rank":0,"rate":12.5,"state":"active"},{"name":"myfs-b","state":"standby"}]}`, nil
+	}
+
+	status, err := GetFilesystemStatus(context, AdminClusterInfo("mycluster"), "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, status.Clients[0].Clients)
+	assert.Equal(t, 2, len(status.MDSMap))
+	assert.Equal(t, FilesystemStatusMDS{Name: "myfs-a", State: "active", Rank: 0, Rate: 12.5, Caps: 5, Inos: 13}, status.MDSMap[0])
+	assert.Equal(t, "standby", status.MDSMap[1].State)
+
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		return "", errors.New("error")
+	}
+	_, err = GetFilesystemStatus(context, AdminClusterInfo("mycluster"), "myfs")
+	assert.Error(t, err)
+}
+
+func TestCreateFilesystemSubVolumeGroup(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	var lastArgs []string
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		lastArgs = args
+		return "", nil
+	}
+
+	err := CreateFilesystemSubVolumeGroup(context, AdminClusterInfo("mycluster"), "myfs", "team-a", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "team-a"}, lastArgs[:5])
+	assert.Equal(t, "--connect-timeout=15", lastArgs[5])
+
+	err = CreateFilesystemSubVolumeGroup(context, AdminClusterInfo("mycluster"), "myfs", "team-a", "myfs-data1", "0755")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "team-a", "--pool_layout", "myfs-data1", "--mode", "0755"}, lastArgs[:9])
+
+	err = ResizeFilesystemSubVolumeGroup(context, AdminClusterInfo("mycluster"), "myfs", "team-a", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "subvolumegroup", "resize", "myfs", "team-a", "inf"}, lastArgs[:6])
+
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		return "", errors.New("error")
+	}
+	err = CreateFilesystemSubVolumeGroup(context, AdminClusterInfo("mycluster"), "myfs", "team-a", "", "")
+	assert.Error(t, err)
+}
+
+func TestGetFilesystemSubVolumeGroupInfo(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		assert.Equal(t, "info", args[2])
+		if args[4] == "team-a" {
+			return `{"atime": "2020-11-20 10:11:12", "bytes_pcent": "12.50", "bytes_quota": 8589934592, "bytes_used": 1073741824, "created_at": "2020-11-20 10:11:12", "data_pool": "myfs-data0", "gid": 0, "mode": 16877, "mon_addrs": ["10.0.0.1:6789"], "uid": 0}`, nil
+		}
+		return `{"bytes_pcent": "undefined", "bytes_quota": "infinite", "bytes_used": 0, "data_pool": "myfs-data0", "gid": 0, "mode": 16877, "uid": 0}`, nil
+	}
+
+	info, err := GetFilesystemSubVolumeGroupInfo(context, AdminClusterInfo("mycluster"), "myfs", "team-a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8589934592), info.Quota())
+	assert.Equal(t, uint64(1073741824), info.BytesUsed)
+	assert.Equal(t, "12.50", info.BytesPercent)
+	assert.Equal(t, "myfs-data0", info.DataPool)
+
+	info, err = GetFilesystemSubVolumeGroupInfo(context, AdminClusterInfo("mycluster"), "myfs", "team-b")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), info.Quota())
+}
+
+func TestGetFilesystemSubVolumePath(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	var lastArgs []string
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		lastArgs = args
+		return "/volumes/team-a/data/1a2b3c\n", nil
+	}
+
+	path, err := GetFilesystemSubVolumePath(context, AdminClusterInfo("mycluster"), "myfs", "data", "team-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "/volumes/team-a/data/1a2b3c", path)
+	assert.Equal(t, []string{"fs", "subvolume", "getpath", "myfs", "data", "--group_name", "team-a"}, lastArgs[:7])
+
+	_, err = GetFilesystemSubVolumePath(context, AdminClusterInfo("mycluster"), "myfs", "data", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fs", "subvolume", "getpath", "myfs", "data"}, lastArgs[:5])
+	assert.Equal(t, "--connect-timeout=15", lastArgs[5])
+
+	executor.MockExecuteCommandWithOutputFile = func(command, outputFile string, args ...string) (string, error) {
+		return "Error ENOENT: subvolume 'data' does not exist", nil
+	}
+	_, err = GetFilesystemSubVolumePath(context, AdminClusterInfo("mycluster"), "myfs", "data", "")
+	assert.Error(t, err)
+}
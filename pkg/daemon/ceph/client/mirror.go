@@ -21,12 +21,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 )
 
+var (
+	// scheduleIntervalRegex matches the snapshot schedule intervals in minutes, hours or days, minutes by default
+	scheduleIntervalRegex = regexp.MustCompile(`^(\d+)([mhd]?)$`)
+)
+
 // PoolMirroringStatus is the mirroring status of a given pool
 type PoolMirroringStatus struct {
 	Summary struct {
@@ -35,6 +44,55 @@ type PoolMirroringStatus struct {
 		ImageHealth  string      `json:"image_health"`
 		States       interface{} `json:"states"`
 	} `json:"summary"`
+	// Daemons and Images are only populated when the status is retrieved in verbose mode
+	Daemons []MirrorDaemonStatus  `json:"daemons,omitempty"`
+	Images  []MirroredImageStatus `json:"images,omitempty"`
+}
+
+// MirrorDaemonStatus is the status of an rbd-mirror daemon as seen by a given pool
+type MirrorDaemonStatus struct {
+	ServiceID string `json:"service_id"`
+	ClientID  string `json:"client_id"`
+	Hostname  string `json:"hostname"`
+	Leader    bool   `json:"leader"`
+	Health    string `json:"health"`
+}
+
+// MirroredImageStatus is the mirroring status of a given image
+type MirroredImageStatus struct {
+	Name        string                    `json:"name"`
+	GlobalID    string                    `json:"global_id"`
+	State       string                    `json:"state"`
+	Description string                    `json:"description"`
+	LastUpdate  string                    `json:"last_update"`
+	PeerSites   []MirroredImagePeerStatus `json:"peer_sites"`
+}
+
+// MirroredImagePeerStatus is the mirroring status of a given image on a peer site
+type MirroredImagePeerStatus struct {
+	SiteName    string `json:"site_name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	LastUpdate  string `json:"last_update"`
+}
+
+const (
+	// MirrorImageStateReplaying is the state of an image being replicated
+	MirrorImageStateReplaying = "up+replaying"
+	splitBrainDescription     = "split-brain"
+)
+
+// IsSplitBrain returns whether the image is in a split-brain situation
+func (i *MirroredImageStatus) IsSplitBrain() bool {
+	if strings.Contains(i.Description, splitBrainDescription) {
+		return true
+	}
+	for _, peer := range i.PeerSites {
+		if strings.Contains(peer.Description, splitBrainDescription) {
+			return true
+		}
+	}
+	return false
 }
 
 // PoolMirroringInfo is the mirroring info of a given pool
@@ -105,6 +163,23 @@ func CreateRBDMirrorBootstrapPeer(context *clusterd.Context, clusterInfo *Cluste
 	return output, nil
 }
 
+// RemoveRBDMirrorPeer removes a mirror peer from the rbd-mirror configuration of a pool
+func RemoveRBDMirrorPeer(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, peerUUID string) error {
+	logger.Infof("removing rbd-mirror peer %q from pool %q", peerUUID, poolName)
+
+	// Build command
+	args := []string{"mirror", "pool", "peer", "remove", poolName, peerUUID}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove rbd-mirror peer %q from pool %q. %s", peerUUID, poolName, output)
+	}
+
+	return nil
+}
+
 // EnablePoolMirroring turns on mirroring on that pool by specifying the mirroring type
 func EnablePoolMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.PoolSpec, poolName string) error {
 	logger.Infof("enabling mirroring type %q for pool %q", pool.Mirroring.Mode, poolName)
@@ -145,6 +220,81 @@ func GetPoolMirroringStatus(context *clusterd.Context, clusterInfo *ClusterInfo,
 	return &poolMirroringStatus, nil
 }
 
+// GetPoolMirroringImagesStatus returns the pool mirroring status including the status of each mirrored image
+func GetPoolMirroringImagesStatus(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (*PoolMirroringStatus, error) {
+	logger.Debugf("retrieving mirroring pool %q images status", poolName)
+
+	// Build command
+	args := []string{"mirror", "pool", "status", poolName, "--verbose"}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve mirroring pool %q images status", poolName)
+	}
+
+	var poolMirroringStatus PoolMirroringStatus
+	if err := json.Unmarshal([]byte(buf), &poolMirroringStatus); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mirror pool images status response")
+	}
+
+	return &poolMirroringStatus, nil
+}
+
+// PromotePoolImages promotes all the non-primary mirrored images of a pool to primary
+func PromotePoolImages(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, force bool) error {
+	logger.Infof("promoting mirrored images of pool %q (force: %t)", poolName, force)
+
+	// Build command
+	args := []string{"mirror", "pool", "promote", poolName}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to promote mirrored images of pool %q. %s", poolName, output)
+	}
+
+	return nil
+}
+
+// DemotePoolImages demotes all the primary mirrored images of a pool to non-primary
+func DemotePoolImages(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) error {
+	logger.Infof("demoting mirrored images of pool %q", poolName)
+
+	// Build command
+	args := []string{"mirror", "pool", "demote", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to demote mirrored images of pool %q. %s", poolName, output)
+	}
+
+	return nil
+}
+
+// PeersAcknowledged returns whether the peer sites acknowledged a promotion or a demotion of the pool images
+// After a promotion, the peers must be replaying every image from us. After a demotion, the peers must have stopped
+// replaying our images.
+func PeersAcknowledged(status *PoolMirroringStatus, promoted bool) bool {
+	for _, image := range status.Images {
+		for _, peer := range image.PeerSites {
+			replaying := peer.State == MirrorImageStateReplaying
+			if promoted != replaying {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // GetPoolMirroringInfo  prints the pool mirroring information
 func GetPoolMirroringInfo(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (*PoolMirroringInfo, error) {
 	logger.Debugf("retrieving mirroring pool %q info", poolName)
@@ -168,3 +318,220 @@ func GetPoolMirroringInfo(context *clusterd.Context, clusterInfo *ClusterInfo, p
 
 	return &poolMirroringInfo, nil
 }
+
+// SnapshotScheduleStatus is the status of the snapshot schedules of a given pool
+type SnapshotScheduleStatus struct {
+	ScheduledImages []ScheduledImage `json:"scheduled_images"`
+}
+
+// ScheduledImage is the next scheduled snapshot of a given image
+type ScheduledImage struct {
+	ScheduleTime string `json:"schedule_time"`
+	Image        string `json:"image"`
+}
+
+// SnapshotSchedule is a list of snapshot schedules for a pool, namespace or image
+type SnapshotSchedule struct {
+	Pool      string                 `json:"pool"`
+	Namespace string                 `json:"namespace"`
+	Image     string                 `json:"image"`
+	Items     []SnapshotScheduleItem `json:"items"`
+}
+
+// SnapshotScheduleItem is a single snapshot schedule
+type SnapshotScheduleItem struct {
+	Interval  string `json:"interval"`
+	StartTime string `json:"start_time"`
+}
+
+func snapshotScheduleArgs(poolName, namespace string) []string {
+	args := []string{"--pool", poolName}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	return args
+}
+
+// AddSnapshotSchedule adds a mirroring snapshot schedule to a pool or a namespace of that pool
+func AddSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, schedule cephv1.SnapshotScheduleSpec) error {
+	logger.Infof("adding snapshot schedule every %q for pool %q (namespace %q)", schedule.Interval, poolName, schedule.Namespace)
+
+	// Build command
+	args := []string{"mirror", "snapshot", "schedule", "add"}
+	args = append(args, snapshotScheduleArgs(poolName, schedule.Namespace)...)
+	args = append(args, schedule.Interval)
+	if schedule.StartTime != "" {
+		args = append(args, schedule.StartTime)
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot schedule every %q for pool %q. %s", schedule.Interval, poolName, output)
+	}
+
+	return nil
+}
+
+// RemoveSnapshotSchedule removes a mirroring snapshot schedule from a pool or a namespace of that pool
+func RemoveSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, schedule cephv1.SnapshotScheduleSpec) error {
+	logger.Infof("removing snapshot schedule every %q for pool %q (namespace %q)", schedule.Interval, poolName, schedule.Namespace)
+
+	// Build command
+	args := []string{"mirror", "snapshot", "schedule", "remove"}
+	args = append(args, snapshotScheduleArgs(poolName, schedule.Namespace)...)
+	args = append(args, schedule.Interval)
+	if schedule.StartTime != "" {
+		args = append(args, schedule.StartTime)
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot schedule every %q for pool %q. %s", schedule.Interval, poolName, output)
+	}
+
+	return nil
+}
+
+// ListSnapshotSchedulesRecursively lists the snapshot schedules of a pool, its namespaces and its images
+func ListSnapshotSchedulesRecursively(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) ([]SnapshotSchedule, error) {
+	logger.Debugf("retrieving snapshot schedules for pool %q", poolName)
+
+	// Build command
+	args := []string{"mirror", "snapshot", "schedule", "ls", "--pool", poolName, "--recursive"}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve snapshot schedules for pool %q. %s", poolName, string(buf))
+	}
+
+	// Unmarshal JSON into Go struct
+	var snapshotSchedules []SnapshotSchedule
+	if err := json.Unmarshal(buf, &snapshotSchedules); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot schedules response")
+	}
+
+	return snapshotSchedules, nil
+}
+
+// GetSnapshotScheduleStatus returns the next scheduled snapshot of each mirrored image of a pool
+func GetSnapshotScheduleStatus(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (*SnapshotScheduleStatus, error) {
+	logger.Debugf("retrieving snapshot schedule status for pool %q", poolName)
+
+	// Build command
+	args := []string{"mirror", "snapshot", "schedule", "status", "--pool", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve snapshot schedule status for pool %q. %s", poolName, string(buf))
+	}
+
+	// Unmarshal JSON into Go struct
+	var snapshotScheduleStatus SnapshotScheduleStatus
+	if err := json.Unmarshal(buf, &snapshotScheduleStatus); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot schedule status response")
+	}
+
+	return &snapshotScheduleStatus, nil
+}
+
+// ReconcileSnapshotSchedules makes sure the pool and namespace level snapshot schedules match the spec
+// Image level schedules are left untouched since they are not managed by the pool spec
+func ReconcileSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, desired []cephv1.SnapshotScheduleSpec) error {
+	current, err := ListSnapshotSchedulesRecursively(context, clusterInfo, poolName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list snapshot schedules for pool %q", poolName)
+	}
+
+	existing := []cephv1.SnapshotScheduleSpec{}
+	for _, s := range current {
+		if s.Image != "" {
+			continue
+		}
+		for _, item := range s.Items {
+			existing = append(existing, cephv1.SnapshotScheduleSpec{Namespace: s.Namespace, Interval: item.Interval, StartTime: item.StartTime})
+		}
+	}
+
+	// Remove the schedules that are not desired anymore
+	for _, schedule := range existing {
+		if !containsSnapshotSchedule(desired, schedule) {
+			if err := RemoveSnapshotSchedule(context, clusterInfo, poolName, schedule); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Add the missing schedules
+	for _, schedule := range desired {
+		if !containsSnapshotSchedule(existing, schedule) {
+			if err := AddSnapshotSchedule(context, clusterInfo, poolName, schedule); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsSnapshotSchedule(schedules []cephv1.SnapshotScheduleSpec, schedule cephv1.SnapshotScheduleSpec) bool {
+	for _, s := range schedules {
+		if s.Namespace == schedule.Namespace &&
+			normalizeScheduleInterval(s.Interval) == normalizeScheduleInterval(schedule.Interval) &&
+			normalizeScheduleStartTime(s.StartTime) == normalizeScheduleStartTime(schedule.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeScheduleInterval returns the interval the way Ceph reports it, e.g. "60m" is reported as "1h"
+func normalizeScheduleInterval(interval string) string {
+	match := scheduleIntervalRegex.FindStringSubmatch(interval)
+	if match == nil {
+		return interval
+	}
+	minutes, err := strconv.Atoi(match[1])
+	if err != nil {
+		return interval
+	}
+	switch match[2] {
+	case "h":
+		minutes *= 60
+	case "d":
+		minutes *= 60 * 24
+	}
+
+	switch {
+	case minutes%(60*24) == 0:
+		return fmt.Sprintf("%dd", minutes/(60*24))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// normalizeScheduleStartTime returns the start time the way Ceph reports it, e.g. "14:00" is reported as "14:00:00"
+func normalizeScheduleStartTime(startTime string) string {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, startTime); err == nil {
+			return t.Format("15:04:05")
+		}
+	}
+	for _, layout := range []string{"15:04Z07:00", "15:04:05Z07:00"} {
+		if t, err := time.Parse(layout, startTime); err == nil {
+			return t.Format("15:04:05-07:00")
+		}
+	}
+	return startTime
+}
@@ -29,6 +29,8 @@ import (
 var (
 	bootstrapPeerToken = `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==` //nolint:gosec // This is just a var name, not a real token
 	mirrorStatus       = `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"starting_replay":1,"replaying":1}}}`
+	snapshotSchedules  = `[{"pool":"pool-test","namespace":"","image":"","items":[{"interval":"1d","start_time":null}]},{"pool":"pool-test","namespace":"ns","image":"","items":[{"interval":"1h","start_time":null}]},{"pool":"pool-test","namespace":"","image":"image-1","items":[{"interval":"5m","start_time":null}]}]`
+	mirrorImagesStatus = `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"stopped":1,"error":1}},"images":[{"name":"image-1","global_id":"3f6b8a1e","state":"up+stopped","description":"local image is primary","last_update":"2020-09-21 09:00:00","peer_sites":[{"site_name":"site-b","state":"up+replaying","description":"replaying","last_update":"2020-09-21 09:00:00"}]},{"name":"image-2","global_id":"9c2d1f0b","state":"up+error","description":"split-brain","last_update":"2020-09-21 09:00:00","peer_sites":[{"site_name":"site-b","state":"up+error","description":"split-brain","last_update":"2020-09-21 09:00:00"}]}]}`
 	mirrorInfo         = `{"mode":"image","site_name":"39074576-5884-4ef3-8a4d-8a0c5ed33031","peers":[{"uuid":"4a6983c0-3c9d-40f5-b2a9-2334a4659827","direction":"rx-tx","site_name":"ocs","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`
 )
 
@@ -156,3 +158,208 @@ func TestGetPoolMirroringInfo(t *testing.T) {
 	assert.Equal(t, "image", poolMirrorInfo.Mode)
 	assert.Equal(t, 1, len(poolMirrorInfo.Peers))
 }
+
+func TestAddSnapshotSchedule(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "snapshot", args[1])
+			assert.Equal(t, "schedule", args[2])
+			assert.Equal(t, "add", args[3])
+			assert.Equal(t, "--pool", args[4])
+			assert.Equal(t, pool, args[5])
+			assert.Equal(t, "--namespace", args[6])
+			assert.Equal(t, "ns", args[7])
+			assert.Equal(t, "1d", args[8])
+			assert.Equal(t, "14:00:00-05:00", args[9])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := AddSnapshotSchedule(context, AdminClusterInfo("mycluster"), pool, cephv1.SnapshotScheduleSpec{Namespace: "ns", Interval: "1d", StartTime: "14:00:00-05:00"})
+	assert.NoError(t, err)
+}
+
+func TestListSnapshotSchedulesRecursively(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "snapshot", args[1])
+			assert.Equal(t, "schedule", args[2])
+			assert.Equal(t, "ls", args[3])
+			assert.Equal(t, "--pool", args[4])
+			assert.Equal(t, pool, args[5])
+			assert.Equal(t, "--recursive", args[6])
+			return snapshotSchedules, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	schedules, err := ListSnapshotSchedulesRecursively(context, AdminClusterInfo("mycluster"), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(schedules))
+	assert.Equal(t, "1d", schedules[0].Items[0].Interval)
+	assert.Equal(t, "", schedules[0].Items[0].StartTime)
+	assert.Equal(t, "ns", schedules[1].Namespace)
+}
+
+func TestGetSnapshotScheduleStatus(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "status", args[3])
+			return `{"scheduled_images":[{"schedule_time":"2020-09-21 09:00:00","image":"pool-test/image-1"}]}`, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	status, err := GetSnapshotScheduleStatus(context, AdminClusterInfo("mycluster"), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(status.ScheduledImages))
+	assert.Equal(t, "pool-test/image-1", status.ScheduledImages[0].Image)
+}
+
+func TestReconcileSnapshotSchedules(t *testing.T) {
+	pool := "pool-test"
+	added := []string{}
+	removed := []string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" {
+			switch args[3] {
+			case "ls":
+				return snapshotSchedules, nil
+			case "add":
+				added = append(added, args[8])
+				return "", nil
+			case "remove":
+				removed = append(removed, args[8])
+				return "", nil
+			}
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// the pool schedule is kept, the namespace schedule is removed and a new one is added
+	desired := []cephv1.SnapshotScheduleSpec{{Interval: "1d"}, {Namespace: "ns", Interval: "4h"}}
+	err := ReconcileSnapshotSchedules(context, AdminClusterInfo("mycluster"), pool, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4h"}, added)
+	assert.Equal(t, []string{"1h"}, removed)
+
+	// the schedules are compared the way ceph normalizes them
+	added, removed = []string{}, []string{}
+	desired = []cephv1.SnapshotScheduleSpec{{Interval: "24h"}, {Namespace: "ns", Interval: "60m"}}
+	err = ReconcileSnapshotSchedules(context, AdminClusterInfo("mycluster"), pool, desired)
+	assert.NoError(t, err)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestNormalizeSnapshotSchedule(t *testing.T) {
+	assert.Equal(t, "1d", normalizeScheduleInterval("1d"))
+	assert.Equal(t, "1d", normalizeScheduleInterval("1440m"))
+	assert.Equal(t, "2h", normalizeScheduleInterval("120"))
+	assert.Equal(t, "90m", normalizeScheduleInterval("90m"))
+	assert.Equal(t, "36h", normalizeScheduleInterval("36h"))
+	assert.Equal(t, "invalid", normalizeScheduleInterval("invalid"))
+
+	assert.Equal(t, "", normalizeScheduleStartTime(""))
+	assert.Equal(t, "14:00:00", normalizeScheduleStartTime("14:00"))
+	assert.Equal(t, "14:00:00", normalizeScheduleStartTime("14:00:00"))
+	assert.Equal(t, "14:00:00+02:00", normalizeScheduleStartTime("14:00+02:00"))
+	assert.Equal(t, "14:00:00+00:00", normalizeScheduleStartTime("14:00:00Z"))
+}
+
+func TestPromotePoolImages(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "pool", args[1])
+			assert.Equal(t, "promote", args[2])
+			assert.Equal(t, pool, args[3])
+			assert.Equal(t, "--force", args[4])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := PromotePoolImages(context, AdminClusterInfo("mycluster"), pool, true)
+	assert.NoError(t, err)
+}
+
+func TestDemotePoolImages(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "pool", args[1])
+			assert.Equal(t, "demote", args[2])
+			assert.Equal(t, pool, args[3])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := DemotePoolImages(context, AdminClusterInfo("mycluster"), pool)
+	assert.NoError(t, err)
+}
+
+func TestGetPoolMirroringImagesStatus(t *testing.T) {
+	pool := "pool-test"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "status", args[2])
+			assert.Equal(t, "--verbose", args[4])
+			return mirrorImagesStatus, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	status, err := GetPoolMirroringImagesStatus(context, AdminClusterInfo("mycluster"), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(status.Images))
+	assert.False(t, status.Images[0].IsSplitBrain())
+	assert.True(t, status.Images[1].IsSplitBrain())
+
+	// the second image peer is not replaying
+	assert.False(t, PeersAcknowledged(status, true))
+	assert.False(t, PeersAcknowledged(status, false))
+	status.Images = status.Images[:1]
+	assert.True(t, PeersAcknowledged(status, true))
+	assert.False(t, PeersAcknowledged(status, false))
+}
+
+func TestRemoveRBDMirrorPeer(t *testing.T) {
+	pool := "pool-test"
+	peerUUID := "4a6983c0-3c9d-40f5-b2a9-2334a4659827"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "pool", args[1])
+			assert.Equal(t, "peer", args[2])
+			assert.Equal(t, "remove", args[3])
+			assert.Equal(t, pool, args[4])
+			assert.Equal(t, peerUUID, args[5])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := RemoveRBDMirrorPeer(context, AdminClusterInfo("mycluster"), pool, peerUUID)
+	assert.NoError(t, err)
+}
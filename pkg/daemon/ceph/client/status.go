@@ -64,6 +64,8 @@ type HealthStatus struct {
 type CheckMessage struct {
 	Severity string  `json:"severity"`
 	Summary  Summary `json:"summary"`
+	// Detail lists the daemons or the objects raising the check, it is only reported by "ceph health detail"
+	Detail []Summary `json:"detail,omitempty"`
 }
 
 type Summary struct {
@@ -160,6 +162,23 @@ func Status(context *clusterd.Context, clusterInfo *ClusterInfo) (CephStatus, er
 	return status, nil
 }
 
+// HealthDetail returns the health checks of the cluster with the detail of the daemons or objects raising them
+func HealthDetail(context *clusterd.Context, clusterInfo *ClusterInfo) (HealthStatus, error) {
+	args := []string{"health", "detail"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	buf, err := cmd.Run()
+	if err != nil {
+		return HealthStatus{}, errors.Wrapf(err, "failed to get health detail. %s", string(buf))
+	}
+
+	var health HealthStatus
+	if err := json.Unmarshal(buf, &health); err != nil {
+		return HealthStatus{}, errors.Wrap(err, "failed to unmarshal health detail response")
+	}
+
+	return health, nil
+}
+
 func StatusWithUser(context *clusterd.Context, clusterInfo *ClusterInfo) (CephStatus, error) {
 	args := []string{"status", "--format", "json"}
 	command, args := FinalizeCephCommandArgs("ceph", clusterInfo, args, context.ConfigDir)
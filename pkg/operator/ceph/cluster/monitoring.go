@@ -93,6 +93,12 @@ func (c *ClusterController) configureCephMonitoring(cluster *cluster, clusterInf
 			logger.Errorf("failed to run bucket controller. %v", err)
 		}
 	}()
+	go func() {
+		err := bucketProvisioner.WatchClaimUpdates(c.context.KubeConfig, cluster.stopCh)
+		if err != nil {
+			logger.Errorf("failed to watch object bucket claim updates. %v", err)
+		}
+	}()
 
 	// enable the cluster watcher once
 	cluster.watchersActivated = true
@@ -20,6 +20,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -103,6 +104,10 @@ func fullDaemonName(daemonID string) string {
 }
 
 func (r *ReconcileCephRBDMirror) reconcileAddBoostrapPeer(cephRBDMirror *cephv1.CephRBDMirror, namespacedName types.NamespacedName) (reconcile.Result, error) {
+	if r.peers == nil {
+		r.peers = make(map[string]*peerSpec)
+	}
+
 	// List all the peers secret, we can have more than one peer we might want to configure
 	// For each, get the Kubernetes Secret and import the "peer token" so that we can configure the mirroring
 	for k, peerSecret := range cephRBDMirror.Spec.Peers.SecretNames {
@@ -148,6 +153,137 @@ func (r *ReconcileCephRBDMirror) reconcileAddBoostrapPeer(cephRBDMirror *cephv1.
 	return reconcile.Result{}, nil
 }
 
+// reconcileRemoveBootstrapPeer removes the peers whose secret is not listed in the spec anymore
+// The peers previously added are tracked in the status since the secret might be gone already
+func (r *ReconcileCephRBDMirror) reconcileRemoveBootstrapPeer(cephRBDMirror *cephv1.CephRBDMirror) error {
+	if cephRBDMirror.Status == nil {
+		return nil
+	}
+
+	for _, peer := range cephRBDMirror.Status.Peers {
+		if isPeerSecretInSpec(cephRBDMirror, peer.SecretName) {
+			continue
+		}
+
+		logger.Infof("bootstrap peer secret %q was removed from the spec, removing peer %q from pool %q", peer.SecretName, peer.UUID, peer.Pool)
+		if peer.UUID != "" {
+			poolMirrorInfo, err := client.GetPoolMirroringInfo(r.context, r.clusterInfo, peer.Pool)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get pool %q mirror information", peer.Pool)
+			}
+
+			// The peer might have been removed manually already
+			if findPeerByUUID(poolMirrorInfo, peer.UUID) != nil {
+				err = client.RemoveRBDMirrorPeer(r.context, r.clusterInfo, peer.Pool, peer.UUID)
+				if err != nil {
+					return errors.Wrapf(err, "failed to remove rbd-mirror peer for secret %q", peer.SecretName)
+				}
+			}
+
+			// Remove the peer config and key if they were created for that peer
+			err = r.deleteBootstrapPeerConfigAndKey(peer.UUID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to delete bootstrap peer %q config and key", peer.UUID)
+			}
+		}
+
+		delete(r.peers, peer.SecretName)
+	}
+
+	return nil
+}
+
+// peersStatus returns the status of the peers currently listed in the spec
+func (r *ReconcileCephRBDMirror) peersStatus(cephRBDMirror *cephv1.CephRBDMirror) []cephv1.RBDMirrorPeerStatus {
+	peers := []cephv1.RBDMirrorPeerStatus{}
+	for _, peerSecret := range cephRBDMirror.Spec.Peers.SecretNames {
+		peer, ok := r.peers[peerSecret]
+		if !ok {
+			continue
+		}
+		status := cephv1.RBDMirrorPeerStatus{SecretName: peerSecret, Pool: peer.poolName, Direction: peer.direction}
+		if peer.peer != nil {
+			status.UUID = peer.peer.UUID
+			status.SiteName = peer.peer.SiteName
+			status.Direction = peer.peer.Direction
+		}
+		peers = append(peers, status)
+	}
+
+	return peers
+}
+
+func isPeerSecretInSpec(cephRBDMirror *cephv1.CephRBDMirror, secretName string) bool {
+	for _, peerSecret := range cephRBDMirror.Spec.Peers.SecretNames {
+		if peerSecret == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+func findPeerByUUID(info *client.PoolMirroringInfo, uuid string) *client.PeersSpec {
+	for i := range info.Peers {
+		if info.Peers[i].UUID == uuid {
+			return &info.Peers[i]
+		}
+	}
+	return nil
+}
+
+// findImportedPeer finds the pool peer added by the import of a bootstrap peer token
+// The new peer is the one missing from the pool peers before the import. If the token was imported already,
+// the import updates the existing peer so it is looked up from the token instead.
+func findImportedPeer(previousInfo, info *client.PoolMirroringInfo, token *PeerToken) *client.PeersSpec {
+	for i := range info.Peers {
+		if findPeerByUUID(previousInfo, info.Peers[i].UUID) == nil {
+			return &info.Peers[i]
+		}
+	}
+
+	return findPeerByToken(info, token)
+}
+
+// findPeerByToken finds the pool peer matching a bootstrap peer token
+// Rook names the peer site after the FSID of the cluster, Ceph defaults to the FSID as well. Peers with another
+// site name are matched on the client of the token when no other peer uses the same client.
+func findPeerByToken(info *client.PoolMirroringInfo, token *PeerToken) *client.PeersSpec {
+	for i := range info.Peers {
+		if strings.HasPrefix(info.Peers[i].SiteName, token.ClusterFSID) {
+			return &info.Peers[i]
+		}
+	}
+
+	var peer *client.PeersSpec
+	clientName := fmt.Sprintf("client.%s", token.ClientID)
+	for i := range info.Peers {
+		if info.Peers[i].ClientName == clientName {
+			if peer != nil {
+				return nil
+			}
+			peer = &info.Peers[i]
+		}
+	}
+
+	return peer
+}
+
+func decodePeerToken(tokenBase64 string) (*PeerToken, error) {
+	// Decode the base64 token
+	decodeToken, err := base64.StdEncoding.DecodeString(tokenBase64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode bootstrap peer token")
+	}
+
+	// Unmarshal JSON into PeerToken struct
+	var token PeerToken
+	if err := json.Unmarshal([]byte(decodeToken), &token); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bootstrap peer token")
+	}
+
+	return &token, nil
+}
+
 func validatePeerToken(data map[string][]byte) (*peerSpec, error) {
 	if len(data) == 0 {
 		return nil, errors.Errorf("failed to lookup 'data' secret field (empty)")
@@ -166,8 +302,14 @@ func validatePeerToken(data map[string][]byte) (*peerSpec, error) {
 }
 
 func (r *ReconcileCephRBDMirror) addPeer(peerSecret string, data map[string][]byte, ownerRef *metav1.OwnerReference) error {
+	// List the pool peers before the import to find the one added from that token
+	previousPoolMirrorInfo, err := client.GetPoolMirroringInfo(r.context, r.clusterInfo, r.peers[peerSecret].poolName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pool mirror information")
+	}
+
 	// Import bootstrap peer
-	err := client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, r.peers[peerSecret].poolName, r.peers[peerSecret].direction, data["token"])
+	err = client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, r.peers[peerSecret].poolName, r.peers[peerSecret].direction, data["token"])
 	if err != nil {
 		return errors.Wrap(err, "failed to import bootstrap peer token")
 	}
@@ -179,6 +321,13 @@ func (r *ReconcileCephRBDMirror) addPeer(peerSecret string, data map[string][]by
 	}
 	r.peers[peerSecret].info = poolMirrorInfo
 
+	// Find which pool peer was added from that token so it can be removed later
+	token, err := decodePeerToken(string(data["token"]))
+	if err != nil {
+		return errors.Wrap(err, "failed to read bootstrap peer token")
+	}
+	r.peers[peerSecret].peer = findImportedPeer(previousPoolMirrorInfo, poolMirrorInfo, token)
+
 	return nil
 }
 
@@ -191,23 +340,22 @@ func validateSpec(r *cephv1.RBDMirroringSpec) error {
 }
 
 func (r *ReconcileCephRBDMirror) createBootstrapPeerConfigAndKey(peerSecret string, tokenBase64 string, ownerRef *metav1.OwnerReference) error {
-	// Decode the base64 token
-	decodeToken, err := base64.StdEncoding.DecodeString(tokenBase64)
+	token, err := decodePeerToken(tokenBase64)
 	if err != nil {
-		return errors.Wrap(err, "failed to decode bootstrap peer token")
+		return err
 	}
 
-	// Unmarshal JSON into PeerToken struct
-	var token PeerToken
-	if err := json.Unmarshal([]byte(decodeToken), &token); err != nil {
-		return errors.Wrap(err, "failed to unmarshal bootstrap peer token")
+	// The config and key are named after the peer imported from the token
+	peer := r.peers[peerSecret].peer
+	if peer == nil {
+		return errors.Errorf("failed to find the pool peer imported from secret %q", peerSecret)
 	}
 
 	// Build peer ceph conf
 	cephPeerConfig := fmt.Sprintf(cephConfPeerTemplate, token.ClusterFSID, token.MonHost)
 
 	// Put it in a ConfigMap
-	cm := generatePeerCephConfigFileConfigMap(r.peers[peerSecret].info.Peers[0].UUID, cephPeerConfig, ownerRef)
+	cm := generatePeerCephConfigFileConfigMap(peer.UUID, cephPeerConfig, ownerRef)
 
 	_, err = r.context.Clientset.CoreV1().ConfigMaps(r.clusterInfo.Namespace).Create(cm)
 	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsAlreadyExists(err) {
@@ -218,7 +366,7 @@ func (r *ReconcileCephRBDMirror) createBootstrapPeerConfigAndKey(peerSecret stri
 	cephPeerKey := fmt.Sprintf(keyringPeerTemplate, token.ClientID, token.Key)
 
 	// Put it in a Secret
-	s := generatePeerKeyringSecret(r.peers[peerSecret].info.Peers[0].UUID, cephPeerKey, ownerRef)
+	s := generatePeerKeyringSecret(peer.UUID, cephPeerKey, ownerRef)
 	_, err = r.context.Clientset.CoreV1().Secrets(r.clusterInfo.Namespace).Create(s)
 	if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsAlreadyExists(err) {
 		return errors.Wrapf(err, "failed to create kubernetes secret %q bootstrap peer key", s.Name)
@@ -227,6 +375,22 @@ func (r *ReconcileCephRBDMirror) createBootstrapPeerConfigAndKey(peerSecret stri
 	return nil
 }
 
+func (r *ReconcileCephRBDMirror) deleteBootstrapPeerConfigAndKey(peerSiteUUID string) error {
+	cmName := generatePeerCephConfigFileConfigMapName(peerSiteUUID)
+	err := r.context.Clientset.CoreV1().ConfigMaps(r.clusterInfo.Namespace).Delete(cmName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete kubernetes config map %q bootstrap peer config", cmName)
+	}
+
+	secretName := generatePeerKeyringSecretName(peerSiteUUID)
+	err = r.context.Clientset.CoreV1().Secrets(r.clusterInfo.Namespace).Delete(secretName, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete kubernetes secret %q bootstrap peer key", secretName)
+	}
+
+	return nil
+}
+
 func generatePeerCephConfigFileConfigMapName(siteName string) string {
 	return fmt.Sprintf("%s-%s", peerCephConfigFileConfigMapName, siteName)
 }
@@ -20,9 +20,16 @@ import (
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
 
+// peerToken is a bootstrap peer token from the cluster c6b087f2-7829-4dbb-bcfc-53dc34e0b35d
+const peerToken = `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==` //nolint:gosec // This is just a var name, not a real token
+
 func Test_validateSpec(t *testing.T) {
 	// Invalid count
 	r := &cephv1.RBDMirroringSpec{Count: 0}
@@ -71,3 +78,112 @@ func Test_validatePeerToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, got.poolName, "foo")
 }
+
+func Test_findPeerByToken(t *testing.T) {
+	info := &client.PoolMirroringInfo{Peers: []client.PeersSpec{
+		{UUID: "4a6983c0", SiteName: "c6b087f2-7829-4dbb-bcfc-53dc34e0b35d-rook-ceph", ClientName: "client.rbd-mirror-peer"},
+		{UUID: "9f2d8a11", SiteName: "site-b", ClientName: "client.rbd-mirror-peer"},
+	}}
+
+	// Match on the fsid
+	token, err := decodePeerToken(peerToken)
+	assert.NoError(t, err)
+	peer := findPeerByToken(info, token)
+	assert.NotNil(t, peer)
+	assert.Equal(t, "4a6983c0", peer.UUID)
+
+	// No match when several peers use the client of the token
+	info.Peers = info.Peers[1:]
+	info.Peers = append(info.Peers, client.PeersSpec{UUID: "1b3c5d7e", SiteName: "site-c", ClientName: "client.rbd-mirror-peer"})
+	assert.Nil(t, findPeerByToken(info, token))
+
+	// Match on the client of the token
+	info.Peers[1].ClientName = "client.site-c"
+	peer = findPeerByToken(info, token)
+	assert.NotNil(t, peer)
+	assert.Equal(t, "9f2d8a11", peer.UUID)
+}
+
+func Test_findImportedPeer(t *testing.T) {
+	token, err := decodePeerToken(peerToken)
+	assert.NoError(t, err)
+	previousInfo := &client.PoolMirroringInfo{Peers: []client.PeersSpec{
+		{UUID: "9f2d8a11", SiteName: "site-b", ClientName: "client.rbd-mirror-peer"},
+	}}
+	info := &client.PoolMirroringInfo{Peers: []client.PeersSpec{
+		{UUID: "9f2d8a11", SiteName: "site-b", ClientName: "client.rbd-mirror-peer"},
+		{UUID: "1b3c5d7e", SiteName: "site-c", ClientName: "client.rbd-mirror-peer"},
+	}}
+
+	// The new peer is the one added by the import, even if its site name is not the fsid
+	peer := findImportedPeer(previousInfo, info, token)
+	assert.NotNil(t, peer)
+	assert.Equal(t, "1b3c5d7e", peer.UUID)
+
+	// The token was imported already and the peers cannot be told apart
+	assert.Nil(t, findImportedPeer(info, info, token))
+}
+
+func Test_addPeer(t *testing.T) {
+	imported := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+				if imported {
+					return `{"mode":"image","site_name":"local","peers":[{"uuid":"9f2d8a11","direction":"rx-tx","site_name":"site-b","mirror_uuid":"","client_name":"client.rbd-mirror-peer"},{"uuid":"1b3c5d7e","direction":"rx-tx","site_name":"site-c","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`, nil
+				}
+				return `{"mode":"image","site_name":"local","peers":[{"uuid":"9f2d8a11","direction":"rx-tx","site_name":"site-b","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`, nil
+			}
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" && args[4] == "import" {
+				imported = true
+				return "", nil
+			}
+			return "", nil
+		},
+	}
+	r := &ReconcileCephRBDMirror{
+		context:     &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)},
+		clusterInfo: client.AdminClusterInfo("rook-ceph"),
+		peers:       map[string]*peerSpec{"foo": {poolName: "pool"}},
+	}
+
+	err := r.addPeer("foo", map[string][]byte{"token": []byte(peerToken), "pool": []byte("pool")}, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, r.peers["foo"].peer)
+	assert.Equal(t, "1b3c5d7e", r.peers["foo"].peer.UUID)
+}
+
+func Test_reconcileRemoveBootstrapPeer(t *testing.T) {
+	removed := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+				return `{"mode":"image","site_name":"local","peers":[{"uuid":"4a6983c0","direction":"rx-tx","site_name":"ocs","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`, nil
+			}
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "remove" {
+				removed = args[5]
+				return "", nil
+			}
+			return "", nil
+		},
+	}
+	r := &ReconcileCephRBDMirror{
+		context:     &clusterd.Context{Executor: executor, Clientset: test.New(t, 1)},
+		clusterInfo: client.AdminClusterInfo("rook-ceph"),
+		peers:       map[string]*peerSpec{"foo": {poolName: "pool"}, "bar": {poolName: "pool"}},
+	}
+	rbdMirror := &cephv1.CephRBDMirror{
+		Spec: cephv1.RBDMirroringSpec{Peers: cephv1.RBDMirroringPeerSpec{SecretNames: []string{"foo"}}},
+		Status: &cephv1.RBDMirrorStatus{Peers: []cephv1.RBDMirrorPeerStatus{
+			{SecretName: "foo", Pool: "pool", UUID: "1b3c5d7e"},
+			{SecretName: "bar", Pool: "pool", UUID: "4a6983c0"},
+		}},
+	}
+
+	// The peer of the "bar" secret is removed
+	err := r.reconcileRemoveBootstrapPeer(rbdMirror)
+	assert.NoError(t, err)
+	assert.Equal(t, "4a6983c0", removed)
+	assert.Equal(t, 1, len(r.peers))
+	assert.Equal(t, 1, len(r.peersStatus(rbdMirror)))
+}
@@ -74,11 +74,14 @@ type ReconcileCephRBDMirror struct {
 	scheme          *runtime.Scheme
 	cephClusterSpec *cephv1.ClusterSpec
 	peers           map[string]*peerSpec
+	// mirrorCheckers holds the stop channel of the status checker of each CephRBDMirror
+	mirrorCheckers map[string]chan struct{}
 }
 
 // peerSpec represents peer details
 type peerSpec struct {
 	info      *cephclient.PoolMirroringInfo
+	peer      *cephclient.PeersSpec
 	poolName  string
 	direction string
 }
@@ -97,10 +100,11 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Rec
 		panic(err)
 	}
 	return &ReconcileCephRBDMirror{
-		client:  mgr.GetClient(),
-		scheme:  mgrScheme,
-		context: context,
-		peers:   make(map[string]*peerSpec),
+		client:         mgr.GetClient(),
+		scheme:         mgrScheme,
+		context:        context,
+		peers:          make(map[string]*peerSpec),
+		mirrorCheckers: make(map[string]chan struct{}),
 	}
 }
 
@@ -153,6 +157,7 @@ func (r *ReconcileCephRBDMirror) reconcile(request reconcile.Request) (reconcile
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("cephRBDMirror resource not found. Ignoring since object must be deleted.")
+			r.stopMirrorChecker(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -199,6 +204,14 @@ func (r *ReconcileCephRBDMirror) reconcile(request reconcile.Request) (reconcile
 		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to add ceph rbd mirror peer")
 	}
 
+	// Remove the peers that are not listed anymore
+	logger.Debug("reconciling ceph rbd mirror peers removal")
+	err = r.reconcileRemoveBootstrapPeer(cephRBDMirror)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove ceph rbd mirror peer")
+	}
+	updateStatusPeers(r.client, request.NamespacedName, r.peersStatus(cephRBDMirror))
+
 	// CREATE/UPDATE
 	logger.Debug("reconciling ceph rbd mirror deployments")
 	reconcileResponse, err = r.reconcileCreateCephRBDMirror(cephRBDMirror)
@@ -206,6 +219,13 @@ func (r *ReconcileCephRBDMirror) reconcile(request reconcile.Request) (reconcile
 		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to create ceph rbd mirror deployments")
 	}
 
+	// Run the goroutine to update the daemons and peers status
+	if !cephRBDMirror.Spec.StatusCheck.Mirror.Disabled {
+		r.startMirrorChecker(request.NamespacedName, &cephRBDMirror.Spec.StatusCheck)
+	} else {
+		r.stopMirrorChecker(request.NamespacedName)
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
@@ -247,7 +267,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 
 	if rbdMirror.Status == nil {
-		rbdMirror.Status = &cephv1.Status{}
+		rbdMirror.Status = &cephv1.RBDMirrorStatus{}
 	}
 
 	rbdMirror.Status.Phase = status
@@ -257,3 +277,60 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 	logger.Debugf("rbd mirror %q status updated to %q", name, status)
 }
+
+// updateStatusPeers updates an object with the list of configured peers
+func updateStatusPeers(client client.Client, name types.NamespacedName, peers []cephv1.RBDMirrorPeerStatus) {
+	rbdMirror := &cephv1.CephRBDMirror{}
+	err := client.Get(context.TODO(), name, rbdMirror)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephRBDMirror resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve rbd mirror %q to update peers status. %v", name, err)
+		return
+	}
+
+	if rbdMirror.Status == nil {
+		rbdMirror.Status = &cephv1.RBDMirrorStatus{}
+	}
+
+	// Keep the health reported by the status checker for the peers that did not change
+	for i := range peers {
+		for _, current := range rbdMirror.Status.Peers {
+			if current.SecretName == peers[i].SecretName && current.UUID == peers[i].UUID {
+				peers[i].LastSync = current.LastSync
+				peers[i].Health = current.Health
+			}
+		}
+	}
+
+	rbdMirror.Status.Peers = peers
+	if err := opcontroller.UpdateStatus(client, rbdMirror); err != nil {
+		logger.Errorf("failed to set rbd mirror %q peers status. %v", rbdMirror.Name, err)
+		return
+	}
+	logger.Debugf("rbd mirror %q peers status updated", name)
+}
+
+func (r *ReconcileCephRBDMirror) startMirrorChecker(name types.NamespacedName, healthCheckSpec *cephv1.MirrorHealthCheckSpec) {
+	if r.mirrorCheckers == nil {
+		r.mirrorCheckers = make(map[string]chan struct{})
+	}
+	if _, ok := r.mirrorCheckers[name.String()]; ok {
+		logger.Debugf("rbd mirror %q status checker already running", name)
+		return
+	}
+
+	stopCh := make(chan struct{})
+	r.mirrorCheckers[name.String()] = stopCh
+	checker := newMirrorChecker(r.context, r.client, r.clusterInfo, name, healthCheckSpec)
+	go checker.checkMirroring(stopCh)
+}
+
+func (r *ReconcileCephRBDMirror) stopMirrorChecker(name types.NamespacedName) {
+	if stopCh, ok := r.mirrorCheckers[name.String()]; ok {
+		close(stopCh)
+		delete(r.mirrorCheckers, name.String())
+	}
+}
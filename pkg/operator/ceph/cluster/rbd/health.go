@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultHealthCheckInterval = 1 * time.Minute
+	healthOK                   = "OK"
+	healthWarning              = "WARNING"
+	healthError                = "ERROR"
+	healthUnknown              = "UNKNOWN"
+)
+
+type mirrorChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+}
+
+// newMirrorChecker creates a new HealthChecker object
+func newMirrorChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, healthCheckSpec *cephv1.MirrorHealthCheckSpec) *mirrorChecker {
+	c := &mirrorChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		client:         client,
+	}
+
+	// allow overriding the check interval
+	checkInterval := healthCheckSpec.Mirror.Interval
+	if checkInterval != "" {
+		if duration, err := time.ParseDuration(checkInterval); err == nil {
+			logger.Infof("rbd mirror status check interval for %q is %q", namespacedName.Name, checkInterval)
+			c.interval = duration
+		}
+	}
+
+	return c
+}
+
+// checkMirroring periodically checks the health of the rbd mirror daemons and their peers
+func (c *mirrorChecker) checkMirroring(stopCh chan struct{}) {
+	// check the mirroring health immediately before starting the loop
+	if err := c.checkMirroringHealth(); err != nil {
+		logger.Debugf("failed to check rbd mirror %q status. %v", c.namespacedName.Name, err)
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			logger.Infof("stopping monitoring rbd mirror %q status", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking rbd mirror %q status", c.namespacedName.Name)
+			if err := c.checkMirroringHealth(); err != nil {
+				logger.Debugf("failed to check rbd mirror %q status. %v", c.namespacedName.Name, err)
+			}
+		}
+	}
+}
+
+func (c *mirrorChecker) checkMirroringHealth() error {
+	rbdMirror := &cephv1.CephRBDMirror{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, rbdMirror); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve rbd mirror %q", c.namespacedName.Name)
+	}
+	if rbdMirror.Status == nil || len(rbdMirror.Status.Peers) == 0 {
+		return nil
+	}
+
+	// Several peers can be configured on the same pool, only fetch the pool status once
+	poolsStatus := make(map[string]*cephclient.PoolMirroringStatus)
+	poolsInfo := make(map[string]*cephclient.PoolMirroringInfo)
+	for _, peer := range rbdMirror.Status.Peers {
+		if _, ok := poolsStatus[peer.Pool]; ok {
+			continue
+		}
+		status, err := cephclient.GetPoolMirroringImagesStatus(c.context, c.clusterInfo, peer.Pool)
+		if err != nil {
+			c.updateStatusMirroring(nil, nil, err.Error())
+			return err
+		}
+		info, err := cephclient.GetPoolMirroringInfo(c.context, c.clusterInfo, peer.Pool)
+		if err != nil {
+			c.updateStatusMirroring(nil, nil, err.Error())
+			return err
+		}
+		poolsStatus[peer.Pool] = status
+		poolsInfo[peer.Pool] = info
+	}
+
+	c.updateStatusMirroring(poolsStatus, poolsInfo, "")
+	return nil
+}
+
+// updateStatusMirroring updates the rbd mirror daemons and peers status
+func (c *mirrorChecker) updateStatusMirroring(poolsStatus map[string]*cephclient.PoolMirroringStatus, poolsInfo map[string]*cephclient.PoolMirroringInfo, details string) {
+	rbdMirror := &cephv1.CephRBDMirror{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, rbdMirror); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephRBDMirror resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve rbd mirror %q to update mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if rbdMirror.Status == nil {
+		rbdMirror.Status = &cephv1.RBDMirrorStatus{}
+	}
+
+	rbdMirror.Status.Details = details
+	// poolsStatus will be nil in case of an error to fetch it, keep the last known state
+	if poolsStatus != nil {
+		rbdMirror.Status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+		rbdMirror.Status.DaemonHealth, rbdMirror.Status.Daemons = daemonsStatus(poolsStatus)
+		for i := range rbdMirror.Status.Peers {
+			peerStatus(&rbdMirror.Status.Peers[i], poolsStatus[rbdMirror.Status.Peers[i].Pool], poolsInfo[rbdMirror.Status.Peers[i].Pool])
+		}
+	}
+
+	if err := opcontroller.UpdateStatus(c.client, rbdMirror); err != nil {
+		logger.Errorf("failed to set rbd mirror %q mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("rbd mirror %q mirroring status updated", c.namespacedName.Name)
+}
+
+// daemonsStatus returns the worst daemon health reported by the pools and the list of daemons
+func daemonsStatus(poolsStatus map[string]*cephclient.PoolMirroringStatus) (string, []cephv1.RBDMirrorDaemonStatus) {
+	health := ""
+	daemons := []cephv1.RBDMirrorDaemonStatus{}
+	seen := make(map[string]bool)
+	for _, status := range poolsStatus {
+		health = worstHealth(health, status.Summary.DaemonHealth)
+		for _, d := range status.Daemons {
+			if seen[d.ServiceID] {
+				continue
+			}
+			seen[d.ServiceID] = true
+			daemons = append(daemons, cephv1.RBDMirrorDaemonStatus{ServiceID: d.ServiceID, Hostname: d.Hostname, Leader: d.Leader, Health: d.Health})
+		}
+	}
+
+	return health, daemons
+}
+
+// peerStatus fills the peer status from the state of the pool images on the peer site
+func peerStatus(peer *cephv1.RBDMirrorPeerStatus, status *cephclient.PoolMirroringStatus, info *cephclient.PoolMirroringInfo) {
+	if status == nil || info == nil {
+		return
+	}
+
+	if p := findPeerByUUID(info, peer.UUID); p != nil {
+		peer.SiteName = p.SiteName
+		peer.Direction = p.Direction
+	} else {
+		peer.Health = healthUnknown
+		return
+	}
+
+	health := healthOK
+	for _, image := range status.Images {
+		for _, site := range image.PeerSites {
+			if site.SiteName != peer.SiteName {
+				continue
+			}
+			if site.LastUpdate > peer.LastSync {
+				peer.LastSync = site.LastUpdate
+			}
+			health = worstHealth(health, imageStateHealth(site.State))
+		}
+	}
+	peer.Health = health
+}
+
+// imageStateHealth converts an image mirroring state into a health
+func imageStateHealth(state string) string {
+	switch {
+	case strings.Contains(state, "error"):
+		return healthError
+	case strings.HasPrefix(state, "down"), strings.Contains(state, "unknown"):
+		return healthWarning
+	default:
+		return healthOK
+	}
+}
+
+func worstHealth(a, b string) string {
+	rank := map[string]int{"": 0, healthOK: 1, healthUnknown: 2, healthWarning: 3, healthError: 4}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
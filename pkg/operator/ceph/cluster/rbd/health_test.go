@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbd
+
+import (
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	poolImagesStatus = `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"stopped":2}},"daemons":[{"service_id":"4152","instance_id":"4154","client_id":"a","hostname":"node-1","version":"15.2.4","leader":true,"health":"OK"}],"images":[{"name":"image-1","global_id":"3f6b8a1e","state":"up+stopped","description":"local image is primary","last_update":"2020-09-21 09:00:00","peer_sites":[{"site_name":"site-b","state":"up+replaying","description":"replaying","last_update":"2020-09-21 09:01:00"}]},{"name":"image-2","global_id":"9c2d1f0b","state":"up+stopped","description":"local image is primary","last_update":"2020-09-21 09:00:00","peer_sites":[{"site_name":"site-b","state":"down+unknown","description":"status not found","last_update":"2020-09-21 08:00:00"}]}]}`
+	poolInfo         = `{"mode":"image","site_name":"site-a","peers":[{"uuid":"4a6983c0","direction":"rx-tx","site_name":"site-b","mirror_uuid":"","client_name":"client.rbd-mirror-peer"}]}`
+)
+
+func TestPeerStatus(t *testing.T) {
+	var status cephclient.PoolMirroringStatus
+	assert.NoError(t, json.Unmarshal([]byte(poolImagesStatus), &status))
+	var info cephclient.PoolMirroringInfo
+	assert.NoError(t, json.Unmarshal([]byte(poolInfo), &info))
+
+	peer := &cephv1.RBDMirrorPeerStatus{SecretName: "foo", Pool: "pool", UUID: "4a6983c0"}
+	peerStatus(peer, &status, &info)
+	assert.Equal(t, "site-b", peer.SiteName)
+	assert.Equal(t, "rx-tx", peer.Direction)
+	assert.Equal(t, "2020-09-21 09:01:00", peer.LastSync)
+	assert.Equal(t, healthWarning, peer.Health)
+
+	// the peer is gone
+	peer = &cephv1.RBDMirrorPeerStatus{SecretName: "foo", Pool: "pool", UUID: "1b3c5d7e"}
+	peerStatus(peer, &status, &info)
+	assert.Equal(t, healthUnknown, peer.Health)
+
+	health, daemons := daemonsStatus(map[string]*cephclient.PoolMirroringStatus{"pool": &status})
+	assert.Equal(t, "OK", health)
+	assert.Equal(t, 1, len(daemons))
+	assert.True(t, daemons[0].Leader)
+}
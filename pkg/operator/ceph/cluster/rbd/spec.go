@@ -19,6 +19,8 @@ package rbd
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	"github.com/rook/rook/pkg/operator/ceph/config"
@@ -67,8 +69,14 @@ func (r *ReconcileCephRBDMirror) makeDeployment(daemonConfig *daemonConfig, rbdM
 	if rbdMirror.Spec.Peers.HasPeers() {
 		// Add the config map and secret
 		// We only use the first peer in the list because peers are all the same, just the pool differs
+		// The config map and the secret are named after the peer imported from the token, the pool may have other peers
 		firstPeer := rbdMirror.Spec.Peers.SecretNames[0]
-		volProjection := peerConfigMapAndSecretVolumeAndMount(r.peers[firstPeer].info.Peers[0].SiteName, r.peers[firstPeer].info.Peers[0].ClientName, r.peers[firstPeer].info.Peers[0].UUID)
+		peerSpec, ok := r.peers[firstPeer]
+		if !ok || peerSpec.peer == nil {
+			return nil, errors.Errorf("failed to find the pool peer imported from secret %q", firstPeer)
+		}
+		peer := peerSpec.peer
+		volProjection := peerConfigMapAndSecretVolumeAndMount(peer.SiteName, peer.ClientName, peer.UUID)
 		podSpec.Spec.Volumes[0].VolumeSource.Projected.Sources = append(podSpec.Spec.Volumes[0].VolumeSource.Projected.Sources, volProjection...)
 	}
 
@@ -103,10 +103,26 @@ func TestPodSpec(t *testing.T) {
 	rbdMirror.Spec.Peers.SecretNames = append(rbdMirror.Spec.Peers.SecretNames, "foo")
 	p := cephclient.PeersSpec{UUID: "c9838c14-d9a1-4e69-b51e-09ff0a4d617c", SiteName: "foo", ClientName: "client.rbd-mirror-peer"}
 	r.peers["foo"] = &peerSpec{poolName: "foo", info: &cephclient.PoolMirroringInfo{Peers: []cephclient.PeersSpec{p}}}
+
+	// The peer imported from the secret is not known
+	_, err = r.makeDeployment(&daemonConf, rbdMirror)
+	assert.Error(t, err)
+
+	r.peers["foo"].peer = &p
 	d, err = r.makeDeployment(&daemonConf, rbdMirror)
 	assert.NoError(t, err)
 	// We now have the volume for the ConfigMap and the Secret
 	assert.Equal(t, 4, len(d.Spec.Template.Spec.Volumes))
 	assert.Equal(t, 3, len(d.Spec.Template.Spec.Volumes[0].Projected.Sources))
 	assert.Equal(t, 4, len(d.Spec.Template.Spec.Containers[0].VolumeMounts))
+
+	// The pool has another peer before the imported one
+	other := cephclient.PeersSpec{UUID: "0b1a6b3e-5a44-4c5f-9d2a-1f1e5d6e7a8b", SiteName: "bar", ClientName: "client.rbd-mirror-peer"}
+	r.peers["foo"].info.Peers = []cephclient.PeersSpec{other, p}
+	d, err = r.makeDeployment(&daemonConf, rbdMirror)
+	assert.NoError(t, err)
+	sources := d.Spec.Template.Spec.Volumes[0].Projected.Sources
+	assert.Equal(t, 3, len(sources))
+	assert.Equal(t, generatePeerCephConfigFileConfigMapName(p.UUID), sources[1].ConfigMap.Name)
+	assert.Equal(t, generatePeerKeyringSecretName(p.UUID), sources[2].Secret.Name)
 }
@@ -27,9 +27,14 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/disruption/machinelabel"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/nodedrain"
 	"github.com/rook/rook/pkg/operator/ceph/file"
+	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
+	"github.com/rook/rook/pkg/operator/ceph/file/subvolumegroup"
 	"github.com/rook/rook/pkg/operator/ceph/nfs"
+	nfsexport "github.com/rook/rook/pkg/operator/ceph/nfs/export"
 	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/ceph/object/notification"
 	"github.com/rook/rook/pkg/operator/ceph/object/realm"
+	"github.com/rook/rook/pkg/operator/ceph/object/topic"
 	objectuser "github.com/rook/rook/pkg/operator/ceph/object/user"
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
@@ -66,7 +71,12 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context) error{
 	object.Add,
 	file.Add,
 	nfs.Add,
+	nfsexport.Add,
 	rbd.Add,
+	mirror.Add,
+	subvolumegroup.Add,
+	topic.Add,
+	notification.Add,
 }
 
 // AddToManager adds all the registered controllers to the passed manager.
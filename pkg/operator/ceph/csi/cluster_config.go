@@ -38,6 +38,14 @@ var (
 type csiClusterConfigEntry struct {
 	ClusterID string   `json:"clusterID"`
 	Monitors  []string `json:"monitors"`
+	// Namespace is the namespace of the CephCluster, only set on the entries of subvolume groups
+	// so that their monitors are updated with the ones of the cluster
+	Namespace string         `json:"namespace,omitempty"`
+	CephFS    *csiCephFSSpec `json:"cephFS,omitempty"`
+}
+
+type csiCephFSSpec struct {
+	SubvolumeGroup string `json:"subvolumeGroup,omitempty"`
 }
 
 type csiClusterConfig []csiClusterConfigEntry
@@ -114,9 +122,61 @@ func UpdateCsiClusterConfig(
 		centry.Monitors = monEndpoints(mons)
 		cc = append(cc, centry)
 	}
+	// the subvolume groups of the cluster use the same monitors
+	for i := range cc {
+		if cc[i].Namespace == clusterKey {
+			cc[i].Monitors = monEndpoints(mons)
+		}
+	}
 	return formatCsiClusterConfig(cc)
 }
 
+// updateCsiSubVolumeGroupConfig adds or updates the entry of a subvolume group of the cluster running
+// in clusterNamespace. The clusterID is the one referenced by the StorageClasses of the group.
+func updateCsiSubVolumeGroupConfig(
+	curr, clusterID, clusterNamespace, subvolumeGroup string, mons map[string]*cephclient.MonInfo) (string, error) {
+
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+
+	entry := csiClusterConfigEntry{
+		ClusterID: clusterID,
+		Monitors:  monEndpoints(mons),
+		Namespace: clusterNamespace,
+		CephFS:    &csiCephFSSpec{SubvolumeGroup: subvolumeGroup},
+	}
+	found := false
+	for i := range cc {
+		if cc[i].ClusterID == clusterID {
+			cc[i] = entry
+			found = true
+			break
+		}
+	}
+	if !found {
+		cc = append(cc, entry)
+	}
+	return formatCsiClusterConfig(cc)
+}
+
+// removeCsiClusterConfig removes the entry of the given clusterID
+func removeCsiClusterConfig(curr, clusterID string) (string, error) {
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+
+	newCC := csiClusterConfig{}
+	for _, centry := range cc {
+		if centry.ClusterID != clusterID {
+			newCC = append(newCC, centry)
+		}
+	}
+	return formatCsiClusterConfig(newCC)
+}
+
 // CreateCsiConfigMap creates an empty config map that will be later used
 // to provide cluster configuration to ceph-csi. If a config map already
 // exists, it will return it.
@@ -154,6 +214,32 @@ func SaveClusterConfig(
 	clientset kubernetes.Interface, clusterNamespace string,
 	clusterInfo *cephclient.ClusterInfo, l sync.Locker) error {
 
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return UpdateCsiClusterConfig(currData, clusterNamespace, clusterInfo.Monitors)
+	})
+}
+
+// SaveSubVolumeGroupConfig adds the entry of a subvolume group to the config map used to
+// provide ceph-csi with the cluster configuration. The clusterID value is the one to set in
+// the storage classes provisioning volumes in the subvolume group.
+func SaveSubVolumeGroupConfig(
+	clientset kubernetes.Interface, clusterNamespace, clusterID, subvolumeGroup string,
+	clusterInfo *cephclient.ClusterInfo, l sync.Locker) error {
+
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return updateCsiSubVolumeGroupConfig(currData, clusterID, clusterNamespace, subvolumeGroup, clusterInfo.Monitors)
+	})
+}
+
+// RemoveClusterConfig removes the entry of the given clusterID from the config map used to
+// provide ceph-csi with the cluster configuration.
+func RemoveClusterConfig(clientset kubernetes.Interface, clusterID string, l sync.Locker) error {
+	return updateCsiConfigMap(clientset, l, func(currData string) (string, error) {
+		return removeCsiClusterConfig(currData, clusterID)
+	})
+}
+
+func updateCsiConfigMap(clientset kubernetes.Interface, l sync.Locker, update func(currData string) (string, error)) error {
 	if !CSIEnabled() {
 		return nil
 	}
@@ -178,8 +264,7 @@ func SaveClusterConfig(
 	if currData == "" {
 		currData = "[]"
 	}
-	newData, err := UpdateCsiClusterConfig(
-		currData, clusterNamespace, clusterInfo.Monitors)
+	newData, err := update(currData)
 	if err != nil {
 		return errors.Wrap(err, "failed to update csi config map data")
 	}
@@ -87,3 +87,45 @@ func TestUpdateCsiClusterConfig(t *testing.T) {
 	_, err = UpdateCsiClusterConfig("qqq", "beta", mons2)
 	assert.Error(t, err)
 }
+
+func TestUpdateCsiSubVolumeGroupConfig(t *testing.T) {
+	mons := map[string]*cephclient.MonInfo{
+		"foo": {Name: "foo", Endpoint: "1.2.3.4:5000"},
+	}
+	s, err := UpdateCsiClusterConfig("[]", "alpha", mons)
+	assert.NoError(t, err)
+
+	// add a subvolume group of the cluster
+	s, err = updateCsiSubVolumeGroupConfig(s, "abcdef", "alpha", "team-a", mons)
+	assert.NoError(t, err)
+	cc, err := parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cc))
+	assert.Equal(t, "abcdef", cc[1].ClusterID)
+	assert.Equal(t, "alpha", cc[1].Namespace)
+	assert.Equal(t, "team-a", cc[1].CephFS.SubvolumeGroup)
+	assert.Equal(t, []string{"1.2.3.4:5000"}, cc[1].Monitors)
+
+	// the monitors of the subvolume group follow the ones of the cluster
+	delete(mons, "foo")
+	mons["bar"] = &cephclient.MonInfo{Name: "bar", Endpoint: "10.11.12.13:5000"}
+	s, err = UpdateCsiClusterConfig(s, "alpha", mons)
+	assert.NoError(t, err)
+	cc, err = parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.11.12.13:5000"}, cc[0].Monitors)
+	assert.Equal(t, []string{"10.11.12.13:5000"}, cc[1].Monitors)
+
+	// updating the group doesn't add a new entry
+	s, err = updateCsiSubVolumeGroupConfig(s, "abcdef", "alpha", "team-b", mons)
+	assert.NoError(t, err)
+	cc, err = parseCsiClusterConfig(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(cc))
+	assert.Equal(t, "team-b", cc[1].CephFS.SubvolumeGroup)
+
+	// remove the group
+	s, err = removeCsiClusterConfig(s, "abcdef")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"clusterID":"alpha","monitors":["10.11.12.13:5000"]}]`, s)
+}
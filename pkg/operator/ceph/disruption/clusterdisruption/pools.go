@@ -59,7 +59,9 @@ func (r *ReconcileClusterDisruption) processPools(request reconcile.Request) (*c
 	poolCount += len(cephFilesystemList.Items)
 	for _, cephFilesystem := range cephFilesystemList.Items {
 		poolSpecs = append(poolSpecs, cephFilesystem.Spec.MetadataPool)
-		poolSpecs = append(poolSpecs, cephFilesystem.Spec.DataPools...)
+		for _, dataPool := range cephFilesystem.Spec.DataPools {
+			poolSpecs = append(poolSpecs, dataPool.PoolSpec)
+		}
 
 	}
 
@@ -157,6 +159,9 @@ func (r *ReconcileClusterDisruption) reconcileCephObjectStore(cephObjectStoreLis
 		}
 
 		rgwCount := objectStore.Spec.Gateway.Instances
+		if objectStore.Spec.Gateway.Autoscaling != nil {
+			rgwCount = objectStore.Spec.Gateway.Autoscaling.MinInstances
+		}
 		minAvailable := &intstr.IntOrString{IntVal: rgwCount - 1}
 		if minAvailable.IntVal <= 1 {
 			break
@@ -202,7 +207,7 @@ func (r *ReconcileClusterDisruption) reconcileCephFilesystem(cephFilesystemList
 			MatchLabels: map[string]string{"rook_file_system": fsName},
 		}
 
-		activeCount := filesystem.Spec.MetadataServer.ActiveCount
+		activeCount := filesystem.DesiredActiveCount()
 		minAvailable := &intstr.IntOrString{IntVal: activeCount - 1}
 		if filesystem.Spec.MetadataServer.ActiveStandby {
 			minAvailable.IntVal++
@@ -21,6 +21,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
@@ -48,6 +50,8 @@ import (
 
 const (
 	controllerName = "ceph-file-controller"
+	// dataPoolRemovalRetryInterval is how often the removal of data pools still in use is retried
+	dataPoolRemovalRetryInterval = time.Minute
 )
 
 var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
@@ -73,6 +77,8 @@ type ReconcileCephFilesystem struct {
 	context         *clusterd.Context
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephclient.ClusterInfo
+	// statusCheckers holds the running status checker of each CephFilesystem
+	statusCheckers map[string]*statusChecker
 }
 
 // Add creates a new CephFilesystem Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -89,9 +95,10 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Rec
 		panic(err)
 	}
 	return &ReconcileCephFilesystem{
-		client:  mgr.GetClient(),
-		scheme:  mgrScheme,
-		context: context,
+		client:         mgr.GetClient(),
+		scheme:         mgrScheme,
+		context:        context,
+		statusCheckers: make(map[string]*statusChecker),
 	}
 }
 
@@ -144,6 +151,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("cephFilesystem resource not found. Ignoring since object must be deleted.")
+			r.stopStatusChecker(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -202,6 +210,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 	// DELETE: the CR was deleted
 	if !cephFilesystem.GetDeletionTimestamp().IsZero() {
 		logger.Debugf("deleting filesystem %q", cephFilesystem.Name)
+		r.stopStatusChecker(request.NamespacedName)
 		err = r.reconcileDeleteFilesystem(cephFilesystem)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrapf(err, "failed to delete filesystem %q. ", cephFilesystem.Name)
@@ -230,9 +239,43 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 		return reconcileResponse, err
 	}
 
+	// Remove the data pools that are not in the spec anymore
+	logger.Debug("reconciling ceph filesystem data pools removal")
+	dataPools, blockedPools, err := removeDataPools(r.context, r.clusterInfo, *cephFilesystem)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to remove data pools of filesystem %q", cephFilesystem.Name)
+	}
+	if err := updateStatusDataPoolRemoval(r.client, request.NamespacedName, dataPools, blockedPools); err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+
+	// Configure the snapshot mirroring of the filesystem
+	logger.Debug("reconciling ceph filesystem mirroring")
+	if err := r.reconcileMirroring(cephFilesystem, request.NamespacedName); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to configure mirroring for filesystem %q", cephFilesystem.Name)
+	}
+
+	// Configure the snapshot schedules of the filesystem
+	logger.Debug("reconciling ceph filesystem snapshot schedules")
+	if err := r.reconcileSnapshotSchedules(cephFilesystem, request.NamespacedName); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to configure snapshot schedules for filesystem %q", cephFilesystem.Name)
+	}
+
+	// Run the goroutine to update the mds, mirroring and snapshot schedules status and to autoscale the mds ranks
+	r.startStatusChecker(request.NamespacedName, cephFilesystem.Spec)
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
+	// Check again later if the removed data pools are still in use
+	if len(blockedPools) > 0 {
+		logger.Debug("done reconciling, data pools removal blocked")
+		return reconcile.Result{Requeue: true, RequeueAfter: dataPoolRemovalRetryInterval}, nil
+	}
+
 	// Return and do not requeue
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, nil
@@ -278,6 +321,33 @@ func (r *ReconcileCephFilesystem) reconcileDeleteFilesystem(cephFilesystem *ceph
 	return nil
 }
 
+// startStatusChecker starts the status checker of the filesystem, the checker is restarted when its intervals change.
+// The other settings of the checks are read from the filesystem at each check.
+func (r *ReconcileCephFilesystem) startStatusChecker(name types.NamespacedName, spec cephv1.FilesystemSpec) {
+	if r.statusCheckers == nil {
+		r.statusCheckers = make(map[string]*statusChecker)
+	}
+	checker := newStatusChecker(r.context, r.client, r.clusterInfo, name, spec)
+	if running, ok := r.statusCheckers[name.String()]; ok {
+		if running.interval == checker.interval && running.mdsInterval == checker.mdsInterval {
+			logger.Debugf("filesystem %q status checker already running", name)
+			return
+		}
+		logger.Infof("restarting filesystem %q status checker with the new check intervals", name)
+		close(running.stopCh)
+	}
+
+	r.statusCheckers[name.String()] = checker
+	go checker.checkStatus()
+}
+
+func (r *ReconcileCephFilesystem) stopStatusChecker(name types.NamespacedName) {
+	if checker, ok := r.statusCheckers[name.String()]; ok {
+		close(checker.stopCh)
+		delete(r.statusCheckers, name.String())
+	}
+}
+
 // updateStatus updates an object with a given status
 func updateStatus(client client.Client, name types.NamespacedName, status string) {
 	fs := &cephv1.CephFilesystem{}
@@ -292,7 +362,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 
 	if fs.Status == nil {
-		fs.Status = &cephv1.Status{}
+		fs.Status = &cephv1.CephFilesystemStatus{}
 	}
 
 	fs.Status.Phase = status
@@ -302,3 +372,64 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 	logger.Debugf("filesystem %q status updated to %q", name, status)
 }
+
+// updateStatusDataPoolRemoval records the data pools created for the filesystem and sets the condition reporting the
+// data pools that cannot be removed from the filesystem. The pools are removed by name, so a failure to record them
+// is returned.
+func updateStatusDataPoolRemoval(client client.Client, name types.NamespacedName, dataPools, blocked []string) error {
+	fs := &cephv1.CephFilesystem{}
+	err := client.Get(context.TODO(), name, fs)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve filesystem %q to update data pools removal status", name)
+	}
+
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	fs.Status.DataPools = dataPools
+
+	var existing *cephv1.Condition
+	for i := range fs.Status.Conditions {
+		if fs.Status.Conditions[i].Type == cephv1.ConditionDataPoolRemovalBlocked {
+			existing = &fs.Status.Conditions[i]
+		}
+	}
+
+	newCondition := cephv1.Condition{
+		Type:    cephv1.ConditionDataPoolRemovalBlocked,
+		Status:  corev1.ConditionFalse,
+		Reason:  "DataPoolsRemoved",
+		Message: "all the data pools removed from the spec were removed from the filesystem",
+	}
+	if len(blocked) > 0 {
+		newCondition.Status = corev1.ConditionTrue
+		newCondition.Reason = "DataPoolInUse"
+		newCondition.Message = strings.Join(blocked, ". ")
+	}
+
+	now := metav1.NewTime(time.Now())
+	// Only report the condition once a removal was blocked
+	if existing == nil && len(blocked) > 0 {
+		newCondition.LastTransitionTime = now
+		newCondition.LastHeartbeatTime = now
+		fs.Status.Conditions = append(fs.Status.Conditions, newCondition)
+	} else if existing != nil {
+		if existing.Status != newCondition.Status {
+			existing.LastTransitionTime = now
+		}
+		existing.Status = newCondition.Status
+		existing.Reason = newCondition.Reason
+		existing.Message = newCondition.Message
+		existing.LastHeartbeatTime = now
+	}
+
+	if err := opcontroller.UpdateStatus(client, fs); err != nil {
+		return errors.Wrapf(err, "failed to set filesystem %q data pools removal status", fs.Name)
+	}
+	logger.Debugf("filesystem %q data pools removal status updated", name)
+	return nil
+}
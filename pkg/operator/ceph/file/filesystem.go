@@ -18,7 +18,10 @@ package file
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/exec"
@@ -39,6 +42,12 @@ const (
 	metaDataPoolSuffix = "metadata"
 )
 
+// snapshotIntervalRegex matches the intervals supported by the snap_schedule mgr module
+var snapshotIntervalRegex = regexp.MustCompile(`^[0-9]+[hdwMy]$`)
+
+// dataPoolNameRegex matches the names allowed for the data pools
+var dataPoolNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
 // Filesystem represents an instance of a Ceph filesystem (CephFS)
 type Filesystem struct {
 	Name      string
@@ -58,7 +67,7 @@ func createFilesystem(
 
 	if len(fs.Spec.DataPools) != 0 {
 		f := newFS(fs.Name, fs.Namespace)
-		if err := f.doFilesystemCreate(context, clusterInfo, fs.Spec); err != nil {
+		if err := f.doFilesystemCreate(context, clusterInfo, fs.Spec, fs.DesiredActiveCount()); err != nil {
 			return errors.Wrapf(err, "failed to create filesystem %q", fs.Name)
 		}
 	}
@@ -75,9 +84,9 @@ func createFilesystem(
 	}
 
 	// set the number of active mds instances
-	if fs.Spec.MetadataServer.ActiveCount > 1 {
-		if err = client.SetNumMDSRanks(context, clusterInfo, fs.Name, fs.Spec.MetadataServer.ActiveCount); err != nil {
-			logger.Warningf("failed setting active mds count to %d. %v", fs.Spec.MetadataServer.ActiveCount, err)
+	if activeCount := fs.DesiredActiveCount(); activeCount > 1 {
+		if err = client.SetNumMDSRanks(context, clusterInfo, fs.Name, activeCount); err != nil {
+			logger.Warningf("failed setting active mds count to %d. %v", activeCount, err)
 		}
 	}
 
@@ -90,6 +99,178 @@ func createFilesystem(
 	return nil
 }
 
+// removeDataPools detaches from the filesystem the data pools created by rook that were removed from the spec and
+// deletes them unless PreservePoolsOnDelete is set. The pools are removed by name, the names of the pools created for
+// the filesystem are returned to be recorded in the status. A pool still holding objects or used as the layout of a
+// subvolume group or of a directory is not removed, the reasons of the blocked removals are returned.
+func removeDataPools(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fs cephv1.CephFilesystem) ([]string, []string, error) {
+	// The filesystem was not created by rook
+	if len(fs.Spec.DataPools) == 0 {
+		return nil, nil, nil
+	}
+
+	filesystems, err := client.ListFilesystems(context, clusterInfo)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list filesystems")
+	}
+	var attachedPools client.CephFilesystem
+	for _, f := range filesystems {
+		if f.Name == fs.Name {
+			attachedPools = f
+		}
+	}
+
+	desiredPools := generateDataPoolNames(newFS(fs.Name, fs.Namespace), fs.Spec)
+	createdPools := getCreatedDataPools(fs, desiredPools, attachedPools.DataPools)
+	if len(createdPools) == len(desiredPools) {
+		return createdPools, nil, nil
+	}
+
+	poolStats, err := client.GetPoolStats(context, clusterInfo)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get pool stats")
+	}
+	subVolumeGroups, err := context.RookClientset.CephV1().CephFilesystemSubVolumeGroups(fs.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list subvolume groups")
+	}
+
+	remainingPools := []string{}
+	blocked := []string{}
+	for _, poolName := range createdPools {
+		if isPoolInList(desiredPools, poolName) {
+			remainingPools = append(remainingPools, poolName)
+			continue
+		}
+
+		reason, err := detachDataPool(context, clusterInfo, fs.Name, poolName, attachedPools, poolStats, subVolumeGroups.Items)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reason != "" {
+			logger.Warningf("not removing data pool %q from filesystem %q. %s", poolName, fs.Name, reason)
+			blocked = append(blocked, reason)
+			remainingPools = append(remainingPools, poolName)
+			continue
+		}
+
+		if fs.Spec.PreservePoolsOnDelete {
+			logger.Infof("preserving data pool %q removed from filesystem %q", poolName, fs.Name)
+			continue
+		}
+		if !poolExists(poolStats, poolName) {
+			continue
+		}
+		if err := client.DeletePool(context, clusterInfo, poolName); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to delete data pool %q removed from filesystem %q", poolName, fs.Name)
+		}
+	}
+
+	return remainingPools, blocked, nil
+}
+
+// detachDataPool removes a data pool from the filesystem unless it is still in use, the reason why the pool cannot be
+// removed yet is returned. A pool already detached from the filesystem is ignored.
+func detachDataPool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fsName, poolName string, filesystem client.CephFilesystem, poolStats *client.CephStoragePoolStats, subVolumeGroups []cephv1.CephFilesystemSubVolumeGroup) (string, error) {
+	for i, attachedPool := range filesystem.DataPools {
+		if attachedPool != poolName {
+			continue
+		}
+		// The default data pool holds the backtraces of all the files, ceph never allows its removal
+		if i == 0 {
+			return fmt.Sprintf("data pool %q is the default data pool of the filesystem and cannot be removed", poolName), nil
+		}
+		reason, err := dataPoolInUse(context, clusterInfo, poolName, filesystem.DataPoolIDs[i], fsName, poolStats, subVolumeGroups)
+		if err != nil || reason != "" {
+			return reason, err
+		}
+		return "", client.RemoveFilesystemDataPool(context, clusterInfo, fsName, poolName)
+	}
+	return "", nil
+}
+
+// dataPoolInUse returns why the data pool cannot be removed yet, an empty string if the pool is not used anymore.
+// The layouts of the directories are read from the cache of the active mds ranks.
+func dataPoolInUse(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, poolID int, fsName string, poolStats *client.CephStoragePoolStats, subVolumeGroups []cephv1.CephFilesystemSubVolumeGroup) (string, error) {
+	for _, p := range poolStats.Pools {
+		if p.Name == poolName && p.Stats.Objects > 0 {
+			return fmt.Sprintf("data pool %q still has %d objects", poolName, int64(p.Stats.Objects)), nil
+		}
+	}
+
+	for _, group := range subVolumeGroups {
+		if group.Spec.FilesystemName != fsName {
+			continue
+		}
+		layoutPool := group.Spec.DataPoolName
+		if group.Status != nil && group.Status.Usage != nil && group.Status.Usage.DataPool != "" {
+			layoutPool = group.Status.Usage.DataPool
+		}
+		if layoutPool == poolName {
+			return fmt.Sprintf("data pool %q is the layout of subvolume group %q", poolName, group.Name), nil
+		}
+	}
+
+	filesystem, err := client.GetFilesystem(context, clusterInfo, fsName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get filesystem %q", fsName)
+	}
+	for _, rank := range filesystem.MDSMap.In {
+		inodes, err := client.GetMDSCachedTree(context, clusterInfo, fsName, rank)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check the directory layouts of data pool %q", poolName)
+		}
+		for _, inode := range inodes {
+			if inode.Layout.PoolID == poolID {
+				return fmt.Sprintf("data pool %q is the layout of %q", poolName, inode.Path), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// getCreatedDataPools returns the data pools created for the filesystem, the pools recorded in the status and the
+// desired pools. The filesystems created before the pools were recorded have their data pools named after their index.
+func getCreatedDataPools(fs cephv1.CephFilesystem, desiredPools, attachedPools []string) []string {
+	createdPools := []string{}
+	if fs.Status != nil {
+		createdPools = append(createdPools, fs.Status.DataPools...)
+	}
+	if len(createdPools) == 0 {
+		rookPoolRegex := regexp.MustCompile(fmt.Sprintf("^%s-%s[0-9]+$", regexp.QuoteMeta(fs.Name), dataPoolSuffix))
+		for _, poolName := range attachedPools {
+			if rookPoolRegex.MatchString(poolName) {
+				createdPools = append(createdPools, poolName)
+			}
+		}
+	}
+	for _, poolName := range desiredPools {
+		if !isPoolInList(createdPools, poolName) {
+			createdPools = append(createdPools, poolName)
+		}
+	}
+	return createdPools
+}
+
+func poolExists(poolStats *client.CephStoragePoolStats, poolName string) bool {
+	for _, p := range poolStats.Pools {
+		if p.Name == poolName {
+			return true
+		}
+	}
+	return false
+}
+
+func isPoolInList(pools []string, poolName string) bool {
+	for _, p := range pools {
+		if p == poolName {
+			return true
+		}
+	}
+	return false
+}
+
 // deleteFilesystem deletes the filesystem from Ceph
 func deleteFilesystem(
 	context *clusterd.Context,
@@ -111,7 +292,7 @@ func deleteFilesystem(
 	c := mds.NewCluster(clusterInfo, context, clusterSpec, fs, filesystem, ownerRefs, dataDirHostPath, scheme)
 
 	// Delete mds CephX keys and configuration in centralized mon database
-	replicas := fs.Spec.MetadataServer.ActiveCount * 2
+	replicas := fs.Spec.MetadataServer.MaxActiveCount() * 2
 	for i := 0; i < int(replicas); i++ {
 		daemonLetterID := k8sutil.IndexToName(i)
 		daemonName := fmt.Sprintf("%s-%s", fs.Name, daemonLetterID)
@@ -138,6 +319,56 @@ func deleteFilesystem(
 	return nil
 }
 
+// validateMDSAutoscaling checks the bounds and the policy of the autoscaling of the active mds ranks
+func validateMDSAutoscaling(autoscaling *cephv1.MDSAutoscalingSpec) error {
+	if autoscaling == nil || !autoscaling.Enabled {
+		return nil
+	}
+	if autoscaling.MinActiveCount < 1 {
+		return errors.New("MetadataServer.Autoscaling.MinActiveCount must be at least 1")
+	}
+	if autoscaling.MaxActiveCount < autoscaling.MinActiveCount {
+		return errors.Errorf("MetadataServer.Autoscaling.MaxActiveCount %d must not be lower than MinActiveCount %d", autoscaling.MaxActiveCount, autoscaling.MinActiveCount)
+	}
+	if autoscaling.TargetRequestRate < 0 {
+		return errors.New("MetadataServer.Autoscaling.TargetRequestRate must not be negative")
+	}
+	if autoscaling.TargetRequestRate == 0 && !autoscaling.ScaleOnCachePressure {
+		return errors.New("MetadataServer.Autoscaling requires a TargetRequestRate or ScaleOnCachePressure")
+	}
+	if autoscaling.Interval != "" {
+		if _, err := time.ParseDuration(autoscaling.Interval); err != nil {
+			return errors.Wrapf(err, "invalid MetadataServer.Autoscaling.Interval %q", autoscaling.Interval)
+		}
+	}
+	return nil
+}
+
+// validateSnapshotSchedules checks the schedules paths and intervals and that the schedules of a directory
+// don't request different retention policies since the retention is a setting of the directory
+func validateSnapshotSchedules(schedules []cephv1.FilesystemSnapshotScheduleSpec) error {
+	retentions := map[string]cephv1.FilesystemSnapshotRetentionSpec{}
+	for _, s := range schedules {
+		if !strings.HasPrefix(s.Path, "/") {
+			return errors.Errorf("snapshot schedule path %q must be an absolute path", s.Path)
+		}
+		if !snapshotIntervalRegex.MatchString(s.Interval) {
+			return errors.Errorf("invalid snapshot schedule interval %q of path %q, it must be a number followed by one of h, d, w, M or y", s.Interval, s.Path)
+		}
+		if s.Retention.Hourly < 0 || s.Retention.Daily < 0 || s.Retention.Weekly < 0 {
+			return errors.Errorf("snapshot retention of path %q must not be negative", s.Path)
+		}
+		if s.Retention == (cephv1.FilesystemSnapshotRetentionSpec{}) {
+			continue
+		}
+		if r, ok := retentions[s.Path]; ok && r != s.Retention {
+			return errors.Errorf("conflicting snapshot retention for path %q", s.Path)
+		}
+		retentions[s.Path] = s.Retention
+	}
+	return nil
+}
+
 func validateFilesystem(context *clusterd.Context, clusterInfo *client.ClusterInfo, f *cephv1.CephFilesystem) error {
 	if f.Name == "" {
 		return errors.New("missing name")
@@ -148,6 +379,19 @@ func validateFilesystem(context *clusterd.Context, clusterInfo *client.ClusterIn
 	if f.Spec.MetadataServer.ActiveCount < 1 {
 		return errors.New("MetadataServer.ActiveCount must be at least 1")
 	}
+	if f.Spec.Mirroring != nil {
+		for _, d := range f.Spec.Mirroring.Directories {
+			if !strings.HasPrefix(d, "/") {
+				return errors.Errorf("mirrored directory %q must be an absolute path", d)
+			}
+		}
+	}
+	if err := validateMDSAutoscaling(f.Spec.MetadataServer.Autoscaling); err != nil {
+		return err
+	}
+	if err := validateSnapshotSchedules(f.Spec.SnapshotSchedules); err != nil {
+		return errors.Wrap(err, "invalid snapshot schedules")
+	}
 	// No data pool means that we expect the fs to exist already
 	if len(f.Spec.DataPools) == 0 {
 		return nil
@@ -156,10 +400,20 @@ func validateFilesystem(context *clusterd.Context, clusterInfo *client.ClusterIn
 		return errors.Wrap(err, "invalid metadata pool")
 	}
 	for _, p := range f.Spec.DataPools {
-		localpoolSpec := p
+		localpoolSpec := p.PoolSpec
 		if err := pool.ValidatePoolSpec(context, clusterInfo, &localpoolSpec); err != nil {
 			return errors.Wrap(err, "Invalid data pool")
 		}
+		if p.Name != "" && (!dataPoolNameRegex.MatchString(p.Name) || p.Name == metaDataPoolSuffix) {
+			return errors.Errorf("invalid data pool name %q", p.Name)
+		}
+	}
+	// The unnamed pools are named after their index, a named pool must not take the name of another pool
+	poolNames := generateDataPoolNames(newFS(f.Name, f.Namespace), f.Spec)
+	for i, poolName := range poolNames {
+		if isPoolInList(poolNames[:i], poolName) {
+			return errors.Errorf("duplicate data pool %q", poolName)
+		}
 	}
 
 	return nil
@@ -185,7 +439,7 @@ func SetPoolSize(f *Filesystem, context *clusterd.Context, clusterInfo *client.C
 	dataPoolNames := generateDataPoolNames(f, spec)
 	for i, pool := range spec.DataPools {
 		poolName := dataPoolNames[i]
-		err := client.CreatePoolWithProfile(context, clusterInfo, poolName, pool, "")
+		err := client.CreatePoolWithProfile(context, clusterInfo, poolName, pool.PoolSpec, "")
 		if err != nil {
 			return errors.Wrapf(err, "failed to update datapool  %q", poolName)
 		}
@@ -194,18 +448,19 @@ func SetPoolSize(f *Filesystem, context *clusterd.Context, clusterInfo *client.C
 }
 
 // doFilesystemCreate starts the Ceph file daemons and creates the filesystem in Ceph.
-func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *client.ClusterInfo, spec cephv1.FilesystemSpec) error {
+// activeCount is the number of active mds ranks, the count chosen by the autoscaler when it is enabled.
+func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *client.ClusterInfo, spec cephv1.FilesystemSpec, activeCount int32) error {
 
 	_, err := client.GetFilesystem(context, clusterInfo, f.Name)
 	if err == nil {
 		logger.Infof("filesystem %s already exists", f.Name)
 		// Even if the fs already exists, the num active mdses may have changed
 
-		if err := client.SetNumMDSRanks(context, clusterInfo, f.Name, spec.MetadataServer.ActiveCount); err != nil {
+		if err := client.SetNumMDSRanks(context, clusterInfo, f.Name, activeCount); err != nil {
 			logger.Errorf(
-				fmt.Sprintf("failed to set num mds ranks (max_mds) to %d for filesystem %s, still continuing. ", spec.MetadataServer.ActiveCount, f.Name) +
+				fmt.Sprintf("failed to set num mds ranks (max_mds) to %d for filesystem %s, still continuing. ", activeCount, f.Name) +
 					"this error is not critical, but mdses may not be as failure tolerant as desired. " +
-					fmt.Sprintf("USER should verify that the number of active mdses is %d with 'ceph fs get %s'", spec.MetadataServer.ActiveCount, f.Name) +
+					fmt.Sprintf("USER should verify that the number of active mdses is %d with 'ceph fs get %s'", activeCount, f.Name) +
 					fmt.Sprintf(". %v", err),
 			)
 		}
@@ -254,7 +509,7 @@ func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *
 		poolName := dataPoolNames[i]
 		if _, poolFound := reversedPoolMap[poolName]; !poolFound {
 			poolsCreated = true
-			err = client.CreatePoolWithProfile(context, clusterInfo, poolName, pool, "")
+			err = client.CreatePoolWithProfile(context, clusterInfo, poolName, pool.PoolSpec, "")
 			if err != nil {
 				return errors.Wrapf(err, "failed to create data pool %q", poolName)
 			}
@@ -288,11 +543,15 @@ func downFilesystem(context *clusterd.Context, clusterInfo *client.ClusterInfo,
 	return nil
 }
 
-// generateDataPoolName generates DataPool name by prefixing the filesystem name to the constant DataPoolSuffix
+// generateDataPoolNames generates the DataPool names by prefixing the filesystem name to the name of the pool, the
+// unnamed pools are named after the constant DataPoolSuffix and their index
 func generateDataPoolNames(f *Filesystem, spec cephv1.FilesystemSpec) []string {
 	var dataPoolNames []string
-	for i := range spec.DataPools {
+	for i, pool := range spec.DataPools {
 		poolName := fmt.Sprintf("%s-%s%d", f.Name, dataPoolSuffix, i)
+		if pool.Name != "" {
+			poolName = fmt.Sprintf("%s-%s", f.Name, pool.Name)
+		}
 		dataPoolNames = append(dataPoolNames, poolName)
 	}
 	return dataPoolNames
@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/daemon/ceph/client"
@@ -56,7 +57,7 @@ func TestValidateSpec(t *testing.T) {
 	// missing data pools
 	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
 	p := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}}
-	fs.Spec.DataPools = append(fs.Spec.DataPools, p)
+	fs.Spec.DataPools = append(fs.Spec.DataPools, cephv1.NamedPoolSpec{PoolSpec: p})
 
 	// missing metadata pool
 	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
@@ -68,6 +69,54 @@ func TestValidateSpec(t *testing.T) {
 
 	// valid!
 	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+
+	// mirrored directories must be absolute paths
+	fs.Spec.Mirroring = &cephv1.FSMirroringSpec{Enabled: true, Directories: []string{"volumes/csi"}}
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.Mirroring.Directories = []string{"/volumes/csi"}
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+
+	// snapshot schedules
+	fs.Spec.SnapshotSchedules = []cephv1.FilesystemSnapshotScheduleSpec{{Path: "/volumes", Interval: "1h"}}
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules[0].Path = "volumes"
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules[0].Path = "/volumes"
+	fs.Spec.SnapshotSchedules[0].Interval = "1x"
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules[0].Interval = "24h"
+	fs.Spec.SnapshotSchedules[0].Retention.Daily = -1
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules[0].Retention.Daily = 7
+
+	// the schedules of a directory must not conflict on the retention
+	fs.Spec.SnapshotSchedules = append(fs.Spec.SnapshotSchedules, cephv1.FilesystemSnapshotScheduleSpec{Path: "/volumes", Interval: "1w"})
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules[1].Retention.Daily = 14
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.SnapshotSchedules = nil
+
+	// the data pools are named after the filesystem and their name or their index
+	fs.Spec.DataPools = append(fs.Spec.DataPools, cephv1.NamedPoolSpec{Name: "ec", PoolSpec: p})
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.DataPools[1].Name = "data0"
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.DataPools[1].Name = "metadata"
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.DataPools[1].Name = "-ec"
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.DataPools = fs.Spec.DataPools[:1]
+
+	// mds autoscaling
+	fs.Spec.MetadataServer.Autoscaling = &cephv1.MDSAutoscalingSpec{Enabled: true, MinActiveCount: 1, MaxActiveCount: 3, TargetRequestRate: 1000}
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.MetadataServer.Autoscaling.MaxActiveCount = 0
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.MetadataServer.Autoscaling.MaxActiveCount = 3
+	fs.Spec.MetadataServer.Autoscaling.TargetRequestRate = 0
+	assert.NotNil(t, validateFilesystem(context, clusterInfo, fs))
+	fs.Spec.MetadataServer.Autoscaling.ScaleOnCachePressure = true
+	assert.Nil(t, validateFilesystem(context, clusterInfo, fs))
 }
 
 func TestCreateFilesystem(t *testing.T) {
@@ -101,7 +150,7 @@ func TestCreateFilesystem(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "ns"},
 		Spec: cephv1.FilesystemSpec{
 			MetadataPool: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}},
-			DataPools:    []cephv1.PoolSpec{{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}}},
+			DataPools:    []cephv1.NamedPoolSpec{{PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 1, RequireSafeReplicaSize: false}}}},
 			MetadataServer: cephv1.MetadataServerSpec{
 				ActiveCount: 1,
 				Resources: v1.ResourceRequirements{
@@ -191,6 +240,133 @@ func TestCreateNopoolFilesystem(t *testing.T) {
 	assert.Nil(t, err)
 	validateStart(t, context, fs)
 }
+
+func TestRemoveDataPools(t *testing.T) {
+	fses := `[{"name":"myfs","metadata_pool":"myfs-metadata","metadata_pool_id":1,"data_pool_ids":[2,3,4],"data_pools":["myfs-data0","myfs-data1","myfs-data2"]}]`
+	stats := `{"pools":[{"name":"myfs-data0","id":2,"stats":{"objects":10}},{"name":"myfs-data1","id":3,"stats":{"objects":0}},{"name":"myfs-data2","id":4,"stats":{"objects":0}}]}`
+	tree := `[{"path":"","layout":{"pool_id":-1}},{"path":"/archive","layout":{"pool_id":-1}}]`
+	removed := []string{}
+	deleted := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "ls" {
+				return fses, nil
+			}
+			if args[0] == "fs" && args[1] == "get" {
+				return `{"mdsmap":{"fs_name":"myfs","in":[0]}}`, nil
+			}
+			if args[0] == "tell" {
+				assert.Equal(t, []string{"mds.myfs:0", "dump", "tree", "/"}, args[1:5])
+				return tree, nil
+			}
+			if args[0] == "df" {
+				return stats, nil
+			}
+			if args[0] == "fs" && args[1] == "rm_data_pool" {
+				assert.Equal(t, "myfs", args[2])
+				removed = append(removed, args[3])
+				return "", nil
+			}
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "get" {
+				return `{"pool":"` + args[3] + `"}`, nil
+			}
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "delete" {
+				deleted = append(deleted, args[3])
+				return "", nil
+			}
+			if args[0] == "osd" && args[1] == "crush" {
+				return "", nil
+			}
+			return "", errors.New("unexpected command")
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			// no rbd images in the pools
+			return `{"images":{"count":0,"snap_count":0}}`, nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor, RookClientset: rookclient.NewSimpleClientset()}
+	clusterInfo := &client.ClusterInfo{Namespace: "ns", FSID: "myfsid"}
+	fs := cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "ns"},
+		Spec: cephv1.FilesystemSpec{
+			DataPools:             []cephv1.NamedPoolSpec{{}, {}, {}},
+			PreservePoolsOnDelete: true,
+		},
+	}
+
+	// all the pools are desired
+	dataPools, blocked, err := removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Empty(t, blocked)
+	assert.Empty(t, removed)
+	assert.Equal(t, []string{"myfs-data0", "myfs-data1", "myfs-data2"}, dataPools)
+
+	// the pools are removed by name, the pool in the middle of the list is removed
+	fs.Status = &cephv1.CephFilesystemStatus{DataPools: dataPools}
+	fs.Spec.DataPools = []cephv1.NamedPoolSpec{{Name: "data0"}, {Name: "data2"}}
+	dataPools, blocked, err = removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Empty(t, blocked)
+	assert.Equal(t, []string{"myfs-data1"}, removed)
+	assert.Empty(t, deleted)
+	assert.Equal(t, []string{"myfs-data0", "myfs-data2"}, dataPools)
+
+	// a pool with objects or used by a subvolume group or by a directory is not removed
+	removed = []string{}
+	fses = `[{"name":"myfs","metadata_pool":"myfs-metadata","metadata_pool_id":1,"data_pool_ids":[2,3,4,5],"data_pools":["myfs-data0","myfs-data1","myfs-data2","myfs-ec"]}]`
+	stats = `{"pools":[{"name":"myfs-data1","id":3,"stats":{"objects":5}},{"name":"myfs-data2","id":4,"stats":{"objects":0}},{"name":"myfs-ec","id":5,"stats":{"objects":0}}]}`
+	tree = `[{"path":"","layout":{"pool_id":-1}},{"path":"/archive","layout":{"pool_id":5}}]`
+	group := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "group-a", Namespace: "ns"},
+		Spec:       cephv1.CephFilesystemSubVolumeGroupSpec{FilesystemName: "myfs", DataPoolName: "myfs-data2"},
+	}
+	context.RookClientset = rookclient.NewSimpleClientset(group)
+	fs.Status.DataPools = []string{"myfs-data0", "myfs-data1", "myfs-data2", "myfs-ec"}
+	fs.Spec.DataPools = []cephv1.NamedPoolSpec{{Name: "data0"}}
+	dataPools, blocked, err = removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`data pool "myfs-data1" still has 5 objects`,
+		`data pool "myfs-data2" is the layout of subvolume group "group-a"`,
+		`data pool "myfs-ec" is the layout of "/archive"`,
+	}, blocked)
+	assert.Empty(t, removed)
+	assert.Equal(t, fs.Status.DataPools, dataPools)
+
+	// the pool is removed and deleted once not used anymore
+	tree = `[{"path":"","layout":{"pool_id":-1}},{"path":"/archive","layout":{"pool_id":-1}}]`
+	fs.Spec.PreservePoolsOnDelete = false
+	dataPools, _, err = removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs-ec"}, removed)
+	assert.Equal(t, []string{"myfs-ec"}, deleted)
+	assert.Equal(t, []string{"myfs-data0", "myfs-data1", "myfs-data2"}, dataPools)
+
+	// the default data pool is never removed
+	removed = []string{}
+	deleted = []string{}
+	fses = `[{"name":"myfs","metadata_pool":"myfs-metadata","metadata_pool_id":1,"data_pool_ids":[2,5],"data_pools":["myfs-data0","myfs-ec"]}]`
+	stats = `{"pools":[{"name":"myfs-data0","id":2,"stats":{"objects":0}},{"name":"myfs-ec","id":5,"stats":{"objects":0}}]}`
+	fs.Status.DataPools = []string{"myfs-data0", "myfs-ec"}
+	fs.Spec.DataPools = []cephv1.NamedPoolSpec{{Name: "ec"}}
+	dataPools, blocked, err = removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`data pool "myfs-data0" is the default data pool of the filesystem and cannot be removed`}, blocked)
+	assert.Empty(t, removed)
+	assert.Empty(t, deleted)
+	assert.Equal(t, []string{"myfs-data0", "myfs-ec"}, dataPools)
+
+	// the pools not created by rook are ignored
+	fs.Status = nil
+	fses = `[{"name":"myfs","metadata_pool":"myfs-metadata","metadata_pool_id":1,"data_pool_ids":[5,2,6],"data_pools":["myfs-ec","myfs-data0","other"]}]`
+	dataPools, blocked, err = removeDataPools(context, clusterInfo, fs)
+	assert.NoError(t, err)
+	assert.Empty(t, blocked)
+	assert.Equal(t, []string{"myfs-data0"}, removed)
+	assert.Equal(t, []string{"myfs-data0"}, deleted)
+	assert.Equal(t, []string{"myfs-ec"}, dataPools)
+}
+
 func contains(arr []string, str string) bool {
 	for _, a := range arr {
 		if a == str {
@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultHealthCheckInterval = 1 * time.Minute
+	healthOK                   = "OK"
+	healthWarning              = "WARNING"
+	healthUnknown              = "UNKNOWN"
+)
+
+type statusChecker struct {
+	context *clusterd.Context
+	// interval is the interval of the mirroring and snapshot schedules checks
+	interval time.Duration
+	// mdsInterval is the interval of the mds status check and of the autoscaling of the ranks
+	mdsInterval    time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	stopCh         chan struct{}
+}
+
+// newStatusChecker creates a new checker of the filesystem mds, mirroring and snapshot schedules status
+func newStatusChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, spec cephv1.FilesystemSpec) *statusChecker {
+	c := &statusChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		mdsInterval:    defaultHealthCheckInterval,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		client:         client,
+		stopCh:         make(chan struct{}),
+	}
+
+	// allow overriding the check intervals
+	if spec.Mirroring != nil && spec.Mirroring.StatusCheck.Mirror.Interval != "" {
+		checkInterval := spec.Mirroring.StatusCheck.Mirror.Interval
+		if duration, err := time.ParseDuration(checkInterval); err == nil {
+			logger.Infof("filesystem %q status check interval is %q", namespacedName.Name, checkInterval)
+			c.interval = duration
+		}
+	}
+	if spec.MetadataServer.Autoscaling != nil && spec.MetadataServer.Autoscaling.Interval != "" {
+		checkInterval := spec.MetadataServer.Autoscaling.Interval
+		if duration, err := time.ParseDuration(checkInterval); err == nil {
+			logger.Infof("filesystem %q mds status check interval is %q", namespacedName.Name, checkInterval)
+			c.mdsInterval = duration
+		}
+	}
+
+	return c
+}
+
+// checkStatus periodically checks the mds, mirroring and snapshot schedules status of the filesystem until the
+// checker is stopped. The mds status and the mirroring status are checked at their own interval.
+func (c *statusChecker) checkStatus() {
+	// check the status immediately before starting the loop
+	if err := c.checkMDS(); err != nil {
+		logger.Debugf("failed to check filesystem %q mds status. %v", c.namespacedName.Name, err)
+	}
+	if err := c.checkFilesystemStatus(); err != nil {
+		logger.Debugf("failed to check filesystem %q status. %v", c.namespacedName.Name, err)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	mdsTicker := time.NewTicker(c.mdsInterval)
+	defer mdsTicker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			logger.Infof("stopping monitoring filesystem %q status", c.namespacedName.Name)
+			return
+
+		case <-mdsTicker.C:
+			logger.Debugf("checking filesystem %q mds status", c.namespacedName.Name)
+			if err := c.checkMDS(); err != nil {
+				logger.Debugf("failed to check filesystem %q mds status. %v", c.namespacedName.Name, err)
+			}
+
+		case <-ticker.C:
+			logger.Debugf("checking filesystem %q status", c.namespacedName.Name)
+			if err := c.checkFilesystemStatus(); err != nil {
+				logger.Debugf("failed to check filesystem %q status. %v", c.namespacedName.Name, err)
+			}
+		}
+	}
+}
+
+// getFilesystem returns the filesystem whose status is checked, nil if it was deleted or has no status yet
+func (c *statusChecker) getFilesystem() (*cephv1.CephFilesystem, error) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve filesystem %q", c.namespacedName.Name)
+	}
+	if fs.Status == nil {
+		return nil, nil
+	}
+	return fs, nil
+}
+
+// checkMDS checks the mds status and autoscales the ranks of the filesystem
+func (c *statusChecker) checkMDS() error {
+	fs, err := c.getFilesystem()
+	if err != nil || fs == nil {
+		return err
+	}
+	return c.checkMDSStatus(fs)
+}
+
+// checkFilesystemStatus checks the mirroring and snapshot schedules status of the filesystem
+func (c *statusChecker) checkFilesystemStatus() error {
+	fs, err := c.getFilesystem()
+	if err != nil || fs == nil {
+		return err
+	}
+
+	var errs []string
+	if fs.Status.MirroringStatus != nil && mirrorStatusCheckEnabled(fs) {
+		if err := c.checkMirroringHealth(fs); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if fs.Status.SnapshotScheduleStatus != nil && len(fs.Spec.SnapshotSchedules) > 0 {
+		if err := c.checkSnapshotSchedules(fs); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ". "))
+	}
+
+	return nil
+}
+
+// mirrorStatusCheckEnabled returns whether the mirroring status of the filesystem must be checked
+func mirrorStatusCheckEnabled(fs *cephv1.CephFilesystem) bool {
+	return fs.Spec.Mirroring != nil && fs.Spec.Mirroring.Enabled && !fs.Spec.Mirroring.StatusCheck.Mirror.Disabled
+}
+
+func (c *statusChecker) checkMirroringHealth(fs *cephv1.CephFilesystem) error {
+	dirMaps := make(map[string]*cephclient.FilesystemMirrorDirMap)
+	dirErrors := make(map[string]string)
+	for _, d := range fs.Status.MirroringStatus.Directories {
+		dirMap, err := cephclient.GetFilesystemMirrorDirMap(c.context, c.clusterInfo, fs.Name, d.Path)
+		if err != nil {
+			dirErrors[d.Path] = err.Error()
+			continue
+		}
+		dirMaps[d.Path] = dirMap
+	}
+
+	daemons, err := cephclient.GetFilesystemMirrorDaemonStatus(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		c.updateStatusMirroring(dirMaps, dirErrors, nil, err.Error())
+		return err
+	}
+
+	c.updateStatusMirroring(dirMaps, dirErrors, daemons, "")
+	return nil
+}
+
+// updateStatusMirroring updates the mirrored directories and peers sync status
+func (c *statusChecker) updateStatusMirroring(dirMaps map[string]*cephclient.FilesystemMirrorDirMap, dirErrors map[string]string, daemons []cephclient.FilesystemMirrorDaemonStatus, details string) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to update mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil || fs.Status.MirroringStatus == nil {
+		return
+	}
+
+	status := fs.Status.MirroringStatus
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	status.Details = details
+	for i := range status.Directories {
+		directoryStatus(&status.Directories[i], dirMaps[status.Directories[i].Path], dirErrors[status.Directories[i].Path])
+	}
+	// daemons will be nil in case of an error to fetch them, keep the last known state
+	if daemons != nil {
+		for i := range status.Peers {
+			filesystemPeerStatus(&status.Peers[i], fs.Name, daemons)
+		}
+	}
+
+	if err := opcontroller.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set filesystem %q mirroring status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("filesystem %q mirroring status updated", c.namespacedName.Name)
+}
+
+// directoryStatus fills the sync status of a mirrored directory from its mapping to a mirror daemon
+func directoryStatus(directory *cephv1.FilesystemMirrorDirectoryStatus, dirMap *cephclient.FilesystemMirrorDirMap, details string) {
+	directory.Details = details
+	if dirMap == nil {
+		return
+	}
+
+	directory.State = dirMap.State
+	directory.InstanceID = dirMap.InstanceID
+	directory.LastShuffled = ""
+	if dirMap.LastShuffled > 0 {
+		directory.LastShuffled = time.Unix(int64(dirMap.LastShuffled), 0).UTC().Format(time.RFC3339)
+	}
+}
+
+// filesystemPeerStatus fills the peer status from the mirror daemons statistics
+func filesystemPeerStatus(peer *cephv1.FilesystemMirrorPeerStatus, fsName string, daemons []cephclient.FilesystemMirrorDaemonStatus) {
+	found := false
+	failures := 0
+	for _, daemon := range daemons {
+		for _, fs := range daemon.Filesystems {
+			if fs.Name != fsName {
+				continue
+			}
+			for _, p := range fs.Peers {
+				if p.UUID == peer.UUID {
+					found = true
+					failures += p.Stats.FailureCount
+				}
+			}
+		}
+	}
+
+	peer.FailureCount = failures
+	switch {
+	case !found:
+		peer.Health = healthUnknown
+	case failures > 0:
+		peer.Health = healthWarning
+	default:
+		peer.Health = healthOK
+	}
+}
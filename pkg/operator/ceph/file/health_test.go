@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDirectoryStatus(t *testing.T) {
+	directory := cephv1.FilesystemMirrorDirectoryStatus{Path: "/volumes/csi"}
+
+	directoryStatus(&directory, &cephclient.FilesystemMirrorDirMap{InstanceID: "284167", LastShuffled: 1605107564.0, State: "mapped"}, "")
+	assert.Equal(t, "mapped", directory.State)
+	assert.Equal(t, "284167", directory.InstanceID)
+	assert.Equal(t, "2020-11-11T15:12:44Z", directory.LastShuffled)
+
+	// an error keeps the last known state
+	directoryStatus(&directory, nil, "failed to get mapping")
+	assert.Equal(t, "mapped", directory.State)
+	assert.Equal(t, "failed to get mapping", directory.Details)
+}
+
+func TestFilesystemPeerStatus(t *testing.T) {
+	var daemons []cephclient.FilesystemMirrorDaemonStatus
+	err := json.Unmarshal([]byte(`[{"daemon_id": 284167, "filesystems": [{"filesystem_id": 1, "name": "myfs", "directory_count": 1, "peers": [{"uuid": "e8bb9b1c", "remote": {"client_name": "client.mirror_remote", "cluster_name": "site-b", "fs_name": "myfs"}, "stats": {"failure_count": 0, "recovery_count": 0}}]}]}]`), &daemons)
+	assert.NoError(t, err)
+
+	peer := cephv1.FilesystemMirrorPeerStatus{UUID: "e8bb9b1c"}
+	filesystemPeerStatus(&peer, "myfs", daemons)
+	assert.Equal(t, healthOK, peer.Health)
+
+	daemons[0].Filesystems[0].Peers[0].Stats.FailureCount = 3
+	filesystemPeerStatus(&peer, "myfs", daemons)
+	assert.Equal(t, healthWarning, peer.Health)
+	assert.Equal(t, 3, peer.FailureCount)
+
+	// the peer of another filesystem
+	filesystemPeerStatus(&peer, "otherfs", daemons)
+	assert.Equal(t, healthUnknown, peer.Health)
+}
+
+func TestStartStatusChecker(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{})
+	r := &ReconcileCephFilesystem{client: fake.NewFakeClientWithScheme(s), context: &clusterd.Context{}}
+	name := types.NamespacedName{Name: "myfs", Namespace: "ns"}
+	spec := cephv1.FilesystemSpec{
+		Mirroring:      &cephv1.FSMirroringSpec{StatusCheck: cephv1.MirrorHealthCheckSpec{Mirror: cephv1.HealthCheckSpec{Interval: "30s"}}},
+		MetadataServer: cephv1.MetadataServerSpec{Autoscaling: &cephv1.MDSAutoscalingSpec{Interval: "10s"}},
+	}
+
+	// the mds status and the mirroring status are checked at their own interval
+	r.startStatusChecker(name, spec)
+	checker := r.statusCheckers[name.String()]
+	assert.Equal(t, 30*time.Second, checker.interval)
+	assert.Equal(t, 10*time.Second, checker.mdsInterval)
+
+	// the checker keeps running while the intervals don't change
+	r.startStatusChecker(name, spec)
+	assert.Equal(t, checker, r.statusCheckers[name.String()])
+
+	// the checker is restarted when an interval changes
+	spec.MetadataServer.Autoscaling.Interval = "2m"
+	r.startStatusChecker(name, spec)
+	restarted := r.statusCheckers[name.String()]
+	assert.NotEqual(t, checker, restarted)
+	assert.Equal(t, 2*time.Minute, restarted.mdsInterval)
+	_, running := <-checker.stopCh
+	assert.False(t, running)
+
+	r.stopStatusChecker(name)
+	assert.Empty(t, r.statusCheckers)
+}
@@ -108,15 +108,16 @@ func (c *Cluster) Start() error {
 	var fsPreparedForUpgrade = false
 	defer func() {
 		if fsPreparedForUpgrade {
-			if err := finishedWithDaemonUpgrade(c.context, c.clusterInfo, c.fs.Name, c.fs.Spec.MetadataServer.ActiveCount); err != nil {
+			if err := finishedWithDaemonUpgrade(c.context, c.clusterInfo, c.fs.Name, c.fs.DesiredActiveCount()); err != nil {
 				logger.Errorf("for filesystem %q, USER should make sure the Ceph fs max_mds property is set to %d. %v",
-					c.fs.Name, c.fs.Spec.MetadataServer.ActiveCount, err)
+					c.fs.Name, c.fs.DesiredActiveCount(), err)
 			}
 		}
 	}()
 
 	// Always create double the number of metadata servers to have standby mdses available
-	replicas := c.fs.Spec.MetadataServer.ActiveCount * 2
+	// With autoscaling, the daemons are deployed for the max number of active ranks
+	replicas := c.fs.Spec.MetadataServer.MaxActiveCount() * 2
 
 	// keep list of deployments we want so unwanted ones can be deleted later
 	desiredDeployments := map[string]bool{} // improvised set
@@ -223,7 +224,7 @@ func (c *Cluster) scaleDownDeployments(replicas int32, desiredDeployments map[st
 			// if the extraneous mdses are the only ones active, Ceph may experience fs downtime
 			// if deleting them too quickly; therefore, wait until number of active mdses is desired
 			if err := client.WaitForActiveRanks(c.context, c.clusterInfo, c.fs.Name,
-				c.fs.Spec.MetadataServer.ActiveCount, true, fsWaitForActiveTimeout); err != nil {
+				c.fs.DesiredActiveCount(), true, fsWaitForActiveTimeout); err != nil {
 				errCount++
 				logger.Errorf(
 					"number of active mds ranks is not as desired. it is potentially unsafe to continue with extraneous mds deletion, so stopping. " +
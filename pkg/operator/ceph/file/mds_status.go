@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// mdsScaleCooldown is the minimum time between two changes of the number of active ranks
+	mdsScaleCooldown = 5 * time.Minute
+	// mdsCacheOversizedCheck is the health check raised when an mds cache is larger than its limit
+	mdsCacheOversizedCheck = "MDS_CACHE_OVERSIZED"
+
+	mdsStateActive        = "active"
+	mdsStateStandby       = "standby"
+	mdsStateStandbyReplay = "standby-replay"
+	mdsStateFailed        = "failed"
+	mdsStateDamaged       = "damaged"
+)
+
+// checkMDSStatus refreshes the state of the mds ranks and daemons of the filesystem and scales the number of active
+// ranks when the autoscaling is enabled
+func (c *statusChecker) checkMDSStatus(fs *cephv1.CephFilesystem) error {
+	details, err := cephclient.GetFilesystem(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		c.updateStatusMDS(nil, err.Error())
+		return errors.Wrapf(err, "failed to get filesystem %q", fs.Name)
+	}
+
+	var errs []string
+	// the load of the daemons is only informative, report the ranks even if it is not available
+	load, err := cephclient.GetFilesystemStatus(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	status := mdsStatus(fs.Name, details, load)
+
+	if fs.Spec.MetadataServer.IsAutoscalingEnabled() {
+		if fs.Status.MDSStatus != nil {
+			status.LastScaleTime = fs.Status.MDSStatus.LastScaleTime
+		}
+		if err := c.autoscaleMDS(fs, status, load != nil); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	detailsMsg := strings.Join(errs, ". ")
+	c.updateStatusMDS(status, detailsMsg)
+	if detailsMsg != "" {
+		return errors.New(detailsMsg)
+	}
+
+	return nil
+}
+
+// autoscaleMDS changes the number of active ranks of the filesystem by one rank if the load requires it. The autoscaler
+// doesn't wait for the ranks to become active, the next checks report them and the cooldown leaves them time to settle.
+func (c *statusChecker) autoscaleMDS(fs *cephv1.CephFilesystem, status *cephv1.FilesystemMDSStatus, loadAvailable bool) error {
+	autoscaling := fs.Spec.MetadataServer.Autoscaling
+	current := fs.DesiredActiveCount()
+	status.DesiredActiveCount = current
+
+	if status.LastScaleTime != "" {
+		if lastScale, err := time.Parse(time.RFC3339, status.LastScaleTime); err == nil && time.Since(lastScale) < mdsScaleCooldown {
+			logger.Debugf("not scaling the active mds ranks of filesystem %q, last change at %s", fs.Name, status.LastScaleTime)
+			return nil
+		}
+	}
+	// without the request rate of the ranks, the rate policy cannot be evaluated
+	if autoscaling.TargetRequestRate > 0 && !loadAvailable {
+		return nil
+	}
+
+	cachePressure := false
+	if autoscaling.ScaleOnCachePressure {
+		health, err := cephclient.HealthDetail(c.context, c.clusterInfo)
+		if err != nil {
+			return errors.Wrap(err, "failed to get ceph health detail")
+		}
+		cachePressure = mdsCacheOversized(health, status.Ranks)
+	}
+
+	desired := desiredActiveRanks(autoscaling, current, status.Ranks, cachePressure)
+	if desired == current {
+		return nil
+	}
+
+	logger.Infof("scaling the active mds ranks of filesystem %q from %d to %d", fs.Name, current, desired)
+	if err := cephclient.SetNumMDSRanks(c.context, c.clusterInfo, fs.Name, desired); err != nil {
+		return err
+	}
+	status.DesiredActiveCount = desired
+	status.LastScaleTime = time.Now().UTC().Format(time.RFC3339)
+
+	return nil
+}
+
+// mdsCacheOversized returns whether the cache of one of the mds daemons of the ranks is larger than its limit
+// The health check is raised for the whole cluster, its detail lists the daemons as "mds.<name>(mds.<rank>): ..."
+func mdsCacheOversized(health cephclient.HealthStatus, ranks []cephv1.MDSRankStatus) bool {
+	check, ok := health.Checks[mdsCacheOversizedCheck]
+	if !ok {
+		return false
+	}
+	for _, detail := range check.Detail {
+		for _, rank := range ranks {
+			for _, daemon := range []string{rank.Daemon, rank.StandbyReplay} {
+				if daemon != "" && strings.HasPrefix(detail.Message, "mds."+daemon+"(") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// desiredActiveRanks returns the number of active ranks required by the load of the filesystem. A rank is added when
+// the average request rate of the active ranks is above the target or the mds caches are under pressure. A rank is
+// removed when the request rate is below half of the target, or when the caches are not under pressure anymore with
+// the cache policy only.
+func desiredActiveRanks(autoscaling *cephv1.MDSAutoscalingSpec, current int32, ranks []cephv1.MDSRankStatus, cachePressure bool) int32 {
+	active, rate := 0, 0
+	for _, r := range ranks {
+		if r.State == mdsStateActive {
+			active++
+			rate += r.RequestRate
+		}
+	}
+
+	desired := current
+	switch {
+	case cachePressure:
+		desired++
+	case autoscaling.TargetRequestRate > 0 && active > 0:
+		averageRate := rate / active
+		if averageRate > autoscaling.TargetRequestRate {
+			desired++
+		} else if averageRate < autoscaling.TargetRequestRate/2 {
+			desired--
+		}
+	case autoscaling.TargetRequestRate == 0:
+		desired--
+	}
+
+	if desired < autoscaling.MinActiveCount {
+		return autoscaling.MinActiveCount
+	}
+	if desired > autoscaling.MaxActiveCount {
+		return autoscaling.MaxActiveCount
+	}
+	return desired
+}
+
+// mdsStatus builds the status of the ranks and daemons of the filesystem from its mds map and the load of its daemons,
+// the load is nil if it could not be retrieved
+func mdsStatus(fsName string, details *cephclient.CephFilesystemDetails, load *cephclient.FilesystemStatus) *cephv1.FilesystemMDSStatus {
+	ranks := map[int]*cephv1.MDSRankStatus{}
+	for _, r := range details.MDSMap.In {
+		ranks[r] = &cephv1.MDSRankStatus{Rank: r}
+	}
+	for _, r := range details.MDSMap.Failed {
+		ranks[r] = &cephv1.MDSRankStatus{Rank: r, State: mdsStateFailed}
+	}
+	for _, r := range details.MDSMap.Damaged {
+		ranks[r] = &cephv1.MDSRankStatus{Rank: r, State: mdsStateDamaged}
+	}
+
+	for _, info := range details.MDSMap.Info {
+		if info.Rank < 0 {
+			continue
+		}
+		rank, ok := ranks[info.Rank]
+		if !ok {
+			rank = &cephv1.MDSRankStatus{Rank: info.Rank}
+			ranks[info.Rank] = rank
+		}
+		state := strings.TrimPrefix(info.State, "up:")
+		if state == mdsStateStandbyReplay {
+			rank.StandbyReplay = info.Name
+			continue
+		}
+		rank.Daemon = info.Name
+		rank.State = state
+	}
+
+	status := &cephv1.FilesystemMDSStatus{}
+	if load != nil {
+		for _, c := range load.Clients {
+			if c.FS == fsName {
+				status.Clients += c.Clients
+			}
+		}
+		for _, daemon := range load.MDSMap {
+			if daemon.State == mdsStateStandby {
+				// the standby daemons are shared by all the filesystems, only report the ones deployed for this one
+				if strings.HasPrefix(daemon.Name, fsName+"-") {
+					status.Standby = append(status.Standby, daemon.Name)
+				}
+				continue
+			}
+			for _, rank := range ranks {
+				if rank.Daemon == daemon.Name {
+					rank.RequestRate = int(daemon.Rate)
+					rank.Caps = daemon.Caps
+					rank.Inodes = daemon.Inos
+				}
+			}
+		}
+		sort.Strings(status.Standby)
+	}
+
+	for _, rank := range ranks {
+		status.Ranks = append(status.Ranks, *rank)
+	}
+	sort.Slice(status.Ranks, func(i, j int) bool { return status.Ranks[i].Rank < status.Ranks[j].Rank })
+
+	return status
+}
+
+// updateStatusMDS updates the mds status of the filesystem, a nil status keeps the last known state of the ranks
+func (c *statusChecker) updateStatusMDS(status *cephv1.FilesystemMDSStatus, details string) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to update mds status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	if status == nil {
+		status = fs.Status.MDSStatus
+		if status == nil {
+			status = &cephv1.FilesystemMDSStatus{}
+		}
+	}
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	status.Details = details
+	fs.Status.MDSStatus = status
+
+	if err := opcontroller.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set filesystem %q mds status. %v", c.namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("filesystem %q mds status updated", c.namespacedName.Name)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMDSStatus(t *testing.T) {
+	fsGet := `{"mdsmap":{"fs_name":"myfs","max_mds":2,"in":[0,1],"up":{"mds_0":4107,"mds_1":4108},"failed":[],"damaged":[],"info":{
+		"gid_4107":{"gid":4107,"name":"myfs-a","rank":0,"state":"up:active"},
+		"gid_4108":{"gid":4108,"name":"myfs-b","rank":1,"state":"up:rejoin"},
+		"gid_4109":{"gid":4109,"name":"myfs-c","rank":0,"state":"up:standby-replay"}}},"id":1}`
+	fsStatus := `{"clients":[{"clients":3,"fs":"myfs"},{"clients":1,"fs":"otherfs"}],"mdsmap":[
+		{"name":"myfs-a","rank":0,"state":"active","rate":1250.5,"caps":120,"inos":4000},
+		{"name":"myfs-b","rank":1,"state":"rejoin","rate":0,"caps":0,"inos":10},
+		{"name":"myfs-c","rank":0,"state":"standby-replay"},
+		{"name":"myfs-d","state":"standby"},
+		{"name":"otherfs-a","state":"standby"}]}`
+
+	var details cephclient.CephFilesystemDetails
+	assert.NoError(t, json.Unmarshal([]byte(fsGet), &details))
+	var load cephclient.FilesystemStatus
+	assert.NoError(t, json.Unmarshal([]byte(fsStatus), &load))
+
+	status := mdsStatus("myfs", &details, &load)
+	assert.Equal(t, 3, status.Clients)
+	assert.Equal(t, []string{"myfs-d"}, status.Standby)
+	assert.Equal(t, []cephv1.MDSRankStatus{
+		{Rank: 0, Daemon: "myfs-a", State: "active", StandbyReplay: "myfs-c", RequestRate: 1250, Caps: 120, Inodes: 4000},
+		{Rank: 1, Daemon: "myfs-b", State: "rejoin", Inodes: 10},
+	}, status.Ranks)
+
+	// without the load, the ranks are still reported
+	details.MDSMap.Failed = []int{1}
+	details.MDSMap.Info = map[string]cephclient.MDSInfo{"gid_4107": details.MDSMap.Info["gid_4107"]}
+	status = mdsStatus("myfs", &details, nil)
+	assert.Equal(t, 0, status.Clients)
+	assert.Nil(t, status.Standby)
+	assert.Equal(t, []cephv1.MDSRankStatus{{Rank: 0, Daemon: "myfs-a", State: "active"}, {Rank: 1, State: "failed"}}, status.Ranks)
+}
+
+func TestDesiredActiveRanks(t *testing.T) {
+	autoscaling := &cephv1.MDSAutoscalingSpec{Enabled: true, MinActiveCount: 1, MaxActiveCount: 3, TargetRequestRate: 1000}
+	ranks := []cephv1.MDSRankStatus{{Rank: 0, State: "active", RequestRate: 1500}, {Rank: 1, State: "active", RequestRate: 900}}
+
+	// the average rate is above the target
+	assert.Equal(t, int32(3), desiredActiveRanks(autoscaling, 2, ranks, false))
+	// never above the max count
+	assert.Equal(t, int32(3), desiredActiveRanks(autoscaling, 3, ranks, false))
+
+	// the average rate is between half of the target and the target
+	ranks[0].RequestRate = 500
+	assert.Equal(t, int32(2), desiredActiveRanks(autoscaling, 2, ranks, false))
+
+	// the average rate is below half of the target
+	ranks[1].RequestRate = 100
+	assert.Equal(t, int32(1), desiredActiveRanks(autoscaling, 2, ranks, false))
+	// unless the caches are under pressure
+	assert.Equal(t, int32(3), desiredActiveRanks(autoscaling, 2, ranks, true))
+
+	// the ranks not active are ignored
+	ranks = []cephv1.MDSRankStatus{{Rank: 0, State: "replay"}}
+	assert.Equal(t, int32(2), desiredActiveRanks(autoscaling, 2, ranks, false))
+
+	// cache pressure policy only
+	autoscaling.TargetRequestRate = 0
+	autoscaling.ScaleOnCachePressure = true
+	assert.Equal(t, int32(2), desiredActiveRanks(autoscaling, 1, ranks, true))
+	assert.Equal(t, int32(1), desiredActiveRanks(autoscaling, 2, ranks, false))
+	assert.Equal(t, int32(1), desiredActiveRanks(autoscaling, 1, ranks, false))
+}
+
+func TestMDSCacheOversized(t *testing.T) {
+	healthDetail := `{"status":"HEALTH_WARN","checks":{"MDS_CACHE_OVERSIZED":{"severity":"HEALTH_WARN",
+		"summary":{"message":"1 MDSs report oversized cache"},
+		"detail":[{"message":"mds.otherfs-a(mds.0): MDS cache is too large (4GB/1GB); 0 inodes in use by clients, 0 stale inodes"}]}}}`
+	var health cephclient.HealthStatus
+	assert.NoError(t, json.Unmarshal([]byte(healthDetail), &health))
+	ranks := []cephv1.MDSRankStatus{{Rank: 0, Daemon: "myfs-a", State: "active", StandbyReplay: "myfs-c"}}
+
+	// the cache of a daemon of another filesystem is oversized
+	assert.False(t, mdsCacheOversized(health, ranks))
+	assert.True(t, mdsCacheOversized(health, []cephv1.MDSRankStatus{{Rank: 0, Daemon: "otherfs-a", State: "active"}}))
+
+	// the cache of a daemon of the filesystem is oversized
+	health.Checks[mdsCacheOversizedCheck].Detail[0].Message = "mds.myfs-c(mds.0): MDS cache is too large (4GB/1GB)"
+	assert.True(t, mdsCacheOversized(health, ranks))
+
+	// no health check
+	assert.False(t, mdsCacheOversized(cephclient.HealthStatus{}, ranks))
+}
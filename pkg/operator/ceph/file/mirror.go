@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileMirroring configures the snapshot mirroring of the filesystem, its peers and the mirrored directories
+func (r *ReconcileCephFilesystem) reconcileMirroring(cephFilesystem *cephv1.CephFilesystem, namespacedName types.NamespacedName) error {
+	mirroringEnabled := cephFilesystem.Spec.Mirroring != nil && cephFilesystem.Spec.Mirroring.Enabled
+	if !mirroringEnabled {
+		// Only disable the mirroring if we enabled it in the first place
+		if cephFilesystem.Status != nil && cephFilesystem.Status.MirroringStatus != nil {
+			if err := cephclient.DisableFilesystemSnapshotMirror(r.context, r.clusterInfo, cephFilesystem.Name); err != nil {
+				return errors.Wrapf(err, "failed to disable mirroring for filesystem %q", cephFilesystem.Name)
+			}
+			updateStatusMirroring(r.client, namespacedName, nil)
+		}
+		return nil
+	}
+
+	if !r.clusterInfo.CephVersion.IsAtLeastPacific() {
+		return errors.Errorf("ceph version %q does not support filesystem mirroring, pacific or newer is required", r.clusterInfo.CephVersion.String())
+	}
+
+	if err := cephclient.MgrEnableModule(r.context, r.clusterInfo, cephclient.FilesystemMirroringModule, false); err != nil {
+		return errors.Wrapf(err, "failed to enable mgr module %q", cephclient.FilesystemMirroringModule)
+	}
+	if err := cephclient.EnableFilesystemSnapshotMirror(r.context, r.clusterInfo, cephFilesystem.Name); err != nil {
+		return errors.Wrapf(err, "failed to enable mirroring for filesystem %q", cephFilesystem.Name)
+	}
+
+	var current *cephv1.FilesystemMirroringStatus
+	if cephFilesystem.Status != nil && cephFilesystem.Status.MirroringStatus != nil {
+		current = cephFilesystem.Status.MirroringStatus
+	} else {
+		current = &cephv1.FilesystemMirroringStatus{}
+	}
+
+	peers, err := r.reconcileMirroringPeers(cephFilesystem, current.Peers)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure mirroring peers for filesystem %q", cephFilesystem.Name)
+	}
+
+	directories, err := r.reconcileMirroredDirectories(cephFilesystem, current.Directories)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure mirrored directories for filesystem %q", cephFilesystem.Name)
+	}
+
+	updateStatusMirroring(r.client, namespacedName, &cephv1.FilesystemMirroringStatus{
+		Peers:       peers,
+		Directories: directories,
+		LastChecked: current.LastChecked,
+		Details:     current.Details,
+	})
+
+	return nil
+}
+
+// reconcileMirroringPeers imports the bootstrap peers listed in the spec and removes the ones that are not listed anymore
+func (r *ReconcileCephFilesystem) reconcileMirroringPeers(cephFilesystem *cephv1.CephFilesystem, currentPeers []cephv1.FilesystemMirrorPeerStatus) ([]cephv1.FilesystemMirrorPeerStatus, error) {
+	cephPeers, err := cephclient.ListFilesystemMirrorPeers(r.context, r.clusterInfo, cephFilesystem.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := []cephv1.FilesystemMirrorPeerStatus{}
+	for _, peerSecret := range cephFilesystem.Spec.Mirroring.Peers.SecretNames {
+		logger.Debugf("fetching bootstrap peer kubernetes secret %q", peerSecret)
+		s, err := r.context.Clientset.CoreV1().Secrets(r.clusterInfo.Namespace).Get(peerSecret, metav1.GetOptions{})
+		// We don't care about IsNotFound here, we still need to fail
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch kubernetes secret %q bootstrap peer", peerSecret)
+		}
+		token, ok := s.Data["token"]
+		if !ok || len(token) == 0 {
+			return nil, errors.Errorf("failed to lookup %q key in secret bootstrap peer %q (missing or empty)", "token", peerSecret)
+		}
+		peerToken, err := cephclient.DecodeFilesystemMirrorPeerToken(string(token))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read bootstrap peer secret %q", peerSecret)
+		}
+
+		// Importing the same token twice would fail, so only import it when the peer is unknown
+		uuid, peer := findFilesystemPeer(cephPeers, peerToken)
+		if peer == nil {
+			err = cephclient.ImportFilesystemMirrorBootstrapPeer(r.context, r.clusterInfo, cephFilesystem.Name, string(token))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to import bootstrap peer secret %q", peerSecret)
+			}
+			cephPeers, err = cephclient.ListFilesystemMirrorPeers(r.context, r.clusterInfo, cephFilesystem.Name)
+			if err != nil {
+				return nil, err
+			}
+			uuid, peer = findFilesystemPeer(cephPeers, peerToken)
+			if peer == nil {
+				return nil, errors.Errorf("failed to find peer %q after importing bootstrap peer secret %q", peerToken.SiteName, peerSecret)
+			}
+		}
+
+		peerStatus := cephv1.FilesystemMirrorPeerStatus{SecretName: peerSecret, UUID: uuid, SiteName: peer.SiteName, FSName: peer.FSName}
+		// Keep the health reported by the status checker
+		for _, p := range currentPeers {
+			if p.UUID == uuid {
+				peerStatus.FailureCount = p.FailureCount
+				peerStatus.Health = p.Health
+			}
+		}
+		peers = append(peers, peerStatus)
+	}
+
+	// Remove the peers that were added from a secret that is not listed anymore
+	for _, p := range currentPeers {
+		if isFilesystemPeerInList(peers, p.UUID) {
+			continue
+		}
+		if _, ok := cephPeers[p.UUID]; !ok {
+			continue
+		}
+		if err := cephclient.RemoveFilesystemMirrorPeer(r.context, r.clusterInfo, cephFilesystem.Name, p.UUID); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove peer added from secret %q", p.SecretName)
+		}
+	}
+
+	return peers, nil
+}
+
+// reconcileMirroredDirectories adds the directories listed in the spec and removes the ones that are not listed anymore
+func (r *ReconcileCephFilesystem) reconcileMirroredDirectories(cephFilesystem *cephv1.CephFilesystem, currentDirectories []cephv1.FilesystemMirrorDirectoryStatus) ([]cephv1.FilesystemMirrorDirectoryStatus, error) {
+	directories := []cephv1.FilesystemMirrorDirectoryStatus{}
+	for _, path := range cephFilesystem.Spec.Mirroring.Directories {
+		if err := cephclient.AddFilesystemMirrorDirectory(r.context, r.clusterInfo, cephFilesystem.Name, path); err != nil {
+			return nil, err
+		}
+		directory := cephv1.FilesystemMirrorDirectoryStatus{Path: path}
+		// Keep the sync status reported by the status checker
+		for _, d := range currentDirectories {
+			if d.Path == path {
+				directory = d
+			}
+		}
+		directories = append(directories, directory)
+	}
+
+	for _, d := range currentDirectories {
+		if isDirectoryInList(cephFilesystem.Spec.Mirroring.Directories, d.Path) {
+			continue
+		}
+		if err := cephclient.RemoveFilesystemMirrorDirectory(r.context, r.clusterInfo, cephFilesystem.Name, d.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	return directories, nil
+}
+
+// findFilesystemPeer returns the peer matching the site and the filesystem of a bootstrap token
+func findFilesystemPeer(peers map[string]cephclient.FilesystemMirrorPeer, token *cephclient.FilesystemMirrorPeerToken) (string, *cephclient.FilesystemMirrorPeer) {
+	for uuid, peer := range peers {
+		if peer.SiteName == token.SiteName && peer.FSName == token.Filesystem {
+			p := peer
+			return uuid, &p
+		}
+	}
+	return "", nil
+}
+
+func isFilesystemPeerInList(peers []cephv1.FilesystemMirrorPeerStatus, uuid string) bool {
+	for _, p := range peers {
+		if p.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+func isDirectoryInList(directories []string, path string) bool {
+	for _, d := range directories {
+		if d == path {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStatusMirroring updates the filesystem mirroring status
+func updateStatusMirroring(client client.Client, name types.NamespacedName, mirroringStatus *cephv1.FilesystemMirroringStatus) {
+	fs := &cephv1.CephFilesystem{}
+	err := client.Get(context.TODO(), name, fs)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to update mirroring status. %v", name, err)
+		return
+	}
+
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	fs.Status.MirroringStatus = mirroringStatus
+	if err := opcontroller.UpdateStatus(client, fs); err != nil {
+		logger.Errorf("failed to set filesystem %q mirroring status. %v", fs.Name, err)
+		return
+	}
+	logger.Debugf("filesystem %q mirroring status updated", name)
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The retention periods of the snap_schedule module, the other ones (minutely, monthly...) are not exposed
+const (
+	retentionHourly = "h"
+	retentionDaily  = "d"
+	retentionWeekly = "w"
+)
+
+// reconcileSnapshotSchedules configures the snapshot schedules and the retention of the filesystem directories
+func (r *ReconcileCephFilesystem) reconcileSnapshotSchedules(cephFilesystem *cephv1.CephFilesystem, namespacedName types.NamespacedName) error {
+	var currentSchedules []cephv1.FilesystemSnapshotSchedulesSpec
+	if cephFilesystem.Status != nil && cephFilesystem.Status.SnapshotScheduleStatus != nil {
+		currentSchedules = cephFilesystem.Status.SnapshotScheduleStatus.SnapshotSchedules
+	}
+	// Nothing was ever configured, don't touch the schedules that may have been added manually
+	if len(cephFilesystem.Spec.SnapshotSchedules) == 0 && (cephFilesystem.Status == nil || cephFilesystem.Status.SnapshotScheduleStatus == nil) {
+		return nil
+	}
+
+	if len(cephFilesystem.Spec.SnapshotSchedules) > 0 {
+		if !r.clusterInfo.CephVersion.IsAtLeastOctopus() {
+			return errors.Errorf("ceph version %q does not support filesystem snapshot schedules, octopus or newer is required", r.clusterInfo.CephVersion.String())
+		}
+		if err := cephclient.MgrEnableModule(r.context, r.clusterInfo, cephclient.SnapshotScheduleModule, false); err != nil {
+			return errors.Wrapf(err, "failed to enable mgr module %q", cephclient.SnapshotScheduleModule)
+		}
+	}
+
+	paths, desired := snapshotSchedulesByPath(cephFilesystem.Spec.SnapshotSchedules)
+	statuses := []cephv1.FilesystemSnapshotSchedulesSpec{}
+	for _, path := range paths {
+		schedules, err := r.reconcileDirectorySnapshotSchedules(cephFilesystem.Name, path, desired[path])
+		if err != nil {
+			updateStatusSnapshotSchedules(r.client, namespacedName, currentSchedules, err.Error())
+			return errors.Wrapf(err, "failed to configure snapshot schedules of path %q", path)
+		}
+		statuses = append(statuses, snapshotSchedulesStatus(schedules)...)
+	}
+
+	// Remove the schedules of the directories that are not listed anymore
+	removed := map[string]bool{}
+	for _, s := range currentSchedules {
+		if _, ok := desired[s.Path]; ok || removed[s.Path] {
+			continue
+		}
+		if err := r.removeDirectorySnapshotSchedules(cephFilesystem.Name, s.Path); err != nil {
+			return errors.Wrapf(err, "failed to remove snapshot schedules of path %q", s.Path)
+		}
+		removed[s.Path] = true
+	}
+
+	if len(cephFilesystem.Spec.SnapshotSchedules) == 0 {
+		updateStatusSnapshotSchedules(r.client, namespacedName, nil, "")
+		return nil
+	}
+	updateStatusSnapshotSchedules(r.client, namespacedName, statuses, "")
+
+	return nil
+}
+
+// reconcileDirectorySnapshotSchedules adds the missing schedules of a directory, removes the ones that are not desired
+// anymore and applies the retention policy. It returns the resulting schedules of the directory.
+func (r *ReconcileCephFilesystem) reconcileDirectorySnapshotSchedules(fsName, path string, desired []cephv1.FilesystemSnapshotScheduleSpec) ([]cephclient.FilesystemSnapshotSchedule, error) {
+	current, err := cephclient.GetFilesystemSnapshotSchedules(r.context, r.clusterInfo, fsName, path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range desired {
+		if findSnapshotSchedule(current, d.Interval, d.StartTime) != nil {
+			continue
+		}
+		if err := cephclient.AddFilesystemSnapshotSchedule(r.context, r.clusterInfo, fsName, path, d.Interval, d.StartTime); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range current {
+		if isSnapshotScheduleDesired(desired, c) {
+			continue
+		}
+		if err := cephclient.RemoveFilesystemSnapshotSchedule(r.context, r.clusterInfo, fsName, path, c.Schedule, c.Start); err != nil {
+			return nil, err
+		}
+	}
+
+	// The retention is a setting of the directory, it is reported with each of its schedules
+	current, err = cephclient.GetFilesystemSnapshotSchedules(r.context, r.clusterInfo, fsName, path)
+	if err != nil {
+		return nil, err
+	}
+	currentRetention := map[string]int{}
+	if len(current) > 0 && current[0].Retention != nil {
+		currentRetention = current[0].Retention
+	}
+	retention := desiredRetention(desired)
+	changed := false
+	for _, period := range []string{retentionHourly, retentionDaily, retentionWeekly} {
+		count := retentionCount(retention, period)
+		if currentRetention[period] == count {
+			continue
+		}
+		changed = true
+		if currentRetention[period] > 0 {
+			if err := cephclient.RemoveFilesystemSnapshotRetention(r.context, r.clusterInfo, fsName, path, period, currentRetention[period]); err != nil {
+				return nil, err
+			}
+		}
+		if count > 0 {
+			if err := cephclient.AddFilesystemSnapshotRetention(r.context, r.clusterInfo, fsName, path, period, count); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !changed {
+		return current, nil
+	}
+
+	return cephclient.GetFilesystemSnapshotSchedules(r.context, r.clusterInfo, fsName, path)
+}
+
+// removeDirectorySnapshotSchedules removes the retention policy and the schedules of a directory
+func (r *ReconcileCephFilesystem) removeDirectorySnapshotSchedules(fsName, path string) error {
+	current, err := cephclient.GetFilesystemSnapshotSchedules(r.context, r.clusterInfo, fsName, path)
+	if err != nil {
+		return err
+	}
+	if len(current) == 0 {
+		return nil
+	}
+
+	// The retention is a setting of the directory, it is reported with each of its schedules
+	for _, period := range []string{retentionHourly, retentionDaily, retentionWeekly} {
+		count := current[0].Retention[period]
+		if count == 0 {
+			continue
+		}
+		if err := cephclient.RemoveFilesystemSnapshotRetention(r.context, r.clusterInfo, fsName, path, period, count); err != nil {
+			return err
+		}
+	}
+	return cephclient.RemoveFilesystemSnapshotSchedule(r.context, r.clusterInfo, fsName, path, "", "")
+}
+
+// checkSnapshotSchedules refreshes the state of the snapshot schedules of the filesystem
+func (c *statusChecker) checkSnapshotSchedules(fs *cephv1.CephFilesystem) error {
+	paths, _ := snapshotSchedulesByPath(fs.Spec.SnapshotSchedules)
+	statuses := []cephv1.FilesystemSnapshotSchedulesSpec{}
+	var errs []string
+	for _, path := range paths {
+		schedules, err := cephclient.GetFilesystemSnapshotSchedules(c.context, c.clusterInfo, fs.Name, path)
+		if err != nil {
+			errs = append(errs, err.Error())
+			// keep the last known state of the directory schedules
+			for _, s := range fs.Status.SnapshotScheduleStatus.SnapshotSchedules {
+				if s.Path == path {
+					statuses = append(statuses, s)
+				}
+			}
+			continue
+		}
+		statuses = append(statuses, snapshotSchedulesStatus(schedules)...)
+	}
+
+	details := strings.Join(errs, ". ")
+	updateStatusSnapshotSchedules(c.client, c.namespacedName, statuses, details)
+	if details != "" {
+		return errors.New(details)
+	}
+
+	return nil
+}
+
+// snapshotSchedulesByPath groups the schedules of the spec per directory, the paths are returned in the spec order
+func snapshotSchedulesByPath(schedules []cephv1.FilesystemSnapshotScheduleSpec) ([]string, map[string][]cephv1.FilesystemSnapshotScheduleSpec) {
+	paths := []string{}
+	byPath := map[string][]cephv1.FilesystemSnapshotScheduleSpec{}
+	for _, s := range schedules {
+		if _, ok := byPath[s.Path]; !ok {
+			paths = append(paths, s.Path)
+		}
+		byPath[s.Path] = append(byPath[s.Path], s)
+	}
+	return paths, byPath
+}
+
+// findSnapshotSchedule returns the schedule with the given interval, an empty start time matches any start time
+func findSnapshotSchedule(schedules []cephclient.FilesystemSnapshotSchedule, interval, startTime string) *cephclient.FilesystemSnapshotSchedule {
+	for i := range schedules {
+		if cephclient.SameFilesystemSnapshotSchedule(schedules[i], interval, startTime) {
+			return &schedules[i]
+		}
+	}
+	return nil
+}
+
+func isSnapshotScheduleDesired(desired []cephv1.FilesystemSnapshotScheduleSpec, schedule cephclient.FilesystemSnapshotSchedule) bool {
+	for _, d := range desired {
+		if cephclient.SameFilesystemSnapshotSchedule(schedule, d.Interval, d.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// desiredRetention returns the retention of a directory, validation ensures the schedules of a directory don't conflict
+func desiredRetention(schedules []cephv1.FilesystemSnapshotScheduleSpec) cephv1.FilesystemSnapshotRetentionSpec {
+	for _, s := range schedules {
+		if s.Retention != (cephv1.FilesystemSnapshotRetentionSpec{}) {
+			return s.Retention
+		}
+	}
+	return cephv1.FilesystemSnapshotRetentionSpec{}
+}
+
+func retentionCount(retention cephv1.FilesystemSnapshotRetentionSpec, period string) int {
+	switch period {
+	case retentionHourly:
+		return retention.Hourly
+	case retentionDaily:
+		return retention.Daily
+	case retentionWeekly:
+		return retention.Weekly
+	}
+	return 0
+}
+
+// snapshotSchedulesStatus converts the schedules reported by ceph to their status representation
+func snapshotSchedulesStatus(schedules []cephclient.FilesystemSnapshotSchedule) []cephv1.FilesystemSnapshotSchedulesSpec {
+	statuses := []cephv1.FilesystemSnapshotSchedulesSpec{}
+	for _, s := range schedules {
+		statuses = append(statuses, cephv1.FilesystemSnapshotSchedulesSpec{
+			Path:      s.Path,
+			Interval:  s.Schedule,
+			StartTime: s.Start,
+			Retention: cephv1.FilesystemSnapshotRetentionSpec{
+				Hourly: s.Retention[retentionHourly],
+				Daily:  s.Retention[retentionDaily],
+				Weekly: s.Retention[retentionWeekly],
+			},
+			Active:           s.Active,
+			LastSnapshot:     s.Last,
+			SnapshotsCreated: s.CreatedCount,
+			SnapshotsPruned:  s.PrunedCount,
+		})
+	}
+	return statuses
+}
+
+// updateStatusSnapshotSchedules updates the filesystem snapshot schedules status
+// nil schedules and empty details remove the snapshot schedules status
+func updateStatusSnapshotSchedules(client client.Client, name types.NamespacedName, schedules []cephv1.FilesystemSnapshotSchedulesSpec, details string) {
+	fs := &cephv1.CephFilesystem{}
+	err := client.Get(context.TODO(), name, fs)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve filesystem %q to update snapshot schedules status. %v", name, err)
+		return
+	}
+
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	if schedules == nil && details == "" {
+		fs.Status.SnapshotScheduleStatus = nil
+	} else {
+		fs.Status.SnapshotScheduleStatus = &cephv1.FilesystemSnapshotScheduleStatusSpec{
+			SnapshotSchedules: schedules,
+			LastChecked:       time.Now().UTC().Format(time.RFC3339),
+			Details:           details,
+		}
+	}
+	if err := opcontroller.UpdateStatus(client, fs); err != nil {
+		logger.Errorf("failed to set filesystem %q snapshot schedules status. %v", fs.Name, err)
+		return
+	}
+	logger.Debugf("filesystem %q snapshot schedules status updated", name)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotSchedulesByPath(t *testing.T) {
+	paths, byPath := snapshotSchedulesByPath([]cephv1.FilesystemSnapshotScheduleSpec{
+		{Path: "/volumes", Interval: "1h"},
+		{Path: "/", Interval: "1d"},
+		{Path: "/volumes", Interval: "1w", Retention: cephv1.FilesystemSnapshotRetentionSpec{Weekly: 4}},
+	})
+	assert.Equal(t, []string{"/volumes", "/"}, paths)
+	assert.Equal(t, 2, len(byPath["/volumes"]))
+	assert.Equal(t, 4, desiredRetention(byPath["/volumes"]).Weekly)
+	assert.Equal(t, cephv1.FilesystemSnapshotRetentionSpec{}, desiredRetention(byPath["/"]))
+}
+
+func TestFindSnapshotSchedule(t *testing.T) {
+	schedules := []cephclient.FilesystemSnapshotSchedule{
+		{Path: "/volumes", Schedule: "1h", Start: "2020-11-11T00:00:00"},
+	}
+	assert.NotNil(t, findSnapshotSchedule(schedules, "1h", ""))
+	assert.NotNil(t, findSnapshotSchedule(schedules, "1h", "2020-11-11T00:00:00"))
+	assert.Nil(t, findSnapshotSchedule(schedules, "1h", "2020-12-01T00:00:00"))
+	assert.Nil(t, findSnapshotSchedule(schedules, "1d", ""))
+	// the equivalent intervals and start times are the same schedule
+	assert.NotNil(t, findSnapshotSchedule(schedules, "01h", "2020-11-11T00:00"))
+	assert.NotNil(t, findSnapshotSchedule(schedules, "1h", "2020-11-11T01:00:00+01:00"))
+	schedules[0].Schedule = "24h"
+	assert.NotNil(t, findSnapshotSchedule(schedules, "1d", ""))
+	schedules[0].Schedule = "1h"
+
+	desired := []cephv1.FilesystemSnapshotScheduleSpec{{Path: "/volumes", Interval: "1h"}}
+	assert.True(t, isSnapshotScheduleDesired(desired, schedules[0]))
+	desired[0].Interval = "2h"
+	assert.False(t, isSnapshotScheduleDesired(desired, schedules[0]))
+	desired[0].Interval = "60h"
+	assert.False(t, isSnapshotScheduleDesired(desired, schedules[0]))
+}
+
+func TestRemoveDirectorySnapshotSchedules(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfileArg string, args ...string) (string, error) {
+			if args[2] == "status" {
+				return `[{"fs":"myfs","path":"/volumes","schedule":"1h","retention":{"h":24,"w":4}}]`, nil
+			}
+			commands = append(commands, strings.Join(args[2:6], " "))
+			return "", nil
+		},
+	}
+	r := &ReconcileCephFilesystem{context: &clusterd.Context{Executor: executor}, clusterInfo: cephclient.AdminClusterInfo("mycluster")}
+
+	// the retention of the directory is removed with its schedules
+	err := r.removeDirectorySnapshotSchedules("myfs", "/volumes")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retention remove /volumes h", "retention remove /volumes w", "remove /volumes --fs myfs"}, commands)
+}
+
+func TestSnapshotSchedulesStatus(t *testing.T) {
+	statuses := snapshotSchedulesStatus([]cephclient.FilesystemSnapshotSchedule{
+		{
+			Path:         "/volumes",
+			Schedule:     "1h",
+			Start:        "2020-11-11T00:00:00",
+			Retention:    map[string]int{"h": 24, "w": 4},
+			Last:         "2020-11-12T10:00:00",
+			CreatedCount: 34,
+			PrunedCount:  10,
+			Active:       true,
+		},
+	})
+	assert.Equal(t, 1, len(statuses))
+	assert.Equal(t, "1h", statuses[0].Interval)
+	assert.Equal(t, "2020-11-11T00:00:00", statuses[0].StartTime)
+	assert.Equal(t, cephv1.FilesystemSnapshotRetentionSpec{Hourly: 24, Weekly: 4}, statuses[0].Retention)
+	assert.Equal(t, "2020-11-12T10:00:00", statuses[0].LastSnapshot)
+	assert.Equal(t, 34, statuses[0].SnapshotsCreated)
+	assert.Equal(t, 10, statuses[0].SnapshotsPruned)
+	assert.True(t, statuses[0].Active)
+}
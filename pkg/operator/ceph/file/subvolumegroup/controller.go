@@ -0,0 +1,377 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package subvolumegroup manages the subvolume groups of a CephFS filesystem
+package subvolumegroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-filesystem-subvolumegroup-controller"
+	// usageRefreshInterval is how often the usage of the group is refreshed in the status
+	usageRefreshInterval = 1 * time.Minute
+	// clusterIDKey is the status info key of the clusterID to set in the StorageClasses
+	clusterIDKey = "clusterID"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var modeRegex = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+var cephFilesystemSubVolumeGroupKind = reflect.TypeOf(cephv1.CephFilesystemSubVolumeGroup{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephFilesystemSubVolumeGroupKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephFilesystemSubVolumeGroup reconciles a CephFilesystemSubVolumeGroup object
+type ReconcileCephFilesystemSubVolumeGroup struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+	// csiConfigMutex serializes the updates of the csi config map by the subvolume groups,
+	// concurrent updates with the cluster controller fail on conflict and are retried
+	csiConfigMutex *sync.Mutex
+}
+
+// Add creates a new CephFilesystemSubVolumeGroup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+	return &ReconcileCephFilesystemSubVolumeGroup{
+		client:         mgr.GetClient(),
+		scheme:         mgrScheme,
+		context:        context,
+		csiConfigMutex: &sync.Mutex{},
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes on the CephFilesystemSubVolumeGroup CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephFilesystemSubVolumeGroup{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephFilesystemSubVolumeGroup object and makes changes based on the state read
+// and what is in the CephFilesystemSubVolumeGroup.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephFilesystemSubVolumeGroup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, nil, nil)
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephFilesystemSubVolumeGroup instance
+	subVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, subVolumeGroup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("cephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephFilesystemSubVolumeGroup")
+	}
+
+	// The CR was just created, initializing status fields
+	if subVolumeGroup.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the deletion of the group since everything is gone already
+		if !subVolumeGroup.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err := opcontroller.RemoveFinalizer(r.client, subVolumeGroup)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		logger.Debugf("CephCluster resource not ready in namespace %q, retrying in %q.", request.NamespacedName.Namespace, reconcileResponse.RequeueAfter.String())
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo
+	// Always populate it during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// Populate CephVersion
+	currentCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, r.clusterInfo, opconfig.MonType)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to retrieve current ceph %q version", opconfig.MonType)
+	}
+	r.clusterInfo.CephVersion = currentCephVersion
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, subVolumeGroup)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !subVolumeGroup.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting subvolume group %q", request.NamespacedName)
+		err := cephclient.DeleteFilesystemSubVolumeGroup(r.context, r.clusterInfo, subVolumeGroup.Spec.FilesystemName, subVolumeGroup.Name)
+		if err != nil {
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, errors.Wrapf(err, "failed to delete subvolume group %q", request.NamespacedName)
+		}
+
+		err = csi.RemoveClusterConfig(r.context.Clientset, buildClusterID(subVolumeGroup), r.csiConfigMutex)
+		if err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove subvolume group from the csi config map")
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, subVolumeGroup)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the subvolume group settings
+	if err := validateSubVolumeGroup(subVolumeGroup); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "invalid subvolume group %q arguments", request.NamespacedName)
+	}
+
+	// The subvolume group commands exist since Pacific
+	if !r.clusterInfo.CephVersion.IsAtLeastPacific() {
+		return reconcile.Result{}, errors.Errorf("ceph version %q does not support subvolume groups management, pacific or newer is required", r.clusterInfo.CephVersion.String())
+	}
+
+	// Make sure the filesystem exists
+	cephFilesystem := &cephv1.CephFilesystem{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: subVolumeGroup.Spec.FilesystemName, Namespace: subVolumeGroup.Namespace}, cephFilesystem)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Infof("filesystem %q of subvolume group %q not found, retrying", subVolumeGroup.Spec.FilesystemName, request.NamespacedName)
+			return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+		}
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to get filesystem %q", subVolumeGroup.Spec.FilesystemName)
+	}
+	if cephFilesystem.Status == nil || cephFilesystem.Status.Phase != k8sutil.ReadyStatus {
+		logger.Infof("filesystem %q of subvolume group %q is not ready, retrying", subVolumeGroup.Spec.FilesystemName, request.NamespacedName)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// CREATE/UPDATE
+	logger.Debug("reconciling ceph filesystem subvolume group")
+	if err := r.reconcileSubVolumeGroup(subVolumeGroup); err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to configure subvolume group %q", request.NamespacedName)
+	}
+
+	clusterID := buildClusterID(subVolumeGroup)
+	err = csi.SaveSubVolumeGroupConfig(r.context.Clientset, subVolumeGroup.Namespace, clusterID, subVolumeGroup.Name, r.clusterInfo, r.csiConfigMutex)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to save subvolume group in the csi config map")
+	}
+
+	// Set Ready status with the usage of the group, we are done reconciling
+	usage := r.subVolumeGroupUsage(subVolumeGroup)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, map[string]string{clusterIDKey: clusterID}, usage)
+
+	// Requeue to refresh the usage of the group
+	logger.Debug("done reconciling ceph filesystem subvolume group")
+	return reconcile.Result{RequeueAfter: usageRefreshInterval}, nil
+}
+
+// reconcileSubVolumeGroup creates the group and applies its quota and pinning
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcileSubVolumeGroup(subVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) error {
+	fsName := subVolumeGroup.Spec.FilesystemName
+	quota := quotaBytes(subVolumeGroup.Spec)
+
+	// Creating an existing group is a no-op, the pool layout and the mode are only applied on creation
+	err := cephclient.CreateFilesystemSubVolumeGroup(r.context, r.clusterInfo, fsName, subVolumeGroup.Name, subVolumeGroup.Spec.DataPoolName, subVolumeGroup.Spec.Mode)
+	if err != nil {
+		return err
+	}
+
+	// Apply quota changes to an existing group, only remove the quota if we set one in the first place
+	previousQuota := uint64(0)
+	if subVolumeGroup.Status != nil && subVolumeGroup.Status.Usage != nil {
+		previousQuota = subVolumeGroup.Status.Usage.BytesQuota
+	}
+	if quota > 0 && quota != previousQuota || quota == 0 && previousQuota > 0 {
+		if err := cephclient.ResizeFilesystemSubVolumeGroup(r.context, r.clusterInfo, fsName, subVolumeGroup.Name, quota); err != nil {
+			return err
+		}
+	}
+
+	if subVolumeGroup.Spec.ExportPin != nil {
+		if err := cephclient.PinFilesystemSubVolumeGroup(r.context, r.clusterInfo, fsName, subVolumeGroup.Name, *subVolumeGroup.Spec.ExportPin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// subVolumeGroupUsage returns the usage of the group, the error to fetch it is reported in the usage details
+func (r *ReconcileCephFilesystemSubVolumeGroup) subVolumeGroupUsage(subVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) *cephv1.SubVolumeGroupUsageStatus {
+	info, err := cephclient.GetFilesystemSubVolumeGroupInfo(r.context, r.clusterInfo, subVolumeGroup.Spec.FilesystemName, subVolumeGroup.Name)
+	if err != nil {
+		usage := &cephv1.SubVolumeGroupUsageStatus{}
+		if subVolumeGroup.Status != nil && subVolumeGroup.Status.Usage != nil {
+			usage = subVolumeGroup.Status.Usage.DeepCopy()
+		}
+		usage.LastChecked = time.Now().UTC().Format(time.RFC3339)
+		usage.Details = err.Error()
+		return usage
+	}
+
+	return usageStatus(info)
+}
+
+// usageStatus converts the group info reported by ceph to its status representation
+func usageStatus(info *cephclient.SubVolumeGroupInfo) *cephv1.SubVolumeGroupUsageStatus {
+	return &cephv1.SubVolumeGroupUsageStatus{
+		BytesUsed:   info.BytesUsed,
+		BytesQuota:  info.Quota(),
+		UsedPercent: info.BytesPercent,
+		DataPool:    info.DataPool,
+		// only keep the permission bits of the directory mode
+		Mode:        fmt.Sprintf("%04o", info.Mode&0777),
+		LastChecked: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func quotaBytes(spec cephv1.CephFilesystemSubVolumeGroupSpec) uint64 {
+	if spec.Quota == nil || spec.Quota.Value() <= 0 {
+		return 0
+	}
+	return uint64(spec.Quota.Value())
+}
+
+// buildClusterID returns the clusterID of the group in the csi config, it is unique per group
+func buildClusterID(subVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) string {
+	return k8sutil.Hash(fmt.Sprintf("%s-%s-file-%s", subVolumeGroup.Namespace, subVolumeGroup.Spec.FilesystemName, subVolumeGroup.Name))
+}
+
+func validateSubVolumeGroup(subVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) error {
+	if subVolumeGroup.Spec.FilesystemName == "" {
+		return errors.New("missing filesystemName")
+	}
+	if subVolumeGroup.Spec.Quota != nil && subVolumeGroup.Spec.Quota.Sign() < 0 {
+		return errors.Errorf("invalid quota %q, it must not be negative", subVolumeGroup.Spec.Quota.String())
+	}
+	if subVolumeGroup.Spec.Mode != "" && !modeRegex.MatchString(subVolumeGroup.Spec.Mode) {
+		return errors.Errorf("invalid mode %q, it must be an octal permission mode like %q", subVolumeGroup.Spec.Mode, "0755")
+	}
+	if subVolumeGroup.Spec.ExportPin != nil && *subVolumeGroup.Spec.ExportPin < -1 {
+		return errors.Errorf("invalid export pin %s, it must be an mds rank or -1", strconv.Itoa(*subVolumeGroup.Spec.ExportPin))
+	}
+	return nil
+}
+
+// updateStatus updates an object with a given status, nil info and usage keep the current ones
+func updateStatus(client client.Client, name types.NamespacedName, status string, info map[string]string, usage *cephv1.SubVolumeGroupUsageStatus) {
+	subVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
+	err := client.Get(context.TODO(), name, subVolumeGroup)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystemSubVolumeGroup resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve subvolume group %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if subVolumeGroup.Status == nil {
+		subVolumeGroup.Status = &cephv1.CephFilesystemSubVolumeGroupStatus{}
+	}
+
+	subVolumeGroup.Status.Phase = status
+	if info != nil {
+		subVolumeGroup.Status.Info = info
+	}
+	if usage != nil {
+		subVolumeGroup.Status.Usage = usage
+	}
+	if err := opcontroller.UpdateStatus(client, subVolumeGroup); err != nil {
+		logger.Errorf("failed to set subvolume group %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("subvolume group %q status updated to %q", name, status)
+}
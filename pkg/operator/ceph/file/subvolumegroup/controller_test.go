@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subvolumegroup
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephFilesystemSubVolumeGroupController(t *testing.T) {
+	var (
+		name      = "team-a"
+		namespace = "rook-ceph"
+	)
+	quota := resource.MustParse("10Gi")
+	pin := 1
+	subVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cephv1.CephFilesystemSubVolumeGroupSpec{
+			FilesystemName: "myfs",
+			Quota:          &quota,
+			Mode:           "0750",
+			ExportPin:      &pin,
+		},
+		TypeMeta: controllerTypeMeta,
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+		Status: cephv1.ClusterStatus{
+			Phase: k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{
+				Health: "HEALTH_OK",
+			},
+		},
+	}
+	cephFilesystem := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myfs",
+			Namespace: namespace,
+		},
+		Status: &cephv1.CephFilesystemStatus{Phase: k8sutil.ReadyStatus},
+	}
+
+	commands := map[string][]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "versions" {
+				return `{"mon":{"ceph version 16.0.0-5959-g8d7ab4d (8d7ab4d4f9e3f0f4b6c0cce2ecb8c55a2a7b2c29) pacific (dev)":3}}`, nil
+			}
+			if args[0] == "fs" && args[1] == "subvolumegroup" {
+				commands[args[2]] = args
+				if args[2] == "info" {
+					return `{"bytes_pcent": "10.00", "bytes_quota": 10737418240, "bytes_used": 1073741824, "data_pool": "myfs-data0", "gid": 0, "mode": 16872, "uid": 0}`, nil
+				}
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     test.New(t, 3),
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"fsid":         []byte(name),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephFilesystem{}, &cephv1.CephFilesystemSubVolumeGroup{})
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// The filesystem does not exist yet, the request is requeued
+	object := []runtime.Object{subVolumeGroup, cephCluster}
+	cl := fake.NewFakeClientWithScheme(s, object...)
+	r := &ReconcileCephFilesystemSubVolumeGroup{client: cl, scheme: s, context: c}
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Empty(t, commands)
+
+	// The group is created with its quota, mode and pinning
+	object = append(object, cephFilesystem)
+	cl = fake.NewFakeClientWithScheme(s, object...)
+	r = &ReconcileCephFilesystemSubVolumeGroup{client: cl, scheme: s, context: c}
+	res, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, usageRefreshInterval, res.RequeueAfter)
+	assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "team-a", "--mode", "0750"}, commands["create"][:7])
+	assert.Equal(t, []string{"fs", "subvolumegroup", "pin", "myfs", "team-a", "export", "1"}, commands["pin"][:7])
+	assert.Equal(t, "10737418240", commands["resize"][5])
+
+	err = r.client.Get(context.TODO(), req.NamespacedName, subVolumeGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, subVolumeGroup.Status.Phase)
+	assert.Equal(t, buildClusterID(subVolumeGroup), subVolumeGroup.Status.Info[clusterIDKey])
+	assert.Equal(t, uint64(1073741824), subVolumeGroup.Status.Usage.BytesUsed)
+	assert.Equal(t, uint64(10737418240), subVolumeGroup.Status.Usage.BytesQuota)
+	assert.Equal(t, "0750", subVolumeGroup.Status.Usage.Mode)
+
+	// The quota is unchanged, no resize
+	delete(commands, "resize")
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.NotContains(t, commands, "resize")
+}
+
+func TestValidateSubVolumeGroup(t *testing.T) {
+	subVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
+	assert.Error(t, validateSubVolumeGroup(subVolumeGroup))
+
+	subVolumeGroup.Spec.FilesystemName = "myfs"
+	assert.NoError(t, validateSubVolumeGroup(subVolumeGroup))
+
+	subVolumeGroup.Spec.Mode = "rwx"
+	assert.Error(t, validateSubVolumeGroup(subVolumeGroup))
+	subVolumeGroup.Spec.Mode = "755"
+	assert.NoError(t, validateSubVolumeGroup(subVolumeGroup))
+
+	pin := -2
+	subVolumeGroup.Spec.ExportPin = &pin
+	assert.Error(t, validateSubVolumeGroup(subVolumeGroup))
+	pin = -1
+	assert.NoError(t, validateSubVolumeGroup(subVolumeGroup))
+
+	quota := resource.MustParse("-1Gi")
+	subVolumeGroup.Spec.Quota = &quota
+	assert.Error(t, validateSubVolumeGroup(subVolumeGroup))
+}
@@ -47,18 +47,25 @@ func getNFSNodeID(n *cephv1.CephNFS, name string) string {
 	return fmt.Sprintf("%s.%s", n.Name, name)
 }
 
-func getGaneshaConfigObject(clusterName string) string {
+// GetGaneshaConfigObject returns the name of the RADOS object included in the config of the servers, it holds the
+// exports of the servers
+func GetGaneshaConfigObject(clusterName string) string {
 	return fmt.Sprintf("conf-nfs.%s", clusterName)
 }
 
 func getRadosURL(n *cephv1.CephNFS) string {
+	return GetRadosObjectURL(n, GetGaneshaConfigObject(n.Name))
+}
+
+// GetRadosObjectURL returns the URL of an object in the RADOS pool and namespace of the servers
+func GetRadosObjectURL(n *cephv1.CephNFS, object string) string {
 	url := fmt.Sprintf("rados://%s/", n.Spec.RADOS.Pool)
 
 	if n.Spec.RADOS.Namespace != "" {
 		url += n.Spec.RADOS.Namespace + "/"
 	}
 
-	url += getGaneshaConfigObject(n.Name)
+	url += object
 	return url
 }
 
@@ -0,0 +1,360 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export manages the exports of the NFS ganesha servers stored in their RADOS config object
+package export
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-nfs-export-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephNFSExportKind = reflect.TypeOf(cephv1.CephNFSExport{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephNFSExportKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephNFSExport reconciles a CephNFSExport object
+type ReconcileCephNFSExport struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+}
+
+// Add creates a new CephNFSExport Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+	return &ReconcileCephNFSExport{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller, the exports of a server share its config object so they are reconciled one at a time
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes on the CephNFSExport CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephNFSExport{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephNFSExport object and makes changes based on the state read
+// and what is in the CephNFSExport.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephNFSExport) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", "")
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephNFSExport) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephNFSExport instance
+	export := &cephv1.CephNFSExport{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, export)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("cephNFSExport resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephNFSExport")
+	}
+
+	// The CR was just created, initializing status fields
+	if export.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, "", "")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the removal of the export since everything is gone already
+		if !export.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err := opcontroller.RemoveFinalizer(r.client, export)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		logger.Debugf("CephCluster resource not ready in namespace %q, retrying in %q.", request.NamespacedName.Namespace, reconcileResponse.RequeueAfter.String())
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo
+	// Always populate it during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, export)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// DELETE: the CR was deleted
+	if !export.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting nfs export %q", request.NamespacedName)
+		if err := r.deleteExport(export); err != nil {
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, errors.Wrapf(err, "failed to delete nfs export %q", request.NamespacedName)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, export)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the export settings
+	if err := validateExport(export); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "invalid nfs export %q arguments", request.NamespacedName)
+	}
+	if err := r.validateUniqueExport(export); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "invalid nfs export %q arguments", request.NamespacedName)
+	}
+
+	// Make sure the servers and the filesystem are ready
+	cephNFS := &cephv1.CephNFS{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: export.Spec.NFSName, Namespace: export.Namespace}, cephNFS)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Infof("nfs %q of export %q not found, retrying", export.Spec.NFSName, request.NamespacedName)
+			return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+		}
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to get nfs %q", export.Spec.NFSName)
+	}
+	if cephNFS.Status == nil || cephNFS.Status.Phase != k8sutil.ReadyStatus {
+		logger.Infof("nfs %q of export %q is not ready, retrying", export.Spec.NFSName, request.NamespacedName)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+	cephFilesystem := &cephv1.CephFilesystem{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: export.Spec.FilesystemName, Namespace: export.Namespace}, cephFilesystem)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Infof("filesystem %q of export %q not found, retrying", export.Spec.FilesystemName, request.NamespacedName)
+			return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+		}
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to get filesystem %q", export.Spec.FilesystemName)
+	}
+	if cephFilesystem.Status == nil || cephFilesystem.Status.Phase != k8sutil.ReadyStatus {
+		logger.Infof("filesystem %q of export %q is not ready, retrying", export.Spec.FilesystemName, request.NamespacedName)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// CREATE/UPDATE
+	logger.Debug("reconciling ceph nfs export")
+	path, err := r.exportPath(export)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+	if err := r.reconcileExport(cephNFS, export, path); err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to configure nfs export %q", request.NamespacedName)
+	}
+
+	// The user of the export was renamed, the previous one is not used anymore
+	user := exportUser(export)
+	if export.Status != nil && export.Status.User != "" && export.Status.User != user {
+		if err := cephclient.AuthDelete(r.context, r.clusterInfo, fmt.Sprintf("client.%s", export.Status.User)); err != nil {
+			logger.Warningf("failed to delete previous user %q of nfs export %q. %v", export.Status.User, request.NamespacedName, err)
+		}
+	}
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, path, user)
+
+	// Return and do not requeue
+	logger.Debug("done reconciling ceph nfs export")
+	return reconcile.Result{}, nil
+}
+
+// exportPath returns the exported path of the filesystem, the path of the subvolume if a subvolume is exported
+func (r *ReconcileCephNFSExport) exportPath(export *cephv1.CephNFSExport) (string, error) {
+	if export.Spec.SubVolume == "" {
+		if export.Spec.Path == "" {
+			return "/", nil
+		}
+		return export.Spec.Path, nil
+	}
+	return cephclient.GetFilesystemSubVolumePath(r.context, r.clusterInfo, export.Spec.FilesystemName, export.Spec.SubVolume, export.Spec.SubVolumeGroup)
+}
+
+// validateUniqueExport checks the export id and the pseudo path are not used by another export of the servers, and
+// the user is not used by another export of the cluster
+func (r *ReconcileCephNFSExport) validateUniqueExport(export *cephv1.CephNFSExport) error {
+	exports := &cephv1.CephNFSExportList{}
+	if err := r.client.List(context.TODO(), exports, client.InNamespace(export.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list nfs exports")
+	}
+	for i, other := range exports.Items {
+		if other.Name == export.Name {
+			continue
+		}
+		if exportUser(&exports.Items[i]) == exportUser(export) {
+			return errors.Errorf("user %q is already used by export %q", exportUser(export), other.Name)
+		}
+		if other.Spec.NFSName != export.Spec.NFSName {
+			continue
+		}
+		if other.Spec.ExportID == export.Spec.ExportID {
+			return errors.Errorf("exportID %d is already used by export %q", export.Spec.ExportID, other.Name)
+		}
+		if other.Spec.PseudoPath == export.Spec.PseudoPath {
+			return errors.Errorf("pseudoPath %q is already used by export %q", export.Spec.PseudoPath, other.Name)
+		}
+	}
+	return nil
+}
+
+// deleteExport removes the export from the servers and deletes its user
+func (r *ReconcileCephNFSExport) deleteExport(export *cephv1.CephNFSExport) error {
+	cephNFS := &cephv1.CephNFS{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: export.Spec.NFSName, Namespace: export.Namespace}, cephNFS)
+	if err == nil {
+		if err := r.removeExport(cephNFS, export); err != nil {
+			return err
+		}
+	} else if !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get nfs %q", export.Spec.NFSName)
+	}
+
+	// only the user created for the export is deleted
+	if export.Status == nil || export.Status.User == "" {
+		return nil
+	}
+	user := export.Status.User
+	if err := cephclient.AuthDelete(r.context, r.clusterInfo, fmt.Sprintf("client.%s", user)); err != nil {
+		logger.Warningf("failed to delete user %q of nfs export %q. %v", user, export.Name, err)
+	}
+	return nil
+}
+
+// recordExportUser records the cephx user of the export in its status, an existing user is only used by the export
+// that recorded it
+func recordExportUser(client client.Client, name types.NamespacedName, user string) error {
+	export := &cephv1.CephNFSExport{}
+	if err := client.Get(context.TODO(), name, export); err != nil {
+		return errors.Wrapf(err, "failed to retrieve nfs export %q to record its user", name)
+	}
+	if export.Status == nil {
+		export.Status = &cephv1.CephNFSExportStatus{}
+	}
+	if export.Status.User == user {
+		return nil
+	}
+
+	export.Status.User = user
+	if err := opcontroller.UpdateStatus(client, export); err != nil {
+		return errors.Wrapf(err, "failed to record user %q of nfs export %q", user, name)
+	}
+	return nil
+}
+
+// updateStatus updates an object with a given status, an empty status, path and user keep the current ones
+func updateStatus(client client.Client, name types.NamespacedName, status, path, user string) {
+	export := &cephv1.CephNFSExport{}
+	err := client.Get(context.TODO(), name, export)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephNFSExport resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve nfs export %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if export.Status == nil {
+		export.Status = &cephv1.CephNFSExportStatus{}
+	}
+
+	if status != "" {
+		export.Status.Phase = status
+	}
+	if path != "" {
+		export.Status.Path = path
+	}
+	if user != "" {
+		export.Status.User = user
+	}
+	if err := opcontroller.UpdateStatus(client, export); err != nil {
+		logger.Errorf("failed to set nfs export %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("nfs export %q status updated to %q", name, status)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephNFSExportController(t *testing.T) {
+	namespace := "rook-ceph"
+	export := newExport()
+	export.TypeMeta = controllerTypeMeta
+	export.Spec.SubVolume = "data"
+	export.Spec.Clients = []cephv1.NFSExportClientSpec{{Addresses: []string{"10.0.0.0/8"}}}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+		Status: cephv1.ClusterStatus{
+			Phase: k8sutil.ReadyStatus,
+			CephStatus: &cephv1.CephStatus{
+				Health: "HEALTH_OK",
+			},
+		},
+	}
+	cephNFS := &cephv1.CephNFS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-nfs",
+			Namespace: namespace,
+		},
+		Spec: cephv1.NFSGaneshaSpec{
+			RADOS: cephv1.GaneshaRADOSSpec{Pool: "myfs-data0", Namespace: "nfs-ns"},
+		},
+		Status: &cephv1.Status{Phase: k8sutil.ReadyStatus},
+	}
+	cephFilesystem := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myfs",
+			Namespace: namespace,
+		},
+		Status: &cephv1.CephFilesystemStatus{Phase: k8sutil.ReadyStatus},
+	}
+
+	// the RADOS objects and the users of the cluster
+	objects := map[string]string{"conf-nfs.my-nfs": ""}
+	users := map[string][]string{}
+	notifications := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "status" {
+				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_OK"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
+			}
+			if args[0] == "fs" && args[1] == "subvolume" && args[2] == "getpath" {
+				return "/volumes/_nogroup/data/1a2b", nil
+			}
+			if args[0] == "auth" {
+				switch args[1] {
+				case "get-key":
+					if _, ok := users[args[2]]; !ok {
+						return "", errors.New("ENOENT")
+					}
+					return `{"key":"AQBsecret=="}`, nil
+				case "get-or-create-key", "caps":
+					users[args[2]] = args[3:9]
+					return `{"key":"AQBsecret=="}`, nil
+				case "del":
+					delete(users, args[2])
+				}
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, "rados", command)
+			assert.Equal(t, []string{"--pool", "myfs-data0", "--namespace", "nfs-ns"}, args[:4])
+			if args[6] == "get" {
+				content, ok := objects[args[7]]
+				if !ok {
+					return "", errors.New("ENOENT")
+				}
+				return strings.TrimSpace(content), nil
+			}
+			return "", nil
+		},
+		MockExecuteCommand: func(command string, args ...string) error {
+			assert.Equal(t, "rados", command)
+			switch args[6] {
+			case "put":
+				content, err := ioutil.ReadFile(args[8])
+				assert.NoError(t, err)
+				objects[args[7]] = string(content)
+			case "stat":
+				if _, ok := objects[args[7]]; !ok {
+					return errors.New("ENOENT")
+				}
+			case "rm":
+				delete(objects, args[7])
+			case "notify":
+				assert.Equal(t, "conf-nfs.my-nfs", args[7])
+				notifications++
+			}
+			return nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		RookClientset: rookclient.NewSimpleClientset(),
+		Clientset:     test.New(t, 3),
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rook-ceph-mon",
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			"fsid":         []byte("data"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(secret)
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephNFS{}, &cephv1.CephFilesystem{}, &cephv1.CephNFSExport{}, &cephv1.CephNFSExportList{})
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: export.Name, Namespace: namespace}}
+
+	// The nfs does not exist yet, the request is requeued
+	object := []runtime.Object{export, cephCluster, cephFilesystem}
+	cl := fake.NewFakeClientWithScheme(s, object...)
+	r := &ReconcileCephNFSExport{client: cl, scheme: s, context: c}
+	res, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.True(t, res.Requeue)
+	assert.Empty(t, users)
+
+	// The export is written with the key of its user and included in the config of the servers
+	object = append(object, cephNFS)
+	cl = fake.NewFakeClientWithScheme(s, object...)
+	r = &ReconcileCephNFSExport{client: cl, scheme: s, context: c}
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mon", "allow r", "mds", "allow rw path=/volumes/_nogroup/data/1a2b", "osd", "allow rw tag cephfs data=myfs"}, users["client.nfs-export.my-nfs.data"])
+	assert.Equal(t, "%url \"rados://myfs-data0/nfs-ns/rook-export-data\"\n", objects["conf-nfs.my-nfs"])
+	assert.Contains(t, objects["rook-export-data"], `Path = "/volumes/_nogroup/data/1a2b";`)
+	assert.Contains(t, objects["rook-export-data"], `Secret_Access_Key = "AQBsecret==";`)
+	assert.Contains(t, objects["rook-export-data"], "Clients = 10.0.0.0/8;")
+	assert.Equal(t, 1, notifications)
+
+	err = r.client.Get(context.TODO(), req.NamespacedName, export)
+	assert.NoError(t, err)
+	assert.Equal(t, k8sutil.ReadyStatus, export.Status.Phase)
+	assert.Equal(t, "/volumes/_nogroup/data/1a2b", export.Status.Path)
+	assert.Equal(t, "nfs-export.my-nfs.data", export.Status.User)
+
+	// The servers are not notified again when the export is unchanged
+	_, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, notifications)
+
+	// Another export of the servers cannot use the same id
+	duplicate := newExport()
+	duplicate.Name = "duplicate"
+	duplicate.Spec.PseudoPath = "/duplicate"
+	assert.NoError(t, r.client.Create(context.TODO(), duplicate))
+	duplicateReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: duplicate.Name, Namespace: namespace}}
+	_, err = r.Reconcile(duplicateReq)
+	assert.Error(t, err)
+
+	// Another export cannot use the same user
+	duplicate.Spec.ExportID = 101
+	duplicate.Spec.User = "nfs-export.my-nfs.data"
+	assert.NoError(t, r.client.Update(context.TODO(), duplicate))
+	_, err = r.Reconcile(duplicateReq)
+	assert.Error(t, err)
+
+	// An existing user that was not created for the export is not used
+	users["client.nfs-export.legacy"] = []string{"mon", "allow *"}
+	duplicate.Spec.User = "nfs-export.legacy"
+	assert.NoError(t, r.client.Update(context.TODO(), duplicate))
+	_, err = r.Reconcile(duplicateReq)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"mon", "allow *"}, users["client.nfs-export.legacy"])
+	delete(users, "client.nfs-export.legacy")
+	assert.NoError(t, r.client.Delete(context.TODO(), duplicate))
+
+	// The user is not created when it cannot be recorded in the status of the export
+	_, err = r.reconcileUser(duplicate, "/volumes/_nogroup/duplicate")
+	assert.Error(t, err)
+	assert.NotContains(t, users, "client.nfs-export.legacy")
+
+	// The export is removed from the servers with its user
+	assert.NoError(t, r.deleteExport(export))
+	assert.Equal(t, "", strings.TrimSpace(objects["conf-nfs.my-nfs"]))
+	assert.NotContains(t, objects, "rook-export-data")
+	assert.Empty(t, users)
+	assert.Equal(t, 2, notifications)
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/nfs"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// exportObjectPrefix is the prefix of the RADOS objects of the EXPORT blocks, the objects are included by the
+	// config object of the servers
+	exportObjectPrefix = "rook-export-"
+	// exportUserPrefix is the prefix of the cephx users of the exports, the operator only manages the users with it
+	exportUserPrefix  = "nfs-export."
+	defaultAccessType = "RW"
+	defaultSquash     = "None"
+	maxExportID       = 65535
+)
+
+var (
+	accessTypes = []string{"RW", "RO", "None"}
+	squashes    = []string{"None", "Root", "RootId", "All"}
+
+	pathRegex          = regexp.MustCompile(`^/[^"\s\\]*$`)
+	clientAddressRegex = regexp.MustCompile(`^[A-Za-z0-9.:/*_-]+$`)
+	userRegex          = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+func exportObject(export *cephv1.CephNFSExport) string {
+	return exportObjectPrefix + export.Name
+}
+
+// exportUser returns the cephx user of the export, without the "client." prefix
+func exportUser(export *cephv1.CephNFSExport) string {
+	if export.Spec.User != "" {
+		return export.Spec.User
+	}
+	return fmt.Sprintf("%s%s.%s", exportUserPrefix, export.Spec.NFSName, export.Name)
+}
+
+func accessType(spec cephv1.CephNFSExportSpec) string {
+	if spec.AccessType == "" {
+		return defaultAccessType
+	}
+	return spec.AccessType
+}
+
+func squash(spec cephv1.CephNFSExportSpec) string {
+	if spec.Squash == "" {
+		return defaultSquash
+	}
+	return spec.Squash
+}
+
+// writable returns whether the export or one of its groups of clients allows writes
+func writable(spec cephv1.CephNFSExportSpec) bool {
+	if accessType(spec) == "RW" {
+		return true
+	}
+	for _, c := range spec.Clients {
+		if c.AccessType == "RW" {
+			return true
+		}
+	}
+	return false
+}
+
+// exportCaps returns the caps of the cephx user of the export, restricted to the exported path
+func exportCaps(export *cephv1.CephNFSExport, path string) []string {
+	access := "r"
+	if writable(export.Spec) {
+		access = "rw"
+	}
+	return []string{
+		"mon", "allow r",
+		"mds", fmt.Sprintf("allow %s path=%s", access, path),
+		"osd", fmt.Sprintf("allow %s tag cephfs data=%s", access, export.Spec.FilesystemName),
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func validateExport(export *cephv1.CephNFSExport) error {
+	spec := export.Spec
+	if spec.NFSName == "" {
+		return errors.New("missing nfsName")
+	}
+	if spec.FilesystemName == "" {
+		return errors.New("missing filesystemName")
+	}
+	if spec.ExportID < 1 || spec.ExportID > maxExportID {
+		return errors.Errorf("invalid exportID %d, it must be between 1 and %d", spec.ExportID, maxExportID)
+	}
+	if !pathRegex.MatchString(spec.PseudoPath) || spec.PseudoPath == "/" {
+		return errors.Errorf("invalid pseudoPath %q, it must be an absolute path other than %q", spec.PseudoPath, "/")
+	}
+	if spec.Path != "" && !pathRegex.MatchString(spec.Path) {
+		return errors.Errorf("invalid path %q, it must be an absolute path", spec.Path)
+	}
+	if spec.Path != "" && spec.SubVolume != "" {
+		return errors.New("path and subVolume cannot be both set")
+	}
+	if spec.SubVolumeGroup != "" && spec.SubVolume == "" {
+		return errors.New("subVolumeGroup requires a subVolume")
+	}
+	if spec.AccessType != "" && !contains(accessTypes, spec.AccessType) {
+		return errors.Errorf("invalid accessType %q, expected one of %s", spec.AccessType, strings.Join(accessTypes, ", "))
+	}
+	if spec.Squash != "" && !contains(squashes, spec.Squash) {
+		return errors.Errorf("invalid squash %q, expected one of %s", spec.Squash, strings.Join(squashes, ", "))
+	}
+	for i, c := range spec.Clients {
+		if len(c.Addresses) == 0 {
+			return errors.Errorf("missing addresses of clients %d", i)
+		}
+		for _, address := range c.Addresses {
+			if !clientAddressRegex.MatchString(address) {
+				return errors.Errorf("invalid address %q of clients %d", address, i)
+			}
+		}
+		if c.AccessType != "" && !contains(accessTypes, c.AccessType) {
+			return errors.Errorf("invalid accessType %q of clients %d, expected one of %s", c.AccessType, i, strings.Join(accessTypes, ", "))
+		}
+		if c.Squash != "" && !contains(squashes, c.Squash) {
+			return errors.Errorf("invalid squash %q of clients %d, expected one of %s", c.Squash, i, strings.Join(squashes, ", "))
+		}
+	}
+	// the operator changes the caps of the user and deletes it, it must not be a user of the cluster or of the clients
+	if spec.User != "" && (!userRegex.MatchString(spec.User) || !strings.HasPrefix(spec.User, exportUserPrefix) || spec.User == exportUserPrefix) {
+		return errors.Errorf("invalid user %q, it must start with %q", spec.User, exportUserPrefix)
+	}
+	return nil
+}
+
+// renderExport renders the Ganesha EXPORT block of the export with the key of its cephx user
+func renderExport(export *cephv1.CephNFSExport, path, user, key string) string {
+	spec := export.Spec
+	var b strings.Builder
+	fmt.Fprintf(&b, "EXPORT {\n")
+	fmt.Fprintf(&b, "\tExport_ID = %d;\n", spec.ExportID)
+	fmt.Fprintf(&b, "\tPath = \"%s\";\n", path)
+	fmt.Fprintf(&b, "\tPseudo = \"%s\";\n", spec.PseudoPath)
+	fmt.Fprintf(&b, "\tAccess_Type = \"%s\";\n", strings.ToUpper(accessType(spec)))
+	fmt.Fprintf(&b, "\tSquash = \"%s\";\n", squash(spec))
+	fmt.Fprintf(&b, "\tProtocols = 4;\n")
+	fmt.Fprintf(&b, "\tTransports = \"TCP\";\n")
+	fmt.Fprintf(&b, "\tFSAL {\n")
+	fmt.Fprintf(&b, "\t\tName = \"CEPH\";\n")
+	fmt.Fprintf(&b, "\t\tUser_Id = \"%s\";\n", user)
+	fmt.Fprintf(&b, "\t\tFilesystem = \"%s\";\n", spec.FilesystemName)
+	fmt.Fprintf(&b, "\t\tSecret_Access_Key = \"%s\";\n", key)
+	fmt.Fprintf(&b, "\t}\n")
+	for _, c := range spec.Clients {
+		fmt.Fprintf(&b, "\tCLIENT {\n")
+		fmt.Fprintf(&b, "\t\tClients = %s;\n", strings.Join(c.Addresses, ", "))
+		if c.AccessType != "" {
+			fmt.Fprintf(&b, "\t\tAccess_Type = \"%s\";\n", strings.ToUpper(c.AccessType))
+		}
+		if c.Squash != "" {
+			fmt.Fprintf(&b, "\t\tSquash = \"%s\";\n", c.Squash)
+		}
+		fmt.Fprintf(&b, "\t}\n")
+	}
+	fmt.Fprintf(&b, "}")
+	return b.String()
+}
+
+// setConfigURL adds or removes the inclusion of a RADOS URL in the content of the config object of the servers and
+// returns whether the content changed, the other lines are kept as they are
+func setConfigURL(config, url string, included bool) (string, bool) {
+	lines := []string{}
+	found, changed := false, false
+	for _, line := range strings.Split(strings.TrimSpace(config), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == fmt.Sprintf("%%url %q", url) || trimmed == fmt.Sprintf("%%url %s", url) {
+			if !included || found {
+				changed = true
+				continue
+			}
+			found = true
+		}
+		if trimmed != "" {
+			lines = append(lines, line)
+		}
+	}
+	if included && !found {
+		lines = append(lines, fmt.Sprintf("%%url %q", url))
+		changed = true
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+// radosArgs returns the arguments of the rados commands on the pool and namespace of the servers
+func (r *ReconcileCephNFSExport) radosArgs(n *cephv1.CephNFS) []string {
+	return []string{
+		"--pool", n.Spec.RADOS.Pool,
+		"--namespace", n.Spec.RADOS.Namespace,
+		"--conf", cephclient.CephConfFilePath(r.context.ConfigDir, n.Namespace),
+	}
+}
+
+func (r *ReconcileCephNFSExport) getObject(n *cephv1.CephNFS, object string) (string, error) {
+	output, err := r.context.Executor.ExecuteCommandWithOutput("rados", append(r.radosArgs(n), "get", object, "-")...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get object %q. %s", object, output)
+	}
+	return output, nil
+}
+
+func (r *ReconcileCephNFSExport) putObject(n *cephv1.CephNFS, object, content string) error {
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		return errors.Wrap(err, "failed to generate temporary file")
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(content + "\n"); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "failed to write object %q", object)
+	}
+	file.Close()
+
+	if err := r.context.Executor.ExecuteCommand("rados", append(r.radosArgs(n), "put", object, file.Name())...); err != nil {
+		return errors.Wrapf(err, "failed to put object %q", object)
+	}
+	return nil
+}
+
+func (r *ReconcileCephNFSExport) removeObject(n *cephv1.CephNFS, object string) error {
+	if err := r.context.Executor.ExecuteCommand("rados", append(r.radosArgs(n), "stat", object)...); err != nil {
+		// the object does not exist
+		return nil
+	}
+	if err := r.context.Executor.ExecuteCommand("rados", append(r.radosArgs(n), "rm", object)...); err != nil {
+		return errors.Wrapf(err, "failed to remove object %q", object)
+	}
+	return nil
+}
+
+// setExportURL adds or removes the inclusion of the object of the export in the config object of the servers and
+// returns whether the config changed
+func (r *ReconcileCephNFSExport) setExportURL(n *cephv1.CephNFS, export *cephv1.CephNFSExport, included bool) (bool, error) {
+	configObject := nfs.GetGaneshaConfigObject(n.Name)
+	config, err := r.getObject(n, configObject)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read the config of nfs %q", n.Name)
+	}
+	updated, changed := setConfigURL(config, nfs.GetRadosObjectURL(n, exportObject(export)), included)
+	if !changed {
+		return false, nil
+	}
+	if err := r.putObject(n, configObject, updated); err != nil {
+		return false, errors.Wrapf(err, "failed to update the config of nfs %q", n.Name)
+	}
+	return true, nil
+}
+
+// notifyServers notifies the servers watching the config object to reload their exports
+func (r *ReconcileCephNFSExport) notifyServers(n *cephv1.CephNFS) error {
+	configObject := nfs.GetGaneshaConfigObject(n.Name)
+	logger.Infof("notifying the servers of nfs %q to reload their exports", n.Name)
+	if err := r.context.Executor.ExecuteCommand("rados", append(r.radosArgs(n), "notify", configObject, configObject)...); err != nil {
+		return errors.Wrapf(err, "failed to notify the servers of nfs %q", n.Name)
+	}
+	return nil
+}
+
+// reconcileUser creates the cephx user of the export or updates its caps and returns its key. An existing user is only
+// updated if it was created for the export.
+func (r *ReconcileCephNFSExport) reconcileUser(export *cephv1.CephNFSExport, path string) (string, error) {
+	user := exportUser(export)
+	name := fmt.Sprintf("client.%s", user)
+	caps := exportCaps(export, path)
+	key, err := cephclient.AuthGetKey(r.context, r.clusterInfo, name)
+	if err != nil {
+		// the user is recorded before it is created so that it is known to belong to the export, the user is not
+		// created if it cannot be recorded
+		if err := recordExportUser(r.client, types.NamespacedName{Name: export.Name, Namespace: export.Namespace}, user); err != nil {
+			return "", err
+		}
+		return cephclient.AuthGetOrCreateKey(r.context, r.clusterInfo, name, caps)
+	}
+	if export.Status == nil || export.Status.User != user {
+		return "", errors.Errorf("user %q already exists and was not created for the export", name)
+	}
+	// the caps follow the changes of the path and the access of the export
+	if err := cephclient.AuthUpdateCaps(r.context, r.clusterInfo, name, caps); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// reconcileExport writes the EXPORT block of the export in its object, includes it in the config of the servers and
+// notifies the servers when the exports changed
+func (r *ReconcileCephNFSExport) reconcileExport(n *cephv1.CephNFS, export *cephv1.CephNFSExport, path string) error {
+	key, err := r.reconcileUser(export, path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure user %q", exportUser(export))
+	}
+
+	block := renderExport(export, path, exportUser(export), key)
+	current, err := r.getObject(n, exportObject(export))
+	changed := err != nil || current != block
+	if changed {
+		logger.Infof("writing export %d of path %q to object %q of nfs %q", export.Spec.ExportID, path, exportObject(export), n.Name)
+		if err := r.putObject(n, exportObject(export), block); err != nil {
+			return err
+		}
+	}
+
+	included, err := r.setExportURL(n, export, true)
+	if err != nil {
+		return err
+	}
+	if changed || included {
+		return r.notifyServers(n)
+	}
+	return nil
+}
+
+// removeExport removes the export from the config of the servers and deletes its object
+func (r *ReconcileCephNFSExport) removeExport(n *cephv1.CephNFS, export *cephv1.CephNFSExport) error {
+	changed, err := r.setExportURL(n, export, false)
+	if err != nil {
+		return err
+	}
+	if changed {
+		if err := r.notifyServers(n); err != nil {
+			return err
+		}
+	}
+	return r.removeObject(n, exportObject(export))
+}
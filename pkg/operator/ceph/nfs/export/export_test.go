@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExport() *cephv1.CephNFSExport {
+	return &cephv1.CephNFSExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "rook-ceph"},
+		Spec: cephv1.CephNFSExportSpec{
+			NFSName:        "my-nfs",
+			ExportID:       100,
+			FilesystemName: "myfs",
+			PseudoPath:     "/data",
+		},
+	}
+}
+
+func TestValidateExport(t *testing.T) {
+	export := newExport()
+	assert.NoError(t, validateExport(export))
+
+	invalid := []func(spec *cephv1.CephNFSExportSpec){
+		func(spec *cephv1.CephNFSExportSpec) { spec.NFSName = "" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.FilesystemName = "" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.ExportID = 0 },
+		func(spec *cephv1.CephNFSExportSpec) { spec.ExportID = 70000 },
+		func(spec *cephv1.CephNFSExportSpec) { spec.PseudoPath = "/" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.PseudoPath = "data" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.Path = "/my data" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.Path, spec.SubVolume = "/data", "data" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.SubVolumeGroup = "team-a" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.AccessType = "rw" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.Squash = "no_root_squash" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.Clients = []cephv1.NFSExportClientSpec{{}} },
+		func(spec *cephv1.CephNFSExportSpec) {
+			spec.Clients = []cephv1.NFSExportClientSpec{{Addresses: []string{"10.0.0.1; Access_Type = RW"}}}
+		},
+		func(spec *cephv1.CephNFSExportSpec) {
+			spec.Clients = []cephv1.NFSExportClientSpec{{Addresses: []string{"10.0.0.0/8"}, Squash: "Everyone"}}
+		},
+		func(spec *cephv1.CephNFSExportSpec) { spec.User = "client.admin;" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.User = "admin" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.User = "csi-cephfs-node" },
+		func(spec *cephv1.CephNFSExportSpec) { spec.User = "nfs-export." },
+	}
+	for i, modify := range invalid {
+		export := newExport()
+		modify(&export.Spec)
+		assert.Error(t, validateExport(export), i)
+	}
+}
+
+func TestRenderExport(t *testing.T) {
+	export := newExport()
+	export.Spec.AccessType = "RO"
+	export.Spec.Clients = []cephv1.NFSExportClientSpec{
+		{Addresses: []string{"10.0.0.0/8", "host1"}, AccessType: "RW", Squash: "Root"},
+	}
+
+	expected := `EXPORT {
+	Export_ID = 100;
+	Path = "/volumes/_nogroup/data/1a2b";
+	Pseudo = "/data";
+	Access_Type = "RO";
+	Squash = "None";
+	Protocols = 4;
+	Transports = "TCP";
+	FSAL {
+		Name = "CEPH";
+		User_Id = "nfs-export.my-nfs.data";
+		Filesystem = "myfs";
+		Secret_Access_Key = "AQBsecret==";
+	}
+	CLIENT {
+		Clients = 10.0.0.0/8, host1;
+		Access_Type = "RW";
+		Squash = "Root";
+	}
+}`
+	assert.Equal(t, expected, renderExport(export, "/volumes/_nogroup/data/1a2b", exportUser(export), "AQBsecret=="))
+}
+
+func TestExportCaps(t *testing.T) {
+	export := newExport()
+	assert.Equal(t, "nfs-export.my-nfs.data", exportUser(export))
+	assert.Equal(t, []string{"mon", "allow r", "mds", "allow rw path=/data", "osd", "allow rw tag cephfs data=myfs"}, exportCaps(export, "/data"))
+
+	// the user is read-only when no client can write
+	export.Spec.AccessType = "RO"
+	export.Spec.Clients = []cephv1.NFSExportClientSpec{{Addresses: []string{"host1"}, AccessType: "None"}}
+	assert.Equal(t, []string{"mon", "allow r", "mds", "allow r path=/data", "osd", "allow r tag cephfs data=myfs"}, exportCaps(export, "/data"))
+
+	export.Spec.Clients[0].AccessType = "RW"
+	assert.Equal(t, "allow rw path=/data", exportCaps(export, "/data")[3])
+
+	export.Spec.User = "nfs-export.team-a"
+	assert.Equal(t, "nfs-export.team-a", exportUser(export))
+	assert.NoError(t, validateExport(export))
+}
+
+func TestSetConfigURL(t *testing.T) {
+	url := "rados://myfs-data0/nfs-ns/rook-export-data"
+
+	config, changed := setConfigURL("", url, true)
+	assert.True(t, changed)
+	assert.Equal(t, `%url "rados://myfs-data0/nfs-ns/rook-export-data"`, config)
+
+	// the url is already included
+	_, changed = setConfigURL(config, url, true)
+	assert.False(t, changed)
+	_, changed = setConfigURL("%url rados://myfs-data0/nfs-ns/rook-export-data\n", url, true)
+	assert.False(t, changed)
+
+	// the other exports are kept
+	config = "%url \"rados://myfs-data0/nfs-ns/export-1\"\n" + config
+	config, changed = setConfigURL(config, url, false)
+	assert.True(t, changed)
+	assert.Equal(t, `%url "rados://myfs-data0/nfs-ns/export-1"`, config)
+
+	_, changed = setConfigURL(config, url, false)
+	assert.False(t, changed)
+}
@@ -126,7 +126,7 @@ func (r *ReconcileCephNFS) upCephNFS(n *cephv1.CephNFS, oldActive int) error {
 
 // Create empty config file for new ganesha server
 func (r *ReconcileCephNFS) addRADOSConfigFile(n *cephv1.CephNFS) error {
-	config := getGaneshaConfigObject(n.Name)
+	config := GetGaneshaConfigObject(n.Name)
 	cmd := "rados"
 	args := []string{
 		"--pool", n.Spec.RADOS.Pool,
@@ -0,0 +1,291 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// the passwords of keystone and ldap are read by rgw from files, the keys of sts have no file option and are
+	// passed as flags from env vars
+	authDir                    = "/etc/ceph/rgw-auth"
+	authPasswordFileName       = "password"
+	keystonePasswordVolumeName = "rook-ceph-rgw-keystone-password"
+	ldapPasswordVolumeName     = "rook-ceph-rgw-ldap-password"
+	authPasswordSecretKey      = "password"
+
+	stsKeySecretKey          = "key"
+	stsKeyEnvVar             = "ROOK_RGW_STS_KEY"
+	stsClientSecretSecretKey = "secret"
+	stsClientSecretEnvVar    = "ROOK_RGW_STS_CLIENT_SECRET"
+
+	// authAnnotation is the hash of the auth settings of the rgw pods, they are restarted when the settings change
+	authAnnotation = "ceph.rook.io/rgw-auth"
+)
+
+// authConfigOptions lists the rgw options of the external authentication, the options of the disabled services are
+// empty so they can be removed from the mon config store
+var authConfigOptions = []string{
+	"rgw_keystone_url",
+	"rgw_keystone_api_version",
+	"rgw_keystone_admin_user",
+	"rgw_keystone_admin_project",
+	"rgw_keystone_admin_domain",
+	"rgw_keystone_admin_password_path",
+	"rgw_keystone_accepted_roles",
+	"rgw_keystone_implicit_tenants",
+	"rgw_s3_auth_use_keystone",
+	"rgw_ldap_uri",
+	"rgw_ldap_binddn",
+	"rgw_ldap_secret",
+	"rgw_ldap_searchdn",
+	"rgw_ldap_dnattr",
+	"rgw_ldap_searchfilter",
+	"rgw_s3_auth_use_ldap",
+	"rgw_s3_auth_use_sts",
+	"rgw_sts_token_introspection_url",
+	"rgw_sts_client_id",
+}
+
+// validateAuth validates the external authentication of the object store
+func validateAuth(auth cephv1.ObjectStoreAuthSpec) error {
+	if keystone := auth.Keystone; keystone != nil {
+		if err := validateURL(keystone.URL, "http", "https"); err != nil {
+			return errors.Wrap(err, "invalid keystone url")
+		}
+		if keystone.AdminUser == "" || keystone.AdminProject == "" || keystone.AdminDomain == "" {
+			return errors.New("keystone requires the admin user, project and domain")
+		}
+		if keystone.PasswordSecretName == "" {
+			return errors.New("keystone requires a password secret")
+		}
+		if len(keystone.AcceptedRoles) == 0 {
+			return errors.New("keystone requires at least one accepted role")
+		}
+	}
+	if ldap := auth.LDAP; ldap != nil {
+		if err := validateURL(ldap.URI, "ldap", "ldaps"); err != nil {
+			return errors.Wrap(err, "invalid ldap uri")
+		}
+		if ldap.BindDN == "" || ldap.SearchDN == "" {
+			return errors.New("ldap requires the bind dn and the search dn")
+		}
+		if ldap.PasswordSecretName == "" {
+			return errors.New("ldap requires a password secret")
+		}
+	}
+	if sts := auth.STS; sts != nil {
+		if sts.KeySecretName == "" {
+			return errors.New("sts requires a key secret")
+		}
+		if web := sts.WebIdentity; web != nil {
+			if err := validateURL(web.IntrospectionURL, "http", "https"); err != nil {
+				return errors.Wrap(err, "invalid web identity introspection url")
+			}
+			if web.ClientID == "" || web.ClientSecretName == "" {
+				return errors.New("web identity requires the client id and a client secret")
+			}
+		}
+	}
+	return nil
+}
+
+func validateURL(value string, schemes ...string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return errors.Errorf("%q is not a valid url", value)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return errors.Errorf("the scheme of %q must be one of %v", value, schemes)
+}
+
+func authPasswordPath(volumeName string) string {
+	return path.Join(authDir, volumeName, authPasswordFileName)
+}
+
+// authOptions returns the rgw options of the external authentication, the options of the disabled services are empty
+func (c *clusterConfig) authOptions() map[string]string {
+	options := make(map[string]string, len(authConfigOptions))
+	for _, option := range authConfigOptions {
+		options[option] = ""
+	}
+	auth := c.store.Spec.Auth
+
+	if keystone := auth.Keystone; keystone != nil {
+		options["rgw_keystone_url"] = keystone.URL
+		options["rgw_keystone_api_version"] = "3"
+		options["rgw_keystone_admin_user"] = keystone.AdminUser
+		options["rgw_keystone_admin_project"] = keystone.AdminProject
+		options["rgw_keystone_admin_domain"] = keystone.AdminDomain
+		options["rgw_keystone_admin_password_path"] = authPasswordPath(keystonePasswordVolumeName)
+		options["rgw_keystone_accepted_roles"] = strings.Join(keystone.AcceptedRoles, ",")
+		options["rgw_keystone_implicit_tenants"] = fmt.Sprintf("%t", keystone.ImplicitTenants)
+		options["rgw_s3_auth_use_keystone"] = fmt.Sprintf("%t", keystone.EnableS3)
+	}
+
+	if ldap := auth.LDAP; ldap != nil {
+		options["rgw_ldap_uri"] = ldap.URI
+		options["rgw_ldap_binddn"] = ldap.BindDN
+		options["rgw_ldap_secret"] = authPasswordPath(ldapPasswordVolumeName)
+		options["rgw_ldap_searchdn"] = ldap.SearchDN
+		options["rgw_ldap_dnattr"] = "uid"
+		if ldap.UIDAttribute != "" {
+			options["rgw_ldap_dnattr"] = ldap.UIDAttribute
+		}
+		options["rgw_ldap_searchfilter"] = ldap.SearchFilter
+		options["rgw_s3_auth_use_ldap"] = "true"
+	}
+
+	if sts := auth.STS; sts != nil {
+		options["rgw_s3_auth_use_sts"] = "true"
+		if sts.WebIdentity != nil {
+			options["rgw_sts_token_introspection_url"] = sts.WebIdentity.IntrospectionURL
+			options["rgw_sts_client_id"] = sts.WebIdentity.ClientID
+		}
+	}
+	return options
+}
+
+// setAuthFlagsMonConfigStore updates the options of the external authentication in the mon config store, the
+// options of the disabled services are removed
+func (c *clusterConfig) setAuthFlagsMonConfigStore(rgwName string) error {
+	monStore := cephconfig.GetMonStore(c.context, c.clusterInfo)
+	who := generateCephXUser(rgwName)
+	current, err := monStore.GetDaemon(who)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the config of %q", who)
+	}
+	currentValues := map[string]string{}
+	for _, option := range current {
+		currentValues[option.Option] = option.Value
+	}
+
+	for flag, val := range c.authOptions() {
+		currentVal, ok := currentValues[flag]
+		switch {
+		case val == "" && ok:
+			if err := monStore.Delete(who, flag); err != nil {
+				return errors.Wrapf(err, "failed to delete %q on %q", flag, who)
+			}
+		case val != "" && val != currentVal:
+			if err := monStore.Set(who, flag, val); err != nil {
+				return errors.Wrapf(err, "failed to set %q to %q on %q", flag, val, who)
+			}
+		}
+	}
+	return nil
+}
+
+// authHash returns the hash of the auth options of the rgw pods, empty if no external authentication is enabled
+func (c *clusterConfig) authHash() string {
+	auth := c.store.Spec.Auth
+	if auth.Keystone == nil && auth.LDAP == nil && auth.STS == nil {
+		return ""
+	}
+	options := []string{}
+	for flag, val := range c.authOptions() {
+		options = append(options, fmt.Sprintf("%s=%s", flag, val))
+	}
+	sort.Strings(options)
+	return k8sutil.Hash(strings.Join(options, "\n"))
+}
+
+// authFlags returns the rgw flags of the sts keys
+func (c *clusterConfig) authFlags() []string {
+	sts := c.store.Spec.Auth.STS
+	if sts == nil {
+		return []string{}
+	}
+	flags := []string{cephconfig.NewFlag("rgw sts key", controller.ContainerEnvVarReference(stsKeyEnvVar))}
+	if sts.WebIdentity != nil {
+		flags = append(flags, cephconfig.NewFlag("rgw sts client secret", controller.ContainerEnvVarReference(stsClientSecretEnvVar)))
+	}
+	return flags
+}
+
+// authEnvVars returns the env vars of the rgw container with the sts keys
+func (c *clusterConfig) authEnvVars() []v1.EnvVar {
+	sts := c.store.Spec.Auth.STS
+	if sts == nil {
+		return []v1.EnvVar{}
+	}
+	envVars := []v1.EnvVar{secretEnvVar(stsKeyEnvVar, sts.KeySecretName, stsKeySecretKey)}
+	if sts.WebIdentity != nil {
+		envVars = append(envVars, secretEnvVar(stsClientSecretEnvVar, sts.WebIdentity.ClientSecretName, stsClientSecretSecretKey))
+	}
+	return envVars
+}
+
+func secretEnvVar(name, secretName, key string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// authVolumes returns the volumes of the keystone and ldap passwords
+func (c *clusterConfig) authVolumes() []v1.Volume {
+	volumes := []v1.Volume{}
+	if keystone := c.store.Spec.Auth.Keystone; keystone != nil {
+		volumes = append(volumes, authPasswordVolume(keystonePasswordVolumeName, keystone.PasswordSecretName))
+	}
+	if ldap := c.store.Spec.Auth.LDAP; ldap != nil {
+		volumes = append(volumes, authPasswordVolume(ldapPasswordVolumeName, ldap.PasswordSecretName))
+	}
+	return volumes
+}
+
+func authPasswordVolume(name, secretName string) v1.Volume {
+	return v1.Volume{
+		Name: name,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []v1.KeyToPath{
+					{Key: authPasswordSecretKey, Path: authPasswordFileName},
+				}}}}
+}
+
+// authVolumeMounts returns the volume mounts of the rgw container with the keystone and ldap passwords
+func (c *clusterConfig) authVolumeMounts() []v1.VolumeMount {
+	mounts := []v1.VolumeMount{}
+	for _, volume := range c.authVolumes() {
+		mounts = append(mounts, v1.VolumeMount{Name: volume.Name, MountPath: path.Join(authDir, volume.Name), ReadOnly: true})
+	}
+	return mounts
+}
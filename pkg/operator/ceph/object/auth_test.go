@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func authSpec() cephv1.ObjectStoreAuthSpec {
+	return cephv1.ObjectStoreAuthSpec{
+		Keystone: &cephv1.ObjectStoreKeystoneSpec{
+			URL:                "https://keystone.example.com:5000",
+			AdminUser:          "rgw",
+			AdminProject:       "admin",
+			AdminDomain:        "default",
+			PasswordSecretName: "keystone-password",
+			AcceptedRoles:      []string{"admin", "member"},
+			EnableS3:           true,
+		},
+		LDAP: &cephv1.ObjectStoreLDAPSpec{
+			URI:                "ldaps://ldap.example.com",
+			BindDN:             "cn=rgw,dc=example,dc=com",
+			PasswordSecretName: "ldap-password",
+			SearchDN:           "ou=users,dc=example,dc=com",
+		},
+		STS: &cephv1.ObjectStoreSTSSpec{
+			KeySecretName: "sts-key",
+			WebIdentity: &cephv1.ObjectStoreWebIdentitySpec{
+				IntrospectionURL: "https://oidc.example.com/token/introspect",
+				ClientID:         "rgw",
+				ClientSecretName: "oidc-client",
+			},
+		},
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	assert.NoError(t, validateAuth(cephv1.ObjectStoreAuthSpec{}))
+	assert.NoError(t, validateAuth(authSpec()))
+
+	invalid := []func(auth *cephv1.ObjectStoreAuthSpec){
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.Keystone.URL = "keystone:5000" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.Keystone.AdminDomain = "" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.Keystone.PasswordSecretName = "" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.Keystone.AcceptedRoles = nil },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.LDAP.URI = "https://ldap.example.com" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.LDAP.SearchDN = "" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.LDAP.PasswordSecretName = "" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.STS.KeySecretName = "" },
+		func(auth *cephv1.ObjectStoreAuthSpec) { auth.STS.WebIdentity.ClientSecretName = "" },
+	}
+	for i, modify := range invalid {
+		auth := authSpec()
+		modify(&auth)
+		assert.Error(t, validateAuth(auth), i)
+	}
+}
+
+func TestAuthPodSpec(t *testing.T) {
+	store := simpleStore()
+	c := &clusterConfig{
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+		rookVersion: "rook/rook:myversion",
+		clusterSpec: &cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v15"},
+		},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s", AppName, c.store.Name)}
+
+	// no auth
+	s, err := c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.NotContains(t, s.ObjectMeta.Annotations, authAnnotation)
+
+	store.Spec.Auth = authSpec()
+	s, err = c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	hash := s.ObjectMeta.Annotations[authAnnotation]
+	assert.NotEmpty(t, hash)
+	container := s.Spec.Containers[0]
+	assert.Contains(t, container.Args, "--rgw-sts-key=$(ROOK_RGW_STS_KEY)")
+	assert.Contains(t, container.Args, "--rgw-sts-client-secret=$(ROOK_RGW_STS_CLIENT_SECRET)")
+	assert.Contains(t, container.Env, secretEnvVar(stsKeyEnvVar, "sts-key", "key"))
+	assert.Contains(t, container.Env, secretEnvVar(stsClientSecretEnvVar, "oidc-client", "secret"))
+	for _, volume := range c.authVolumes() {
+		assert.Contains(t, s.Spec.Volumes, volume)
+	}
+	assert.Contains(t, container.VolumeMounts, c.authVolumeMounts()[0])
+	assert.Contains(t, container.VolumeMounts, c.authVolumeMounts()[1])
+	assert.Equal(t, "/etc/ceph/rgw-auth/rook-ceph-rgw-ldap-password", c.authVolumeMounts()[1].MountPath)
+
+	// the pods are restarted when the options change
+	store.Spec.Auth.LDAP.SearchFilter = "(objectclass=inetorgperson)"
+	s, err = c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, s.ObjectMeta.Annotations[authAnnotation])
+}
+
+func TestSetAuthFlagsMonConfigStore(t *testing.T) {
+	commands := []string{}
+	current := `{"rgw_zone": {"value": "default", "section": "client.rgw.default.a"},
+	"rgw_ldap_uri": {"value": "ldaps://old.example.com", "section": "client.rgw.default.a"},
+	"rgw_ldap_binddn": {"value": "cn=rgw,dc=example,dc=com", "section": "client.rgw.default.a"},
+	"rgw_s3_auth_use_sts": {"value": "true", "section": "client.rgw.default.a"}}`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "config" && args[1] == "get" {
+				return current, nil
+			}
+			commands = append(commands, strings.Join(args[:4], " "))
+			return "", nil
+		},
+	}
+	store := simpleStore()
+	store.Spec.Auth.LDAP = authSpec().LDAP
+	c := &clusterConfig{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+	}
+
+	// the ldap options are updated and sts is disabled
+	err := c.setAuthFlagsMonConfigStore("rook-ceph-rgw-default-a")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"config set client.rgw.default.a rgw_ldap_uri",
+		"config set client.rgw.default.a rgw_ldap_secret",
+		"config set client.rgw.default.a rgw_ldap_searchdn",
+		"config set client.rgw.default.a rgw_ldap_dnattr",
+		"config set client.rgw.default.a rgw_s3_auth_use_ldap",
+		"config rm client.rgw.default.a rgw_s3_auth_use_sts",
+	}, commands)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// defaultRequestsPerSecondMetric is the pod metric of the requests per second of the rgw pods
+	defaultRequestsPerSecondMetric = "rgw_requests_per_second"
+)
+
+// validateAutoscaling validates the autoscaling settings of the gateway
+func validateAutoscaling(gateway cephv1.GatewaySpec) error {
+	spec := gateway.Autoscaling
+	if spec == nil {
+		return nil
+	}
+	if spec.MinInstances < 1 {
+		return errors.New("the minimum number of autoscaled instances must be at least 1")
+	}
+	if spec.MaxInstances < spec.MinInstances {
+		return errors.Errorf("the maximum number of autoscaled instances %d is lower than the minimum %d", spec.MaxInstances, spec.MinInstances)
+	}
+	if spec.TargetCPUUtilizationPercentage == nil && spec.TargetRequestsPerSecond == nil {
+		return errors.New("autoscaling requires a target CPU utilization or a target number of requests per second")
+	}
+	if spec.TargetCPUUtilizationPercentage != nil {
+		if *spec.TargetCPUUtilizationPercentage < 1 {
+			return errors.New("the target CPU utilization must be at least 1 percent")
+		}
+		// the utilization is relative to the requests of the pods
+		if _, ok := gateway.Resources.Requests[v1.ResourceCPU]; !ok {
+			return errors.New("autoscaling on the CPU utilization requires the CPU requests of the gateway resources")
+		}
+	}
+	if spec.TargetRequestsPerSecond != nil && *spec.TargetRequestsPerSecond < 1 {
+		return errors.New("the target number of requests per second must be at least 1")
+	}
+	return nil
+}
+
+// rgwInstances returns the number of rgw deployments of the store, the autoscaler scales a single deployment
+func (c *clusterConfig) rgwInstances() int {
+	if c.store.Spec.Gateway.Autoscaling != nil {
+		return 1
+	}
+	return int(c.store.Spec.Gateway.Instances)
+}
+
+func (c *clusterConfig) generateAutoscaler(deploymentName string) *autoscalingv2beta1.HorizontalPodAutoscaler {
+	spec := c.store.Spec.Gateway.Autoscaling
+	minReplicas := spec.MinInstances
+	hpa := &autoscalingv2beta1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instanceName(c.store.Name),
+			Namespace: c.store.Namespace,
+			Labels:    getLabels(c.store.Name, c.store.Namespace, true),
+		},
+		Spec: autoscalingv2beta1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       deploymentName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: spec.MaxInstances,
+		},
+	}
+	if spec.TargetCPUUtilizationPercentage != nil {
+		target := *spec.TargetCPUUtilizationPercentage
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2beta1.MetricSpec{
+			Type: autoscalingv2beta1.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta1.ResourceMetricSource{
+				Name:                     v1.ResourceCPU,
+				TargetAverageUtilization: &target,
+			},
+		})
+	}
+	if spec.TargetRequestsPerSecond != nil {
+		metric := spec.RequestsPerSecondMetric
+		if metric == "" {
+			metric = defaultRequestsPerSecondMetric
+		}
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2beta1.MetricSpec{
+			Type: autoscalingv2beta1.PodsMetricSourceType,
+			Pods: &autoscalingv2beta1.PodsMetricSource{
+				MetricName:         metric,
+				TargetAverageValue: *resource.NewQuantity(int64(*spec.TargetRequestsPerSecond), resource.DecimalSI),
+			},
+		})
+	}
+	return hpa
+}
+
+// reconcileAutoscaler creates or updates the horizontal pod autoscaler of the rgw deployment, or deletes it if the
+// autoscaling is not in the spec anymore
+func (c *clusterConfig) reconcileAutoscaler(deploymentName string) error {
+	if c.store.Spec.Gateway.Autoscaling == nil {
+		existing, err := c.context.Clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(c.store.Namespace).Get(instanceName(c.store.Name), metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to get object store %q horizontal pod autoscaler", c.store.Name)
+		}
+		// an autoscaler of the same name created by the user is left untouched
+		if !metav1.IsControlledBy(existing, c.store) {
+			return nil
+		}
+		logger.Infof("deleting object store %q horizontal pod autoscaler", c.store.Name)
+		return k8sutil.DeleteHorizontalPodAutoscaler(c.context.Clientset, c.store.Namespace, existing.Name)
+	}
+
+	hpa := c.generateAutoscaler(deploymentName)
+	err := controllerutil.SetControllerReference(c.store, hpa, c.scheme)
+	if err != nil {
+		return errors.Wrap(err, "failed to set owner reference to ceph object store horizontal pod autoscaler")
+	}
+	if _, err := k8sutil.CreateOrUpdateHorizontalPodAutoscaler(c.context.Clientset, c.store.Namespace, hpa); err != nil {
+		return errors.Wrapf(err, "failed to create or update object store %q horizontal pod autoscaler", c.store.Name)
+	}
+	logger.Infof("object store %q gateway autoscaled from %d to %d instances", c.store.Name, c.store.Spec.Gateway.Autoscaling.MinInstances, c.store.Spec.Gateway.Autoscaling.MaxInstances)
+	return nil
+}
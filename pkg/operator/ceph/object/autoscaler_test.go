@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	apps "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func autoscalingSpec() *cephv1.RGWAutoscalingSpec {
+	cpu := int32(70)
+	requests := int32(500)
+	return &cephv1.RGWAutoscalingSpec{
+		MinInstances:                   2,
+		MaxInstances:                   10,
+		TargetCPUUtilizationPercentage: &cpu,
+		TargetRequestsPerSecond:        &requests,
+	}
+}
+
+func TestValidateAutoscaling(t *testing.T) {
+	gateway := cephv1.GatewaySpec{}
+	assert.NoError(t, validateAutoscaling(gateway))
+	gateway.Autoscaling = autoscalingSpec()
+	gateway.Resources.Requests = v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+	assert.NoError(t, validateAutoscaling(gateway))
+
+	invalid := []func(gateway *cephv1.GatewaySpec){
+		func(gateway *cephv1.GatewaySpec) { gateway.Autoscaling.MinInstances = 0 },
+		func(gateway *cephv1.GatewaySpec) { gateway.Autoscaling.MaxInstances = 1 },
+		func(gateway *cephv1.GatewaySpec) {
+			gateway.Autoscaling.TargetCPUUtilizationPercentage = nil
+			gateway.Autoscaling.TargetRequestsPerSecond = nil
+		},
+		func(gateway *cephv1.GatewaySpec) { gateway.Resources.Requests = nil },
+		func(gateway *cephv1.GatewaySpec) { *gateway.Autoscaling.TargetRequestsPerSecond = 0 },
+	}
+	for i, modify := range invalid {
+		gateway := cephv1.GatewaySpec{Autoscaling: autoscalingSpec()}
+		gateway.Resources.Requests = v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+		modify(&gateway)
+		assert.Error(t, validateAutoscaling(gateway), i)
+	}
+
+	// the cpu requests are only required to scale on the cpu utilization
+	gateway = cephv1.GatewaySpec{Autoscaling: autoscalingSpec()}
+	gateway.Autoscaling.TargetCPUUtilizationPercentage = nil
+	assert.NoError(t, validateAutoscaling(gateway))
+}
+
+func TestGenerateAutoscaler(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Gateway.Autoscaling = autoscalingSpec()
+	c := &clusterConfig{store: store}
+
+	hpa := c.generateAutoscaler("rook-ceph-rgw-default-a")
+	assert.Equal(t, "rook-ceph-rgw-default", hpa.Name)
+	assert.Equal(t, autoscalingv2beta1.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "rook-ceph-rgw-default-a"}, hpa.Spec.ScaleTargetRef)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+	assert.Equal(t, 2, len(hpa.Spec.Metrics))
+	assert.Equal(t, v1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, int32(70), *hpa.Spec.Metrics[0].Resource.TargetAverageUtilization)
+	assert.Equal(t, "rgw_requests_per_second", hpa.Spec.Metrics[1].Pods.MetricName)
+	assert.Equal(t, "500", hpa.Spec.Metrics[1].Pods.TargetAverageValue.String())
+
+	store.Spec.Gateway.Autoscaling.TargetCPUUtilizationPercentage = nil
+	store.Spec.Gateway.Autoscaling.RequestsPerSecondMetric = "ceph_rgw_req_rate"
+	hpa = c.generateAutoscaler("rook-ceph-rgw-default-a")
+	assert.Equal(t, 1, len(hpa.Spec.Metrics))
+	assert.Equal(t, "ceph_rgw_req_rate", hpa.Spec.Metrics[0].Pods.MetricName)
+}
+
+func TestAutoscaledMultisiteStore(t *testing.T) {
+	clientset := testop.New(t, 3)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutputFile: func(command string, outFileArg string, args ...string) (string, error) {
+			if args[0] == "config" && args[1] == "get" {
+				return `{}`, nil
+			}
+			return `{"key":"mysecurekey"}`, nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return `{"id":"test-id"}`, nil
+		},
+	}
+	defer func(update func(*clusterd.Context, *cephclient.ClusterInfo, *apps.Deployment, string, string, bool, bool) error) {
+		updateDeploymentAndWait = update
+	}(updateDeploymentAndWait)
+	updateDeploymentAndWait = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, deployment *apps.Deployment, daemonType, daemonName string, skipUpgradeChecks, continueUpgradeAfterChecksEvenIfNotHealthy bool) error {
+		_, err := context.Clientset.AppsV1().Deployments(deployment.Namespace).Update(deployment)
+		return err
+	}
+
+	configDir, _ := ioutil.TempDir("", "")
+	defer os.RemoveAll(configDir)
+	store := simpleStore()
+	store.UID = "store-uid"
+	store.Spec.Zone.Name = "zone-a"
+	store.Spec.Gateway.Instances = 3
+	store.Spec.Gateway.Autoscaling = autoscalingSpec()
+	c := &clusterConfig{
+		context:     &clusterd.Context{Clientset: clientset, Executor: executor, ConfigDir: configDir},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+		rookVersion: "v1.1.0",
+		clusterSpec: &cephv1.ClusterSpec{},
+		DataPathMap: config.NewStatelessDaemonDataPathMap(config.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+		scheme:      scheme.Scheme,
+	}
+	deployments := func() []apps.Deployment {
+		deps, err := clientset.AppsV1().Deployments(store.Namespace).List(metav1.ListOptions{})
+		assert.NoError(t, err)
+		return deps.Items
+	}
+
+	// a single deployment is scaled by the autoscaler
+	assert.NoError(t, c.createOrUpdateStore("realm-a", "zonegroup-a", "zone-a"))
+	deps := deployments()
+	assert.Equal(t, 1, len(deps))
+	assert.Equal(t, "rook-ceph-rgw-default-a", deps[0].Name)
+	assert.Equal(t, int32(2), *deps[0].Spec.Replicas)
+	assert.Equal(t, apps.RollingUpdateDeploymentStrategyType, deps[0].Spec.Strategy.Type)
+	hpa, err := clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "rook-ceph-rgw-default-a", hpa.Spec.ScaleTargetRef.Name)
+	assert.True(t, metav1.IsControlledBy(hpa, store))
+
+	// the replicas set by the autoscaler are kept
+	replicas := int32(7)
+	deps[0].Spec.Replicas = &replicas
+	_, err = clientset.AppsV1().Deployments(store.Namespace).Update(&deps[0])
+	assert.NoError(t, err)
+	store.Spec.Gateway.Autoscaling.MaxInstances = 20
+	assert.NoError(t, c.createOrUpdateStore("realm-a", "zonegroup-a", "zone-a"))
+	deps = deployments()
+	assert.Equal(t, 1, len(deps))
+	assert.Equal(t, int32(7), *deps[0].Spec.Replicas)
+	hpa, err = clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(20), hpa.Spec.MaxReplicas)
+
+	// the instances are started again without autoscaling
+	store.Spec.Gateway.Autoscaling = nil
+	assert.NoError(t, c.createOrUpdateStore("realm-a", "zonegroup-a", "zone-a"))
+	deps = deployments()
+	assert.Equal(t, 3, len(deps))
+	for _, d := range deps {
+		assert.Equal(t, int32(1), *d.Spec.Replicas)
+		assert.Equal(t, apps.RecreateDeploymentStrategyType, d.Spec.Strategy.Type)
+	}
+	_, err = clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.Error(t, err)
+}
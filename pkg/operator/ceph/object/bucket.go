@@ -49,10 +49,19 @@ type ObjectBucket struct {
 
 type rgwBucketStats struct {
 	Bucket string `json:"bucket"`
+	Owner  string `json:"owner"`
 	Usage  map[string]struct {
 		Size            uint64 `json:"size"`
 		NumberOfObjects uint64 `json:"num_objects"`
 	}
+	BucketQuota rgwQuota `json:"bucket_quota"`
+}
+
+// rgwQuota is the quota of a bucket or a user, the limits are negative when they are not set
+type rgwQuota struct {
+	Enabled    bool  `json:"enabled"`
+	MaxSize    int64 `json:"max_size"`
+	MaxObjects int64 `json:"max_objects"`
 }
 
 type ObjectBuckets []ObjectBucket
@@ -103,6 +112,22 @@ func GetBucketStats(c *Context, bucketName string) (*ObjectBucketStats, bool, er
 }
 
 func GetBucketsStats(c *Context) (map[string]ObjectBucketStats, error) {
+	rgwStats, err := listBucketsStats(c)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := map[string]ObjectBucketStats{}
+
+	for _, rgwStat := range rgwStats {
+		stats[rgwStat.Bucket] = bucketStatsFromRGW(rgwStat)
+	}
+
+	return stats, nil
+}
+
+// listBucketsStats returns the stats of all the buckets as reported by rgw
+func listBucketsStats(c *Context) ([]rgwBucketStats, error) {
 	result, err := runAdminCommand(c,
 		"bucket",
 		"stats")
@@ -114,14 +139,7 @@ func GetBucketsStats(c *Context) (map[string]ObjectBucketStats, error) {
 	if err := json.Unmarshal([]byte(result), &rgwStats); err != nil {
 		return nil, errors.Wrapf(err, "failed to read buckets stats result=%s", result)
 	}
-
-	stats := map[string]ObjectBucketStats{}
-
-	for _, rgwStat := range rgwStats {
-		stats[rgwStat.Bucket] = bucketStatsFromRGW(rgwStat)
-	}
-
-	return stats, nil
+	return rgwStats, nil
 }
 
 func getBucketMetadata(c *Context, bucket string) (*ObjectBucketMetadata, bool, error) {
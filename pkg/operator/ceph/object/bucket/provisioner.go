@@ -21,6 +21,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
 	apibkt "github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner/api"
 	storagev1 "k8s.io/api/storage/v1"
 
@@ -46,6 +47,15 @@ type Provisioner struct {
 	objectStoreName      string
 	endpoint             string
 	additionalConfigData map[string]string
+	// versioning, object lock and lifecycle settings of the bucket
+	settings *bucketSettings
+	// placement target of the bucket, the default placement target of the zonegroup if empty
+	placementTarget string
+	// the "<namespace>/<name>" of the OBC owning the bucket and the access granted to it when the bucket is shared
+	sharedBucketClaim  string
+	sharedBucketAccess string
+	// client of the OBCs and OBs, to get the bucket shared by another OBC
+	bucketClient bktclient.Interface
 }
 
 var _ apibkt.Provisioner = &Provisioner{}
@@ -64,6 +74,21 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 	if err != nil {
 		return nil, err
 	}
+
+	// the claim may request access to the bucket of another claim instead of a new bucket
+	request, err := parseSharedBucketRequest(options.ObjectBucketClaim)
+	if err != nil {
+		return nil, err
+	}
+	if request != nil {
+		if *p.settings != (bucketSettings{}) {
+			logger.Warningf("ignoring versioning, object lock and lifecycle settings of OBC %q, they only apply to new buckets", options.ObjectBucketClaim.Name)
+		}
+		if err := p.grantSharedBucket(options.ObjectBucketClaim, request); err != nil {
+			return nil, errors.Wrapf(err, "failed to grant access to the bucket of OBC %q", request.claimKey())
+		}
+		return p.composeObjectBucket(), nil
+	}
 	logger.Infof("Provision: creating bucket %q for OBC %q", p.bucketName, options.ObjectBucketClaim.Name)
 
 	// dynamically create a new ceph user
@@ -77,8 +102,8 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 		return nil, err
 	}
 
-	// create the bucket
-	err = s3svc.CreateBucket(p.bucketName)
+	// create the bucket, object lock and the placement can only be set when the bucket is created
+	err = s3svc.CreateBucketInPlacement(p.bucketName, p.placementTarget, p.settings.objectLock)
 	if err != nil {
 		err = errors.Wrapf(err, "error creating bucket %q", p.bucketName)
 		logger.Errorf(err.Error())
@@ -100,6 +125,13 @@ func (p Provisioner) Provision(options *apibkt.BucketOptions) (*bktv1alpha1.Obje
 		return nil, err
 	}
 
+	// setting versioning, object lock and lifecycle if they are requested
+	err = applyBucketSettings(s3svc, p.bucketName, p.settings, nil)
+	if err != nil {
+		p.deleteOBCResourceLogError(p.bucketName)
+		return nil, err
+	}
+
 	return p.composeObjectBucket(), nil
 }
 
@@ -113,6 +145,9 @@ func (p Provisioner) Grant(options *apibkt.BucketOptions) (*bktv1alpha1.ObjectBu
 		return nil, err
 	}
 	logger.Infof("Grant: allowing access to bucket %q for OBC %q", p.bucketName, options.ObjectBucketClaim.Name)
+	if *p.settings != (bucketSettings{}) {
+		logger.Warningf("ignoring versioning, object lock and lifecycle settings of OBC %q, they only apply to new buckets", options.ObjectBucketClaim.Name)
+	}
 
 	// check and make sure the bucket exists
 	logger.Infof("Checking for existing bucket %q", p.bucketName)
@@ -201,6 +236,20 @@ func (p Provisioner) Delete(ob *bktv1alpha1.ObjectBucket) error {
 	if err != nil {
 		return err
 	}
+	// the bucket shared by another claim is not deleted
+	if isSharedBucket(ob) {
+		return p.revokeSharedBucket()
+	}
+	// the bucket is not deleted while other claims share it, the deletion is retried until they are deleted
+	if p.bucketClient != nil {
+		consumers, err := sharedBucketConsumers(p.bucketClient, ob)
+		if err != nil {
+			return err
+		}
+		if len(consumers) > 0 {
+			return errors.Errorf("bucket %q of OB %q is still shared with OBCs %v, delete them first", p.bucketName, ob.Name, consumers)
+		}
+	}
 	logger.Infof("Delete: deleting bucket %q for OB %q", p.bucketName, ob.Name)
 
 	if err := p.deleteOBCResource(p.bucketName); err != nil {
@@ -217,6 +266,9 @@ func (p Provisioner) Revoke(ob *bktv1alpha1.ObjectBucket) error {
 	if err != nil {
 		return err
 	}
+	if isSharedBucket(ob) {
+		return p.revokeSharedBucket()
+	}
 	logger.Infof("Revoke: denying access to bucket %q for OB %q", p.bucketName, ob.Name)
 
 	bucket, _, err := cephObject.GetBucket(p.objectContext, p.bucketName)
@@ -320,7 +372,12 @@ func (p *Provisioner) initializeCreateOrGrant(options *apibkt.BucketOptions) err
 
 	p.setObjectStoreName(sc)
 	p.setRegion(sc)
+	p.setPlacementTarget(sc)
 	p.setAdditionalConfigData(obc.Spec.AdditionalConfig)
+	p.settings, err = parseBucketSettings(bucketConfig(sc.Parameters, obc.Spec.AdditionalConfig))
+	if err != nil {
+		return errors.Wrapf(err, "invalid bucket settings of OBC %q", obc.Name)
+	}
 	p.setEndpoint(sc)
 	err = p.setObjectContext()
 	if err != nil {
@@ -383,6 +440,11 @@ func (p *Provisioner) composeObjectBucket() *bktv1alpha1.ObjectBucket {
 		},
 	}
 
+	if p.sharedBucketClaim != "" {
+		conn.AdditionalState[sharedBucketClaimKey] = p.sharedBucketClaim
+		conn.AdditionalState[sharedBucketAccessKey] = p.sharedBucketAccess
+	}
+
 	return &bktv1alpha1.ObjectBucket{
 		Spec: bktv1alpha1.ObjectBucketSpec{
 			Connection: conn,
@@ -471,6 +533,11 @@ func (p *Provisioner) setRegion(sc *storagev1.StorageClass) {
 	p.region = sc.Parameters[key]
 }
 
+func (p *Provisioner) setPlacementTarget(sc *storagev1.StorageClass) {
+	const key = "placementTarget"
+	p.placementTarget = sc.Parameters[key]
+}
+
 func (p Provisioner) getObjectStoreEndpoint() string {
 	return fmt.Sprintf("%s:%d", p.storeDomainName, p.storePort)
 }
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+)
+
+// The settings of the bucket that can be set in the additionalConfig of the OBC or in the parameters of the
+// StorageClass, the OBC takes precedence
+const (
+	bucketVersioningKey                   = "bucketVersioning"
+	objectLockEnabledKey                  = "objectLockEnabled"
+	objectLockModeKey                     = "objectLockMode"
+	objectLockRetentionDaysKey            = "objectLockRetentionDays"
+	objectLockRetentionYearsKey           = "objectLockRetentionYears"
+	lifecyclePrefixKey                    = "lifecyclePrefix"
+	expirationDaysKey                     = "expirationDays"
+	noncurrentVersionExpirationDaysKey    = "noncurrentVersionExpirationDays"
+	abortIncompleteMultipartUploadDaysKey = "abortIncompleteMultipartUploadDays"
+
+	// lifecycleRuleID is the ID of the lifecycle rule of the bucket managed by the provisioner
+	lifecycleRuleID = "rook-obc-lifecycle"
+)
+
+var bucketSettingKeys = []string{
+	bucketVersioningKey,
+	objectLockEnabledKey,
+	objectLockModeKey,
+	objectLockRetentionDaysKey,
+	objectLockRetentionYearsKey,
+	lifecyclePrefixKey,
+	expirationDaysKey,
+	noncurrentVersionExpirationDaysKey,
+	abortIncompleteMultipartUploadDaysKey,
+}
+
+// bucketSettings are the versioning, object lock and lifecycle settings of a bucket
+type bucketSettings struct {
+	versioning                         string
+	objectLock                         bool
+	objectLockMode                     string
+	objectLockRetentionDays            int64
+	objectLockRetentionYears           int64
+	lifecyclePrefix                    string
+	expirationDays                     int64
+	noncurrentVersionExpirationDays    int64
+	abortIncompleteMultipartUploadDays int64
+}
+
+// bucketConfig returns the bucket settings of the StorageClass parameters overridden by the ones of the OBC
+func bucketConfig(scParameters, additionalConfig map[string]string) map[string]string {
+	config := map[string]string{}
+	for _, key := range bucketSettingKeys {
+		if value, ok := scParameters[key]; ok {
+			config[key] = value
+		}
+		if value, ok := additionalConfig[key]; ok {
+			config[key] = value
+		}
+	}
+	return config
+}
+
+// parseBucketSettings parses and validates the bucket settings
+func parseBucketSettings(config map[string]string) (*bucketSettings, error) {
+	var err error
+	settings := &bucketSettings{lifecyclePrefix: config[lifecyclePrefixKey]}
+
+	if versioning := config[bucketVersioningKey]; versioning != "" {
+		switch {
+		case strings.EqualFold(versioning, s3.BucketVersioningStatusEnabled):
+			settings.versioning = s3.BucketVersioningStatusEnabled
+		case strings.EqualFold(versioning, s3.BucketVersioningStatusSuspended):
+			settings.versioning = s3.BucketVersioningStatusSuspended
+		default:
+			return nil, errors.Errorf("invalid %s %q, expected %q or %q", bucketVersioningKey, versioning, s3.BucketVersioningStatusEnabled, s3.BucketVersioningStatusSuspended)
+		}
+	}
+
+	if objectLock := config[objectLockEnabledKey]; objectLock != "" {
+		if settings.objectLock, err = strconv.ParseBool(objectLock); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s %q", objectLockEnabledKey, objectLock)
+		}
+	}
+	if mode := config[objectLockModeKey]; mode != "" {
+		settings.objectLockMode = strings.ToUpper(mode)
+		if settings.objectLockMode != s3.ObjectLockRetentionModeGovernance && settings.objectLockMode != s3.ObjectLockRetentionModeCompliance {
+			return nil, errors.Errorf("invalid %s %q, expected %q or %q", objectLockModeKey, mode, s3.ObjectLockRetentionModeGovernance, s3.ObjectLockRetentionModeCompliance)
+		}
+	}
+
+	days := map[string]*int64{
+		objectLockRetentionDaysKey:            &settings.objectLockRetentionDays,
+		objectLockRetentionYearsKey:           &settings.objectLockRetentionYears,
+		expirationDaysKey:                     &settings.expirationDays,
+		noncurrentVersionExpirationDaysKey:    &settings.noncurrentVersionExpirationDays,
+		abortIncompleteMultipartUploadDaysKey: &settings.abortIncompleteMultipartUploadDays,
+	}
+	for key, value := range days {
+		if config[key] == "" {
+			continue
+		}
+		if *value, err = strconv.ParseInt(config[key], 10, 64); err != nil || *value <= 0 {
+			return nil, errors.Errorf("invalid %s %q, expected a positive number", key, config[key])
+		}
+	}
+
+	if settings.objectLockMode != "" && !settings.objectLock {
+		return nil, errors.Errorf("%s requires %s", objectLockModeKey, objectLockEnabledKey)
+	}
+	if settings.objectLockMode != "" && (settings.objectLockRetentionDays > 0) == (settings.objectLockRetentionYears > 0) {
+		return nil, errors.Errorf("%s requires exactly one of %s or %s", objectLockModeKey, objectLockRetentionDaysKey, objectLockRetentionYearsKey)
+	}
+	if settings.objectLockMode == "" && (settings.objectLockRetentionDays > 0 || settings.objectLockRetentionYears > 0) {
+		return nil, errors.Errorf("the object lock retention requires %s", objectLockModeKey)
+	}
+	if settings.objectLock && settings.versioning == s3.BucketVersioningStatusSuspended {
+		return nil, errors.New("the versioning of a bucket with object lock cannot be suspended")
+	}
+
+	return settings, nil
+}
+
+func (s *bucketSettings) hasLifecycle() bool {
+	return s.expirationDays > 0 || s.noncurrentVersionExpirationDays > 0 || s.abortIncompleteMultipartUploadDays > 0
+}
+
+// lifecycleRules returns the lifecycle rule of the bucket applying to the objects with the lifecycle prefix
+func (s *bucketSettings) lifecycleRules() []*s3.LifecycleRule {
+	rule := &s3.LifecycleRule{
+		ID:     aws.String(lifecycleRuleID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+		Filter: &s3.LifecycleRuleFilter{Prefix: aws.String(s.lifecyclePrefix)},
+	}
+	if s.expirationDays > 0 {
+		rule.Expiration = &s3.LifecycleExpiration{Days: aws.Int64(s.expirationDays)}
+	}
+	if s.noncurrentVersionExpirationDays > 0 {
+		rule.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{NoncurrentDays: aws.Int64(s.noncurrentVersionExpirationDays)}
+	}
+	if s.abortIncompleteMultipartUploadDays > 0 {
+		rule.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int64(s.abortIncompleteMultipartUploadDays)}
+	}
+	return []*s3.LifecycleRule{rule}
+}
+
+// applyBucketSettings sets the versioning, object lock retention and lifecycle of the bucket. The previous settings
+// are the ones applied before an update of the OBC, the lifecycle and retention removed since then are removed from
+// the bucket. The versioning of a bucket cannot be disabled once enabled, it can only be suspended.
+func applyBucketSettings(s3svc *cephObject.S3Agent, bucket string, settings, previous *bucketSettings) error {
+	if settings.versioning != "" {
+		if err := s3svc.PutBucketVersioning(bucket, settings.versioning); err != nil {
+			return err
+		}
+		logger.Infof("set versioning of bucket %q to %q", bucket, settings.versioning)
+	}
+
+	if settings.objectLockMode != "" {
+		if err := s3svc.PutObjectLockConfiguration(bucket, settings.objectLockMode, settings.objectLockRetentionDays, settings.objectLockRetentionYears); err != nil {
+			return err
+		}
+		logger.Infof("set default object lock retention of bucket %q", bucket)
+	} else if previous != nil && previous.objectLockMode != "" {
+		if err := s3svc.PutObjectLockConfiguration(bucket, "", 0, 0); err != nil {
+			return err
+		}
+		logger.Infof("removed default object lock retention of bucket %q", bucket)
+	}
+
+	if settings.hasLifecycle() || (previous != nil && previous.hasLifecycle()) {
+		if err := applyLifecycleRule(s3svc, bucket, settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyLifecycleRule replaces the lifecycle rule of the OBC in the lifecycle of the bucket, or removes it when the
+// OBC has no lifecycle. The rules added to the bucket by its users are kept.
+func applyLifecycleRule(s3svc *cephObject.S3Agent, bucket string, settings *bucketSettings) error {
+	current, err := s3svc.GetBucketLifecycle(bucket)
+	if err != nil {
+		return err
+	}
+	rules := []*s3.LifecycleRule{}
+	for _, rule := range current {
+		if aws.StringValue(rule.ID) != lifecycleRuleID {
+			rules = append(rules, rule)
+		}
+	}
+	if settings.hasLifecycle() {
+		rules = append(rules, settings.lifecycleRules()...)
+	}
+
+	if len(rules) == 0 {
+		if len(current) == 0 {
+			return nil
+		}
+		if err := s3svc.DeleteBucketLifecycle(bucket); err != nil {
+			return err
+		}
+		logger.Infof("removed lifecycle of bucket %q", bucket)
+		return nil
+	}
+	if err := s3svc.PutBucketLifecycle(bucket, rules); err != nil {
+		return err
+	}
+	logger.Infof("set lifecycle of bucket %q", bucket)
+	return nil
+}
+
+// validateObjectLockUpdate makes sure an update does not enable object lock, it can only be enabled when the bucket is
+// created. When the previous settings are unknown, object lock must already be enabled on the bucket.
+func validateObjectLockUpdate(s3svc *cephObject.S3Agent, bucket string, settings, previous *bucketSettings) error {
+	if !settings.objectLock || (previous != nil && previous.objectLock) {
+		return nil
+	}
+	if previous == nil {
+		enabled, err := s3svc.GetObjectLockEnabled(bucket)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			return nil
+		}
+	}
+	return errors.Errorf("object lock of bucket %q can only be enabled when the bucket is created", bucket)
+}
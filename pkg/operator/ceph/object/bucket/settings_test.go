@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketConfig(t *testing.T) {
+	scParameters := map[string]string{
+		"objectStoreName":  "my-store",
+		"bucketVersioning": "Enabled",
+		"expirationDays":   "30",
+	}
+	additionalConfig := map[string]string{
+		"maxObjects":     "1000",
+		"expirationDays": "7",
+	}
+
+	config := bucketConfig(scParameters, additionalConfig)
+	assert.Equal(t, map[string]string{"bucketVersioning": "Enabled", "expirationDays": "7"}, config)
+}
+
+func TestParseBucketSettings(t *testing.T) {
+	settings, err := parseBucketSettings(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, bucketSettings{}, *settings)
+	assert.False(t, settings.hasLifecycle())
+
+	settings, err = parseBucketSettings(map[string]string{
+		"bucketVersioning":                   "enabled",
+		"objectLockEnabled":                  "true",
+		"objectLockMode":                     "governance",
+		"objectLockRetentionDays":            "10",
+		"lifecyclePrefix":                    "logs/",
+		"expirationDays":                     "30",
+		"noncurrentVersionExpirationDays":    "7",
+		"abortIncompleteMultipartUploadDays": "1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, bucketSettings{
+		versioning:                         "Enabled",
+		objectLock:                         true,
+		objectLockMode:                     "GOVERNANCE",
+		objectLockRetentionDays:            10,
+		lifecyclePrefix:                    "logs/",
+		expirationDays:                     30,
+		noncurrentVersionExpirationDays:    7,
+		abortIncompleteMultipartUploadDays: 1,
+	}, *settings)
+	assert.True(t, settings.hasLifecycle())
+
+	rules := settings.lifecycleRules()
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "logs/", aws.StringValue(rules[0].Filter.Prefix))
+	assert.Equal(t, int64(30), aws.Int64Value(rules[0].Expiration.Days))
+	assert.Equal(t, int64(7), aws.Int64Value(rules[0].NoncurrentVersionExpiration.NoncurrentDays))
+	assert.Equal(t, int64(1), aws.Int64Value(rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation))
+
+	invalid := []map[string]string{
+		{"bucketVersioning": "Disabled"},
+		{"objectLockEnabled": "yes please"},
+		{"objectLockEnabled": "true", "objectLockMode": "LEGAL_HOLD", "objectLockRetentionDays": "1"},
+		// mode without object lock
+		{"objectLockMode": "COMPLIANCE", "objectLockRetentionDays": "1"},
+		// mode without retention or with both retentions
+		{"objectLockEnabled": "true", "objectLockMode": "COMPLIANCE"},
+		{"objectLockEnabled": "true", "objectLockMode": "COMPLIANCE", "objectLockRetentionDays": "1", "objectLockRetentionYears": "1"},
+		// retention without mode
+		{"objectLockEnabled": "true", "objectLockRetentionYears": "1"},
+		{"objectLockEnabled": "true", "bucketVersioning": "Suspended"},
+		{"expirationDays": "0"},
+		{"noncurrentVersionExpirationDays": "a week"},
+	}
+	for _, config := range invalid {
+		_, err = parseBucketSettings(config)
+		assert.Error(t, err, config)
+	}
+}
+
+func TestApplyBucketSettings(t *testing.T) {
+	requests := []string{}
+	lifecycle := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if lifecycle == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("<Error><Code>NoSuchLifecycleConfiguration</Code></Error>"))
+				return
+			}
+			_, _ = w.Write([]byte(lifecycle))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RawQuery+" "+string(body))
+		if strings.HasPrefix(r.URL.RawQuery, "lifecycle") {
+			if r.Method == http.MethodPut {
+				lifecycle = string(body)
+			} else {
+				lifecycle = ""
+			}
+		}
+	}))
+	defer server.Close()
+	s3svc, err := cephObject.NewS3Agent("access", "secret", server.URL, false)
+	assert.NoError(t, err)
+
+	// nothing to set
+	err = applyBucketSettings(s3svc, "my-bucket", &bucketSettings{}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	settings := &bucketSettings{
+		versioning:              "Enabled",
+		objectLock:              true,
+		objectLockMode:          "COMPLIANCE",
+		objectLockRetentionDays: 10,
+		expirationDays:          30,
+	}
+	err = applyBucketSettings(s3svc, "my-bucket", settings, nil)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 3)
+	assert.Contains(t, requests[0], "PUT versioning=")
+	assert.Contains(t, requests[0], "<Status>Enabled</Status>")
+	assert.Contains(t, requests[1], "PUT object-lock=")
+	assert.Contains(t, requests[1], "<Mode>COMPLIANCE</Mode>")
+	assert.Contains(t, requests[1], "<Days>10</Days>")
+	assert.Contains(t, requests[2], "PUT lifecycle=")
+	assert.Contains(t, requests[2], "<Expiration><Days>30</Days></Expiration>")
+
+	// the retention and the lifecycle were removed from the OBC
+	requests = []string{}
+	err = applyBucketSettings(s3svc, "my-bucket", &bucketSettings{objectLock: true}, settings)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Contains(t, requests[0], "PUT object-lock=")
+	assert.NotContains(t, requests[0], "<Rule>")
+	assert.Contains(t, requests[1], "DELETE lifecycle=")
+
+	// the rules of the users are kept
+	lifecycle = `<LifecycleConfiguration>
+<Rule><ID>user-rule</ID><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Expiration><Days>7</Days></Expiration></Rule>
+<Rule><ID>rook-obc-lifecycle</ID><Status>Enabled</Status><Filter><Prefix></Prefix></Filter><Expiration><Days>30</Days></Expiration></Rule>
+</LifecycleConfiguration>`
+	requests = []string{}
+	err = applyBucketSettings(s3svc, "my-bucket", &bucketSettings{expirationDays: 60}, &bucketSettings{expirationDays: 30})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Contains(t, requests[0], "PUT lifecycle=")
+	assert.Contains(t, requests[0], "<ID>user-rule</ID>")
+	assert.Contains(t, requests[0], "<Expiration><Days>60</Days></Expiration>")
+	assert.NotContains(t, requests[0], "<Expiration><Days>30</Days></Expiration>")
+
+	requests = []string{}
+	err = applyBucketSettings(s3svc, "my-bucket", &bucketSettings{}, &bucketSettings{expirationDays: 60})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Contains(t, requests[0], "PUT lifecycle=")
+	assert.Contains(t, requests[0], "<ID>user-rule</ID>")
+	assert.NotContains(t, requests[0], "rook-obc-lifecycle")
+}
+
+func TestValidateObjectLockUpdate(t *testing.T) {
+	objectLock := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if objectLock == "" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>ObjectLockConfigurationNotFoundError</Code></Error>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<ObjectLockConfiguration><ObjectLockEnabled>` + objectLock + `</ObjectLockEnabled></ObjectLockConfiguration>`))
+	}))
+	defer server.Close()
+	s3svc, err := cephObject.NewS3Agent("access", "secret", server.URL, false)
+	assert.NoError(t, err)
+
+	// object lock is not requested or was already enabled
+	assert.NoError(t, validateObjectLockUpdate(s3svc, "my-bucket", &bucketSettings{}, nil))
+	assert.NoError(t, validateObjectLockUpdate(s3svc, "my-bucket", &bucketSettings{objectLock: true}, &bucketSettings{objectLock: true}))
+
+	// object lock cannot be enabled on an existing bucket
+	assert.Error(t, validateObjectLockUpdate(s3svc, "my-bucket", &bucketSettings{objectLock: true}, &bucketSettings{}))
+
+	// the bucket is checked when the previous settings are unknown
+	assert.Error(t, validateObjectLockUpdate(s3svc, "my-bucket", &bucketSettings{objectLock: true}, nil))
+	objectLock = "Enabled"
+	assert.NoError(t, validateObjectLockUpdate(s3svc, "my-bucket", &bucketSettings{objectLock: true}, nil))
+}
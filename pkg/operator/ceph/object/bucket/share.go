@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// sharedBucketClaimKey is the additional config of an OBC requesting access to the bucket of another OBC, in the
+	// "<namespace>/<name>" format. It is also kept in the additional state of the OB of the consumer.
+	sharedBucketClaimKey = "sharedBucketClaim"
+	// sharedBucketAccessKey is the access requested to the shared bucket, read-only by default
+	sharedBucketAccessKey = "sharedBucketAccess"
+	// sharedBucketAllowListAnnotation is the comma separated list of the claims allowed to access the bucket of an
+	// OBC. The entries are "<namespace>/<name>" or "<namespace>/*", followed by "=read-only" to restrict the access.
+	sharedBucketAllowListAnnotation = "ceph.rook.io/shared-bucket-allowlist"
+
+	readOnlyAccess  = "read-only"
+	readWriteAccess = "read-write"
+)
+
+// sharedBucketRequest is the request of an OBC to access the bucket of another OBC
+type sharedBucketRequest struct {
+	namespace string
+	name      string
+	access    string
+}
+
+func (r *sharedBucketRequest) claimKey() string {
+	return fmt.Sprintf("%s/%s", r.namespace, r.name)
+}
+
+// parseSharedBucketRequest returns the request of the OBC to access the bucket of another OBC, nil if the OBC
+// does not request a shared bucket
+func parseSharedBucketRequest(obc *bktv1alpha1.ObjectBucketClaim) (*sharedBucketRequest, error) {
+	claim := obc.Spec.AdditionalConfig[sharedBucketClaimKey]
+	if claim == "" {
+		return nil, nil
+	}
+	parts := strings.Split(claim, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("invalid %s %q, expected \"<namespace>/<name>\"", sharedBucketClaimKey, claim)
+	}
+	if parts[0] == obc.Namespace && parts[1] == obc.Name {
+		return nil, errors.Errorf("OBC %q cannot request access to its own bucket", obc.Name)
+	}
+	access := obc.Spec.AdditionalConfig[sharedBucketAccessKey]
+	switch access {
+	case "":
+		access = readOnlyAccess
+	case readOnlyAccess, readWriteAccess:
+	default:
+		return nil, errors.Errorf("invalid %s %q, expected %q or %q", sharedBucketAccessKey, access, readOnlyAccess, readWriteAccess)
+	}
+	return &sharedBucketRequest{namespace: parts[0], name: parts[1], access: access}, nil
+}
+
+// sharedBucketAllowed returns whether the allow-list of the owner OBC grants the access to a claim
+func sharedBucketAllowed(owner *bktv1alpha1.ObjectBucketClaim, namespace, name, access string) bool {
+	for _, entry := range strings.Split(owner.Annotations[sharedBucketAllowListAnnotation], ",") {
+		claim, maxAccess := strings.TrimSpace(entry), readWriteAccess
+		if i := strings.Index(claim, "="); i >= 0 {
+			claim, maxAccess = strings.TrimSpace(claim[:i]), strings.TrimSpace(claim[i+1:])
+		}
+		if claim != fmt.Sprintf("%s/%s", namespace, name) && claim != fmt.Sprintf("%s/*", namespace) {
+			continue
+		}
+		if maxAccess == readWriteAccess || (maxAccess == readOnlyAccess && access == readOnlyAccess) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSharedBucket(ob *bktv1alpha1.ObjectBucket) bool {
+	return ob.Spec.AdditionalState[sharedBucketClaimKey] != ""
+}
+
+// sharedBucketConsumers returns the "<namespace>/<name>" of the claims sharing the bucket of the OB of an OBC
+func sharedBucketConsumers(client bktclient.Interface, ob *bktv1alpha1.ObjectBucket) ([]string, error) {
+	if ob.Spec.ClaimRef == nil {
+		return []string{}, nil
+	}
+	obs, err := client.ObjectbucketV1alpha1().ObjectBuckets().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list object buckets")
+	}
+
+	ownerKey := fmt.Sprintf("%s/%s", ob.Spec.ClaimRef.Namespace, ob.Spec.ClaimRef.Name)
+	consumers := []string{}
+	for _, consumer := range obs.Items {
+		if consumer.Spec.AdditionalState[sharedBucketClaimKey] != ownerKey || consumer.Spec.ClaimRef == nil {
+			continue
+		}
+		consumers = append(consumers, fmt.Sprintf("%s/%s", consumer.Spec.ClaimRef.Namespace, consumer.Spec.ClaimRef.Name))
+	}
+	return consumers, nil
+}
+
+// grantSharedBucket creates the user of an OBC and allows it to access the bucket of the OBC of the request
+func (p *Provisioner) grantSharedBucket(obc *bktv1alpha1.ObjectBucketClaim, request *sharedBucketRequest) error {
+	if p.bucketClient == nil {
+		return errors.New("object bucket client is not initialized")
+	}
+	owner, err := p.bucketClient.ObjectbucketV1alpha1().ObjectBucketClaims(request.namespace).Get(request.name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get OBC %q sharing its bucket", request.claimKey())
+	}
+	if !sharedBucketAllowed(owner, obc.Namespace, obc.Name, request.access) {
+		return errors.Errorf("OBC %q does not allow %s access of OBC \"%s/%s\" to its bucket in its %q annotation", request.claimKey(), request.access, obc.Namespace, obc.Name, sharedBucketAllowListAnnotation)
+	}
+	if owner.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound || owner.Spec.ObjectBucketName == "" {
+		return errors.Errorf("OBC %q sharing its bucket is not bound", request.claimKey())
+	}
+	ownerOB, err := p.bucketClient.ObjectbucketV1alpha1().ObjectBuckets().Get(owner.Spec.ObjectBucketName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get OB %q of OBC %q", owner.Spec.ObjectBucketName, request.claimKey())
+	}
+	if isSharedBucket(ownerOB) {
+		return errors.Errorf("OBC %q does not own its bucket", request.claimKey())
+	}
+	sc, err := p.getStorageClassWithBackoff(ownerOB.Spec.StorageClassName)
+	if err != nil {
+		return err
+	}
+	if sc.Provisioner != cephObject.GetObjectBucketProvisioner(p.context, p.clusterInfo.Namespace) || getObjectStoreName(sc) != p.objectStoreName {
+		return errors.Errorf("bucket of OBC %q is not in object store %q", request.claimKey(), p.objectStoreName)
+	}
+
+	p.setBucketName(getBucketName(ownerOB))
+	logger.Infof("granting %s access to bucket %q of OBC %q to OBC %q", request.access, p.bucketName, request.claimKey(), obc.Name)
+
+	p.accessKeyID, p.secretAccessKey, err = p.createCephUser("")
+	if err != nil {
+		return errors.Wrap(err, "failed to create ceph user")
+	}
+	// the user can only access the shared bucket
+	_, err = cephObject.SetQuotaUserBucketMax(p.objectContext, p.cephUserName, -1)
+	if err != nil {
+		p.deleteOBCResourceLogError("")
+		return err
+	}
+	if err := p.setSharedBucketStatement(p.cephUserName, request.access, true); err != nil {
+		p.deleteOBCResourceLogError("")
+		return err
+	}
+
+	p.sharedBucketClaim = request.claimKey()
+	p.sharedBucketAccess = request.access
+	return nil
+}
+
+// revokeSharedBucket removes the statement of the user of the OBC from the policy of the shared bucket and deletes
+// the user, the bucket is left to the OBC owning it
+func (p *Provisioner) revokeSharedBucket() error {
+	logger.Infof("revoking access of user %q to shared bucket %q", p.cephUserName, p.bucketName)
+	if err := p.setSharedBucketStatement(p.cephUserName, "", false); err != nil {
+		// the bucket may have been deleted with its OBC
+		logger.Warningf("failed to remove user %q from the policy of bucket %q. %v", p.cephUserName, p.bucketName, err)
+	}
+	p.deleteOBCResourceLogError("")
+	return nil
+}
+
+// setSharedBucketStatement adds or replaces the statement allowing a user to access the bucket, or drops it when
+// the access is not allowed. The policy is changed with the credentials of the owner of the bucket.
+func (p *Provisioner) setSharedBucketStatement(user, access string, allowed bool) error {
+	bucket, _, err := cephObject.GetBucket(p.objectContext, p.bucketName)
+	if err != nil {
+		return errors.Wrapf(err, "could not get bucket stats (bucket: %s)", p.bucketName)
+	}
+	owner, _, err := cephObject.GetUser(p.objectContext, bucket.Owner)
+	if err != nil {
+		return errors.Wrapf(err, "could not get user (user: %s)", bucket.Owner)
+	}
+	s3svc, err := cephObject.NewS3Agent(*owner.AccessKey, *owner.SecretKey, p.getObjectStoreEndpoint(), true)
+	if err != nil {
+		return err
+	}
+
+	policy, err := s3svc.GetBucketPolicy(p.bucketName)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchBucketPolicy" {
+			return errors.Wrapf(err, "failed to get policy of bucket %q", p.bucketName)
+		}
+		policy = nil
+	}
+
+	if !allowed {
+		if policy == nil {
+			return nil
+		}
+		policy = policy.DropPolicyStatements(user)
+		if len(policy.Statement) == 0 {
+			return s3svc.DeleteBucketPolicy(p.bucketName)
+		}
+	} else {
+		statement := sharedBucketStatement(user, p.bucketName, access)
+		if policy == nil {
+			policy = cephObject.NewBucketPolicy(*statement)
+		} else {
+			policy = policy.ModifyBucketPolicy(*statement)
+		}
+	}
+	if _, err := s3svc.PutBucketPolicy(p.bucketName, *policy); err != nil {
+		return errors.Wrapf(err, "failed to update policy of bucket %q", p.bucketName)
+	}
+	return nil
+}
+
+// sharedBucketStatement returns the policy statement allowing a user to access a bucket
+func sharedBucketStatement(user, bucket, access string) *cephObject.PolicyStatement {
+	statement := cephObject.NewPolicyStatement().
+		WithSID(user).
+		ForPrincipals(user).
+		ForResources(bucket).
+		ForSubResources(bucket).
+		Allows()
+	if access == readWriteAccess {
+		return statement.Actions(cephObject.AllowedActions...)
+	}
+	return statement.Actions(cephObject.ReadOnlyActions...)
+}
+
+// updateSharedBucketAccess updates the policy statements of the claims sharing the bucket of an OBC after a change
+// of its allow-list, the claims that are not allowed anymore lose their access
+func (p *Provisioner) updateSharedBucketAccess(client bktclient.Interface, owner *bktv1alpha1.ObjectBucketClaim, ob *bktv1alpha1.ObjectBucket) error {
+	obs, err := client.ObjectbucketV1alpha1().ObjectBuckets().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list object buckets")
+	}
+	if err := p.initializeDeleteOrRevoke(ob); err != nil {
+		return err
+	}
+
+	ownerKey := fmt.Sprintf("%s/%s", owner.Namespace, owner.Name)
+	for _, consumer := range obs.Items {
+		if consumer.Spec.AdditionalState[sharedBucketClaimKey] != ownerKey || consumer.Spec.ClaimRef == nil {
+			continue
+		}
+		claim := consumer.Spec.ClaimRef
+		user := getCephUser(&consumer)
+		access := consumer.Spec.AdditionalState[sharedBucketAccessKey]
+		allowed := sharedBucketAllowed(owner, claim.Namespace, claim.Name, access)
+		logger.Infof("updating %s access of OBC \"%s/%s\" to bucket %q of OBC %q, allowed: %t", access, claim.Namespace, claim.Name, p.bucketName, ownerKey, allowed)
+		if err := p.setSharedBucketStatement(user, access, allowed); err != nil {
+			return errors.Wrapf(err, "failed to update access of OBC \"%s/%s\"", claim.Namespace, claim.Name)
+		}
+	}
+	return nil
+}
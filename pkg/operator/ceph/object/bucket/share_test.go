@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"testing"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktfake "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func sharingClaim(allowList string) *bktv1alpha1.ObjectBucketClaim {
+	return &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "datasets",
+			Namespace:   "team-a",
+			Annotations: map[string]string{sharedBucketAllowListAnnotation: allowList},
+		},
+		Spec: bktv1alpha1.ObjectBucketClaimSpec{ObjectBucketName: "obc-team-a-datasets"},
+		Status: bktv1alpha1.ObjectBucketClaimStatus{
+			Phase: bktv1alpha1.ObjectBucketClaimStatusPhaseBound,
+		},
+	}
+}
+
+func consumerClaim(config map[string]string) *bktv1alpha1.ObjectBucketClaim {
+	return &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "team-b"},
+		Spec:       bktv1alpha1.ObjectBucketClaimSpec{AdditionalConfig: config},
+	}
+}
+
+func TestParseSharedBucketRequest(t *testing.T) {
+	request, err := parseSharedBucketRequest(consumerClaim(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, request)
+
+	request, err = parseSharedBucketRequest(consumerClaim(map[string]string{sharedBucketClaimKey: "team-a/datasets"}))
+	assert.NoError(t, err)
+	assert.Equal(t, &sharedBucketRequest{namespace: "team-a", name: "datasets", access: readOnlyAccess}, request)
+	assert.Equal(t, "team-a/datasets", request.claimKey())
+
+	request, err = parseSharedBucketRequest(consumerClaim(map[string]string{sharedBucketClaimKey: "team-a/datasets", sharedBucketAccessKey: "read-write"}))
+	assert.NoError(t, err)
+	assert.Equal(t, readWriteAccess, request.access)
+
+	invalid := []map[string]string{
+		{sharedBucketClaimKey: "datasets"},
+		{sharedBucketClaimKey: "team-a/"},
+		{sharedBucketClaimKey: "team-b/reader"},
+		{sharedBucketClaimKey: "team-a/datasets", sharedBucketAccessKey: "write-only"},
+	}
+	for i, config := range invalid {
+		_, err := parseSharedBucketRequest(consumerClaim(config))
+		assert.Error(t, err, i)
+	}
+}
+
+func TestSharedBucketAllowed(t *testing.T) {
+	assert.False(t, sharedBucketAllowed(sharingClaim(""), "team-b", "reader", readOnlyAccess))
+
+	owner := sharingClaim("team-b/reader, team-c/*=read-only")
+	assert.True(t, sharedBucketAllowed(owner, "team-b", "reader", readOnlyAccess))
+	assert.True(t, sharedBucketAllowed(owner, "team-b", "reader", readWriteAccess))
+	assert.False(t, sharedBucketAllowed(owner, "team-b", "writer", readOnlyAccess))
+	assert.True(t, sharedBucketAllowed(owner, "team-c", "any", readOnlyAccess))
+	assert.False(t, sharedBucketAllowed(owner, "team-c", "any", readWriteAccess))
+	assert.False(t, sharedBucketAllowed(owner, "team-d", "reader", readOnlyAccess))
+
+	// an invalid access does not allow anything
+	assert.False(t, sharedBucketAllowed(sharingClaim("team-b/reader=all"), "team-b", "reader", readOnlyAccess))
+}
+
+func TestSharedBucketStatement(t *testing.T) {
+	statement := sharedBucketStatement("ceph-user-abcd", "datasets-1234", readOnlyAccess)
+	assert.Equal(t, "ceph-user-abcd", statement.Sid)
+	assert.Equal(t, []string{"arn:aws:iam:::user/ceph-user-abcd"}, statement.Principal["AWS"])
+	assert.Equal(t, []string{"arn:aws:s3:::datasets-1234", "arn:aws:s3:::datasets-1234/*"}, statement.Resource)
+	assert.Equal(t, len(cephObject.ReadOnlyActions), len(statement.Action))
+
+	statement = sharedBucketStatement("ceph-user-abcd", "datasets-1234", readWriteAccess)
+	assert.Equal(t, len(cephObject.AllowedActions), len(statement.Action))
+}
+
+func TestGrantSharedBucketValidation(t *testing.T) {
+	clusterInfo := client.AdminClusterInfo("rook-ceph")
+	consumer := consumerClaim(map[string]string{sharedBucketClaimKey: "team-a/datasets", sharedBucketAccessKey: readWriteAccess})
+	request, err := parseSharedBucketRequest(consumer)
+	assert.NoError(t, err)
+	grant := func(objects ...runtime.Object) error {
+		p := NewProvisioner(&clusterd.Context{}, clusterInfo)
+		p.bucketClient = bktfake.NewSimpleClientset(objects...)
+		return p.grantSharedBucket(consumer, request)
+	}
+
+	// the owner does not exist
+	assert.Error(t, grant())
+
+	// the owner only allows read-only access
+	assert.Error(t, grant(sharingClaim("team-b/reader=read-only")))
+
+	// the owner is not bound yet
+	owner := sharingClaim("team-b/reader")
+	owner.Status.Phase = bktv1alpha1.ObjectBucketClaimStatusPhasePending
+	assert.Error(t, grant(owner))
+
+	// the owner does not own the bucket it claims
+	ob := &bktv1alpha1.ObjectBucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "obc-team-a-datasets"},
+		Spec: bktv1alpha1.ObjectBucketSpec{
+			Connection: &bktv1alpha1.Connection{
+				Endpoint:        &bktv1alpha1.Endpoint{BucketName: "datasets-1234"},
+				AdditionalState: map[string]string{cephUser: "ceph-user-abcd", sharedBucketClaimKey: "team-c/origin"},
+			},
+		},
+	}
+	assert.Error(t, grant(sharingClaim("team-b/reader"), ob))
+}
+
+func TestComposeSharedObjectBucket(t *testing.T) {
+	p := &Provisioner{bucketName: "datasets-1234", cephUserName: "ceph-user-efgh"}
+	ob := p.composeObjectBucket()
+	assert.False(t, isSharedBucket(ob))
+
+	p.sharedBucketClaim = "team-a/datasets"
+	p.sharedBucketAccess = readOnlyAccess
+	ob = p.composeObjectBucket()
+	assert.True(t, isSharedBucket(ob))
+	assert.Equal(t, map[string]string{
+		cephUser:              "ceph-user-efgh",
+		sharedBucketClaimKey:  "team-a/datasets",
+		sharedBucketAccessKey: readOnlyAccess,
+	}, ob.Spec.AdditionalState)
+	assert.Equal(t, "datasets-1234", getBucketName(ob))
+}
+
+func TestSharedBucketConsumers(t *testing.T) {
+	owner := &bktv1alpha1.ObjectBucket{
+		ObjectMeta: metav1.ObjectMeta{Name: "obc-team-a-datasets"},
+		Spec: bktv1alpha1.ObjectBucketSpec{
+			ClaimRef:   &v1.ObjectReference{Namespace: "team-a", Name: "datasets"},
+			Connection: &bktv1alpha1.Connection{AdditionalState: map[string]string{cephUser: "ceph-user-abcd"}},
+		},
+	}
+	consumer := func(name, claim string) *bktv1alpha1.ObjectBucket {
+		return &bktv1alpha1.ObjectBucket{
+			ObjectMeta: metav1.ObjectMeta{Name: "obc-team-b-" + name},
+			Spec: bktv1alpha1.ObjectBucketSpec{
+				ClaimRef:   &v1.ObjectReference{Namespace: "team-b", Name: name},
+				Connection: &bktv1alpha1.Connection{AdditionalState: map[string]string{sharedBucketClaimKey: claim}},
+			},
+		}
+	}
+
+	// no claim shares the bucket
+	client := bktfake.NewSimpleClientset(owner, consumer("other", "team-c/datasets"))
+	consumers, err := sharedBucketConsumers(client, owner)
+	assert.NoError(t, err)
+	assert.Empty(t, consumers)
+
+	client = bktfake.NewSimpleClientset(owner, consumer("reader", "team-a/datasets"), consumer("other", "team-c/datasets"))
+	consumers, err = sharedBucketConsumers(client, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"team-b/reader"}, consumers)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
+	bktinformers "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/informers/externalversions"
+	apibkt "github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner/api"
+	"github.com/pkg/errors"
+	cephObject "github.com/rook/rook/pkg/operator/ceph/object"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// claimUpdates are the updates of the OBCs waiting to be applied to their bucket. The failed updates are retried with
+// a rate limit until they succeed or the OBC is deleted.
+type claimUpdates struct {
+	sync.Mutex
+	// applied is the claim whose config was last applied to the bucket, the next update is compared to it
+	applied map[string]*bktv1alpha1.ObjectBucketClaim
+	queue   workqueue.RateLimitingInterface
+}
+
+func newClaimUpdates() *claimUpdates {
+	return &claimUpdates{
+		applied: map[string]*bktv1alpha1.ObjectBucketClaim{},
+		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "obc-updates"),
+	}
+}
+
+// add queues the update of a claim, the old claim is the one applied to the bucket unless an update of the claim is
+// already pending
+func (u *claimUpdates) add(old *bktv1alpha1.ObjectBucketClaim) {
+	key, err := cache.MetaNamespaceKeyFunc(old)
+	if err != nil {
+		logger.Errorf("failed to get the key of OBC %q. %v", old.Name, err)
+		return
+	}
+	u.Lock()
+	if _, ok := u.applied[key]; !ok {
+		u.applied[key] = old
+	}
+	u.Unlock()
+	u.queue.Add(key)
+}
+
+// remove forgets a deleted claim
+func (u *claimUpdates) remove(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	u.Lock()
+	delete(u.applied, key)
+	u.Unlock()
+}
+
+// processNext applies the next queued update with the apply func, it returns false when the queue is shut down
+func (u *claimUpdates) processNext(get func(namespace, name string) (*bktv1alpha1.ObjectBucketClaim, error),
+	apply func(previous, obc *bktv1alpha1.ObjectBucketClaim) error) bool {
+	obj, shutdown := u.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer u.queue.Done(obj)
+	key := obj.(string)
+
+	u.Lock()
+	previous, ok := u.applied[key]
+	u.Unlock()
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if !ok || err != nil {
+		u.queue.Forget(obj)
+		return true
+	}
+	obc, err := get(namespace, name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			u.remove(previous)
+			u.queue.Forget(obj)
+			return true
+		}
+		logger.Errorf("failed to get OBC %q. %v", key, err)
+		u.queue.AddRateLimited(obj)
+		return true
+	}
+
+	if err := apply(previous, obc); err != nil {
+		logger.Errorf("failed to update the bucket of OBC %q, retrying. %v", key, err)
+		u.queue.AddRateLimited(obj)
+		return true
+	}
+	u.queue.Forget(obj)
+	u.Lock()
+	if _, ok := u.applied[key]; ok {
+		u.applied[key] = obc
+	}
+	u.Unlock()
+	return true
+}
+
+// WatchClaimUpdates applies the changes of the additional config of the bound OBCs to their bucket. The bucket
+// library only calls the provisioner when a claim is created or deleted.
+func (p *Provisioner) WatchClaimUpdates(cfg *rest.Config, stopCh <-chan struct{}) error {
+	client, err := bktclient.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create object bucket client")
+	}
+
+	updates := newClaimUpdates()
+	defer updates.queue.ShutDown()
+
+	factory := bktinformers.NewSharedInformerFactory(client, 0)
+	claims := factory.Objectbucket().V1alpha1().ObjectBucketClaims()
+	claims.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldObc := old.(*bktv1alpha1.ObjectBucketClaim)
+			newObc := new.(*bktv1alpha1.ObjectBucketClaim)
+			if claimSettingsChanged(oldObc, newObc) {
+				updates.add(oldObc)
+			}
+		},
+		DeleteFunc: updates.remove,
+	})
+
+	factory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, claims.Informer().HasSynced) {
+		return errors.New("failed to sync the OBC cache")
+	}
+
+	get := func(namespace, name string) (*bktv1alpha1.ObjectBucketClaim, error) {
+		return claims.Lister().ObjectBucketClaims(namespace).Get(name)
+	}
+	apply := func(previous, obc *bktv1alpha1.ObjectBucketClaim) error {
+		return p.applyClaimUpdate(client, previous, obc)
+	}
+	go wait.Until(func() {
+		for updates.processNext(get, apply) {
+		}
+	}, time.Second, stopCh)
+
+	<-stopCh
+	return nil
+}
+
+// claimSettingsChanged returns whether the additional config or the allow list of the claim changed
+func claimSettingsChanged(previous, obc *bktv1alpha1.ObjectBucketClaim) bool {
+	return !reflect.DeepEqual(previous.Spec.AdditionalConfig, obc.Spec.AdditionalConfig) ||
+		previous.Annotations[sharedBucketAllowListAnnotation] != obc.Annotations[sharedBucketAllowListAnnotation]
+}
+
+// applyClaimUpdate applies the changes of a bound claim since the previous claim applied to its bucket
+func (p *Provisioner) applyClaimUpdate(client bktclient.Interface, previous, obc *bktv1alpha1.ObjectBucketClaim) error {
+	if obc.DeletionTimestamp != nil || obc.Status.Phase != bktv1alpha1.ObjectBucketClaimStatusPhaseBound || obc.Spec.ObjectBucketName == "" {
+		return nil
+	}
+	if !claimSettingsChanged(previous, obc) {
+		return nil
+	}
+
+	ob, err := client.ObjectbucketV1alpha1().ObjectBuckets().Get(obc.Spec.ObjectBucketName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get object bucket %q of OBC %q", obc.Spec.ObjectBucketName, obc.Name)
+	}
+	// the provisioner fields are set per claim, don't share them with the bucket library calls
+	if !reflect.DeepEqual(previous.Spec.AdditionalConfig, obc.Spec.AdditionalConfig) {
+		provisioner := *p
+		if err := provisioner.updateBucket(previous, obc, ob); err != nil {
+			return errors.Wrapf(err, "failed to update bucket of OBC %q in namespace %q", obc.Name, obc.Namespace)
+		}
+	}
+	if previous.Annotations[sharedBucketAllowListAnnotation] != obc.Annotations[sharedBucketAllowListAnnotation] && !isSharedBucket(ob) {
+		provisioner := *p
+		if err := provisioner.updateSharedBucketAccess(client, obc, ob); err != nil {
+			return errors.Wrapf(err, "failed to update the access to the bucket of OBC %q in namespace %q", obc.Name, obc.Namespace)
+		}
+	}
+	return nil
+}
+
+// updateBucket applies the quotas and the bucket settings of an updated OBC to its bucket
+func (p *Provisioner) updateBucket(previous, obc *bktv1alpha1.ObjectBucketClaim, ob *bktv1alpha1.ObjectBucket) error {
+	sc, err := p.getStorageClassWithBackoff(ob.Spec.StorageClassName)
+	if err != nil {
+		return err
+	}
+	if sc.Provisioner != cephObject.GetObjectBucketProvisioner(p.context, p.clusterInfo.Namespace) {
+		return nil
+	}
+	if _, isStatic := isStaticBucket(sc); isStatic || isSharedBucket(ob) {
+		logger.Debugf("not updating existing bucket of OBC %q", obc.Name)
+		return nil
+	}
+
+	settings, err := parseBucketSettings(bucketConfig(sc.Parameters, obc.Spec.AdditionalConfig))
+	if err != nil {
+		return errors.Wrapf(err, "invalid bucket settings of OBC %q", obc.Name)
+	}
+	// the settings applied before were validated when they were applied, they are unknown if the storage class
+	// changed since then
+	previousSettings, err := parseBucketSettings(bucketConfig(sc.Parameters, previous.Spec.AdditionalConfig))
+	if err != nil {
+		logger.Debugf("previous bucket settings of OBC %q are unknown. %v", obc.Name, err)
+		previousSettings = nil
+	}
+
+	if err := p.initializeDeleteOrRevoke(ob); err != nil {
+		return err
+	}
+	logger.Infof("updating bucket %q of OBC %q", p.bucketName, obc.Name)
+
+	if err := p.setAdditionalSettings(&apibkt.BucketOptions{ObjectBucketClaim: obc}); err != nil {
+		return err
+	}
+
+	user, _, err := cephObject.GetUser(p.objectContext, p.cephUserName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user %q", p.cephUserName)
+	}
+	s3svc, err := cephObject.NewS3Agent(*user.AccessKey, *user.SecretKey, p.getObjectStoreEndpoint(), false)
+	if err != nil {
+		return err
+	}
+
+	if err := validateObjectLockUpdate(s3svc, p.bucketName, settings, previousSettings); err != nil {
+		return errors.Wrapf(err, "invalid bucket settings of OBC %q", obc.Name)
+	}
+
+	return applyBucketSettings(s3svc, p.bucketName, settings, previousSettings)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bucket
+
+import (
+	"testing"
+
+	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func claimWithConfig(versioning string) *bktv1alpha1.ObjectBucketClaim {
+	return &bktv1alpha1.ObjectBucketClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "team-a"},
+		Spec: bktv1alpha1.ObjectBucketClaimSpec{
+			ObjectBucketName: "obc-team-a-logs",
+			AdditionalConfig: map[string]string{"versioning": versioning},
+		},
+	}
+}
+
+func TestClaimUpdates(t *testing.T) {
+	updates := newClaimUpdates()
+	defer updates.queue.ShutDown()
+	original := claimWithConfig("Enabled")
+	current := claimWithConfig("Suspended")
+	get := func(namespace, name string) (*bktv1alpha1.ObjectBucketClaim, error) {
+		if current == nil {
+			return nil, kerrors.NewNotFound(schema.GroupResource{Resource: "objectbucketclaims"}, name)
+		}
+		return current, nil
+	}
+	applied := []*bktv1alpha1.ObjectBucketClaim{}
+	failures := 1
+	apply := func(previous, obc *bktv1alpha1.ObjectBucketClaim) error {
+		applied = append(applied, previous)
+		if failures > 0 {
+			failures--
+			return errors.New("rgw unavailable")
+		}
+		return nil
+	}
+
+	// the failed update is retried against the claim applied before
+	updates.add(original)
+	updates.add(claimWithConfig("Suspended"))
+	assert.True(t, updates.processNext(get, apply))
+	assert.Equal(t, 1, updates.queue.NumRequeues("team-a/logs"))
+	assert.True(t, updates.processNext(get, apply))
+	assert.Equal(t, []*bktv1alpha1.ObjectBucketClaim{original, original}, applied)
+	assert.Equal(t, 0, updates.queue.NumRequeues("team-a/logs"))
+	assert.Equal(t, current, updates.applied["team-a/logs"])
+
+	// the next update is compared to the claim applied last
+	applied = []*bktv1alpha1.ObjectBucketClaim{}
+	updates.add(claimWithConfig("Suspended"))
+	current = claimWithConfig("Enabled")
+	assert.True(t, updates.processNext(get, apply))
+	assert.Equal(t, "Suspended", applied[0].Spec.AdditionalConfig["versioning"])
+
+	// a deleted claim is forgotten
+	updates.add(current)
+	current = nil
+	assert.True(t, updates.processNext(get, apply))
+	assert.Empty(t, updates.applied)
+	assert.Equal(t, 0, updates.queue.Len())
+}
+
+func TestClaimSettingsChanged(t *testing.T) {
+	assert.False(t, claimSettingsChanged(claimWithConfig("Enabled"), claimWithConfig("Enabled")))
+	assert.True(t, claimSettingsChanged(claimWithConfig("Enabled"), claimWithConfig("Suspended")))
+	shared := claimWithConfig("Enabled")
+	shared.Annotations = map[string]string{sharedBucketAllowListAnnotation: "team-b"}
+	assert.True(t, claimSettingsChanged(claimWithConfig("Enabled"), shared))
+}
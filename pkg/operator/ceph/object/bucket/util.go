@@ -21,6 +21,7 @@ import (
 
 	"github.com/coreos/pkg/capnslog"
 	bktv1alpha1 "github.com/kube-object-storage/lib-bucket-provisioner/pkg/apis/objectbucket.io/v1alpha1"
+	bktclient "github.com/kube-object-storage/lib-bucket-provisioner/pkg/client/clientset/versioned"
 	"github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner"
 	apibkt "github.com/kube-object-storage/lib-bucket-provisioner/pkg/provisioner/api"
 	"github.com/pkg/errors"
@@ -48,6 +49,11 @@ const (
 func NewBucketController(cfg *rest.Config, p *Provisioner) (*provisioner.Provisioner, error) {
 	const allNamespaces = ""
 	provName := cephObject.GetObjectBucketProvisioner(p.context, p.clusterInfo.Namespace)
+	client, err := bktclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create object bucket client")
+	}
+	p.bucketClient = client
 
 	logger.Infof("ceph bucket provisioner launched watching for provisioner %q", provName)
 	return provisioner.NewProvisioner(cfg, provName, p, allNamespaces)
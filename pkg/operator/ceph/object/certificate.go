@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// certAnnotation is the hash of the certificate of the rgw pods, they are restarted when the certificate is renewed
+	certAnnotation = "ceph.rook.io/rgw-cert"
+	// certExpiryWarningPeriod is the time before the expiry of the certificate from which warning events are emitted
+	certExpiryWarningPeriod = 30 * 24 * time.Hour
+	// certExpiryWarningInterval is the interval of the warning events until the certificate is renewed
+	certExpiryWarningInterval = 24 * time.Hour
+
+	certExpiringReason = "CertificateExpiring"
+	certExpiredReason  = "CertificateExpired"
+)
+
+// reconcileCertificate reads the certificate of the gateway and keeps its hash so the rgw pods are restarted when
+// the secret is updated. Nil is returned if the gateway does not serve SSL.
+func (c *clusterConfig) reconcileCertificate() (*x509.Certificate, error) {
+	c.certHash = ""
+	secretName := c.store.Spec.Gateway.SSLCertificateRef
+	if secretName == "" {
+		return nil, nil
+	}
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.store.Namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get certificate secret %q", secretName)
+	}
+	data, ok := secret.Data[certKeyName]
+	if !ok {
+		return nil, errors.Errorf("certificate secret %q has no %q key", secretName, certKeyName)
+	}
+	cert, err := parseCertificate(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate secret %q", secretName)
+	}
+	c.certHash = k8sutil.Hash(string(data))
+	return cert, nil
+}
+
+// parseCertificate returns the first certificate of a pem bundle, which is the certificate of the server since the
+// key, the certificate and the CA certificates are in the same file for rgw
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no pem certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// checkCertificateExpiry reports the expiry of the certificate in the status of the object store and emits warning
+// events when the certificate is about to expire. The returned result requeues the store for the next check.
+func (r *ReconcileCephObjectStore) checkCertificateExpiry(cephObjectStore *cephv1.CephObjectStore, cert *x509.Certificate, namespacedName types.NamespacedName) reconcile.Result {
+	if cert == nil {
+		updateStatusCertificate(r.client, namespacedName, nil)
+		return reconcile.Result{}
+	}
+	secretName := cephObjectStore.Spec.Gateway.SSLCertificateRef
+	notAfter := cert.NotAfter.UTC().Format(time.RFC3339)
+	updateStatusCertificate(r.client, namespacedName, &cephv1.CertificateStatus{
+		SecretName: secretName,
+		Subject:    cert.Subject.String(),
+		NotAfter:   notAfter,
+	})
+
+	remaining := time.Until(cert.NotAfter)
+	if remaining > certExpiryWarningPeriod {
+		return reconcile.Result{RequeueAfter: remaining - certExpiryWarningPeriod}
+	}
+	if remaining <= 0 {
+		logger.Warningf("certificate of object store %q in secret %q expired on %s", cephObjectStore.Name, secretName, notAfter)
+		r.recorder.Eventf(cephObjectStore, v1.EventTypeWarning, certExpiredReason, "certificate in secret %q expired on %s", secretName, notAfter)
+	} else {
+		logger.Warningf("certificate of object store %q in secret %q expires on %s", cephObjectStore.Name, secretName, notAfter)
+		r.recorder.Eventf(cephObjectStore, v1.EventTypeWarning, certExpiringReason, "certificate in secret %q expires on %s", secretName, notAfter)
+	}
+	return reconcile.Result{RequeueAfter: certExpiryWarningInterval}
+}
+
+// updateStatusCertificate updates the certificate in the status of an object store
+func updateStatusCertificate(client client.Client, name types.NamespacedName, certificate *cephv1.CertificateStatus) {
+	objectStore := &cephv1.CephObjectStore{}
+	if err := client.Get(context.TODO(), name, objectStore); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectStore resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object store %q to update the certificate status. %v", name, err)
+		return
+	}
+	if objectStore.Status == nil {
+		objectStore.Status = &cephv1.ObjectStoreStatus{}
+	}
+	if reflect.DeepEqual(objectStore.Status.Certificate, certificate) {
+		return
+	}
+	objectStore.Status.Certificate = certificate
+	if err := opcontroller.UpdateStatus(client, objectStore); err != nil {
+		logger.Errorf("failed to set object store %q certificate status. %v", name, err)
+		return
+	}
+	logger.Debugf("object store %q certificate status updated", name)
+}
+
+// certificateWatches watches the certificate secrets referenced by the object stores. The secrets are created by the
+// user and not owned by the stores, each secret has its own informer restricted to its name so that the other
+// secrets of the cluster are not cached.
+type certificateWatches struct {
+	mutex      sync.Mutex
+	controller controller.Controller
+	client     client.Client
+	clientset  kubernetes.Interface
+	// stopChans stops the informers of the watched secrets
+	stopChans map[types.NamespacedName]chan struct{}
+}
+
+func newCertificateWatches(client client.Client, clientset kubernetes.Interface) *certificateWatches {
+	return &certificateWatches{
+		client:    client,
+		clientset: clientset,
+		stopChans: map[types.NamespacedName]chan struct{}{},
+	}
+}
+
+// update watches the certificate secrets referenced by the object stores of the namespace and stops watching the
+// secrets that are not referenced anymore
+func (w *certificateWatches) update(namespace string) error {
+	if w == nil || w.controller == nil {
+		return nil
+	}
+	stores := &cephv1.CephObjectStoreList{}
+	if err := w.client.List(context.TODO(), stores, client.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "failed to list object stores in namespace %q", namespace)
+	}
+	referenced := map[types.NamespacedName]bool{}
+	for _, store := range stores.Items {
+		if store.Spec.Gateway.SSLCertificateRef != "" && store.GetDeletionTimestamp().IsZero() {
+			referenced[types.NamespacedName{Name: store.Spec.Gateway.SSLCertificateRef, Namespace: namespace}] = true
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for secret, stopChan := range w.stopChans {
+		if secret.Namespace == namespace && !referenced[secret] {
+			logger.Debugf("stopping the watch of certificate secret %q", secret)
+			close(stopChan)
+			delete(w.stopChans, secret)
+		}
+	}
+	for secret := range referenced {
+		if _, ok := w.stopChans[secret]; ok {
+			continue
+		}
+		stopChan := make(chan struct{})
+		informer := w.newSecretInformer(secret)
+		err := w.controller.Watch(&source.Informer{Informer: informer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+				return certificateSecretRequests(w.client, obj.Meta.GetNamespace(), obj.Meta.GetName())
+			}),
+		}, certificateSecretPredicate())
+		if err != nil {
+			return errors.Wrapf(err, "failed to watch certificate secret %q", secret)
+		}
+		go informer.Run(stopChan)
+		w.stopChans[secret] = stopChan
+		logger.Debugf("watching certificate secret %q", secret)
+	}
+	return nil
+}
+
+// newSecretInformer returns an informer of a single secret
+func (w *certificateWatches) newSecretInformer(secret types.NamespacedName) cache.SharedIndexInformer {
+	selector := fields.OneTermEqualSelector("metadata.name", secret.Name).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return w.clientset.CoreV1().Secrets(secret.Namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return w.clientset.CoreV1().Secrets(secret.Namespace).Watch(options)
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &v1.Secret{}, 0, cache.Indexers{})
+}
+
+// certificateSecretRequests returns the requests of the object stores serving the certificate of a secret
+func certificateSecretRequests(c client.Client, namespace, secretName string) []reconcile.Request {
+	stores := &cephv1.CephObjectStoreList{}
+	if err := c.List(context.TODO(), stores, client.InNamespace(namespace)); err != nil {
+		logger.Errorf("failed to list object stores of certificate secret %q. %v", secretName, err)
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, store := range stores.Items {
+		if store.Spec.Gateway.SSLCertificateRef == secretName {
+			logger.Infof("certificate secret %q of object store %q changed, reconciling", secretName, store.Name)
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: store.Name, Namespace: namespace}})
+		}
+	}
+	return requests
+}
+
+// certificateSecretPredicate reconciles the object stores when a secret is created or its content changes
+func certificateSecretPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*v1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*v1.Secret)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// testCertificate returns a self-signed certificate in the rgw format, the key followed by the certificate
+func testCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rgw.example.com"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return append(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+}
+
+func TestReconcileCertificate(t *testing.T) {
+	notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rgw-cert", Namespace: "mycluster"},
+		Data:       map[string][]byte{certKeyName: testCertificate(t, notAfter)},
+	}
+	clientset := fake.NewSimpleClientset(secret)
+	store := simpleStore()
+	c := &clusterConfig{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+		clusterSpec: &cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v15"}},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s", AppName, store.Name)}
+
+	// no ssl
+	cert, err := c.reconcileCertificate()
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+	s, err := c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.NotContains(t, s.ObjectMeta.Annotations, certAnnotation)
+
+	store.Spec.Gateway.SSLCertificateRef = "rgw-cert"
+	cert, err = c.reconcileCertificate()
+	assert.NoError(t, err)
+	assert.True(t, notAfter.Equal(cert.NotAfter))
+	assert.Equal(t, "CN=rgw.example.com", cert.Subject.String())
+	s, err = c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	hash := s.ObjectMeta.Annotations[certAnnotation]
+	assert.NotEmpty(t, hash)
+
+	// the pods are restarted when the certificate is renewed
+	secret.Data[certKeyName] = testCertificate(t, notAfter.Add(90*24*time.Hour))
+	_, err = clientset.CoreV1().Secrets(store.Namespace).Update(secret)
+	assert.NoError(t, err)
+	_, err = c.reconcileCertificate()
+	assert.NoError(t, err)
+	s, err = c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, s.ObjectMeta.Annotations[certAnnotation])
+
+	// invalid certificates
+	secret.Data = map[string][]byte{"tls.crt": secret.Data[certKeyName]}
+	_, err = clientset.CoreV1().Secrets(store.Namespace).Update(secret)
+	assert.NoError(t, err)
+	_, err = c.reconcileCertificate()
+	assert.Error(t, err)
+	secret.Data = map[string][]byte{certKeyName: []byte("not a certificate")}
+	_, err = clientset.CoreV1().Secrets(store.Namespace).Update(secret)
+	assert.NoError(t, err)
+	_, err = c.reconcileCertificate()
+	assert.Error(t, err)
+	assert.Empty(t, c.certHash)
+}
+
+func TestCheckCertificateExpiry(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Gateway.SSLCertificateRef = "rgw-cert"
+	name := types.NamespacedName{Name: store.Name, Namespace: store.Namespace}
+	cl := fakeclient.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{store}...)
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileCephObjectStore{client: cl, scheme: scheme.Scheme, recorder: recorder}
+	parse := func(notAfter time.Time) *x509.Certificate {
+		cert, err := parseCertificate(testCertificate(t, notAfter))
+		require.NoError(t, err)
+		return cert
+	}
+	status := func() *cephv1.CertificateStatus {
+		objectStore := &cephv1.CephObjectStore{}
+		require.NoError(t, cl.Get(context.TODO(), name, objectStore))
+		return objectStore.Status.Certificate
+	}
+
+	// the store is checked again when the warnings start
+	notAfter := time.Now().Add(60 * 24 * time.Hour)
+	result := r.checkCertificateExpiry(store, parse(notAfter), name)
+	assert.InDelta(t, float64(30*24*time.Hour), float64(result.RequeueAfter), float64(time.Minute))
+	assert.Equal(t, &cephv1.CertificateStatus{
+		SecretName: "rgw-cert",
+		Subject:    "CN=rgw.example.com",
+		NotAfter:   notAfter.UTC().Format(time.RFC3339),
+	}, status())
+	assert.Empty(t, recorder.Events)
+
+	// the warnings are emitted every day until the certificate is renewed
+	result = r.checkCertificateExpiry(store, parse(time.Now().Add(10*24*time.Hour)), name)
+	assert.Equal(t, certExpiryWarningInterval, result.RequeueAfter)
+	assert.True(t, strings.HasPrefix(<-recorder.Events, "Warning CertificateExpiring"))
+	result = r.checkCertificateExpiry(store, parse(time.Now().Add(-time.Hour)), name)
+	assert.Equal(t, certExpiryWarningInterval, result.RequeueAfter)
+	assert.True(t, strings.HasPrefix(<-recorder.Events, "Warning CertificateExpired"))
+
+	// the status is cleared when the gateway does not serve ssl anymore
+	result = r.checkCertificateExpiry(store, nil, name)
+	assert.Equal(t, time.Duration(0), result.RequeueAfter)
+	assert.Nil(t, status())
+}
+
+func TestCertificateSecretRequests(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Gateway.SSLCertificateRef = "rgw-cert"
+	other := simpleStore()
+	other.Name = "other"
+	cl := fakeclient.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{store, other}...)
+
+	requests := certificateSecretRequests(cl, "mycluster", "rgw-cert")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, types.NamespacedName{Name: "default", Namespace: "mycluster"}, requests[0].NamespacedName)
+	assert.Empty(t, certificateSecretRequests(cl, "mycluster", "other-secret"))
+	assert.Empty(t, certificateSecretRequests(cl, "other-namespace", "rgw-cert"))
+}
+
+// fakeController records the sources of the watches
+type fakeController struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *fakeController) Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
+func TestCertificateWatches(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Gateway.SSLCertificateRef = "rgw-cert"
+	other := simpleStore()
+	other.Name = "other"
+	other.Spec.Gateway.SSLCertificateRef = "rgw-cert"
+	cl := fakeclient.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{store, other}...)
+	c := &fakeController{}
+	w := newCertificateWatches(cl, fake.NewSimpleClientset())
+
+	// nothing is watched before the controller is set
+	assert.NoError(t, w.update("mycluster"))
+	assert.Empty(t, w.stopChans)
+
+	// the secret referenced by both stores is watched once
+	w.controller = c
+	assert.NoError(t, w.update("mycluster"))
+	assert.NoError(t, w.update("mycluster"))
+	assert.Len(t, c.sources, 1)
+	secret := types.NamespacedName{Name: "rgw-cert", Namespace: "mycluster"}
+	assert.Contains(t, w.stopChans, secret)
+
+	// the secret is watched until no store references it
+	assert.NoError(t, cl.Delete(context.TODO(), store))
+	assert.NoError(t, w.update("mycluster"))
+	assert.Contains(t, w.stopChans, secret)
+	assert.NoError(t, cl.Delete(context.TODO(), other))
+	assert.NoError(t, w.update("mycluster"))
+	assert.Empty(t, w.stopChans)
+}
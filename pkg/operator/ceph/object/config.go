@@ -101,6 +101,11 @@ func (c *clusterConfig) setDefaultFlagsMonConfigStore(rgwName string) error {
 	configOptions["rgw_enable_usage_log"] = "true"
 	configOptions["rgw_zone"] = c.store.Name
 	configOptions["rgw_zonegroup"] = c.store.Name
+	for flag, val := range c.authOptions() {
+		if val != "" {
+			configOptions[flag] = val
+		}
+	}
 
 	for flag, val := range configOptions {
 		err := monStore.Set(who, flag, val)
@@ -18,6 +18,7 @@ package object
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -34,14 +35,17 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
 	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
 	"github.com/rook/rook/pkg/util/exec"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -82,17 +86,24 @@ type ReconcileCephObjectStore struct {
 	cephClusterSpec     *cephv1.ClusterSpec
 	clusterInfo         *cephclient.ClusterInfo
 	objectStoreChannels map[string]*objectStoreHealth
+	recorder            record.EventRecorder
+	certificateWatches  *certificateWatches
 }
 
 type objectStoreHealth struct {
 	stopChan          chan struct{}
 	monitoringRunning bool
+	// metricsStopChan stops the collection of the usage metrics, nil if the metrics are not collected
+	metricsStopChan chan struct{}
+	metricsInterval time.Duration
+	// checker is the running health check of the object store, its probes follow the changes of the settings
+	checker *bucketChecker
 }
 
 // Add creates a new cephObjectStore Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context) error {
-	return add(mgr, newReconciler(mgr, context))
+	return add(mgr, newReconciler(mgr, context), context)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -108,10 +119,12 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Rec
 		context:             context,
 		bktclient:           bktclient.NewForConfigOrDie(context.KubeConfig),
 		objectStoreChannels: make(map[string]*objectStoreHealth),
+		recorder:            mgr.GetEventRecorderFor(controllerName),
+		certificateWatches:  newCertificateWatches(mgr.GetClient(), context.Clientset),
 	}
 }
 
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, context *clusterd.Context) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -136,6 +149,23 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// The certificate secrets of the gateways are watched by the reconciler as they are referenced by the object stores
+	if reconciler, ok := r.(*ReconcileCephObjectStore); ok {
+		reconciler.certificateWatches.controller = c
+	}
+
+	// Watch the ingresses if the cluster supports them
+	if k8sutil.IngressSupported(context.Clientset) {
+		ingress := &networkingv1beta1.Ingress{TypeMeta: metav1.TypeMeta{Kind: "Ingress", APIVersion: networkingv1beta1.SchemeGroupVersion.String()}}
+		err = c.Watch(&source.Kind{Type: ingress}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &cephv1.CephObjectStore{},
+		}, opcontroller.WatchPredicateForNonCRDObject(&cephv1.CephObjectStore{TypeMeta: controllerTypeMeta}, mgr.GetScheme()))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -160,12 +190,20 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debug("cephObjectStore resource not found. Ignoring since object must be deleted.")
+			if err := r.certificateWatches.update(request.Namespace); err != nil {
+				logger.Warningf("failed to update the watches of the certificate secrets. %v", err)
+			}
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, errors.Wrap(err, "failed to get cephObjectStore")
 	}
 
+	// Watch the certificate secret of the gateway so the rgw pods are restarted when it is renewed
+	if err := r.certificateWatches.update(request.Namespace); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to watch the certificate secrets")
+	}
+
 	// The CR was just created, initializing status fields
 	if cephObjectStore.Status == nil {
 		// The store is not available so let's not build the status Info yet
@@ -250,6 +288,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 
 			// Close the channel to stop the healthcheck of the endpoint
 			close(r.objectStoreChannels[cephObjectStore.Name].stopChan)
+			r.objectStoreChannels[cephObjectStore.Name].stopMetrics()
 
 			// Remove object store from the map
 			delete(r.objectStoreChannels, cephObjectStore.Name)
@@ -271,7 +310,7 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	}
 
 	// CREATE/UPDATE
-	_, err = r.reconcileCreateObjectStore(cephObjectStore, request.NamespacedName)
+	response, err := r.reconcileCreateObjectStore(cephObjectStore, request.NamespacedName)
 	if err != nil {
 		return r.setFailedStatus(request.NamespacedName, "failed to create object store deployments", err)
 	}
@@ -279,9 +318,9 @@ func (r *ReconcileCephObjectStore) reconcile(request reconcile.Request) (reconci
 	// Set Progressing status, we are done reconciling, the health check go routine will update the status
 	updateStatus(r.client, request.NamespacedName, cephv1.ConditionProgressing, buildStatusInfo(cephObjectStore))
 
-	// Return and do not requeue
+	// Return and only requeue to check the expiry of the certificate
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return response, nil
 }
 
 func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *cephv1.CephObjectStore, namespacedName types.NamespacedName) (reconcile.Result, error) {
@@ -299,6 +338,8 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 	objContext.UID = string(cephObjectStore.UID)
 
 	var serviceIP string
+	var cert *x509.Certificate
+	placementRemovalPending := false
 	var err error
 
 	if r.cephClusterSpec.External.Enable {
@@ -349,9 +390,15 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		}
 
 		// Reconcile Pool Creation
-		if !cephObjectStore.Spec.IsMultisite() {
+		if cephObjectStore.Spec.IsSharedPools() {
+			logger.Info("reconciling object store shared pools")
+			err = createSharedPools(objContext, cephObjectStore.Spec)
+			if err != nil {
+				return r.setFailedStatus(namespacedName, "failed to create shared object pools", err)
+			}
+		} else if !cephObjectStore.Spec.IsMultisite() {
 			logger.Info("reconciling object store pools")
-			err = CreatePools(objContext, cephObjectStore.Spec.MetadataPool, cephObjectStore.Spec.DataPool)
+			err = CreatePools(objContext, cephObjectStore.Spec.MetadataPool, cephObjectStore.Spec.DataPool, cephObjectStore.Spec.PlacementTargets)
 			if err != nil {
 				return r.setFailedStatus(namespacedName, "failed to create object pools", err)
 			}
@@ -364,6 +411,26 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 			return r.setFailedStatus(namespacedName, "failed to configure multisite for object store", err)
 		}
 
+		// The zone must use the namespaces of the shared pools before the gateways write to its pools
+		if cephObjectStore.Spec.IsSharedPools() {
+			err = configureSharedPools(objContext, cephObjectStore.Spec.SharedPools)
+			if err != nil {
+				return r.setFailedStatus(namespacedName, "failed to configure shared pools for object store", err)
+			}
+		}
+
+		// Reconcile the placement targets once the zone and the zonegroup exist
+		placementRemovalPending, err = configurePlacementTargets(objContext, cephObjectStore.Spec.PlacementTargets, cephObjectStore.Spec.PreservePoolsOnDelete)
+		if err != nil {
+			return r.setFailedStatus(namespacedName, "failed to configure placement targets for object store", err)
+		}
+
+		// The rgw pods are restarted when the certificate changes
+		cert, err = cfg.reconcileCertificate()
+		if err != nil {
+			return r.setFailedStatus(namespacedName, "failed to read the gateway certificate", err)
+		}
+
 		// Create or Update Store
 		err = cfg.createOrUpdateStore(realmName, zoneGroupName, zoneName)
 		if err != nil {
@@ -376,7 +443,15 @@ func (r *ReconcileCephObjectStore) reconcileCreateObjectStore(cephObjectStore *c
 		r.startMonitoring(cephObjectStore, objContext, serviceIP, namespacedName)
 	}
 
-	return reconcile.Result{}, nil
+	// Start or stop the collection of the usage metrics
+	r.reconcileMetrics(cephObjectStore, objContext, namespacedName)
+
+	result := r.checkCertificateExpiry(cephObjectStore, cert, namespacedName)
+	// the removed placement targets are removed once their buckets and objects are deleted
+	if placementRemovalPending && (result.RequeueAfter == 0 || result.RequeueAfter > placementRemovalRequeueInterval) {
+		result.RequeueAfter = placementRemovalRequeueInterval
+	}
+	return result, nil
 }
 
 func (r *ReconcileCephObjectStore) reconcileCephZone(store *cephv1.CephObjectStore, zoneGroupName string, realmName string) (reconcile.Result, error) {
@@ -462,6 +537,9 @@ func (r *ReconcileCephObjectStore) startMonitoring(objectstore *cephv1.CephObjec
 	// Start monitoring object store
 	if r.objectStoreChannels[objectstore.Name].monitoringRunning {
 		logger.Debug("external rgw endpoint monitoring go routine already running!")
+		if checker := r.objectStoreChannels[objectstore.Name].checker; checker != nil {
+			checker.setProbes(objectstore.Spec.HealthCheck.Probes)
+		}
 		return
 	}
 
@@ -479,7 +557,8 @@ func (r *ReconcileCephObjectStore) startMonitoring(objectstore *cephv1.CephObjec
 		return
 	}
 
-	rgwChecker := newBucketChecker(r.context, objContext, serviceIP, port, r.client, namespacedName, &objectstore.Spec.HealthCheck, r.cephClusterSpec.External.Enable)
+	rgwChecker := newBucketChecker(r.context, objContext, serviceIP, port, r.client, namespacedName, objectstore, r.cephClusterSpec.External.Enable)
+	r.objectStoreChannels[objectstore.Name].checker = rgwChecker
 	logger.Info("starting rgw healthcheck")
 	go rgwChecker.checkObjectStore(r.objectStoreChannels[objectstore.Name].stopChan)
 }
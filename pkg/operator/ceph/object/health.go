@@ -18,8 +18,12 @@ package object
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
@@ -48,10 +52,16 @@ type bucketChecker struct {
 	namespacedName  types.NamespacedName
 	healthCheckSpec *cephv1.BucketHealthCheckSpec
 	isExternal      bool
+	// kmsKeyID is the key of the server side encryption check, the check is skipped if empty
+	kmsKeyID string
+	// probes are the synthetic probes run after the bucket check, nil if they are not configured
+	probesMutex sync.Mutex
+	probesSpec  *cephv1.ObjectProbesSpec
+	probes      *objectProbes
 }
 
 // newbucketChecker creates a new HealthChecker object
-func newBucketChecker(context *clusterd.Context, objContext *Context, serviceIP, port string, client client.Client, namespacedName types.NamespacedName, healthCheckSpec *cephv1.BucketHealthCheckSpec, isExternal bool) *bucketChecker {
+func newBucketChecker(context *clusterd.Context, objContext *Context, serviceIP, port string, client client.Client, namespacedName types.NamespacedName, objectStore *cephv1.CephObjectStore, isExternal bool) *bucketChecker {
 	c := &bucketChecker{
 		context:         context,
 		objContext:      objContext,
@@ -60,12 +70,16 @@ func newBucketChecker(context *clusterd.Context, objContext *Context, serviceIP,
 		port:            port,
 		namespacedName:  namespacedName,
 		client:          client,
-		healthCheckSpec: healthCheckSpec,
+		healthCheckSpec: &objectStore.Spec.HealthCheck,
 		isExternal:      isExternal,
 	}
+	if kms := objectStore.Spec.Security.KMS; kms != nil {
+		c.kmsKeyID = kms.HealthCheckKeyID
+	}
+	c.setProbes(objectStore.Spec.HealthCheck.Probes)
 
 	// allow overriding the check interval
-	checkInterval := healthCheckSpec.Bucket.Interval
+	checkInterval := objectStore.Spec.HealthCheck.Bucket.Interval
 	if checkInterval != "" {
 		if duration, err := time.ParseDuration(checkInterval); err == nil {
 			logger.Infof("ceph rgw status check interval for object store %q is %q", namespacedName.Name, checkInterval)
@@ -76,14 +90,45 @@ func newBucketChecker(context *clusterd.Context, objContext *Context, serviceIP,
 	return c
 }
 
+// setProbes replaces the probes when their settings change, the latencies of the previous probes are discarded
+func (c *bucketChecker) setProbes(spec *cephv1.ObjectProbesSpec) {
+	c.probesMutex.Lock()
+	defer c.probesMutex.Unlock()
+	if reflect.DeepEqual(spec, c.probesSpec) {
+		return
+	}
+	c.probesSpec = spec.DeepCopy()
+	c.probes = nil
+	if spec == nil {
+		return
+	}
+	probes, err := newObjectProbes(spec)
+	if err != nil {
+		logger.Errorf("failed to configure the probes of object store %q. %v", c.namespacedName.Name, err)
+		return
+	}
+	logger.Infof("configured the probes of object store %q", c.namespacedName.Name)
+	c.probes = probes
+}
+
+func (c *bucketChecker) getProbes() *objectProbes {
+	c.probesMutex.Lock()
+	defer c.probesMutex.Unlock()
+	return c.probes
+}
+
+// probeStatuses returns the latencies of the probes for the status of the object store
+func (c *bucketChecker) probeStatuses() []cephv1.ObjectProbeStatus {
+	if probes := c.getProbes(); probes != nil {
+		return probes.statuses()
+	}
+	return nil
+}
+
 // checkObjectStore periodically checks the health of the cluster
 func (c *bucketChecker) checkObjectStore(stopCh chan struct{}) {
 	// check the object store health immediately before starting the loop
-	err := c.checkObjectStoreHealth()
-	if err != nil {
-		updateStatusBucket(c.client, c.namespacedName, cephv1.ConditionFailure, err.Error())
-		logger.Debugf("failed to check rgw health for object store %q. %v", c.namespacedName.Name, err)
-	}
+	c.runHealthCheck()
 
 	for {
 		select {
@@ -91,20 +136,29 @@ func (c *bucketChecker) checkObjectStore(stopCh chan struct{}) {
 			// purge bucket and s3 user
 			// Needed for external mode where in converged everything goes away with the CR deletion
 			c.cleanupHealthCheck()
+			storeCollector.setHealthCheck(c.namespacedName, nil)
+			storeCollector.setProbes(c.namespacedName, nil)
 			logger.Infof("stopping monitoring of rgw endpoints for object store %q", c.namespacedName.Name)
 			return
 
 		case <-time.After(c.interval):
 			logger.Debugf("checking rgw health of object store %q", c.namespacedName.Name)
-			err := c.checkObjectStoreHealth()
-			if err != nil {
-				updateStatusBucket(c.client, c.namespacedName, cephv1.ConditionFailure, err.Error())
-				logger.Debugf("failed to check rgw health for object store %q. %v", c.namespacedName.Name, err)
-			}
+			c.runHealthCheck()
 		}
 	}
 }
 
+// runHealthCheck checks the health of the object store and publishes the duration of the check in the metrics
+func (c *bucketChecker) runHealthCheck() {
+	start := time.Now()
+	err := c.checkObjectStoreHealth()
+	storeCollector.setHealthCheck(c.namespacedName, &healthCheckResult{duration: time.Since(start), success: err == nil})
+	if err != nil {
+		updateStatusBucket(c.client, c.namespacedName, cephv1.ConditionFailure, err.Error(), c.probeStatuses())
+		logger.Debugf("failed to check rgw health for object store %q. %v", c.namespacedName.Name, err)
+	}
+}
+
 func (c *bucketChecker) checkObjectStoreHealth() error {
 	/*
 		0. purge the s3 object by default
@@ -166,8 +220,26 @@ func (c *bucketChecker) checkObjectStoreHealth() error {
 
 	logger.Debugf("successfully checked object store endpoint for object store %q", c.namespacedName.Name)
 
+	// Synthetic probes, the store is degraded when their latencies exceed the thresholds
+	phase, details := cephv1.ConditionConnected, ""
+	probes := c.getProbes()
+	if probes == nil {
+		storeCollector.setProbes(c.namespacedName, nil)
+	} else {
+		err = probes.run(s3client, bucketName)
+		storeCollector.setProbes(c.namespacedName, probes.summaries())
+		if err != nil {
+			return errors.Wrapf(err, "failed to run the probes of object store %q", c.namespacedName.Name)
+		}
+		if exceeded := probes.exceededThresholds(); len(exceeded) > 0 {
+			phase = cephv1.ConditionDegraded
+			details = fmt.Sprintf("latency thresholds exceeded: %s", strings.Join(exceeded, "; "))
+			logger.Warningf("object store %q is degraded. %s", c.namespacedName.Name, details)
+		}
+	}
+
 	// Update the EndpointStatus in the CR to reflect the healthyness
-	updateStatusBucket(c.client, c.namespacedName, cephv1.ConditionConnected, "")
+	updateStatusBucket(c.client, c.namespacedName, phase, details, c.probeStatuses())
 
 	return nil
 }
@@ -261,5 +333,35 @@ func (c *bucketChecker) testBucketHealth(s3client *S3Agent, bucket string) error
 		return errors.Wrapf(err, "wrong file content, old file hash is %q and new one is %q for object store %q", oldHash, currentHash, c.namespacedName.Name)
 	}
 
+	if c.kmsKeyID != "" {
+		return c.testEncryptionHealth(s3client, bucket)
+	}
+	return nil
+}
+
+// testEncryptionHealth checks that an object encrypted with the kms key of the health check round-trips
+func (c *bucketChecker) testEncryptionHealth(s3client *S3Agent, bucket string) error {
+	logger.Debugf("putting encrypted object %q in bucket %q for object store %q", s3HealthCheckObjectKey, bucket, c.namespacedName.Name)
+	err := s3client.PutEncryptedObjectInBucket(bucket, string(s3HealthCheckObjectBody), s3HealthCheckObjectKey, contentType, c.kmsKeyID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to put object encrypted with kms key %q for object store %q", c.kmsKeyID, c.namespacedName.Name)
+	}
+
+	encryption, keyID, err := s3client.GetObjectEncryption(bucket, s3HealthCheckObjectKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get encryption of object %q for object store %q", s3HealthCheckObjectKey, c.namespacedName.Name)
+	}
+	if encryption != s3.ServerSideEncryptionAwsKms || keyID != c.kmsKeyID {
+		return errors.Errorf("object %q is not encrypted with kms key %q for object store %q (encryption %q, key %q)", s3HealthCheckObjectKey, c.kmsKeyID, c.namespacedName.Name, encryption, keyID)
+	}
+
+	read, err := s3client.GetObjectInBucket(bucket, s3HealthCheckObjectKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get encrypted object %q for object store %q", s3HealthCheckObjectKey, c.namespacedName.Name)
+	}
+	if k8sutil.Hash(read) != k8sutil.Hash(s3HealthCheckObjectBody) {
+		return errors.Errorf("wrong content of the encrypted object %q for object store %q", s3HealthCheckObjectKey, c.namespacedName.Name)
+	}
+
 	return nil
 }
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// validateServiceSpec validates the settings of the service and the ingress of the object store
+func validateServiceSpec(gateway cephv1.GatewaySpec) error {
+	if spec := gateway.Service; spec != nil {
+		nodePorts := spec.Type == v1.ServiceTypeNodePort || spec.Type == v1.ServiceTypeLoadBalancer
+		switch {
+		case spec.Type != "" && spec.Type != v1.ServiceTypeClusterIP && !nodePorts:
+			return errors.Errorf("invalid service type %q, expected ClusterIP, NodePort or LoadBalancer", spec.Type)
+		case (spec.NodePort != 0 || spec.SecureNodePort != 0) && !nodePorts:
+			return errors.New("node ports require a service of type NodePort or LoadBalancer")
+		case spec.LoadBalancerIP != "" && spec.Type != v1.ServiceTypeLoadBalancer:
+			return errors.New("a load balancer IP requires a service of type LoadBalancer")
+		case spec.ExternalTrafficPolicy != "" && !nodePorts:
+			return errors.New("an external traffic policy requires a service of type NodePort or LoadBalancer")
+		case spec.ExternalTrafficPolicy != "" && spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyTypeLocal &&
+			spec.ExternalTrafficPolicy != v1.ServiceExternalTrafficPolicyTypeCluster:
+			return errors.Errorf("invalid external traffic policy %q, expected Local or Cluster", spec.ExternalTrafficPolicy)
+		}
+	}
+	if ingress := gateway.Ingress; ingress != nil {
+		if errs := validation.IsDNS1123Subdomain(ingress.Host); len(errs) > 0 {
+			return errors.Errorf("invalid ingress host %q. %v", ingress.Host, errs)
+		}
+	}
+	return nil
+}
+
+// ingressHosts returns the host of the object store and the wildcard of the hosts of its buckets
+func ingressHosts(ingress *cephv1.RGWIngressSpec) []string {
+	return []string{ingress.Host, "*." + ingress.Host}
+}
+
+func (c *clusterConfig) generateIngress(cephObjectStore *cephv1.CephObjectStore) *networkingv1beta1.Ingress {
+	spec := cephObjectStore.Spec.Gateway.Ingress
+	ingress := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instanceName(cephObjectStore.Name),
+			Namespace:   cephObjectStore.Namespace,
+			Labels:      getLabels(cephObjectStore.Name, cephObjectStore.Namespace, true),
+			Annotations: spec.Annotations,
+		},
+	}
+
+	// the ingress terminates the TLS and forwards the requests to the http port unless the store only has an https port
+	servicePort := intstr.FromString("http")
+	if cephObjectStore.Spec.Gateway.Port == 0 {
+		servicePort = intstr.FromString("https")
+	}
+	backend := networkingv1beta1.IngressRuleValue{
+		HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+			Paths: []networkingv1beta1.HTTPIngressPath{
+				{
+					Path: "/",
+					Backend: networkingv1beta1.IngressBackend{
+						ServiceName: instanceName(cephObjectStore.Name),
+						ServicePort: servicePort,
+					},
+				},
+			},
+		},
+	}
+	for _, host := range ingressHosts(spec) {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1beta1.IngressRule{Host: host, IngressRuleValue: backend})
+	}
+	if spec.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{{Hosts: ingressHosts(spec), SecretName: spec.TLSSecretName}}
+	}
+	return ingress
+}
+
+// reconcileIngress creates or updates the ingress of the object store, or deletes it if it is not in the spec anymore
+func (c *clusterConfig) reconcileIngress(cephObjectStore *cephv1.CephObjectStore) error {
+	if cephObjectStore.Spec.Gateway.Ingress == nil {
+		// the ingress is not found either if the cluster does not support ingresses
+		existing, err := c.context.Clientset.NetworkingV1beta1().Ingresses(cephObjectStore.Namespace).Get(instanceName(cephObjectStore.Name), metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to get object store %q ingress", cephObjectStore.Name)
+		}
+		// an ingress of the same name created by the user is left untouched
+		if !metav1.IsControlledBy(existing, cephObjectStore) {
+			return nil
+		}
+		logger.Infof("deleting object store %q ingress", cephObjectStore.Name)
+		return k8sutil.DeleteIngress(c.context.Clientset, cephObjectStore.Namespace, existing.Name)
+	}
+	if !k8sutil.IngressSupported(c.context.Clientset) {
+		return errors.New("ingresses require kubernetes 1.14 or newer")
+	}
+
+	ingress := c.generateIngress(cephObjectStore)
+	err := controllerutil.SetControllerReference(cephObjectStore, ingress, c.scheme)
+	if err != nil {
+		return errors.Wrap(err, "failed to set owner reference to ceph object store ingress")
+	}
+	if _, err := k8sutil.CreateOrUpdateIngress(c.context.Clientset, cephObjectStore.Namespace, ingress); err != nil {
+		return errors.Wrapf(err, "failed to create or update object store %q ingress", cephObjectStore.Name)
+	}
+	logger.Infof("ceph object store gateway ingress serving %v", ingressHosts(cephObjectStore.Spec.Gateway.Ingress))
+	return nil
+}
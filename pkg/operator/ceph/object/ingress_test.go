@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateServiceSpec(t *testing.T) {
+	assert.NoError(t, validateServiceSpec(cephv1.GatewaySpec{}))
+	valid := cephv1.GatewaySpec{
+		Service: &cephv1.RGWServiceSpec{
+			Type:                  v1.ServiceTypeLoadBalancer,
+			LoadBalancerIP:        "10.0.0.10",
+			ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+			NodePort:              30080,
+		},
+		Ingress: &cephv1.RGWIngressSpec{Host: "s3.example.com"},
+	}
+	assert.NoError(t, validateServiceSpec(valid))
+
+	invalid := []*cephv1.RGWServiceSpec{
+		{Type: v1.ServiceTypeExternalName},
+		{NodePort: 30080},
+		{Type: v1.ServiceTypeNodePort, LoadBalancerIP: "10.0.0.10"},
+		{ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal},
+		{Type: v1.ServiceTypeNodePort, ExternalTrafficPolicy: "Global"},
+	}
+	for i, spec := range invalid {
+		assert.Error(t, validateServiceSpec(cephv1.GatewaySpec{Service: spec}), i)
+	}
+	assert.Error(t, validateServiceSpec(cephv1.GatewaySpec{Ingress: &cephv1.RGWIngressSpec{Host: "*.s3.example.com"}}))
+}
+
+func TestGenerateServiceSpec(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Gateway.SecurePort = 443
+	store.Spec.Gateway.Service = &cephv1.RGWServiceSpec{
+		Type:                  v1.ServiceTypeLoadBalancer,
+		Annotations:           map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+		LoadBalancerIP:        "10.0.0.10",
+		ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyTypeLocal,
+		NodePort:              30080,
+	}
+	c := &clusterConfig{store: store, clusterSpec: &cephv1.ClusterSpec{}}
+
+	svc := c.generateService(store)
+	assert.Equal(t, v1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	assert.Equal(t, "true", svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"])
+	assert.Equal(t, "10.0.0.10", svc.Spec.LoadBalancerIP)
+	assert.Equal(t, v1.ServiceExternalTrafficPolicyTypeLocal, svc.Spec.ExternalTrafficPolicy)
+	assert.Equal(t, 2, len(svc.Spec.Ports))
+	assert.Equal(t, int32(30080), svc.Spec.Ports[0].NodePort)
+	assert.Equal(t, int32(0), svc.Spec.Ports[1].NodePort)
+	assert.Equal(t, getLabels(store.Name, store.Namespace, true), svc.Spec.Selector)
+
+	// ClusterIP by default
+	store.Spec.Gateway.Service = nil
+	svc = c.generateService(store)
+	assert.Equal(t, v1.ServiceType(""), svc.Spec.Type)
+	assert.Empty(t, svc.Annotations)
+}
+
+func TestReconcileIngress(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{GitVersion: "v1.18.3"}
+	store := simpleStore()
+	store.UID = "store-uid"
+	store.Spec.Gateway.Ingress = &cephv1.RGWIngressSpec{
+		Host:          "s3.example.com",
+		TLSSecretName: "s3-tls",
+		Annotations:   map[string]string{"kubernetes.io/ingress.class": "nginx"},
+	}
+	c := &clusterConfig{
+		context: &clusterd.Context{Clientset: clientset},
+		store:   store,
+		scheme:  scheme.Scheme,
+	}
+
+	// the ingress serves the store and the buckets
+	assert.NoError(t, c.reconcileIngress(store))
+	ingress, err := clientset.NetworkingV1beta1().Ingresses(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx", ingress.Annotations["kubernetes.io/ingress.class"])
+	assert.True(t, metav1.IsControlledBy(ingress, store))
+	assert.Equal(t, 2, len(ingress.Spec.Rules))
+	assert.Equal(t, "s3.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "*.s3.example.com", ingress.Spec.Rules[1].Host)
+	backend := ingress.Spec.Rules[1].HTTP.Paths[0].Backend
+	assert.Equal(t, networkingv1beta1.IngressBackend{ServiceName: "rook-ceph-rgw-default", ServicePort: intstr.FromString("http")}, backend)
+	assert.Equal(t, []networkingv1beta1.IngressTLS{{Hosts: []string{"s3.example.com", "*.s3.example.com"}, SecretName: "s3-tls"}}, ingress.Spec.TLS)
+
+	// the host is updated
+	store.Spec.Gateway.Ingress.Host = "objects.example.com"
+	assert.NoError(t, c.reconcileIngress(store))
+	ingress, err = clientset.NetworkingV1beta1().Ingresses(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "objects.example.com", ingress.Spec.Rules[0].Host)
+
+	// the ingress is deleted with the ingress spec
+	store.Spec.Gateway.Ingress = nil
+	assert.NoError(t, c.reconcileIngress(store))
+	_, err = clientset.NetworkingV1beta1().Ingresses(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	// an ingress of the user is not deleted
+	ingress.OwnerReferences = nil
+	ingress.ResourceVersion = ""
+	_, err = clientset.NetworkingV1beta1().Ingresses(store.Namespace).Create(ingress)
+	assert.NoError(t, err)
+	assert.NoError(t, c.reconcileIngress(store))
+	_, err = clientset.NetworkingV1beta1().Ingresses(store.Namespace).Get("rook-ceph-rgw-default", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// ingresses are not supported before k8s 1.14
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{GitVersion: "v1.13.5"}
+	store.Spec.Gateway.Ingress = &cephv1.RGWIngressSpec{Host: "s3.example.com"}
+	assert.Error(t, c.reconcileIngress(store))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	kmsBackendVault   = "vault"
+	kmsBackendTesting = "testing"
+
+	// the token is copied from the secret to a volume owned by the ceph user since rgw refuses a token file that
+	// other users can read
+	vaultTokenSecretVolumeName = "rook-ceph-rgw-vault-token-secret"
+	vaultTokenSecretDir        = "/etc/ceph/vault-secret"
+	vaultTokenVolumeName       = "rook-ceph-rgw-vault-token"
+	vaultTokenDir              = "/etc/ceph/vault"
+	vaultTokenFileName         = "vault.token"
+	vaultTokenSecretKey        = "token"
+
+	kmsTestingKeysSecretKey = "keys"
+	kmsTestingKeysEnvVar    = "ROOK_RGW_KMS_TESTING_KEYS"
+)
+
+var vaultSecretEngines = map[string]bool{"kv": true, "transit": true}
+
+// validateKMS validates the key management service of the server side encryption, RGW requires SSL for the
+// encryption requests unless the insecure encryption is allowed
+func validateKMS(kms *cephv1.ObjectStoreKMSSpec, gateway cephv1.GatewaySpec) error {
+	if kms == nil {
+		return nil
+	}
+	if (kms.Vault == nil) == (kms.TestingKeysSecretName == "") {
+		return errors.New("the kms requires either a vault backend or a testing keys secret")
+	}
+	if gateway.SSLCertificateRef == "" && !kms.AllowInsecureEncryption {
+		return errors.New("the kms requires an SSL certificate of the gateway, or allowInsecureEncryption to encrypt over plain http")
+	}
+	if kms.Vault == nil {
+		return nil
+	}
+	if u, err := url.Parse(kms.Vault.Address); err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.Errorf("invalid vault address %q", kms.Vault.Address)
+	}
+	if !vaultSecretEngines[kms.Vault.SecretEngine] {
+		return errors.Errorf("invalid vault secret engine %q, expected kv or transit", kms.Vault.SecretEngine)
+	}
+	if kms.Vault.TokenSecretName == "" {
+		return errors.New("the vault backend requires a token secret")
+	}
+	return nil
+}
+
+func vaultTokenPath() string {
+	return path.Join(vaultTokenDir, vaultTokenFileName)
+}
+
+// kmsFlags returns the rgw flags of the server side encryption
+func (c *clusterConfig) kmsFlags() []string {
+	kms := c.store.Spec.Security.KMS
+	if kms == nil {
+		return []string{}
+	}
+	flags := []string{}
+	// the encryption over plain http was explicitly allowed for a store without a certificate
+	if kms.AllowInsecureEncryption && c.store.Spec.Gateway.SSLCertificateRef == "" {
+		flags = append(flags, cephconfig.NewFlag("rgw crypt require ssl", "false"))
+	}
+	if kms.Vault == nil {
+		return append(flags,
+			cephconfig.NewFlag("rgw crypt s3 kms backend", kmsBackendTesting),
+			cephconfig.NewFlag("rgw crypt s3 kms encryption keys", controller.ContainerEnvVarReference(kmsTestingKeysEnvVar)),
+		)
+	}
+
+	flags = append(flags,
+		cephconfig.NewFlag("rgw crypt s3 kms backend", kmsBackendVault),
+		cephconfig.NewFlag("rgw crypt vault auth", "token"),
+		cephconfig.NewFlag("rgw crypt vault addr", kms.Vault.Address),
+		cephconfig.NewFlag("rgw crypt vault token file", vaultTokenPath()),
+		cephconfig.NewFlag("rgw crypt vault secret engine", kms.Vault.SecretEngine),
+	)
+	if kms.Vault.Prefix != "" {
+		flags = append(flags, cephconfig.NewFlag("rgw crypt vault prefix", kms.Vault.Prefix))
+	}
+	if kms.Vault.Namespace != "" {
+		flags = append(flags, cephconfig.NewFlag("rgw crypt vault namespace", kms.Vault.Namespace))
+	}
+	return flags
+}
+
+// kmsEnvVars returns the env vars of the rgw container with the testing keys
+func (c *clusterConfig) kmsEnvVars() []v1.EnvVar {
+	kms := c.store.Spec.Security.KMS
+	if kms == nil || kms.TestingKeysSecretName == "" {
+		return []v1.EnvVar{}
+	}
+	return []v1.EnvVar{secretEnvVar(kmsTestingKeysEnvVar, kms.TestingKeysSecretName, kmsTestingKeysSecretKey)}
+}
+
+// kmsVolumes returns the volumes of the vault token
+func (c *clusterConfig) kmsVolumes() []v1.Volume {
+	kms := c.store.Spec.Security.KMS
+	if kms == nil || kms.Vault == nil {
+		return []v1.Volume{}
+	}
+	userReadOnly := int32(0400)
+	return []v1.Volume{
+		{
+			Name: vaultTokenSecretVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: kms.Vault.TokenSecretName,
+					Items: []v1.KeyToPath{
+						{Key: vaultTokenSecretKey, Path: vaultTokenFileName, Mode: &userReadOnly},
+					}}}},
+		{
+			Name: vaultTokenVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory},
+			}},
+	}
+}
+
+// kmsVolumeMounts returns the volume mounts of the rgw container with the vault token
+func (c *clusterConfig) kmsVolumeMounts() []v1.VolumeMount {
+	kms := c.store.Spec.Security.KMS
+	if kms == nil || kms.Vault == nil {
+		return []v1.VolumeMount{}
+	}
+	return []v1.VolumeMount{{Name: vaultTokenVolumeName, MountPath: vaultTokenDir, ReadOnly: true}}
+}
+
+// kmsInitContainers returns the init container copying the vault token to a file owned by the ceph user
+func (c *clusterConfig) kmsInitContainers() []v1.Container {
+	kms := c.store.Spec.Security.KMS
+	if kms == nil || kms.Vault == nil {
+		return []v1.Container{}
+	}
+	secretPath := path.Join(vaultTokenSecretDir, vaultTokenFileName)
+	return []v1.Container{{
+		Name:    "vault-token",
+		Command: []string{"/bin/bash", "-c"},
+		Args: []string{fmt.Sprintf("cp %s %s && chown ceph:ceph %s && chmod 0400 %s",
+			secretPath, vaultTokenPath(), vaultTokenPath(), vaultTokenPath())},
+		Image: c.clusterSpec.CephVersion.Image,
+		VolumeMounts: []v1.VolumeMount{
+			{Name: vaultTokenSecretVolumeName, MountPath: vaultTokenSecretDir, ReadOnly: true},
+			{Name: vaultTokenVolumeName, MountPath: vaultTokenDir},
+		},
+		Resources:       c.store.Spec.Gateway.Resources,
+		SecurityContext: mon.PodSecurityContext(),
+	}}
+}
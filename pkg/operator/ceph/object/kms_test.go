@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	cephconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func vaultKMSSpec() *cephv1.ObjectStoreKMSSpec {
+	return &cephv1.ObjectStoreKMSSpec{
+		Vault: &cephv1.ObjectStoreVaultSpec{
+			Address:         "http://vault.default.svc:8200",
+			SecretEngine:    "transit",
+			Prefix:          "/v1/transit",
+			TokenSecretName: "vault-token",
+		},
+		HealthCheckKeyID: "rook-key",
+	}
+}
+
+func TestValidateKMS(t *testing.T) {
+	gateway := cephv1.GatewaySpec{SSLCertificateRef: "mycert"}
+	assert.NoError(t, validateKMS(nil, cephv1.GatewaySpec{}))
+	assert.NoError(t, validateKMS(vaultKMSSpec(), gateway))
+	assert.NoError(t, validateKMS(&cephv1.ObjectStoreKMSSpec{TestingKeysSecretName: "keys"}, gateway))
+
+	// the encryption over plain http must be explicitly allowed
+	assert.Error(t, validateKMS(vaultKMSSpec(), cephv1.GatewaySpec{}))
+	insecure := vaultKMSSpec()
+	insecure.AllowInsecureEncryption = true
+	assert.NoError(t, validateKMS(insecure, cephv1.GatewaySpec{}))
+
+	invalid := []func(kms *cephv1.ObjectStoreKMSSpec){
+		func(kms *cephv1.ObjectStoreKMSSpec) { kms.Vault = nil },
+		func(kms *cephv1.ObjectStoreKMSSpec) { kms.TestingKeysSecretName = "keys" },
+		func(kms *cephv1.ObjectStoreKMSSpec) { kms.Vault.Address = "vault:8200" },
+		func(kms *cephv1.ObjectStoreKMSSpec) { kms.Vault.SecretEngine = "pki" },
+		func(kms *cephv1.ObjectStoreKMSSpec) { kms.Vault.TokenSecretName = "" },
+	}
+	for i, modify := range invalid {
+		kms := vaultKMSSpec()
+		modify(kms)
+		assert.Error(t, validateKMS(kms, gateway), i)
+	}
+}
+
+func TestKMSPodSpec(t *testing.T) {
+	store := simpleStore()
+	store.Spec.Security.KMS = vaultKMSSpec()
+	store.Spec.Security.KMS.AllowInsecureEncryption = true
+	c := &clusterConfig{
+		clusterInfo: clienttest.CreateTestClusterInfo(1),
+		store:       store,
+		rookVersion: "rook/rook:myversion",
+		clusterSpec: &cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v15"},
+		},
+		DataPathMap: cephconfig.NewStatelessDaemonDataPathMap(cephconfig.RgwType, "default", "rook-ceph", "/var/lib/rook/"),
+	}
+	rgwConfig := &rgwConfig{ResourceName: fmt.Sprintf("%s-%s", AppName, c.store.Name)}
+
+	// the vault token is copied to a volume of the rgw container
+	s, err := c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.Len(t, s.Spec.InitContainers, 2)
+	assert.Equal(t, "vault-token", s.Spec.InitContainers[1].Name)
+	volumes := map[string]bool{}
+	for _, volume := range s.Spec.Volumes {
+		volumes[volume.Name] = true
+	}
+	assert.True(t, volumes[vaultTokenSecretVolumeName])
+	assert.True(t, volumes[vaultTokenVolumeName])
+	container := s.Spec.Containers[0]
+	assert.Contains(t, container.VolumeMounts, c.kmsVolumeMounts()[0])
+	assert.Contains(t, container.Args, "--rgw-crypt-s3-kms-backend=vault")
+	assert.Contains(t, container.Args, "--rgw-crypt-vault-token-file=/etc/ceph/vault/vault.token")
+	assert.Contains(t, container.Args, "--rgw-crypt-vault-secret-engine=transit")
+	assert.Contains(t, container.Args, "--rgw-crypt-vault-prefix=/v1/transit")
+	assert.Contains(t, container.Args, "--rgw-crypt-require-ssl=false")
+
+	// the testing keys are read from the secret
+	store.Spec.Security.KMS = &cephv1.ObjectStoreKMSSpec{TestingKeysSecretName: "keys", AllowInsecureEncryption: true}
+	store.Spec.Gateway.SSLCertificateRef = "mycert"
+	s, err = c.makeRGWPodSpec(rgwConfig)
+	assert.NoError(t, err)
+	assert.Len(t, s.Spec.InitContainers, 1)
+	container = s.Spec.Containers[0]
+	assert.Contains(t, container.Args, "--rgw-crypt-s3-kms-backend=testing")
+	assert.Contains(t, container.Args, "--rgw-crypt-s3-kms-encryption-keys=$(ROOK_RGW_KMS_TESTING_KEYS)")
+	assert.NotContains(t, container.Args, "--rgw-crypt-require-ssl=false")
+	assert.Contains(t, container.Env, c.kmsEnvVars()[0])
+}
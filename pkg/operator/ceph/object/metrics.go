@@ -0,0 +1,437 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// defaultMetricsInterval is the default interval of the collection of the usage of an object store
+	defaultMetricsInterval = 5 * time.Minute
+	// operatorMetricsName is the name of the service and service monitor of the metrics endpoint of the operator
+	operatorMetricsName = "rook-ceph-operator-metrics"
+	// operatorMetricsPort is the port of the metrics endpoint of the controller-runtime manager of the operator
+	operatorMetricsPort = 8080
+	// operatorMetricsPortName is the name of the port scraped by the service monitor template
+	operatorMetricsPortName = "http-metrics"
+	monitoringPath          = "/etc/ceph-monitoring/"
+	serviceMonitorFile      = "service-monitor.yaml"
+)
+
+var (
+	storeLabels  = []string{"namespace", "object_store"}
+	bucketLabels = []string{"namespace", "object_store", "bucket", "owner"}
+	userLabels   = []string{"namespace", "object_store", "user"}
+	probeLabels  = []string{"namespace", "object_store", "operation", "object_size"}
+	// probeQuantiles are the quantiles of the latencies of the probes published in the summaries
+	probeQuantiles = []float64{0.5, 0.9, 0.99}
+
+	bucketSizeDesc = prometheus.NewDesc("rook_ceph_object_bucket_size_bytes",
+		"Size of the objects of the bucket", bucketLabels, nil)
+	bucketObjectsDesc = prometheus.NewDesc("rook_ceph_object_bucket_objects",
+		"Number of objects of the bucket", bucketLabels, nil)
+	bucketQuotaSizeDesc = prometheus.NewDesc("rook_ceph_object_bucket_quota_size_utilization_ratio",
+		"Size of the bucket relative to the maximum size of its quota", bucketLabels, nil)
+	bucketQuotaObjectsDesc = prometheus.NewDesc("rook_ceph_object_bucket_quota_objects_utilization_ratio",
+		"Number of objects of the bucket relative to the maximum number of objects of its quota", bucketLabels, nil)
+	userSizeDesc = prometheus.NewDesc("rook_ceph_object_user_size_bytes",
+		"Size of the objects of the buckets of the user", userLabels, nil)
+	userObjectsDesc = prometheus.NewDesc("rook_ceph_object_user_objects",
+		"Number of objects of the buckets of the user", userLabels, nil)
+	userBucketsDesc = prometheus.NewDesc("rook_ceph_object_user_buckets",
+		"Number of buckets of the user", userLabels, nil)
+	userQuotaSizeDesc = prometheus.NewDesc("rook_ceph_object_user_quota_size_utilization_ratio",
+		"Size of the buckets of the user relative to the maximum size of its quota", userLabels, nil)
+	userQuotaObjectsDesc = prometheus.NewDesc("rook_ceph_object_user_quota_objects_utilization_ratio",
+		"Number of objects of the user relative to the maximum number of objects of its quota", userLabels, nil)
+	healthCheckDurationDesc = prometheus.NewDesc("rook_ceph_object_health_check_duration_seconds",
+		"Duration of the last bucket health check of the object store", storeLabels, nil)
+	healthCheckSuccessDesc = prometheus.NewDesc("rook_ceph_object_health_check_success",
+		"Whether the last bucket health check of the object store succeeded", storeLabels, nil)
+	probeLatencyDesc = prometheus.NewDesc("rook_ceph_object_probe_latency_seconds",
+		"Latency of the operations of the probes of the object store over their last samples", probeLabels, nil)
+
+	// storeCollector publishes the metrics of the object stores on the metrics endpoint of the operator
+	storeCollector = newObjectStoreCollector()
+)
+
+func init() {
+	metrics.Registry.MustRegister(storeCollector)
+}
+
+// objectStoreUsage is the usage of the buckets and users of an object store at the last collection
+type objectStoreUsage struct {
+	buckets []bucketUsage
+	users   []userUsage
+}
+
+type bucketUsage struct {
+	name  string
+	owner string
+	ObjectBucketStats
+	quota rgwQuota
+}
+
+type userUsage struct {
+	name    string
+	buckets uint64
+	ObjectBucketStats
+	quota rgwQuota
+}
+
+// healthCheckResult is the result of the last bucket health check of an object store
+type healthCheckResult struct {
+	duration time.Duration
+	success  bool
+}
+
+// probeSummary is the summary of the latencies of an operation of the probes of an object store
+type probeSummary struct {
+	operation  string
+	objectSize string
+	count      uint64
+	sum        float64
+	quantiles  map[float64]float64
+}
+
+// objectStoreCollector is a prometheus collector of the last usage, health check and probes of the object stores.
+// The metrics of an object store disappear when its collection stops.
+type objectStoreCollector struct {
+	mutex        sync.Mutex
+	usage        map[types.NamespacedName]*objectStoreUsage
+	healthChecks map[types.NamespacedName]healthCheckResult
+	probes       map[types.NamespacedName][]probeSummary
+}
+
+func newObjectStoreCollector() *objectStoreCollector {
+	return &objectStoreCollector{
+		usage:        map[types.NamespacedName]*objectStoreUsage{},
+		healthChecks: map[types.NamespacedName]healthCheckResult{},
+		probes:       map[types.NamespacedName][]probeSummary{},
+	}
+}
+
+func (c *objectStoreCollector) setUsage(name types.NamespacedName, usage *objectStoreUsage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if usage == nil {
+		delete(c.usage, name)
+		return
+	}
+	c.usage[name] = usage
+}
+
+func (c *objectStoreCollector) setHealthCheck(name types.NamespacedName, result *healthCheckResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if result == nil {
+		delete(c.healthChecks, name)
+		return
+	}
+	c.healthChecks[name] = *result
+}
+
+func (c *objectStoreCollector) setProbes(name types.NamespacedName, summaries []probeSummary) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if summaries == nil {
+		delete(c.probes, name)
+		return
+	}
+	c.probes[name] = summaries
+}
+
+// Describe implements prometheus.Collector
+func (c *objectStoreCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		bucketSizeDesc, bucketObjectsDesc, bucketQuotaSizeDesc, bucketQuotaObjectsDesc,
+		userSizeDesc, userObjectsDesc, userBucketsDesc, userQuotaSizeDesc, userQuotaObjectsDesc,
+		healthCheckDurationDesc, healthCheckSuccessDesc, probeLatencyDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *objectStoreCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for name, usage := range c.usage {
+		for _, b := range usage.buckets {
+			labels := []string{name.Namespace, name.Name, b.name, b.owner}
+			ch <- prometheus.MustNewConstMetric(bucketSizeDesc, prometheus.GaugeValue, float64(b.Size), labels...)
+			ch <- prometheus.MustNewConstMetric(bucketObjectsDesc, prometheus.GaugeValue, float64(b.NumberOfObjects), labels...)
+			collectQuota(ch, bucketQuotaSizeDesc, bucketQuotaObjectsDesc, b.quota, b.ObjectBucketStats, labels)
+		}
+		for _, u := range usage.users {
+			labels := []string{name.Namespace, name.Name, u.name}
+			ch <- prometheus.MustNewConstMetric(userSizeDesc, prometheus.GaugeValue, float64(u.Size), labels...)
+			ch <- prometheus.MustNewConstMetric(userObjectsDesc, prometheus.GaugeValue, float64(u.NumberOfObjects), labels...)
+			ch <- prometheus.MustNewConstMetric(userBucketsDesc, prometheus.GaugeValue, float64(u.buckets), labels...)
+			collectQuota(ch, userQuotaSizeDesc, userQuotaObjectsDesc, u.quota, u.ObjectBucketStats, labels)
+		}
+	}
+
+	for name, result := range c.healthChecks {
+		success := 0.0
+		if result.success {
+			success = 1
+		}
+		ch <- prometheus.MustNewConstMetric(healthCheckDurationDesc, prometheus.GaugeValue, result.duration.Seconds(), name.Namespace, name.Name)
+		ch <- prometheus.MustNewConstMetric(healthCheckSuccessDesc, prometheus.GaugeValue, success, name.Namespace, name.Name)
+	}
+
+	for name, summaries := range c.probes {
+		for _, s := range summaries {
+			ch <- prometheus.MustNewConstSummary(probeLatencyDesc, s.count, s.sum, s.quantiles, name.Namespace, name.Name, s.operation, s.objectSize)
+		}
+	}
+}
+
+// collectQuota publishes the utilization of the limits of a quota, the limits that are not set are skipped
+func collectQuota(ch chan<- prometheus.Metric, sizeDesc, objectsDesc *prometheus.Desc, quota rgwQuota, stats ObjectBucketStats, labels []string) {
+	if !quota.Enabled {
+		return
+	}
+	if quota.MaxSize > 0 {
+		ch <- prometheus.MustNewConstMetric(sizeDesc, prometheus.GaugeValue, float64(stats.Size)/float64(quota.MaxSize), labels...)
+	}
+	if quota.MaxObjects > 0 {
+		ch <- prometheus.MustNewConstMetric(objectsDesc, prometheus.GaugeValue, float64(stats.NumberOfObjects)/float64(quota.MaxObjects), labels...)
+	}
+}
+
+// collectUsage collects the usage and quotas of the buckets and users of an object store. The usage of a user is
+// the sum of the usage of the buckets it owns.
+func collectUsage(c *Context) (*objectStoreUsage, error) {
+	stats, err := listBucketsStats(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the stats of the buckets")
+	}
+	userIDs, _, err := ListUsers(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the users")
+	}
+
+	users := map[string]*userUsage{}
+	for _, id := range userIDs {
+		users[id] = &userUsage{name: id}
+	}
+
+	usage := &objectStoreUsage{}
+	for _, s := range stats {
+		bucket := bucketUsage{name: s.Bucket, owner: s.Owner, ObjectBucketStats: bucketStatsFromRGW(s), quota: s.BucketQuota}
+		usage.buckets = append(usage.buckets, bucket)
+		if user, ok := users[s.Owner]; ok {
+			user.buckets++
+			user.Size += bucket.Size
+			user.NumberOfObjects += bucket.NumberOfObjects
+		}
+	}
+
+	for _, id := range userIDs {
+		user := users[id]
+		quota, err := getUserQuota(c, id)
+		if err != nil {
+			// the usage of the user is still published without the utilization of its quota
+			logger.Warningf("failed to get the quota of user %q of object store %q. %v", id, c.Name, err)
+		} else {
+			user.quota = *quota
+		}
+		usage.users = append(usage.users, *user)
+	}
+	sort.Slice(usage.buckets, func(i, j int) bool { return usage.buckets[i].name < usage.buckets[j].name })
+	sort.Slice(usage.users, func(i, j int) bool { return usage.users[i].name < usage.users[j].name })
+
+	return usage, nil
+}
+
+// getUserQuota returns the quota of a user
+func getUserQuota(c *Context, id string) (*rgwQuota, error) {
+	result, err := runAdminCommand(c, "user", "info", "--uid", id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "radosgw-admin command err. %s", result)
+	}
+	var user rgwUserInfo
+	if err := json.Unmarshal([]byte(result), &user); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal json. %s", result)
+	}
+	return &user.UserQuota, nil
+}
+
+// validateMetrics validates the metrics settings of the object store
+func validateMetrics(spec cephv1.ObjectStoreMetricsSpec) error {
+	if spec.Interval == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(spec.Interval)
+	if err != nil {
+		return errors.Wrapf(err, "invalid metrics interval %q", spec.Interval)
+	}
+	if interval <= 0 {
+		return errors.Errorf("metrics interval %q must be positive", spec.Interval)
+	}
+	return nil
+}
+
+// metricsInterval returns the interval of the collection of the usage of the object store
+func metricsInterval(spec cephv1.ObjectStoreMetricsSpec) time.Duration {
+	if interval, err := time.ParseDuration(spec.Interval); err == nil && interval > 0 {
+		return interval
+	}
+	return defaultMetricsInterval
+}
+
+// usageCollector periodically collects the usage of an object store
+type usageCollector struct {
+	objContext     *Context
+	namespacedName types.NamespacedName
+	interval       time.Duration
+}
+
+// collect collects the usage of the object store until the stop channel is closed
+func (u *usageCollector) collect(stopCh chan struct{}) {
+	u.update()
+	for {
+		select {
+		case <-stopCh:
+			storeCollector.setUsage(u.namespacedName, nil)
+			logger.Infof("stopping the collection of the usage metrics of object store %q", u.namespacedName.Name)
+			return
+
+		case <-time.After(u.interval):
+			u.update()
+		}
+	}
+}
+
+func (u *usageCollector) update() {
+	logger.Debugf("collecting the usage metrics of object store %q", u.namespacedName.Name)
+	usage, err := collectUsage(u.objContext)
+	if err != nil {
+		// the last usage is published until the next successful collection
+		logger.Errorf("failed to collect the usage metrics of object store %q. %v", u.namespacedName.Name, err)
+		return
+	}
+	storeCollector.setUsage(u.namespacedName, usage)
+}
+
+// reconcileMetrics starts the collection of the usage of the object store, restarts it when its interval changes
+// and stops it when the metrics are disabled
+func (r *ReconcileCephObjectStore) reconcileMetrics(objectstore *cephv1.CephObjectStore, objContext *Context, namespacedName types.NamespacedName) {
+	health := r.objectStoreChannels[objectstore.Name]
+	spec := objectstore.Spec.Metrics
+	interval := metricsInterval(spec)
+
+	if health.metricsStopChan != nil && (!spec.Enabled || interval != health.metricsInterval) {
+		close(health.metricsStopChan)
+		health.metricsStopChan = nil
+	}
+	if !spec.Enabled {
+		return
+	}
+
+	if health.metricsStopChan == nil {
+		health.metricsStopChan = make(chan struct{})
+		health.metricsInterval = interval
+		collector := &usageCollector{objContext: objContext, namespacedName: namespacedName, interval: interval}
+		logger.Infof("collecting the usage metrics of object store %q every %s", objectstore.Name, interval)
+		go collector.collect(health.metricsStopChan)
+	}
+
+	if spec.ServiceMonitor {
+		if err := enableMetricsServiceMonitor(r.context.Clientset); err != nil {
+			logger.Errorf("failed to enable the service monitor of the operator metrics. %v", err)
+		}
+	}
+}
+
+// stopMetrics stops the collection of the usage of the object store
+func (h *objectStoreHealth) stopMetrics() {
+	if h.metricsStopChan != nil {
+		close(h.metricsStopChan)
+		h.metricsStopChan = nil
+	}
+}
+
+// operatorMetricsService returns the service of the metrics endpoint of the operator
+func operatorMetricsService(namespace string) *v1.Service {
+	labels := map[string]string{k8sutil.AppAttr: operatorMetricsName}
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorMetricsName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{k8sutil.AppAttr: "rook-ceph-operator"},
+			Ports: []v1.ServicePort{
+				{
+					Name:       operatorMetricsPortName,
+					Port:       operatorMetricsPort,
+					TargetPort: intstr.FromInt(operatorMetricsPort),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// enableMetricsServiceMonitor creates the service of the metrics endpoint of the operator and a service monitor so
+// prometheus scrapes it. They are shared by the object stores and are not deleted with them.
+func enableMetricsServiceMonitor(clientset kubernetes.Interface) error {
+	namespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if namespace == "" {
+		return errors.Errorf("the namespace of the operator is not set in %q", k8sutil.PodNamespaceEnvVar)
+	}
+	service := operatorMetricsService(namespace)
+	if _, err := k8sutil.CreateOrUpdateService(clientset, namespace, service); err != nil {
+		return errors.Wrap(err, "failed to create the service of the operator metrics")
+	}
+
+	serviceMonitor, err := k8sutil.GetServiceMonitor(path.Join(monitoringPath, serviceMonitorFile))
+	if err != nil {
+		return errors.Wrap(err, "service monitor could not be enabled")
+	}
+	serviceMonitor.SetName(operatorMetricsName)
+	serviceMonitor.SetNamespace(namespace)
+	serviceMonitor.Spec.NamespaceSelector.MatchNames = []string{namespace}
+	serviceMonitor.Spec.Selector.MatchLabels = service.GetLabels()
+	serviceMonitor.Spec.Endpoints[0].Port = operatorMetricsPortName
+	if _, err := k8sutil.CreateOrUpdateServiceMonitor(serviceMonitor); err != nil {
+		return errors.Wrap(err, "service monitor could not be enabled")
+	}
+	return nil
+}
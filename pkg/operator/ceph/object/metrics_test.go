@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	usageBucketStatsJSON = `[
+  {"bucket": "photos", "owner": "alice", "usage": {"rgw.main": {"size": 300, "num_objects": 3}},
+   "bucket_quota": {"enabled": true, "max_size": 1000, "max_objects": -1}},
+  {"bucket": "videos", "owner": "alice", "usage": {"rgw.main": {"size": 700, "num_objects": 1}},
+   "bucket_quota": {"enabled": false, "max_size": -1, "max_objects": -1}},
+  {"bucket": "logs", "owner": "bob", "usage": {},
+   "bucket_quota": {"enabled": false, "max_size": -1, "max_objects": -1}}
+]`
+	usageAliceInfoJSON = `{"user_id": "alice", "keys": [], "user_quota": {"enabled": true, "max_size": 4000, "max_objects": 8}}`
+	usageBobInfoJSON   = `{"user_id": "bob", "keys": [], "user_quota": {"enabled": false, "max_size": -1, "max_objects": -1}}`
+)
+
+func usageExecutor() *exectest.MockExecutor {
+	return &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "bucket" && args[1] == "stats":
+				return usageBucketStatsJSON, nil
+			case args[0] == "user" && args[1] == "list":
+				return `["alice", "bob"]`, nil
+			case args[0] == "user" && args[1] == "info" && args[3] == "alice":
+				return usageAliceInfoJSON, nil
+			case args[0] == "user" && args[1] == "info" && args[3] == "bob":
+				return usageBobInfoJSON, nil
+			}
+			return "", nil
+		},
+	}
+}
+
+func TestCollectUsage(t *testing.T) {
+	objContext := NewContext(&clusterd.Context{Executor: usageExecutor()}, &client.ClusterInfo{Namespace: "mycluster"}, "my-store")
+
+	usage, err := collectUsage(objContext)
+	require.NoError(t, err)
+	assert.Equal(t, []bucketUsage{
+		{name: "logs", owner: "bob", quota: rgwQuota{MaxSize: -1, MaxObjects: -1}},
+		{name: "photos", owner: "alice", ObjectBucketStats: ObjectBucketStats{Size: 300, NumberOfObjects: 3}, quota: rgwQuota{Enabled: true, MaxSize: 1000, MaxObjects: -1}},
+		{name: "videos", owner: "alice", ObjectBucketStats: ObjectBucketStats{Size: 700, NumberOfObjects: 1}, quota: rgwQuota{MaxSize: -1, MaxObjects: -1}},
+	}, usage.buckets)
+	assert.Equal(t, []userUsage{
+		{name: "alice", buckets: 2, ObjectBucketStats: ObjectBucketStats{Size: 1000, NumberOfObjects: 4}, quota: rgwQuota{Enabled: true, MaxSize: 4000, MaxObjects: 8}},
+		{name: "bob", buckets: 1, quota: rgwQuota{MaxSize: -1, MaxObjects: -1}},
+	}, usage.users)
+}
+
+func TestObjectStoreCollector(t *testing.T) {
+	collector := newObjectStoreCollector()
+	name := types.NamespacedName{Namespace: "mycluster", Name: "my-store"}
+	collector.setUsage(name, &objectStoreUsage{
+		buckets: []bucketUsage{
+			{name: "photos", owner: "alice", ObjectBucketStats: ObjectBucketStats{Size: 300, NumberOfObjects: 3}, quota: rgwQuota{Enabled: true, MaxSize: 1000, MaxObjects: -1}},
+		},
+		users: []userUsage{
+			{name: "alice", buckets: 1, ObjectBucketStats: ObjectBucketStats{Size: 300, NumberOfObjects: 3}, quota: rgwQuota{Enabled: true, MaxSize: -1, MaxObjects: 12}},
+		},
+	})
+	collector.setHealthCheck(name, &healthCheckResult{duration: 1500 * time.Millisecond, success: true})
+
+	expected := `
+# HELP rook_ceph_object_bucket_quota_size_utilization_ratio Size of the bucket relative to the maximum size of its quota
+# TYPE rook_ceph_object_bucket_quota_size_utilization_ratio gauge
+rook_ceph_object_bucket_quota_size_utilization_ratio{bucket="photos",namespace="mycluster",object_store="my-store",owner="alice"} 0.3
+# HELP rook_ceph_object_bucket_size_bytes Size of the objects of the bucket
+# TYPE rook_ceph_object_bucket_size_bytes gauge
+rook_ceph_object_bucket_size_bytes{bucket="photos",namespace="mycluster",object_store="my-store",owner="alice"} 300
+# HELP rook_ceph_object_health_check_duration_seconds Duration of the last bucket health check of the object store
+# TYPE rook_ceph_object_health_check_duration_seconds gauge
+rook_ceph_object_health_check_duration_seconds{namespace="mycluster",object_store="my-store"} 1.5
+# HELP rook_ceph_object_user_buckets Number of buckets of the user
+# TYPE rook_ceph_object_user_buckets gauge
+rook_ceph_object_user_buckets{namespace="mycluster",object_store="my-store",user="alice"} 1
+# HELP rook_ceph_object_user_quota_objects_utilization_ratio Number of objects of the user relative to the maximum number of objects of its quota
+# TYPE rook_ceph_object_user_quota_objects_utilization_ratio gauge
+rook_ceph_object_user_quota_objects_utilization_ratio{namespace="mycluster",object_store="my-store",user="alice"} 0.25
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"rook_ceph_object_bucket_quota_size_utilization_ratio",
+		"rook_ceph_object_bucket_quota_objects_utilization_ratio",
+		"rook_ceph_object_bucket_size_bytes",
+		"rook_ceph_object_health_check_duration_seconds",
+		"rook_ceph_object_user_buckets",
+		"rook_ceph_object_user_quota_size_utilization_ratio",
+		"rook_ceph_object_user_quota_objects_utilization_ratio")
+	assert.NoError(t, err)
+	assert.Equal(t, 9, testutil.CollectAndCount(collector))
+
+	// the metrics of the store disappear when the collection stops
+	collector.setUsage(name, nil)
+	collector.setHealthCheck(name, nil)
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+}
+
+func TestValidateMetrics(t *testing.T) {
+	assert.NoError(t, validateMetrics(cephv1.ObjectStoreMetricsSpec{}))
+	assert.NoError(t, validateMetrics(cephv1.ObjectStoreMetricsSpec{Enabled: true, Interval: "1m"}))
+	assert.Error(t, validateMetrics(cephv1.ObjectStoreMetricsSpec{Interval: "often"}))
+	assert.Error(t, validateMetrics(cephv1.ObjectStoreMetricsSpec{Interval: "-1m"}))
+
+	assert.Equal(t, defaultMetricsInterval, metricsInterval(cephv1.ObjectStoreMetricsSpec{}))
+	assert.Equal(t, time.Minute, metricsInterval(cephv1.ObjectStoreMetricsSpec{Interval: "1m"}))
+}
+
+func TestReconcileMetrics(t *testing.T) {
+	store := simpleStore()
+	name := types.NamespacedName{Namespace: store.Namespace, Name: store.Name}
+	objContext := NewContext(&clusterd.Context{Executor: usageExecutor()}, &client.ClusterInfo{Namespace: store.Namespace}, store.Name)
+	health := &objectStoreHealth{stopChan: make(chan struct{})}
+	r := &ReconcileCephObjectStore{objectStoreChannels: map[string]*objectStoreHealth{store.Name: health}}
+
+	// the metrics are disabled by default
+	r.reconcileMetrics(store, objContext, name)
+	assert.Nil(t, health.metricsStopChan)
+
+	store.Spec.Metrics = cephv1.ObjectStoreMetricsSpec{Enabled: true}
+	r.reconcileMetrics(store, objContext, name)
+	stopChan := health.metricsStopChan
+	assert.NotNil(t, stopChan)
+	assert.Equal(t, defaultMetricsInterval, health.metricsInterval)
+
+	// the collection is not restarted if the settings do not change
+	r.reconcileMetrics(store, objContext, name)
+	assert.Equal(t, stopChan, health.metricsStopChan)
+
+	// the collection is restarted with a new interval
+	store.Spec.Metrics.Interval = "1m"
+	r.reconcileMetrics(store, objContext, name)
+	assert.NotEqual(t, stopChan, health.metricsStopChan)
+	assert.Equal(t, time.Minute, health.metricsInterval)
+
+	store.Spec.Metrics.Enabled = false
+	r.reconcileMetrics(store, objContext, name)
+	assert.Nil(t, health.metricsStopChan)
+}
+
+func TestOperatorMetricsService(t *testing.T) {
+	svc := operatorMetricsService("rook-ceph")
+	assert.Equal(t, "rook-ceph-operator-metrics", svc.Name)
+	assert.Equal(t, "rook-ceph", svc.Namespace)
+	assert.Equal(t, map[string]string{"app": "rook-ceph-operator"}, svc.Spec.Selector)
+	assert.Equal(t, "http-metrics", svc.Spec.Ports[0].Name)
+	assert.Equal(t, int32(8080), svc.Spec.Ports[0].TargetPort.IntVal)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+var (
+	syncSourceRegex       = regexp.MustCompile(`\(([^)]*)\)$`)
+	syncCaughtUpRegex     = regexp.MustCompile(`^(metadata|data) is caught up with`)
+	syncBehindRegex       = regexp.MustCompile(`^(metadata|data) is behind on (\d+) shards?`)
+	syncRecoveringRegex   = regexp.MustCompile(`^(\d+) shards? (is|are) recovering`)
+	syncOldestChangeRegex = regexp.MustCompile(`^oldest incremental change not applied: (.*)$`)
+)
+
+// GetZoneSyncStatus returns the replication status of the zone of the context from `radosgw-admin sync status`
+func GetZoneSyncStatus(objContext *Context) (*cephv1.ObjectZoneSyncStatus, error) {
+	args := append([]string{"sync", "status"}, multisiteArgs(objContext)...)
+	output, err := RunAdminCommandNoMultisite(objContext, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sync status of zone %q. %s", objContext.Zone, output)
+	}
+	status := parseSyncStatus(output)
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	return &status, nil
+}
+
+// parseSyncStatus parses the output of `radosgw-admin sync status`. The metadata section is followed by a section for
+// each source zone of the data:
+//
+//	metadata sync syncing
+//	              full sync: 0/64 shards
+//	              incremental sync: 64/64 shards
+//	              metadata is caught up with master
+//	    data sync source: 6a9ba48e-f8a3-4cfd-a16b-9e8b9e0f8b14 (zone-a)
+//	                      syncing
+//	                      ...
+func parseSyncStatus(output string) cephv1.ObjectZoneSyncStatus {
+	status := cephv1.ObjectZoneSyncStatus{}
+	var current *cephv1.ObjectSyncStatus
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "metadata sync "):
+			current = &status.Metadata
+			current.State = strings.TrimPrefix(line, "metadata sync ")
+		case strings.HasPrefix(line, "data sync source: "):
+			source := strings.TrimPrefix(line, "data sync source: ")
+			if match := syncSourceRegex.FindStringSubmatch(source); match != nil {
+				source = match[1]
+			}
+			status.Data = append(status.Data, cephv1.ObjectDataSyncStatus{Source: source})
+			current = &status.Data[len(status.Data)-1].ObjectSyncStatus
+		case current == nil:
+			// the realm, zone group and zone of the status
+		default:
+			parseSyncStatusLine(current, line)
+		}
+	}
+	return status
+}
+
+func parseSyncStatusLine(status *cephv1.ObjectSyncStatus, line string) {
+	if match := syncBehindRegex.FindStringSubmatch(line); match != nil {
+		status.ShardsBehind, _ = strconv.Atoi(match[2])
+		return
+	}
+	if match := syncRecoveringRegex.FindStringSubmatch(line); match != nil {
+		status.RecoveringShards, _ = strconv.Atoi(match[1])
+		return
+	}
+	if match := syncOldestChangeRegex.FindStringSubmatch(line); match != nil {
+		status.OldestChange = match[1]
+		return
+	}
+	switch {
+	case syncCaughtUpRegex.MatchString(line):
+		status.CaughtUp = true
+	case strings.HasPrefix(line, "failed") || strings.HasPrefix(line, "ERROR"):
+		status.Errors = append(status.Errors, line)
+	case strings.Contains(line, "sync:") || strings.Contains(line, "shards:"):
+		// the details of the full and incremental sync, and the list of shards behind or recovering
+	case status.State == "":
+		// the state of a data sync source is on the line following the source
+		status.State = line
+	}
+}
+
+// GetZoneMasterStatus returns whether the zone of the context is the master zone of its zone group and whether its
+// zone group is the master zone group of the realm
+func GetZoneMasterStatus(objContext *Context) (bool, bool, error) {
+	zoneIsMaster, err := checkZoneIsMaster(objContext)
+	if err != nil {
+		return false, false, errors.Wrapf(err, "failed to check if zone %q is master", objContext.Zone)
+	}
+	zoneGroupIsMaster, err := checkZoneGroupIsMaster(objContext)
+	if err != nil {
+		return false, false, errors.Wrapf(err, "failed to check if zone group %q is master", objContext.ZoneGroup)
+	}
+	return zoneIsMaster, zoneGroupIsMaster, nil
+}
+
+// PromoteZone makes the zone of the context the master zone of its zone group and commits the period. It returns
+// whether the zone was promoted, nothing is done if the zone is already the master zone.
+func PromoteZone(objContext *Context) (bool, error) {
+	zoneIsMaster, err := checkZoneIsMaster(objContext)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if zone %q is master", objContext.Zone)
+	}
+	if zoneIsMaster {
+		return false, nil
+	}
+	archive, err := checkZoneIsArchive(objContext)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if zone %q is an archive zone", objContext.Zone)
+	}
+	if archive {
+		return false, errors.Errorf("archive zone %q cannot be promoted to master", objContext.Zone)
+	}
+	zoneGroupIsMaster, err := checkZoneGroupIsMaster(objContext)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if zone group %q is master", objContext.ZoneGroup)
+	}
+
+	logger.Infof("promoting zone %q to master of zone group %q", objContext.Zone, objContext.ZoneGroup)
+	args := append([]string{"zone", "modify"}, multisiteArgs(objContext)...)
+	args = append(args, "--master", "--default", "--read-only=false")
+	output, err := RunAdminCommandNoMultisite(objContext, args...)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to promote zone %q. %s", objContext.Zone, output)
+	}
+	output, err = RunAdminCommandNoMultisite(objContext, "period", "update", "--commit", fmt.Sprintf("--rgw-realm=%s", objContext.Realm))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to commit the period after promoting zone %q. %s", objContext.Zone, output)
+	}
+
+	if zoneGroupIsMaster {
+		logger.Infof("zone %q is the master zone of the realm %q", objContext.Zone, objContext.Realm)
+	} else {
+		logger.Infof("zone %q is the master zone of zone group %q", objContext.Zone, objContext.ZoneGroup)
+	}
+	return true, nil
+}
+
+func multisiteArgs(objContext *Context) []string {
+	return []string{
+		fmt.Sprintf("--rgw-realm=%s", objContext.Realm),
+		fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup),
+		fmt.Sprintf("--rgw-zone=%s", objContext.Zone),
+	}
+}
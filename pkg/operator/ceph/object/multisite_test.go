@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	secondarySyncStatus = `          realm 237e6250-5f7d-4b85-9359-8cb2b1848507 (realm-a)
+      zonegroup fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf (zonegroup-a)
+           zone b1abbebb-e8ae-4c3b-880e-b009728bad53 (zone-b)
+  metadata sync syncing
+                full sync: 0/64 shards
+                incremental sync: 64/64 shards
+                metadata is caught up with master
+      data sync source: 6cb39d2c-3005-49da-9be3-c1a92a97d28a (zone-a)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is behind on 3 shards
+                        behind shards: [12,45,98]
+                        oldest incremental change not applied: 2020-09-01T10:11:12.123456+0000 [12]
+                        2 shards are recovering
+                        recovering shards: [45,98]
+      data sync source: 0f13bb55-68f7-4b17-9bb5-93e29e1ffb5a (zone-c)
+                        failed to retrieve sync info: (5) Input/output error
+`
+	masterSyncStatus = `          realm 237e6250-5f7d-4b85-9359-8cb2b1848507 (realm-a)
+      zonegroup fd8ff110-d3fd-49b4-b24f-f6cd3dddfedf (zonegroup-a)
+           zone 6cb39d2c-3005-49da-9be3-c1a92a97d28a (zone-a)
+  metadata sync no sync (zone is master)
+      data sync source: b1abbebb-e8ae-4c3b-880e-b009728bad53 (zone-b)
+                        syncing
+                        full sync: 0/128 shards
+                        incremental sync: 128/128 shards
+                        data is caught up with source
+`
+)
+
+func TestParseSyncStatus(t *testing.T) {
+	status := parseSyncStatus(secondarySyncStatus)
+	assert.Equal(t, cephv1.ObjectSyncStatus{State: "syncing", CaughtUp: true}, status.Metadata)
+	assert.Equal(t, []cephv1.ObjectDataSyncStatus{
+		{
+			Source: "zone-a",
+			ObjectSyncStatus: cephv1.ObjectSyncStatus{
+				State:            "syncing",
+				ShardsBehind:     3,
+				RecoveringShards: 2,
+				OldestChange:     "2020-09-01T10:11:12.123456+0000 [12]",
+			},
+		},
+		{
+			Source: "zone-c",
+			ObjectSyncStatus: cephv1.ObjectSyncStatus{
+				Errors: []string{"failed to retrieve sync info: (5) Input/output error"},
+			},
+		},
+	}, status.Data)
+
+	status = parseSyncStatus(masterSyncStatus)
+	assert.Equal(t, cephv1.ObjectSyncStatus{State: "no sync (zone is master)"}, status.Metadata)
+	assert.Equal(t, []cephv1.ObjectDataSyncStatus{
+		{Source: "zone-b", ObjectSyncStatus: cephv1.ObjectSyncStatus{State: "syncing", CaughtUp: true}},
+	}, status.Data)
+
+	status = parseSyncStatus("")
+	assert.Equal(t, cephv1.ObjectZoneSyncStatus{}, status)
+}
+
+func TestPromoteZone(t *testing.T) {
+	masterZoneID := "6cb39d2c-3005-49da-9be3-c1a92a97d28a"
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return `{"master_zone": "` + masterZoneID + `", "is_master": "true"}`, nil
+			case args[0] == "zone" && args[1] == "get":
+				return `{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53"}`, nil
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "zone-b")
+	objContext.Realm = "realm-a"
+	objContext.ZoneGroup = "zonegroup-a"
+	objContext.Zone = "zone-b"
+
+	promoted, err := PromoteZone(objContext)
+	assert.NoError(t, err)
+	assert.True(t, promoted)
+	assert.Len(t, commands, 2)
+	assert.Contains(t, commands[0], "zone modify --rgw-realm=realm-a --rgw-zonegroup=zonegroup-a --rgw-zone=zone-b --master --default --read-only=false")
+	assert.Contains(t, commands[1], "period update --commit --rgw-realm=realm-a")
+
+	// the zone is already the master zone
+	commands = []string{}
+	masterZoneID = "b1abbebb-e8ae-4c3b-880e-b009728bad53"
+	promoted, err = PromoteZone(objContext)
+	assert.NoError(t, err)
+	assert.False(t, promoted)
+	assert.Empty(t, commands)
+
+	zoneIsMaster, zoneGroupIsMaster, err := GetZoneMasterStatus(objContext)
+	assert.NoError(t, err)
+	assert.True(t, zoneIsMaster)
+	assert.True(t, zoneGroupIsMaster)
+}
+
+func TestPromoteArchiveZone(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return `{"master_zone": "6cb39d2c-3005-49da-9be3-c1a92a97d28a", "is_master": "true", "zones": [{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53", "name": "zone-b", "tier_type": "archive"}]}`, nil
+			case args[0] == "zone" && args[1] == "get":
+				return `{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53"}`, nil
+			}
+			assert.Fail(t, "unexpected command", args)
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "zone-b")
+	objContext.Realm = "realm-a"
+	objContext.ZoneGroup = "zonegroup-a"
+	objContext.Zone = "zone-b"
+
+	promoted, err := PromoteZone(objContext)
+	assert.Error(t, err)
+	assert.False(t, promoted)
+
+	// an archive zone can join the multisite, unless it is the master zone
+	masterZoneID := "6cb39d2c-3005-49da-9be3-c1a92a97d28a"
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "zonegroup" && args[1] == "get" {
+			return `{"master_zone": "` + masterZoneID + `", "is_master": "true", "zones": [{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53", "name": "zone-b", "tier_type": "archive"}]}`, nil
+		}
+		return `{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53"}`, nil
+	}
+	err = joinMultisite(objContext, "--endpoints=http://10.0.0.1:80", "http://10.0.0.1:80", "rook-ceph")
+	assert.NoError(t, err)
+	masterZoneID = "b1abbebb-e8ae-4c3b-880e-b009728bad53"
+	err = joinMultisite(objContext, "--endpoints=http://10.0.0.1:80", "http://10.0.0.1:80", "rook-ceph")
+	assert.Error(t, err)
+}
@@ -0,0 +1,477 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification manages the notifications sent by the buckets of an object store to the topics.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-notification-controller"
+	// bucketNameKey is the key of the bucket name in the ConfigMap of an ObjectBucketClaim
+	bucketNameKey = "BUCKET_NAME"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketNotificationKind = reflect.TypeOf(cephv1.CephBucketNotification{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketNotificationKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// supported filter rules of the object keys
+var keyFilterRules = []string{"prefix", "suffix", "regex"}
+
+// ReconcileBucketNotification reconciles a CephBucketNotification object
+type ReconcileBucketNotification struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	context     *clusterd.Context
+	clusterInfo *cephclient.ClusterInfo
+}
+
+// Add creates a new CephBucketNotification Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+
+	return &ReconcileBucketNotification{
+		client:  mgr.GetClient(),
+		scheme:  mgrScheme,
+		context: context,
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketNotification CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketNotification{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Watch the topics, the notifications must follow the ARN of their topic when it is created, recreated or deleted
+	topic := &cephv1.CephBucketTopic{TypeMeta: metav1.TypeMeta{Kind: reflect.TypeOf(cephv1.CephBucketTopic{}).Name(), APIVersion: controllerTypeMeta.APIVersion}}
+	err = c.Watch(&source.Kind{Type: topic}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+			return topicNotificationRequests(mgr.GetClient(), obj.Meta.GetNamespace(), obj.Meta.GetName())
+		}),
+	}, topicPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// topicNotificationRequests returns the requests to reconcile the notifications sent to a topic
+func topicNotificationRequests(c client.Client, namespace, topicName string) []reconcile.Request {
+	notifications := &cephv1.CephBucketNotificationList{}
+	if err := c.List(context.TODO(), notifications, client.InNamespace(namespace)); err != nil {
+		logger.Errorf("failed to list the bucket notifications of topic %q. %v", topicName, err)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, notification := range notifications.Items {
+		if notification.Spec.Topic == topicName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: notification.Name, Namespace: notification.Namespace}})
+		}
+	}
+	return requests
+}
+
+// topicPredicate passes the creation and the deletion of the topics, and the changes of their ARN
+func topicPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldTopic, ok := e.ObjectOld.(*cephv1.CephBucketTopic)
+			if !ok {
+				return false
+			}
+			newTopic, ok := e.ObjectNew.(*cephv1.CephBucketTopic)
+			if !ok {
+				return false
+			}
+			return topicARN(oldTopic) != topicARN(newTopic) || !newTopic.GetDeletionTimestamp().IsZero()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func topicARN(topic *cephv1.CephBucketTopic) string {
+	if topic.Status == nil {
+		return ""
+	}
+	return topic.Status.ARN
+}
+
+// Reconcile reads that state of the cluster for a CephBucketNotification object and makes changes based on the state read
+// and what is in the CephBucketNotification.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketNotification) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketNotification) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketNotification instance
+	notification := &cephv1.CephBucketNotification{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, notification)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketNotification")
+	}
+
+	// The CR was just created, initializing status fields
+	if notification.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, nil)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		if !notification.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, notification)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, notification)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	// DELETE: the CR was deleted
+	if !notification.GetDeletionTimestamp().IsZero() {
+		if err := r.deleteNotification(notification); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to delete bucket notification %q", notification.Name)
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, notification)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the notification settings
+	if err := validateNotification(notification); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, &cephv1.BucketNotificationStatus{Details: err.Error()})
+		return reconcile.Result{}, errors.Wrapf(err, "invalid bucket notification CR %q spec", notification.Name)
+	}
+
+	// The topic must be created before the notifications referencing it
+	topic, err := r.getTopic(notification)
+	if err != nil {
+		logger.Debugf("topic of bucket notification %q not ready, retrying in %q. %v",
+			request.NamespacedName, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, &cephv1.BucketNotificationStatus{Details: err.Error()})
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	bucketName, err := r.getBucketName(notification)
+	if err != nil {
+		logger.Debugf("bucket of bucket notification %q not ready, retrying in %q. %v",
+			request.NamespacedName, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, &cephv1.BucketNotificationStatus{Details: err.Error()})
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// The bucket of the notification changed, remove the notification from the previous bucket
+	if notification.Status != nil && notification.Status.BucketName != "" && notification.Status.BucketName != bucketName {
+		if err := r.deleteNotification(notification); err != nil {
+			updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, &cephv1.BucketNotificationStatus{Details: err.Error()})
+			return opcontroller.ImmediateRetryResult, err
+		}
+	}
+
+	// CREATE/UPDATE NOTIFICATION
+	s3Agent, err := r.bucketOwnerS3Agent(topic.Spec.ObjectStoreName, notification.Namespace, bucketName)
+	if err == nil {
+		err = s3Agent.PutBucketNotification(bucketName, topicConfiguration(notification, topic.Status.ARN))
+	}
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, &cephv1.BucketNotificationStatus{BucketName: bucketName, TopicARN: topic.Status.ARN, Details: err.Error()})
+		return opcontroller.ImmediateRetryResult, err
+	}
+	logger.Infof("notification %q of bucket %q to topic %q configured", notification.Name, bucketName, topic.Status.ARN)
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, &cephv1.BucketNotificationStatus{BucketName: bucketName, TopicARN: topic.Status.ARN})
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// deleteNotification removes the notification from the bucket it was configured on
+func (r *ReconcileBucketNotification) deleteNotification(notification *cephv1.CephBucketNotification) error {
+	if notification.Status == nil || notification.Status.BucketName == "" {
+		return nil
+	}
+	bucketName := notification.Status.BucketName
+
+	topic, err := r.getTopic(notification)
+	if err != nil {
+		// Without the topic the object store of the bucket is unknown, the notification is removed with the topic
+		logger.Warningf("not removing notification %q from bucket %q. %v", notification.Name, bucketName, err)
+		return nil
+	}
+
+	s3Agent, err := r.bucketOwnerS3Agent(topic.Spec.ObjectStoreName, notification.Namespace, bucketName)
+	if err != nil {
+		// The bucket or the object store is gone, so is the notification
+		logger.Warningf("not removing notification %q from bucket %q. %v", notification.Name, bucketName, err)
+		return nil
+	}
+
+	logger.Infof("removing notification %q from bucket %q", notification.Name, bucketName)
+	return s3Agent.DeleteBucketNotification(bucketName, notification.Name)
+}
+
+// getTopic returns the topic of the notification once it is created
+func (r *ReconcileBucketNotification) getTopic(notification *cephv1.CephBucketNotification) (*cephv1.CephBucketTopic, error) {
+	topic := &cephv1.CephBucketTopic{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: notification.Spec.Topic, Namespace: notification.Namespace}, topic)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get topic %q", notification.Spec.Topic)
+	}
+	if topic.Status == nil || topic.Status.ARN == "" {
+		return nil, errors.Errorf("topic %q is not created yet", topic.Name)
+	}
+	return topic, nil
+}
+
+// getBucketName returns the name of the bucket of the notification, the bucket of an ObjectBucketClaim is read from
+// the ConfigMap created for the claim
+func (r *ReconcileBucketNotification) getBucketName(notification *cephv1.CephBucketNotification) (string, error) {
+	claim := notification.Spec.ObjectBucketClaim
+	if claim == nil {
+		return notification.Spec.BucketName, nil
+	}
+
+	namespace := claim.Namespace
+	if namespace == "" {
+		namespace = notification.Namespace
+	}
+	cm, err := r.context.Clientset.CoreV1().ConfigMaps(namespace).Get(claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get configmap of object bucket claim %q", claim.Name)
+	}
+	bucketName, ok := cm.Data[bucketNameKey]
+	if !ok || bucketName == "" {
+		return "", errors.Errorf("object bucket claim %q has no bucket yet", claim.Name)
+	}
+	return bucketName, nil
+}
+
+// bucketOwnerS3Agent returns an S3 agent authenticated with the owner of the bucket, only the owner can configure
+// the notifications of a bucket
+func (r *ReconcileBucketNotification) bucketOwnerS3Agent(storeName, namespace, bucketName string) (*object.S3Agent, error) {
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: storeName, Namespace: namespace}, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object store %q", storeName)
+	}
+	if store.Status == nil || store.Status.Info["endpoint"] == "" {
+		return nil, errors.Errorf("object store %q has no endpoint yet", store.Name)
+	}
+
+	objContext, err := object.NewMultisiteContext(r.context, r.clusterInfo, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to set multisite context of object store %q", store.Name)
+	}
+
+	bucket, _, err := object.GetBucket(objContext, bucketName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get bucket %q", bucketName)
+	}
+
+	return object.NewUserS3Agent(objContext, bucket.Owner, store.Status.Info["endpoint"])
+}
+
+// topicConfiguration returns the s3 configuration of the notification, it is identified by the name of the CR
+func topicConfiguration(notification *cephv1.CephBucketNotification, topicARN string) *s3.TopicConfiguration {
+	config := &s3.TopicConfiguration{
+		Id:       aws.String(notification.Name),
+		TopicArn: aws.String(topicARN),
+		Events:   aws.StringSlice(notification.Spec.Events),
+	}
+
+	if notification.Spec.Filter != nil && len(notification.Spec.Filter.KeyFilters) > 0 {
+		rules := []*s3.FilterRule{}
+		for _, f := range notification.Spec.Filter.KeyFilters {
+			rules = append(rules, &s3.FilterRule{Name: aws.String(f.Name), Value: aws.String(f.Value)})
+		}
+		config.Filter = &s3.NotificationConfigurationFilter{Key: &s3.KeyFilter{FilterRules: rules}}
+	}
+
+	return config
+}
+
+// validateNotification validates the notification arguments
+func validateNotification(notification *cephv1.CephBucketNotification) error {
+	if notification.Spec.Topic == "" {
+		return errors.New("missing topic")
+	}
+	if (notification.Spec.ObjectBucketClaim == nil) == (notification.Spec.BucketName == "") {
+		return errors.New("exactly one of objectBucketClaim or bucketName must be specified")
+	}
+	if notification.Spec.ObjectBucketClaim != nil && notification.Spec.ObjectBucketClaim.Name == "" {
+		return errors.New("missing object bucket claim name")
+	}
+	for _, event := range notification.Spec.Events {
+		if !strings.HasPrefix(event, "s3:") {
+			return errors.Errorf("invalid event %q, expected an s3 event like \"s3:ObjectCreated:*\"", event)
+		}
+	}
+	if notification.Spec.Filter != nil {
+		for _, f := range notification.Spec.Filter.KeyFilters {
+			if !isKeyFilterRule(f.Name) {
+				return errors.Errorf("invalid key filter %q, expected one of %v", f.Name, keyFilterRules)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isKeyFilterRule(name string) bool {
+	for _, rule := range keyFilterRules {
+		if name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStatus updates a bucket notification with a given status, the bucket and topic are kept if the given status
+// has none
+func updateStatus(client client.Client, name types.NamespacedName, status string, notificationStatus *cephv1.BucketNotificationStatus) {
+	notification := &cephv1.CephBucketNotification{}
+	if err := client.Get(context.TODO(), name, notification); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketNotification resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve bucket notification %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if notification.Status == nil {
+		notification.Status = &cephv1.BucketNotificationStatus{}
+	}
+
+	notification.Status.Phase = status
+	if notificationStatus != nil {
+		if notificationStatus.BucketName != "" {
+			notification.Status.BucketName = notificationStatus.BucketName
+		}
+		if notificationStatus.TopicARN != "" {
+			notification.Status.TopicARN = notificationStatus.TopicARN
+		}
+		notification.Status.Details = notificationStatus.Details
+	}
+	if err := opcontroller.UpdateStatus(client, notification); err != nil {
+		logger.Errorf("failed to set bucket notification %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("bucket notification %q status updated to %q", name, status)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateNotification(t *testing.T) {
+	notification := &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: "rook-ceph"},
+		Spec: cephv1.BucketNotificationSpec{
+			Topic:      "my-topic",
+			BucketName: "my-bucket",
+			Events:     []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:Delete"},
+			Filter: &cephv1.NotificationFilterSpec{
+				KeyFilters: []cephv1.NotificationKeyFilterRule{{Name: "prefix", Value: "images/"}},
+			},
+		},
+	}
+	assert.NoError(t, validateNotification(notification))
+
+	// both a bucket and a claim
+	notification.Spec.ObjectBucketClaim = &cephv1.ObjectBucketClaimReference{Name: "my-claim"}
+	assert.Error(t, validateNotification(notification))
+	notification.Spec.BucketName = ""
+	assert.NoError(t, validateNotification(notification))
+
+	// neither a bucket nor a claim
+	notification.Spec.ObjectBucketClaim = nil
+	assert.Error(t, validateNotification(notification))
+	notification.Spec.BucketName = "my-bucket"
+
+	notification.Spec.Events = []string{"ObjectCreated:*"}
+	assert.Error(t, validateNotification(notification))
+	notification.Spec.Events = nil
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Filter.KeyFilters[0].Name = "contains"
+	assert.Error(t, validateNotification(notification))
+	notification.Spec.Filter.KeyFilters[0].Name = "regex"
+	assert.NoError(t, validateNotification(notification))
+
+	notification.Spec.Topic = ""
+	assert.Error(t, validateNotification(notification))
+}
+
+func TestTopicConfiguration(t *testing.T) {
+	notification := &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: "rook-ceph"},
+		Spec: cephv1.BucketNotificationSpec{
+			Topic:      "my-topic",
+			BucketName: "my-bucket",
+			Events:     []string{"s3:ObjectCreated:*"},
+		},
+	}
+
+	config := topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Equal(t, "my-notification", aws.StringValue(config.Id))
+	assert.Equal(t, "arn:aws:sns:my-store::my-topic", aws.StringValue(config.TopicArn))
+	assert.Equal(t, []string{"s3:ObjectCreated:*"}, aws.StringValueSlice(config.Events))
+	assert.Nil(t, config.Filter)
+
+	notification.Spec.Filter = &cephv1.NotificationFilterSpec{
+		KeyFilters: []cephv1.NotificationKeyFilterRule{{Name: "prefix", Value: "images/"}, {Name: "suffix", Value: ".png"}},
+	}
+	config = topicConfiguration(notification, "arn:aws:sns:my-store::my-topic")
+	assert.Len(t, config.Filter.Key.FilterRules, 2)
+	assert.Equal(t, "suffix", aws.StringValue(config.Filter.Key.FilterRules[1].Name))
+	assert.Equal(t, ".png", aws.StringValue(config.Filter.Key.FilterRules[1].Value))
+}
+
+func TestGetBucketName(t *testing.T) {
+	clientset := test.New(t, 1)
+	r := &ReconcileBucketNotification{context: &clusterd.Context{Clientset: clientset}}
+	notification := &cephv1.CephBucketNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-notification", Namespace: "rook-ceph"},
+		Spec: cephv1.BucketNotificationSpec{
+			Topic:      "my-topic",
+			BucketName: "my-bucket",
+		},
+	}
+
+	bucketName, err := r.getBucketName(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-bucket", bucketName)
+
+	// the claim is not provisioned yet
+	notification.Spec.BucketName = ""
+	notification.Spec.ObjectBucketClaim = &cephv1.ObjectBucketClaimReference{Name: "my-claim", Namespace: "apps"}
+	_, err = r.getBucketName(notification)
+	assert.Error(t, err)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-claim", Namespace: "apps"},
+		Data:       map[string]string{"BUCKET_NAME": "my-claim-4a9c1e"},
+	}
+	_, err = clientset.CoreV1().ConfigMaps("apps").Create(cm)
+	assert.NoError(t, err)
+	bucketName, err = r.getBucketName(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-claim-4a9c1e", bucketName)
+}
+
+func TestTopicNotificationRequests(t *testing.T) {
+	notification := func(name, namespace, topic string) *cephv1.CephBucketNotification {
+		return &cephv1.CephBucketNotification{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.BucketNotificationSpec{Topic: topic, BucketName: "my-bucket"},
+		}
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBucketNotification{}, &cephv1.CephBucketNotificationList{})
+	cl := fake.NewFakeClientWithScheme(s,
+		notification("a", "rook-ceph", "my-topic"),
+		notification("b", "rook-ceph", "other-topic"),
+		notification("c", "other-ns", "my-topic"),
+		notification("d", "rook-ceph", "my-topic"))
+
+	requests := topicNotificationRequests(cl, "rook-ceph", "my-topic")
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "a", Namespace: "rook-ceph"}},
+		{NamespacedName: types.NamespacedName{Name: "d", Namespace: "rook-ceph"}},
+	}, requests)
+	assert.Empty(t, topicNotificationRequests(cl, "rook-ceph", "unknown"))
+}
+
+func TestTopicPredicate(t *testing.T) {
+	oldTopic := &cephv1.CephBucketTopic{ObjectMeta: metav1.ObjectMeta{Name: "my-topic"}, Status: &cephv1.BucketTopicStatus{ARN: "arn:aws:sns:my-store::my-topic"}}
+	newTopic := oldTopic.DeepCopy()
+	newTopic.Status.Phase = "Ready"
+
+	p := topicPredicate()
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: oldTopic, ObjectNew: newTopic}))
+	newTopic.Status.ARN = "arn:aws:sns:my-store:tenant:my-topic"
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldTopic, ObjectNew: newTopic}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: oldTopic}))
+}
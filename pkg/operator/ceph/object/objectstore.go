@@ -60,6 +60,8 @@ var (
 
 	// An user with system privileges for dashboard service
 	DashboardUser = "dashboard-admin"
+	// ArchiveTierType is the tier type of an archive zone
+	ArchiveTierType = "archive"
 )
 
 type idType struct {
@@ -73,8 +75,10 @@ type zoneGroupType struct {
 }
 
 type zoneType struct {
+	ID        string   `json:"id"`
 	Name      string   `json:"name"`
 	Endpoints []string `json:"endpoints"`
+	TierType  string   `json:"tier_type"`
 }
 
 type realmType struct {
@@ -154,6 +158,12 @@ func deleteSingleSiteRealmAndPools(objContext *Context, spec cephv1.ObjectStoreS
 	}
 	logger.Infof("Found stores %v when deleting store %s", stores, objContext.Name)
 
+	// the zone is deleted with the realm
+	removedPools, err := removedPlacementPools(objContext, spec.PlacementTargets)
+	if err != nil {
+		logger.Warningf("failed to find the pools of the placement targets removed from object store %q. %v", objContext.Name, err)
+	}
+
 	err = deleteRealm(objContext)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete realm")
@@ -164,11 +174,20 @@ func deleteSingleSiteRealmAndPools(objContext *Context, spec cephv1.ObjectStoreS
 		lastStore = true
 	}
 
-	if !spec.PreservePoolsOnDelete {
+	// the RADOS namespaces of a store using shared pools are deleted even if the pools are preserved
+	if !spec.PreservePoolsOnDelete || spec.IsSharedPools() {
 		err = deletePools(objContext, spec, lastStore)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete object store pools")
 		}
+		for _, pool := range removedPools {
+			if err := ceph.DeletePool(objContext.Context, objContext.clusterInfo, pool); err != nil {
+				logger.Warningf("failed to delete pool %q. %v", pool, err)
+			}
+			if err := ceph.DeleteErasureCodeProfile(objContext.Context, objContext.clusterInfo, ceph.GetErasureCodeProfileForPool(pool)); err != nil {
+				logger.Debugf("failed to delete erasure code profile of pool %q. %v", pool, err)
+			}
+		}
 	} else {
 		logger.Infof("PreservePoolsOnDelete is set in object store %s. Pools not deleted", objContext.Name)
 	}
@@ -235,6 +254,26 @@ func checkZoneIsMaster(objContext *Context) (bool, error) {
 	return false, nil
 }
 
+func checkZoneIsArchive(objContext *Context) (bool, error) {
+	realmArg := fmt.Sprintf("--rgw-realm=%s", objContext.Realm)
+	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup)
+
+	zoneGroupJson, err := RunAdminCommandNoMultisite(objContext, "zonegroup", "get", realmArg, zoneGroupArg)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rgw zone group")
+	}
+	zoneGroupOutput, err := DecodeZoneGroupConfig(zoneGroupJson)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse zonegroup get json")
+	}
+	for _, zone := range zoneGroupOutput.Zones {
+		if zone.Name == objContext.Zone {
+			return zone.TierType == ArchiveTierType, nil
+		}
+	}
+	return false, nil
+}
+
 func checkZoneGroupIsMaster(objContext *Context) (bool, error) {
 	logger.Debugf("checking if zone group %v is the master zone group", objContext.ZoneGroup)
 	realmArg := fmt.Sprintf("--rgw-realm=%s", objContext.Realm)
@@ -403,8 +442,17 @@ func joinMultisite(objContext *Context, endpointArg, zoneEndpoints, namespace st
 	zoneGroupIsMaster := false
 
 	if zoneIsMaster {
+		// an archive zone only receives the objects of the other zones
+		archive, err := checkZoneIsArchive(objContext)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if zone %q is an archive zone", objContext.Zone)
+		}
+		if archive {
+			return errors.Errorf("archive zone %q cannot be the master zone of zone group %q", objContext.Zone, objContext.ZoneGroup)
+		}
+
 		// endpoints that are part of a master zone are supposed to be the endpoints for a zone group
-		_, err := RunAdminCommandNoMultisite(objContext, "zonegroup", "modify", realmArg, zoneGroupArg, endpointArg)
+		_, err = RunAdminCommandNoMultisite(objContext, "zonegroup", "modify", realmArg, zoneGroupArg, endpointArg)
 		if err != nil {
 			return errors.Wrapf(err, "failed to add object store %q in rgw zone group %q", objContext.Name, objContext.ZoneGroup)
 		}
@@ -569,6 +617,11 @@ func getObjectStores(context *Context) ([]string, error) {
 }
 
 func deletePools(context *Context, spec cephv1.ObjectStoreSpec, lastStore bool) error {
+	if spec.IsSharedPools() {
+		// the shared pools are owned by all the stores using them
+		return deleteSharedPools(context, spec)
+	}
+
 	if emptyPool(spec.DataPool) && emptyPool(spec.MetadataPool) {
 		logger.Info("skipping removal of pools since not specified in the object store")
 		return nil
@@ -586,6 +639,17 @@ func deletePools(context *Context, spec cephv1.ObjectStoreSpec, lastStore bool)
 		}
 	}
 
+	// delete the pools of the placement targets, their erasure coded data pools have their own profile
+	for _, pool := range placementPools(spec.PlacementTargets) {
+		name := poolName(context.Name, pool)
+		if err := ceph.DeletePool(context.Context, context.clusterInfo, name); err != nil {
+			logger.Warningf("failed to delete pool %q. %v", name, err)
+		}
+		if err := ceph.DeleteErasureCodeProfile(context.Context, context.clusterInfo, client.GetErasureCodeProfileForPool(name)); err != nil {
+			logger.Debugf("failed to delete erasure code profile of pool %q. %v", name, err)
+		}
+	}
+
 	// Delete erasure code profile if any
 	erasureCodes, err := ceph.ListErasureCodeProfiles(context.Context, context.clusterInfo)
 	if err != nil {
@@ -605,7 +669,8 @@ func deletePools(context *Context, spec cephv1.ObjectStoreSpec, lastStore bool)
 	return nil
 }
 
-func CreatePools(context *Context, metadataPool, dataPool cephv1.PoolSpec) error {
+// CreatePools creates the metadata and data pools of the object store and the pools of its placement targets
+func CreatePools(context *Context, metadataPool, dataPool cephv1.PoolSpec, placementTargets []cephv1.ObjectPlacementTargetSpec) error {
 	if emptyPool(dataPool) && emptyPool(metadataPool) {
 		logger.Info("no pools specified for the CR, checking for their existence...")
 		pools := append(metadataPools, dataPoolName)
@@ -648,44 +713,54 @@ func CreatePools(context *Context, metadataPool, dataPool cephv1.PoolSpec) error
 		return errors.Wrap(err, "failed to create data pool")
 	}
 
+	if err := createPlacementPools(context, placementTargets, metadataPoolPGs); err != nil {
+		return errors.Wrap(err, "failed to create placement target pools")
+	}
+
 	return nil
 }
 
 func createSimilarPools(context *Context, pools []string, poolSpec cephv1.PoolSpec, pgCount, ecProfileName string) error {
 	for _, pool := range pools {
-		// create the pool if it doesn't exist yet
-		name := poolName(context.Name, pool)
-		if poolDetails, err := ceph.GetPoolDetails(context.Context, context.clusterInfo, name); err != nil {
-			// If the ceph config has an EC profile, an EC pool must be created. Otherwise, it's necessary
-			// to create a replicated pool.
-			var err error
-			if poolSpec.IsErasureCoded() {
-				// An EC pool backing an object store does not need to enable EC overwrites, so the pool is
-				// created with that property disabled to avoid unnecessary performance impact.
-				err = ceph.CreateECPoolForApp(context.Context, context.clusterInfo, name, ecProfileName, poolSpec, pgCount, AppName, false /* enableECOverwrite */)
-			} else {
-				err = ceph.CreateReplicatedPoolForApp(context.Context, context.clusterInfo, name, poolSpec, pgCount, AppName)
-			}
-			if err != nil {
-				return errors.Wrapf(err, "failed to create pool %s for object store %s.", name, context.Name)
-			}
+		if err := createPool(context, poolName(context.Name, pool), poolSpec, pgCount, ecProfileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createPool creates the pool if it doesn't exist yet or updates its replication
+func createPool(context *Context, name string, poolSpec cephv1.PoolSpec, pgCount, ecProfileName string) error {
+	if poolDetails, err := ceph.GetPoolDetails(context.Context, context.clusterInfo, name); err != nil {
+		// If the ceph config has an EC profile, an EC pool must be created. Otherwise, it's necessary
+		// to create a replicated pool.
+		var err error
+		if poolSpec.IsErasureCoded() {
+			// An EC pool backing an object store does not need to enable EC overwrites, so the pool is
+			// created with that property disabled to avoid unnecessary performance impact.
+			err = ceph.CreateECPoolForApp(context.Context, context.clusterInfo, name, ecProfileName, poolSpec, pgCount, AppName, false /* enableECOverwrite */)
 		} else {
-			// pools already exist
-			if !poolSpec.IsErasureCoded() {
-				// detect if the replication is different from the pool details
-				if poolDetails.Size != poolSpec.Replicated.Size {
-					logger.Infof("pool size is changed from %d to %d", poolDetails.Size, poolSpec.Replicated.Size)
-					if err := ceph.SetPoolReplicatedSizeProperty(context.Context, context.clusterInfo, poolDetails.Name, strconv.FormatUint(uint64(poolSpec.Replicated.Size), 10)); err != nil {
-						return errors.Wrapf(err, "failed to set size property to replicated pool %q to %d", poolDetails.Name, poolSpec.Replicated.Size)
-					}
+			err = ceph.CreateReplicatedPoolForApp(context.Context, context.clusterInfo, name, poolSpec, pgCount, AppName)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to create pool %s for object store %s.", name, context.Name)
+		}
+	} else {
+		// pools already exist
+		if !poolSpec.IsErasureCoded() {
+			// detect if the replication is different from the pool details
+			if poolDetails.Size != poolSpec.Replicated.Size {
+				logger.Infof("pool size is changed from %d to %d", poolDetails.Size, poolSpec.Replicated.Size)
+				if err := ceph.SetPoolReplicatedSizeProperty(context.Context, context.clusterInfo, poolDetails.Name, strconv.FormatUint(uint64(poolSpec.Replicated.Size), 10)); err != nil {
+					return errors.Wrapf(err, "failed to set size property to replicated pool %q to %d", poolDetails.Name, poolSpec.Replicated.Size)
 				}
 			}
 		}
-		// Set the pg_num_min if not the default so the autoscaler won't immediately increase the pg count
-		if pgCount != ceph.DefaultPGCount {
-			if err := ceph.SetPoolProperty(context.Context, context.clusterInfo, name, "pg_num_min", pgCount); err != nil {
-				return errors.Wrapf(err, "failed to set pg_num_min on pool %q to %q", name, pgCount)
-			}
+	}
+	// Set the pg_num_min if not the default so the autoscaler won't immediately increase the pg count
+	if pgCount != ceph.DefaultPGCount {
+		if err := ceph.SetPoolProperty(context.Context, context.clusterInfo, name, "pg_num_min", pgCount); err != nil {
+			return errors.Wrapf(err, "failed to set pg_num_min on pool %q to %q", name, pgCount)
 		}
 	}
 	return nil
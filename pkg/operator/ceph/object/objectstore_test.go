@@ -151,6 +151,9 @@ func deleteStore(t *testing.T, name string, existingStores string, expectedDelet
 			return "", nil
 		}
 		if args[0] == "zone" {
+			if args[1] == "get" {
+				return `{"name":"myobj","placement_pools":[]}`, nil
+			}
 			assert.Equal(t, "delete", args[1])
 			zoneDeleted = true
 			return "", nil
@@ -0,0 +1,407 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	ceph "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	// DefaultPlacementTarget is the placement target of the buckets when none is requested
+	DefaultPlacementTarget = "default-placement"
+	standardStorageClass   = "STANDARD"
+	// the interval of the reconciles of an object store while removed placement targets still have objects
+	placementRemovalRequeueInterval = 5 * time.Minute
+)
+
+// the names are part of the pool names, a dot would make them ambiguous
+var placementNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+type zonePlacementConfig struct {
+	PlacementPools []struct {
+		Key string `json:"key"`
+		Val struct {
+			IndexPool      string `json:"index_pool"`
+			DataExtraPool  string `json:"data_extra_pool"`
+			StorageClasses map[string]struct {
+				DataPool string `json:"data_pool"`
+			} `json:"storage_classes"`
+		} `json:"val"`
+	} `json:"placement_pools"`
+}
+
+type zoneGroupPlacementConfig struct {
+	Zones []struct {
+		Name string `json:"name"`
+	} `json:"zones"`
+	PlacementTargets []struct {
+		Name           string   `json:"name"`
+		StorageClasses []string `json:"storage_classes"`
+	} `json:"placement_targets"`
+}
+
+// placementIndexPool returns the name of the bucket index pool of a placement target without the store prefix
+func placementIndexPool(target string) string {
+	return fmt.Sprintf("rgw.%s.index", target)
+}
+
+// placementDataExtraPool returns the name of the non-ec pool of a placement target without the store prefix
+func placementDataExtraPool(target string) string {
+	return fmt.Sprintf("rgw.%s.non-ec", target)
+}
+
+// placementDataPool returns the name of the data pool of a storage class of a placement target without the
+// store prefix
+func placementDataPool(target, storageClass string) string {
+	if storageClass == standardStorageClass {
+		return fmt.Sprintf("rgw.%s.data", target)
+	}
+	return fmt.Sprintf("rgw.%s.%s.data", target, strings.ToLower(storageClass))
+}
+
+// placementPools returns the names of the pools of the placement targets without the store prefix
+func placementPools(targets []cephv1.ObjectPlacementTargetSpec) []string {
+	pools := []string{}
+	for _, target := range targets {
+		if target.Name != DefaultPlacementTarget {
+			pools = append(pools, placementIndexPool(target.Name), placementDataExtraPool(target.Name), placementDataPool(target.Name, standardStorageClass))
+		}
+		for _, storageClass := range target.StorageClasses {
+			pools = append(pools, placementDataPool(target.Name, storageClass.Name))
+		}
+	}
+	return pools
+}
+
+// validatePlacementTargets validates the names and the pools of the placement targets of an object store
+func validatePlacementTargets(targets []cephv1.ObjectPlacementTargetSpec) error {
+	names := map[string]bool{}
+	for _, target := range targets {
+		// the pools of a "buckets" placement target would be the pools of the default placement target
+		if !placementNameRegex.MatchString(target.Name) || target.Name == "buckets" {
+			return errors.Errorf("invalid placement target name %q", target.Name)
+		}
+		if names[target.Name] {
+			return errors.Errorf("duplicate placement target %q", target.Name)
+		}
+		names[target.Name] = true
+
+		if target.Name == DefaultPlacementTarget {
+			if !emptyPool(target.MetadataPool) || !emptyPool(target.DataPool) {
+				return errors.Errorf("the pools of the %q placement target are the metadata and data pools of the object store", DefaultPlacementTarget)
+			}
+		} else if emptyPool(target.MetadataPool) || emptyPool(target.DataPool) {
+			return errors.Errorf("placement target %q requires a metadata pool and a data pool", target.Name)
+		}
+		if target.MetadataPool.IsErasureCoded() {
+			return errors.Errorf("the metadata pool of placement target %q cannot be erasure coded", target.Name)
+		}
+
+		classes := map[string]bool{}
+		for _, storageClass := range target.StorageClasses {
+			if !placementNameRegex.MatchString(storageClass.Name) {
+				return errors.Errorf("invalid storage class name %q of placement target %q", storageClass.Name, target.Name)
+			}
+			if storageClass.Name == standardStorageClass {
+				return errors.Errorf("the %s storage class of placement target %q is its data pool", standardStorageClass, target.Name)
+			}
+			if classes[strings.ToLower(storageClass.Name)] {
+				return errors.Errorf("duplicate storage class %q of placement target %q", storageClass.Name, target.Name)
+			}
+			classes[strings.ToLower(storageClass.Name)] = true
+			if emptyPool(storageClass.DataPool) {
+				return errors.Errorf("storage class %q of placement target %q requires a data pool", storageClass.Name, target.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// createPlacementPools creates the pools of the placement targets and their storage classes
+func createPlacementPools(context *Context, targets []cephv1.ObjectPlacementTargetSpec, metadataPoolPGs string) error {
+	for _, target := range targets {
+		if target.Name != DefaultPlacementTarget {
+			pools := []string{placementIndexPool(target.Name), placementDataExtraPool(target.Name)}
+			if err := createSimilarPools(context, pools, target.MetadataPool, metadataPoolPGs, ""); err != nil {
+				return errors.Wrapf(err, "failed to create metadata pools of placement target %q", target.Name)
+			}
+			if err := createDataPool(context, placementDataPool(target.Name, standardStorageClass), target.DataPool); err != nil {
+				return errors.Wrapf(err, "failed to create data pool of placement target %q", target.Name)
+			}
+		}
+		for _, storageClass := range target.StorageClasses {
+			if err := createDataPool(context, placementDataPool(target.Name, storageClass.Name), storageClass.DataPool); err != nil {
+				return errors.Wrapf(err, "failed to create data pool of storage class %q of placement target %q", storageClass.Name, target.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// createDataPool creates a data pool with its own erasure code profile if it is erasure coded
+func createDataPool(context *Context, pool string, poolSpec cephv1.PoolSpec) error {
+	ecProfileName := ""
+	if poolSpec.IsErasureCoded() {
+		ecProfileName = ceph.GetErasureCodeProfileForPool(poolName(context.Name, pool))
+		if err := ceph.CreateErasureCodeProfile(context.Context, context.clusterInfo, ecProfileName, poolSpec); err != nil {
+			return errors.Wrap(err, "failed to create erasure code profile")
+		}
+	}
+	return createSimilarPools(context, []string{pool}, poolSpec, ceph.DefaultPGCount, ecProfileName)
+}
+
+// removedPlacement is a placement target, or one of its storage classes, created for the object store and removed
+// from its spec
+type removedPlacement struct {
+	target       string
+	storageClass string
+	pools        []string
+}
+
+// removedPlacements returns the placement targets and storage classes of the zone whose pools were created for the
+// object store and that are no longer in its spec. The targets and storage classes configured by other means are
+// not returned.
+func removedPlacements(context *Context, targets []cephv1.ObjectPlacementTargetSpec, zone zonePlacementConfig) []removedPlacement {
+	desired := map[string]map[string]bool{}
+	for _, target := range targets {
+		desired[target.Name] = map[string]bool{}
+		for _, storageClass := range target.StorageClasses {
+			desired[target.Name][storageClass.Name] = true
+		}
+	}
+
+	removed := []removedPlacement{}
+	for _, placement := range zone.PlacementPools {
+		classes, inSpec := desired[placement.Key]
+		if !inSpec && placement.Key != DefaultPlacementTarget &&
+			placement.Val.IndexPool == poolName(context.Name, placementIndexPool(placement.Key)) {
+			pools := []string{placement.Val.IndexPool, placement.Val.DataExtraPool}
+			for storageClass, classPools := range placement.Val.StorageClasses {
+				if classPools.DataPool == poolName(context.Name, placementDataPool(placement.Key, storageClass)) {
+					pools = append(pools, classPools.DataPool)
+				}
+			}
+			sort.Strings(pools)
+			removed = append(removed, removedPlacement{target: placement.Key, pools: pools})
+			continue
+		}
+
+		storageClasses := []string{}
+		for storageClass, classPools := range placement.Val.StorageClasses {
+			if storageClass != standardStorageClass && !classes[storageClass] &&
+				classPools.DataPool == poolName(context.Name, placementDataPool(placement.Key, storageClass)) {
+				storageClasses = append(storageClasses, storageClass)
+			}
+		}
+		sort.Strings(storageClasses)
+		for _, storageClass := range storageClasses {
+			dataPool := placement.Val.StorageClasses[storageClass].DataPool
+			removed = append(removed, removedPlacement{target: placement.Key, storageClass: storageClass, pools: []string{dataPool}})
+		}
+	}
+	return removed
+}
+
+func getZonePlacementConfig(context *Context) (zonePlacementConfig, error) {
+	var zone zonePlacementConfig
+	output, err := runAdminCommand(context, "zone", "get")
+	if err != nil {
+		return zone, errors.Wrapf(err, "failed to get zone %q", context.Zone)
+	}
+	if err := json.Unmarshal([]byte(output), &zone); err != nil {
+		return zone, errors.Wrapf(err, "failed to parse zone %q", context.Zone)
+	}
+	return zone, nil
+}
+
+// removedPlacementPools returns the pools of the placement targets and storage classes removed from the spec that were
+// kept because they still had objects
+func removedPlacementPools(context *Context, targets []cephv1.ObjectPlacementTargetSpec) ([]string, error) {
+	zone, err := getZonePlacementConfig(context)
+	if err != nil {
+		return nil, err
+	}
+	pools := []string{}
+	for _, placement := range removedPlacements(context, targets, zone) {
+		pools = append(pools, placement.pools...)
+	}
+	return pools, nil
+}
+
+// nonEmptyPool returns a pool with objects and its number of objects, the pools that don't exist are empty
+func nonEmptyPool(poolStats *ceph.CephStoragePoolStats, pools []string) (string, int64) {
+	for _, pool := range pools {
+		for _, p := range poolStats.Pools {
+			if p.Name == pool && p.Stats.Objects > 0 {
+				return pool, int64(p.Stats.Objects)
+			}
+		}
+	}
+	return "", 0
+}
+
+// configurePlacementTargets adds the placement targets and their storage classes to the zonegroup and their pools to
+// the zone. The placement targets and storage classes created for the object store and removed from its spec are
+// removed once their pools are empty, their pools are then deleted unless the pools are preserved. The zonegroup is
+// only changed for the removals if the zone is its only zone, the other zones may still use them. The period is only
+// committed if the configuration changed. Returns whether removed placement targets or storage classes are kept until
+// their pools are empty.
+func configurePlacementTargets(context *Context, targets []cephv1.ObjectPlacementTargetSpec, preservePools bool) (bool, error) {
+	output, err := runAdminCommand(context, "zonegroup", "get")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get zonegroup %q", context.ZoneGroup)
+	}
+	var zoneGroup zoneGroupPlacementConfig
+	if err := json.Unmarshal([]byte(output), &zoneGroup); err != nil {
+		return false, errors.Wrapf(err, "failed to parse zonegroup %q", context.ZoneGroup)
+	}
+	zone, err := getZonePlacementConfig(context)
+	if err != nil {
+		return false, err
+	}
+
+	// the storage classes of the placement targets in the zonegroup
+	zoneGroupClasses := map[string]map[string]bool{}
+	for _, target := range zoneGroup.PlacementTargets {
+		zoneGroupClasses[target.Name] = map[string]bool{}
+		for _, storageClass := range target.StorageClasses {
+			zoneGroupClasses[target.Name][storageClass] = true
+		}
+	}
+	// the pools of the placement targets in the zone, the data pools are keyed by storage class
+	zonePools := map[string]map[string]string{}
+	for _, placement := range zone.PlacementPools {
+		pools := map[string]string{"index": placement.Val.IndexPool, "non-ec": placement.Val.DataExtraPool}
+		for storageClass, classPools := range placement.Val.StorageClasses {
+			pools[storageClass] = classPools.DataPool
+		}
+		zonePools[placement.Key] = pools
+	}
+
+	changed := false
+	run := func(args ...string) error {
+		if output, err := runAdminCommand(context, args...); err != nil {
+			return errors.Wrapf(err, "failed to run %q. %s", strings.Join(args[:3], " "), output)
+		}
+		changed = true
+		return nil
+	}
+	for _, target := range targets {
+		placementArg := fmt.Sprintf("--placement-id=%s", target.Name)
+		if _, ok := zoneGroupClasses[target.Name]; !ok {
+			if err := run("zonegroup", "placement", "add", placementArg); err != nil {
+				return false, err
+			}
+		}
+		if target.Name != DefaultPlacementTarget {
+			pools := zonePools[target.Name]
+			indexPool := poolName(context.Name, placementIndexPool(target.Name))
+			dataExtraPool := poolName(context.Name, placementDataExtraPool(target.Name))
+			dataPool := poolName(context.Name, placementDataPool(target.Name, standardStorageClass))
+			if pools["index"] != indexPool || pools["non-ec"] != dataExtraPool || pools[standardStorageClass] != dataPool {
+				if err := run("zone", "placement", "add", placementArg,
+					fmt.Sprintf("--index-pool=%s", indexPool),
+					fmt.Sprintf("--data-extra-pool=%s", dataExtraPool),
+					fmt.Sprintf("--data-pool=%s", dataPool)); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		for _, storageClass := range target.StorageClasses {
+			storageClassArg := fmt.Sprintf("--storage-class=%s", storageClass.Name)
+			if !zoneGroupClasses[target.Name][storageClass.Name] {
+				if err := run("zonegroup", "placement", "add", placementArg, storageClassArg); err != nil {
+					return false, err
+				}
+			}
+			dataPool := poolName(context.Name, placementDataPool(target.Name, storageClass.Name))
+			if zonePools[target.Name][storageClass.Name] != dataPool {
+				if err := run("zone", "placement", "add", placementArg, storageClassArg, fmt.Sprintf("--data-pool=%s", dataPool)); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	removed := removedPlacements(context, targets, zone)
+	removedPools := []string{}
+	pending := false
+	if len(removed) > 0 {
+		poolStats, err := ceph.GetPoolStats(context.Context, context.clusterInfo)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get the pool stats of the removed placement targets")
+		}
+		for _, placement := range removed {
+			args := []string{fmt.Sprintf("--placement-id=%s", placement.target)}
+			description := fmt.Sprintf("placement target %q", placement.target)
+			if placement.storageClass != "" {
+				args = append(args, fmt.Sprintf("--storage-class=%s", placement.storageClass))
+				description = fmt.Sprintf("storage class %q of placement target %q", placement.storageClass, placement.target)
+			}
+			if pool, objects := nonEmptyPool(poolStats, placement.pools); pool != "" {
+				logger.Warningf("not removing %s from object store %q, pool %q still has %d objects", description, context.Name, pool, objects)
+				pending = true
+				continue
+			}
+
+			if len(zoneGroup.Zones) <= 1 {
+				if err := run(append([]string{"zonegroup", "placement", "rm"}, args...)...); err != nil {
+					return false, err
+				}
+			}
+			if err := run(append([]string{"zone", "placement", "rm"}, args...)...); err != nil {
+				return false, err
+			}
+			logger.Infof("removed %s from object store %q", description, context.Name)
+			removedPools = append(removedPools, placement.pools...)
+		}
+	}
+
+	if !changed {
+		return pending, nil
+	}
+	if output, err := runAdminCommand(context, "period", "update", "--commit"); err != nil {
+		return false, errors.Wrapf(err, "failed to update period after configuring placement targets. %s", output)
+	}
+	logger.Infof("configured placement targets of object store %q", context.Name)
+
+	if preservePools {
+		if len(removedPools) > 0 {
+			logger.Infof("PreservePoolsOnDelete is set in object store %q. Pools %v of the removed placement targets not deleted", context.Name, removedPools)
+		}
+		return pending, nil
+	}
+	for _, pool := range removedPools {
+		if err := ceph.DeletePool(context.Context, context.clusterInfo, pool); err != nil {
+			logger.Warningf("failed to delete pool %q. %v", pool, err)
+		}
+		if err := ceph.DeleteErasureCodeProfile(context.Context, context.clusterInfo, ceph.GetErasureCodeProfileForPool(pool)); err != nil {
+			logger.Debugf("failed to delete erasure code profile of pool %q. %v", pool, err)
+		}
+	}
+	return pending, nil
+}
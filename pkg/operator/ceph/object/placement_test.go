@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	placementZoneGroupJSON = `{
+	"name": "my-store",
+	"placement_targets": [
+		{"name": "default-placement", "tags": [], "storage_classes": ["STANDARD"]},
+		{"name": "fast", "tags": [], "storage_classes": ["STANDARD"]}
+	],
+	"default_placement": "default-placement"
+}`
+	placementZoneJSON = `{
+	"name": "my-store",
+	"placement_pools": [
+		{
+			"key": "default-placement",
+			"val": {
+				"index_pool": "my-store.rgw.buckets.index",
+				"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.buckets.data"}},
+				"data_extra_pool": "my-store.rgw.buckets.non-ec",
+				"index_type": 0
+			}
+		},
+		{
+			"key": "fast",
+			"val": {
+				"index_pool": "my-store.rgw.fast.index",
+				"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.fast.data"}},
+				"data_extra_pool": "my-store.rgw.fast.non-ec",
+				"index_type": 0
+			}
+		}
+	]
+}`
+)
+
+func placementTargetsSpec() []cephv1.ObjectPlacementTargetSpec {
+	replicated := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	erasureCoded := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	return []cephv1.ObjectPlacementTargetSpec{
+		{
+			Name:           DefaultPlacementTarget,
+			StorageClasses: []cephv1.ObjectStorageClassSpec{{Name: "COLD", DataPool: erasureCoded}},
+		},
+		{
+			Name:         "fast",
+			MetadataPool: replicated,
+			DataPool:     replicated,
+		},
+	}
+}
+
+func TestValidatePlacementTargets(t *testing.T) {
+	assert.NoError(t, validatePlacementTargets(nil))
+	assert.NoError(t, validatePlacementTargets(placementTargetsSpec()))
+
+	invalid := []func(targets []cephv1.ObjectPlacementTargetSpec){
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[1].Name = "fast.ssd" },
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[1].Name = "buckets" },
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[1].Name = DefaultPlacementTarget },
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[0].DataPool = targets[1].DataPool },
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[1].DataPool = cephv1.PoolSpec{} },
+		func(targets []cephv1.ObjectPlacementTargetSpec) {
+			targets[1].MetadataPool = targets[0].StorageClasses[0].DataPool
+		},
+		func(targets []cephv1.ObjectPlacementTargetSpec) { targets[0].StorageClasses[0].Name = "STANDARD" },
+		func(targets []cephv1.ObjectPlacementTargetSpec) {
+			targets[0].StorageClasses[0].DataPool = cephv1.PoolSpec{}
+		},
+		func(targets []cephv1.ObjectPlacementTargetSpec) {
+			targets[0].StorageClasses = append(targets[0].StorageClasses, cephv1.ObjectStorageClassSpec{Name: "cold", DataPool: targets[1].DataPool})
+		},
+	}
+	for i, modify := range invalid {
+		targets := placementTargetsSpec()
+		modify(targets)
+		assert.Error(t, validatePlacementTargets(targets), i)
+	}
+}
+
+func TestPlacementPools(t *testing.T) {
+	assert.Equal(t, []string{
+		"rgw.default-placement.cold.data",
+		"rgw.fast.index",
+		"rgw.fast.non-ec",
+		"rgw.fast.data",
+	}, placementPools(placementTargetsSpec()))
+}
+
+func TestConfigurePlacementTargets(t *testing.T) {
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return placementZoneGroupJSON, nil
+			case args[0] == "zone" && args[1] == "get":
+				return placementZoneJSON, nil
+			}
+			commands = append(commands, strings.Join(args[:3], " ")+" "+strings.Join(args[3:], " "))
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "my-store")
+
+	// the placement targets not created for the store are not removed
+	otherContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "other-store")
+	_, err := configurePlacementTargets(otherContext, nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+
+	// the fast placement target is already configured, only the COLD storage class is added
+	_, err = configurePlacementTargets(objContext, placementTargetsSpec(), false)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 3)
+	assert.Contains(t, commands[0], "zonegroup placement add --placement-id=default-placement --storage-class=COLD")
+	assert.Contains(t, commands[1], "zone placement add --placement-id=default-placement --storage-class=COLD --data-pool=my-store.rgw.default-placement.cold.data")
+	assert.Contains(t, commands[2], "period update --commit")
+
+	// a new placement target
+	commands = []string{}
+	targets := append(placementTargetsSpec()[1:], placementTargetsSpec()[1])
+	targets[1].Name = "archive"
+	_, err = configurePlacementTargets(objContext, targets, false)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 3)
+	assert.Contains(t, commands[0], "zonegroup placement add --placement-id=archive")
+	assert.Contains(t, commands[1], "zone placement add --placement-id=archive --index-pool=my-store.rgw.archive.index --data-extra-pool=my-store.rgw.archive.non-ec --data-pool=my-store.rgw.archive.data")
+	assert.Contains(t, commands[2], "period update --commit")
+
+	// the placement targets are configured
+	commands = []string{}
+	_, err = configurePlacementTargets(objContext, placementTargetsSpec()[1:], false)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+}
+
+func TestRemovePlacementTargets(t *testing.T) {
+	// the COLD storage class and the fast placement target were created for the store
+	zoneJSON := strings.Replace(placementZoneJSON, `"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.buckets.data"}}`,
+		`"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.buckets.data"}, "COLD": {"data_pool": "my-store.rgw.default-placement.cold.data"}}`, 1)
+	zoneGroupJSON := placementZoneGroupJSON
+	fastObjects := 10
+	commands := []string{}
+	deletedPools := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return zoneGroupJSON, nil
+			case args[0] == "zone" && args[1] == "get":
+				return zoneJSON, nil
+			case args[0] == "pool" && args[1] == "stats":
+				return `{"images":{"count":0,"snap_count":0}}`, nil
+			}
+			commands = append(commands, strings.Join(args[:3], " ")+" "+strings.Join(args[3:], " "))
+			return "", nil
+		},
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			switch {
+			case args[0] == "df":
+				return fmt.Sprintf(`{"pools":[{"name":"my-store.rgw.fast.index","stats":{"objects":%d}},{"name":"my-store.rgw.default-placement.cold.data","stats":{"objects":0}}]}`, fastObjects), nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+				return `{"pool_id":1}`, nil
+			case args[0] == "osd" && args[1] == "pool" && args[2] == "delete":
+				deletedPools = append(deletedPools, args[3])
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "my-store")
+
+	zone := zonePlacementConfig{}
+	assert.NoError(t, json.Unmarshal([]byte(zoneJSON), &zone))
+	assert.Empty(t, removedPlacements(objContext, placementTargetsSpec(), zone))
+	assert.Equal(t, []removedPlacement{
+		{target: DefaultPlacementTarget, storageClass: "COLD", pools: []string{"my-store.rgw.default-placement.cold.data"}},
+		{target: "fast", pools: []string{"my-store.rgw.fast.data", "my-store.rgw.fast.index", "my-store.rgw.fast.non-ec"}},
+	}, removedPlacements(objContext, nil, zone))
+
+	// the placement target with objects is kept
+	pending, err := configurePlacementTargets(objContext, nil, false)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+	assert.Len(t, commands, 3)
+	assert.Contains(t, commands[0], "zonegroup placement rm --placement-id=default-placement --storage-class=COLD")
+	assert.Contains(t, commands[1], "zone placement rm --placement-id=default-placement --storage-class=COLD")
+	assert.Contains(t, commands[2], "period update --commit")
+	assert.Equal(t, []string{"my-store.rgw.default-placement.cold.data"}, deletedPools)
+
+	// the zonegroup is not changed if it has other zones, the pools are preserved
+	commands = []string{}
+	deletedPools = []string{}
+	fastObjects = 0
+	zoneJSON = placementZoneJSON
+	zoneGroupJSON = strings.Replace(placementZoneGroupJSON, `"name": "my-store",`, `"name": "my-store", "zones": [{"name": "my-store"}, {"name": "other"}],`, 1)
+	pending, err = configurePlacementTargets(objContext, nil, true)
+	assert.NoError(t, err)
+	assert.False(t, pending)
+	assert.Len(t, commands, 2)
+	assert.Contains(t, commands[0], "zone placement rm --placement-id=fast")
+	assert.Contains(t, commands[1], "period update --commit")
+	assert.Empty(t, deletedPools)
+}
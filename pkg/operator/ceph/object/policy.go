@@ -112,6 +112,16 @@ var AllowedActions = []action{
 	RestoreObject,
 }
 
+// ReadOnlyActions is the list of actions to list and read the objects of a bucket
+var ReadOnlyActions = []action{
+	GetBucketLocation,
+	GetBucketVersioning,
+	GetObject,
+	GetObjectVersion,
+	ListBucket,
+	ListBucketVersions,
+}
+
 type effect string
 
 // effectAllow and effectDeny values are expected by the S3 API to be 'Allow' or 'Deny' explicitly
@@ -179,6 +189,14 @@ func (s *S3Agent) PutBucketPolicy(bucket string, policy BucketPolicy) (*s3.PutBu
 	return out, nil
 }
 
+// DeleteBucketPolicy removes the policy of the bucket
+func (s *S3Agent) DeleteBucketPolicy(bucket string) error {
+	_, err := s.Client.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
+		Bucket: &bucket,
+	})
+	return err
+}
+
 func (s *S3Agent) GetBucketPolicy(bucket string) (*BucketPolicy, error) {
 	out, err := s.Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
 		Bucket: &bucket,
@@ -204,6 +222,7 @@ func (bp *BucketPolicy) ModifyBucketPolicy(ps ...PolicyStatement) *BucketPolicy
 		for j, oldP := range bp.Statement {
 			if newP.Sid == oldP.Sid {
 				bp.Statement[j] = newP
+				match = true
 			}
 		}
 		if !match {
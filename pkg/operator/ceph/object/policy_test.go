@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModifyBucketPolicy(t *testing.T) {
+	reader := NewPolicyStatement().WithSID("reader").ForPrincipals("reader").ForResources("bucket").Allows().Actions(ReadOnlyActions...)
+	policy := NewBucketPolicy(*reader)
+
+	// a statement with a new SID is added
+	writer := NewPolicyStatement().WithSID("writer").ForPrincipals("writer").ForResources("bucket").Allows().Actions(AllowedActions...)
+	policy = policy.ModifyBucketPolicy(*writer)
+	assert.Equal(t, 2, len(policy.Statement))
+
+	// a statement with an existing SID is replaced
+	reader = NewPolicyStatement().WithSID("reader").ForPrincipals("reader").ForResources("bucket").Allows().Actions(AllowedActions...)
+	policy = policy.ModifyBucketPolicy(*reader)
+	assert.Equal(t, 2, len(policy.Statement))
+	assert.Equal(t, "reader", policy.Statement[0].Sid)
+	assert.Equal(t, len(AllowedActions), len(policy.Statement[0].Action))
+
+	policy = policy.DropPolicyStatements("reader")
+	assert.Equal(t, 1, len(policy.Statement))
+	assert.Equal(t, "writer", policy.Statement[0].Sid)
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	probeOperationPut       = "put"
+	probeOperationGet       = "get"
+	probeOperationList      = "list"
+	probeOperationDelete    = "delete"
+	probeOperationMultipart = "multipart"
+
+	// probeObjectPrefix is the prefix of the objects of the probes in the bucket of the health check
+	probeObjectPrefix        = "rook-probe/"
+	defaultProbeObjectSize   = "4Ki"
+	defaultProbeSamples      = 100
+	defaultProbePercentile   = 99
+	maxProbeSamples          = 10000
+	maxProbeObjectSize       = 256 * 1024 * 1024
+	probeMultipartPartSize   = 5 * 1024 * 1024
+	probeLatencyRoundingUnit = 100 * time.Microsecond
+)
+
+var probeOperations = []string{probeOperationPut, probeOperationGet, probeOperationList, probeOperationDelete, probeOperationMultipart}
+
+// probeLatencies are the last latencies of an operation of the probes
+type probeLatencies struct {
+	operation  string
+	objectSize string
+	// samples is a ring buffer of the last latencies, next is the index of the oldest one when it is full
+	samples []time.Duration
+	next    int
+	// count and sum are the totals of all the latencies since the probes started
+	count uint64
+	sum   time.Duration
+}
+
+func (l *probeLatencies) add(latency time.Duration, maxSamples int) {
+	if len(l.samples) < maxSamples {
+		l.samples = append(l.samples, latency)
+	} else {
+		l.samples[l.next] = latency
+		l.next = (l.next + 1) % maxSamples
+	}
+	l.count++
+	l.sum += latency
+}
+
+// percentile returns the latency at a percentile of the samples with the nearest-rank method
+func (l *probeLatencies) percentile(p float64) time.Duration {
+	if len(l.samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, l.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+type probeThreshold struct {
+	operation  string
+	percentile int
+	latency    time.Duration
+}
+
+// objectProbes runs the synthetic workload of the probes of an object store and keeps the latencies of their
+// operations
+type objectProbes struct {
+	objectSizes   []resource.Quantity
+	multipartSize *resource.Quantity
+	samples       int
+	thresholds    []probeThreshold
+	// latencies of each operation and object size, in the order they were first measured
+	latencies []*probeLatencies
+}
+
+// newObjectProbes validates the probes settings and returns the probes
+func newObjectProbes(spec *cephv1.ObjectProbesSpec) (*objectProbes, error) {
+	p := &objectProbes{samples: spec.Samples}
+	if p.samples == 0 {
+		p.samples = defaultProbeSamples
+	}
+	if p.samples < 0 || p.samples > maxProbeSamples {
+		return nil, errors.Errorf("the number of samples %d must be between 1 and %d", spec.Samples, maxProbeSamples)
+	}
+
+	sizes := spec.ObjectSizes
+	if len(sizes) == 0 {
+		sizes = []string{defaultProbeObjectSize}
+	}
+	seen := map[int64]bool{}
+	for _, s := range sizes {
+		size, err := parseProbeSize(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[size.Value()] {
+			return nil, errors.Errorf("duplicate object size %q", s)
+		}
+		seen[size.Value()] = true
+		p.objectSizes = append(p.objectSizes, size)
+	}
+	if spec.MultipartSize != "" {
+		size, err := parseProbeSize(spec.MultipartSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid multipart size")
+		}
+		p.multipartSize = &size
+	}
+
+	for _, t := range spec.LatencyThresholds {
+		threshold := probeThreshold{operation: t.Operation, percentile: t.Percentile}
+		if !isProbeOperation(t.Operation) {
+			return nil, errors.Errorf("invalid operation %q of latency threshold, expected one of %s", t.Operation, strings.Join(probeOperations, ", "))
+		}
+		if t.Operation == probeOperationMultipart && p.multipartSize == nil {
+			return nil, errors.New("a latency threshold of the multipart uploads requires a multipart size")
+		}
+		if threshold.percentile == 0 {
+			threshold.percentile = defaultProbePercentile
+		}
+		if threshold.percentile < 1 || threshold.percentile > 100 {
+			return nil, errors.Errorf("invalid percentile %d of latency threshold, expected a percentile between 1 and 100", t.Percentile)
+		}
+		latency, err := time.ParseDuration(t.Latency)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid latency %q of %s threshold", t.Latency, t.Operation)
+		}
+		if latency <= 0 {
+			return nil, errors.Errorf("latency %q of %s threshold must be positive", t.Latency, t.Operation)
+		}
+		threshold.latency = latency
+		p.thresholds = append(p.thresholds, threshold)
+	}
+	return p, nil
+}
+
+func parseProbeSize(s string) (resource.Quantity, error) {
+	size, err := resource.ParseQuantity(s)
+	if err != nil {
+		return size, errors.Wrapf(err, "invalid object size %q", s)
+	}
+	if size.Value() <= 0 || size.Value() > maxProbeObjectSize {
+		return size, errors.Errorf("object size %q must be positive and at most 256Mi", s)
+	}
+	return size, nil
+}
+
+func isProbeOperation(operation string) bool {
+	for _, o := range probeOperations {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// validateProbes validates the probes settings of the health check
+func validateProbes(spec *cephv1.ObjectProbesSpec) error {
+	if spec == nil {
+		return nil
+	}
+	_, err := newObjectProbes(spec)
+	return err
+}
+
+// measure runs an operation of the probes and records its latency when it succeeds
+func (p *objectProbes) measure(operation, objectSize string, run func() error) error {
+	start := time.Now()
+	if err := run(); err != nil {
+		if objectSize != "" {
+			return errors.Wrapf(err, "failed to %s object of %s", operation, objectSize)
+		}
+		return errors.Wrapf(err, "failed to %s objects", operation)
+	}
+	latency := time.Since(start)
+
+	for _, l := range p.latencies {
+		if l.operation == operation && l.objectSize == objectSize {
+			l.add(latency, p.samples)
+			return nil
+		}
+	}
+	l := &probeLatencies{operation: operation, objectSize: objectSize}
+	l.add(latency, p.samples)
+	p.latencies = append(p.latencies, l)
+	return nil
+}
+
+func probeObjectKey(size resource.Quantity) string {
+	return fmt.Sprintf("%sobject-%s", probeObjectPrefix, size.String())
+}
+
+// probeBody is the body of the objects of the probes. Its bytes are generated as they are read so that the large
+// objects are never allocated in memory.
+type probeBody struct {
+	size   int64
+	offset int64
+}
+
+func newProbeBody(size int64) *probeBody {
+	return &probeBody{size: size}
+}
+
+// ReadAt implements io.ReaderAt
+func (b *probeBody) ReadAt(p []byte, off int64) (int, error) {
+	if off >= b.size {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if remaining := b.size - off; int64(n) > remaining {
+		n = int(remaining)
+	}
+	for i := 0; i < n; i++ {
+		p[i] = 'r'
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read implements io.Reader
+func (b *probeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadAt(p, b.offset)
+	b.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (b *probeBody) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("invalid offset %d", offset)
+	}
+	b.offset = offset
+	return offset, nil
+}
+
+// run puts, reads, lists and deletes the objects of the probes in the bucket
+func (p *objectProbes) run(s3client *S3Agent, bucket string) error {
+	keys := []string{}
+	for _, size := range p.objectSizes {
+		key := probeObjectKey(size)
+		err := p.measure(probeOperationPut, size.String(), func() error {
+			return s3client.PutObjectReaderInBucket(bucket, key, newProbeBody(size.Value()), contentType)
+		})
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+
+		err = p.measure(probeOperationGet, size.String(), func() error {
+			read, err := s3client.ReadObjectInBucket(bucket, key)
+			if err != nil {
+				return err
+			}
+			if read != size.Value() {
+				return errors.Errorf("read %d bytes instead of %d", read, size.Value())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.multipartSize != nil {
+		key := fmt.Sprintf("%smultipart", probeObjectPrefix)
+		size := p.multipartSize.Value()
+		err := p.measure(probeOperationMultipart, p.multipartSize.String(), func() error {
+			return s3client.PutMultipartObjectInBucket(bucket, key, newProbeBody(size), size, probeMultipartPartSize)
+		})
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	err := p.measure(probeOperationList, "", func() error {
+		listed, err := s3client.ListObjectsInBucket(bucket, probeObjectPrefix)
+		if err != nil {
+			return err
+		}
+		if len(listed) < len(keys) {
+			return errors.Errorf("listed %d objects instead of %d", len(listed), len(keys))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		size := ""
+		if i < len(p.objectSizes) {
+			size = p.objectSizes[i].String()
+		} else {
+			size = p.multipartSize.String()
+		}
+		err := p.measure(probeOperationDelete, size, func() error {
+			_, err := s3client.DeleteObjectInBucket(bucket, key)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statuses returns the latency percentiles of the operations for the status of the object store
+func (p *objectProbes) statuses() []cephv1.ObjectProbeStatus {
+	statuses := []cephv1.ObjectProbeStatus{}
+	for _, l := range p.latencies {
+		statuses = append(statuses, cephv1.ObjectProbeStatus{
+			Operation:  l.operation,
+			ObjectSize: l.objectSize,
+			Samples:    len(l.samples),
+			P50:        l.percentile(50).Round(probeLatencyRoundingUnit).String(),
+			P90:        l.percentile(90).Round(probeLatencyRoundingUnit).String(),
+			P99:        l.percentile(99).Round(probeLatencyRoundingUnit).String(),
+		})
+	}
+	return statuses
+}
+
+// summaries returns the latencies of the operations for the metrics
+func (p *objectProbes) summaries() []probeSummary {
+	summaries := []probeSummary{}
+	for _, l := range p.latencies {
+		quantiles := map[float64]float64{}
+		for _, q := range probeQuantiles {
+			quantiles[q] = l.percentile(q * 100).Seconds()
+		}
+		summaries = append(summaries, probeSummary{
+			operation:  l.operation,
+			objectSize: l.objectSize,
+			count:      l.count,
+			sum:        l.sum.Seconds(),
+			quantiles:  quantiles,
+		})
+	}
+	return summaries
+}
+
+// exceededThresholds returns the descriptions of the latencies above their thresholds
+func (p *objectProbes) exceededThresholds() []string {
+	exceeded := []string{}
+	for _, t := range p.thresholds {
+		for _, l := range p.latencies {
+			if l.operation != t.operation {
+				continue
+			}
+			latency := l.percentile(float64(t.percentile))
+			if latency <= t.latency {
+				continue
+			}
+			operation := l.operation
+			if l.objectSize != "" {
+				operation = fmt.Sprintf("%s %s", l.operation, l.objectSize)
+			}
+			exceeded = append(exceeded, fmt.Sprintf("p%d of %s is %s, above %s", t.percentile, operation, latency.Round(probeLatencyRoundingUnit), t.latency))
+		}
+	}
+	return exceeded
+}
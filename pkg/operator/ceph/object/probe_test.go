@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestProbeLatencies(t *testing.T) {
+	l := &probeLatencies{}
+	assert.Equal(t, time.Duration(0), l.percentile(99))
+
+	for i := 1; i <= 10; i++ {
+		l.add(time.Duration(i)*time.Millisecond, 10)
+	}
+	assert.Equal(t, 5*time.Millisecond, l.percentile(50))
+	assert.Equal(t, 9*time.Millisecond, l.percentile(90))
+	assert.Equal(t, 10*time.Millisecond, l.percentile(99))
+	assert.Equal(t, 1*time.Millisecond, l.percentile(1))
+
+	// the oldest samples are replaced, the totals keep all of them
+	for i := 0; i < 5; i++ {
+		l.add(100*time.Millisecond, 10)
+	}
+	assert.Equal(t, 10, len(l.samples))
+	assert.Equal(t, 10*time.Millisecond, l.percentile(50))
+	assert.Equal(t, 100*time.Millisecond, l.percentile(60))
+	assert.Equal(t, uint64(15), l.count)
+	assert.Equal(t, 555*time.Millisecond, l.sum)
+}
+
+func TestNewObjectProbes(t *testing.T) {
+	p, err := newObjectProbes(&cephv1.ObjectProbesSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultProbeSamples, p.samples)
+	assert.Equal(t, 1, len(p.objectSizes))
+	assert.Equal(t, int64(4096), p.objectSizes[0].Value())
+	assert.Nil(t, p.multipartSize)
+
+	p, err = newObjectProbes(&cephv1.ObjectProbesSpec{
+		ObjectSizes:   []string{"4Ki", "1Mi"},
+		MultipartSize: "20Mi",
+		Samples:       60,
+		LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{
+			{Operation: "put", Percentile: 90, Latency: "200ms"},
+			{Operation: "multipart", Latency: "2s"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 60, p.samples)
+	assert.Equal(t, 2, len(p.objectSizes))
+	assert.Equal(t, int64(20*1024*1024), p.multipartSize.Value())
+	assert.Equal(t, []probeThreshold{
+		{operation: "put", percentile: 90, latency: 200 * time.Millisecond},
+		{operation: "multipart", percentile: 99, latency: 2 * time.Second},
+	}, p.thresholds)
+
+	invalid := []cephv1.ObjectProbesSpec{
+		{Samples: -1},
+		{Samples: 20000},
+		{ObjectSizes: []string{"big"}},
+		{ObjectSizes: []string{"0"}},
+		{ObjectSizes: []string{"1Gi"}},
+		{ObjectSizes: []string{"4Ki", "4096"}},
+		{MultipartSize: "-5Mi"},
+		{LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "copy", Latency: "1s"}}},
+		{LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "multipart", Latency: "1s"}}},
+		{LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "get", Percentile: 101, Latency: "1s"}}},
+		{LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "get", Latency: "fast"}}},
+		{LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "get", Latency: "0s"}}},
+	}
+	for i, spec := range invalid {
+		assert.Error(t, validateProbes(&spec), i)
+	}
+	assert.NoError(t, validateProbes(nil))
+}
+
+func TestProbesThresholds(t *testing.T) {
+	p, err := newObjectProbes(&cephv1.ObjectProbesSpec{
+		Samples:           10,
+		LatencyThresholds: []cephv1.ObjectProbeLatencyThreshold{{Operation: "put", Percentile: 90, Latency: "50ms"}},
+	})
+	require.NoError(t, err)
+	measure := func(operation, size string, latency time.Duration) {
+		assert.NoError(t, p.measure(operation, size, func() error {
+			time.Sleep(latency)
+			return nil
+		}))
+	}
+
+	measure("put", "4Ki", time.Millisecond)
+	measure("list", "", time.Millisecond)
+	assert.Empty(t, p.exceededThresholds())
+
+	// a failed operation is not measured
+	assert.Error(t, p.measure("get", "4Ki", func() error { return errors.New("timeout") }))
+
+	measure("put", "4Ki", 60*time.Millisecond)
+	exceeded := p.exceededThresholds()
+	require.Equal(t, 1, len(exceeded))
+	assert.True(t, strings.HasPrefix(exceeded[0], "p90 of put 4Ki is "), exceeded[0])
+	assert.True(t, strings.HasSuffix(exceeded[0], ", above 50ms"), exceeded[0])
+
+	statuses := p.statuses()
+	require.Equal(t, 2, len(statuses))
+	assert.Equal(t, "put", statuses[0].Operation)
+	assert.Equal(t, "4Ki", statuses[0].ObjectSize)
+	assert.Equal(t, 2, statuses[0].Samples)
+	assert.Equal(t, "list", statuses[1].Operation)
+	assert.Equal(t, "", statuses[1].ObjectSize)
+
+	summaries := p.summaries()
+	require.Equal(t, 2, len(summaries))
+	assert.Equal(t, uint64(2), summaries[0].count)
+	assert.Equal(t, 3, len(summaries[0].quantiles))
+}
+
+func TestProbesCollector(t *testing.T) {
+	collector := newObjectStoreCollector()
+	name := types.NamespacedName{Namespace: "mycluster", Name: "my-store"}
+	collector.setProbes(name, []probeSummary{
+		{operation: "put", objectSize: "4Ki", count: 4, sum: 0.2, quantiles: map[float64]float64{0.5: 0.04, 0.9: 0.06, 0.99: 0.08}},
+	})
+
+	expected := `
+# HELP rook_ceph_object_probe_latency_seconds Latency of the operations of the probes of the object store over their last samples
+# TYPE rook_ceph_object_probe_latency_seconds summary
+rook_ceph_object_probe_latency_seconds{namespace="mycluster",object_size="4Ki",object_store="my-store",operation="put",quantile="0.5"} 0.04
+rook_ceph_object_probe_latency_seconds{namespace="mycluster",object_size="4Ki",object_store="my-store",operation="put",quantile="0.9"} 0.06
+rook_ceph_object_probe_latency_seconds{namespace="mycluster",object_size="4Ki",object_store="my-store",operation="put",quantile="0.99"} 0.08
+rook_ceph_object_probe_latency_seconds_sum{namespace="mycluster",object_size="4Ki",object_store="my-store",operation="put"} 0.2
+rook_ceph_object_probe_latency_seconds_count{namespace="mycluster",object_size="4Ki",object_store="my-store",operation="put"} 4
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "rook_ceph_object_probe_latency_seconds"))
+
+	collector.setProbes(name, nil)
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+}
+
+func TestBucketCheckerSetProbes(t *testing.T) {
+	c := &bucketChecker{namespacedName: types.NamespacedName{Namespace: "mycluster", Name: "my-store"}}
+	c.setProbes(nil)
+	assert.Nil(t, c.getProbes())
+	assert.Nil(t, c.probeStatuses())
+
+	spec := &cephv1.ObjectProbesSpec{Samples: 10}
+	c.setProbes(spec)
+	probes := c.getProbes()
+	require.NotNil(t, probes)
+	assert.NoError(t, probes.measure("list", "", func() error { return nil }))
+
+	// the probes are kept while the settings do not change
+	c.setProbes(&cephv1.ObjectProbesSpec{Samples: 10})
+	assert.Equal(t, probes, c.getProbes())
+	assert.Equal(t, 1, len(c.probeStatuses()))
+
+	c.setProbes(&cephv1.ObjectProbesSpec{Samples: 20})
+	assert.NotEqual(t, probes, c.getProbes())
+	assert.Empty(t, c.probeStatuses())
+
+	// invalid settings disable the probes
+	c.setProbes(&cephv1.ObjectProbesSpec{Samples: -1})
+	assert.Nil(t, c.getProbes())
+}
+
+func TestProbeBody(t *testing.T) {
+	body := newProbeBody(10)
+	content, err := ioutil.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "rrrrrrrrrr", string(content))
+
+	// the body is read again after seeking to its start, as when the request is signed
+	offset, err := body.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+	content, err = ioutil.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Len(t, content, 10)
+
+	// a section of the body is a part of a multipart upload
+	content, err = ioutil.ReadAll(io.NewSectionReader(body, 8, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, "rr", string(content))
+
+	_, err = body.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package object
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -48,6 +49,8 @@ type clusterConfig struct {
 	DataPathMap *config.DataPathMap
 	client      client.Client
 	scheme      *runtime.Scheme
+	// certHash is the hash of the SSL certificate of the gateway
+	certHash string
 }
 
 type rgwConfig struct {
@@ -89,7 +92,7 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			"setting 'AllNodes' to %t is not supported anymore, please use 'instances' instead, removing old DaemonSets if any and replace them with Deployments in object store %s",
 			c.store.Spec.Gateway.AllNodes, c.store.Name)
 	}
-	if c.store.Spec.Gateway.Instances < 1 {
+	if c.store.Spec.Gateway.Instances < 1 && c.store.Spec.Gateway.Autoscaling == nil {
 		// Set the minimum of at least one instance
 		logger.Warning("spec.gateway.instances must be set to at least 1")
 		c.store.Spec.Gateway.Instances = 1
@@ -104,7 +107,7 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 	c.ownerRef = ref
 
 	// start a new deployment and scale up
-	desiredRgwInstances := int(c.store.Spec.Gateway.Instances)
+	desiredRgwInstances := c.rgwInstances()
 	for i := 0; i < desiredRgwInstances; i++ {
 		var err error
 
@@ -130,7 +133,7 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 		}
 
 		// Check for existing deployment and set the daemon config flags
-		_, err = c.context.Clientset.AppsV1().Deployments(c.store.Namespace).Get(rgwConfig.ResourceName, metav1.GetOptions{})
+		existing, err := c.context.Clientset.AppsV1().Deployments(c.store.Namespace).Get(rgwConfig.ResourceName, metav1.GetOptions{})
 		// We don't need to handle any error here
 		if err != nil {
 			// Apply the flag only when the deployment is not found
@@ -141,6 +144,12 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 					return errors.Wrap(err, "failed to set default rgw config options")
 				}
 			}
+		} else {
+			// the auth options follow the spec of the object store
+			err = c.setAuthFlagsMonConfigStore(rgwConfig.ResourceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to set rgw auth config options")
+			}
 		}
 
 		// Create deployment
@@ -149,6 +158,10 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 			return nil
 		}
 		logger.Infof("object store %q deployment %q started", c.store.Name, deployment.Name)
+		if c.store.Spec.Gateway.Autoscaling != nil && existing != nil && existing.Spec.Replicas != nil {
+			// keep the replicas set by the horizontal pod autoscaler
+			deployment.Spec.Replicas = existing.Spec.Replicas
+		}
 
 		// Set owner ref to cephObjectStore object
 		err = controllerutil.SetControllerReference(c.store, deployment, c.scheme)
@@ -179,6 +192,11 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 		}
 	}
 
+	// The single deployment of the store is scaled by the horizontal pod autoscaler
+	if err := c.reconcileAutoscaler(fmt.Sprintf("%s-%s-%s", AppName, c.store.Name, k8sutil.IndexToName(0))); err != nil {
+		return errors.Wrap(err, "failed to reconcile rgw horizontal pod autoscaler")
+	}
+
 	// scale down scenario
 	deps, err := k8sutil.GetDeployments(c.context.Clientset, c.store.Namespace, c.storeLabelSelector())
 	if err != nil {
@@ -187,7 +205,7 @@ func (c *clusterConfig) startRGWPods(realmName, zoneGroupName, zoneName string)
 
 	currentRgwInstances := int(len(deps.Items))
 	if currentRgwInstances > desiredRgwInstances {
-		logger.Infof("found more rgw deployments %d than desired %d in object store %q, scaling down", currentRgwInstances, c.store.Spec.Gateway.Instances, c.store.Name)
+		logger.Infof("found more rgw deployments %d than desired %d in object store %q, scaling down", currentRgwInstances, desiredRgwInstances, c.store.Name)
 		diffCount := currentRgwInstances - desiredRgwInstances
 		for i := 0; i < diffCount; {
 			depIDToRemove := currentRgwInstances - 1
@@ -275,7 +293,7 @@ func (c *clusterConfig) deleteStore() error {
 
 	if !c.clusterSpec.External.Enable {
 		// Delete rgw CephX keys and configuration in centralized mon database
-		for i := 0; i < int(c.store.Spec.Gateway.Instances); i++ {
+		for i := 0; i < c.rgwInstances(); i++ {
 			daemonLetterID := k8sutil.IndexToName(i)
 			depNameToRemove := fmt.Sprintf("%s-%s-%s", AppName, c.store.Name, daemonLetterID)
 
@@ -360,6 +378,59 @@ func (r *ReconcileCephObjectStore) validateStore(s *cephv1.CephObjectStore) erro
 		}
 	}
 
+	if s.Spec.IsSharedPools() {
+		stores := &cephv1.CephObjectStoreList{}
+		if err := r.client.List(context.TODO(), stores, client.InNamespace(s.Namespace)); err != nil {
+			return errors.Wrap(err, "failed to list object stores")
+		}
+		if err := validateSharedPools(s, stores.Items); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Spec.PlacementTargets) > 0 {
+		// the pools of a multisite object store are created by its zone
+		if s.Spec.IsMultisite() {
+			return errors.New("placement targets are not supported in a multisite object store")
+		}
+		if err := validatePlacementTargets(s.Spec.PlacementTargets); err != nil {
+			return err
+		}
+		for _, target := range s.Spec.PlacementTargets {
+			pools := []cephv1.PoolSpec{target.MetadataPool, target.DataPool}
+			for _, storageClass := range target.StorageClasses {
+				pools = append(pools, storageClass.DataPool)
+			}
+			for i := range pools {
+				if emptyPool(pools[i]) {
+					continue
+				}
+				if err := pool.ValidatePoolSpec(r.context, r.clusterInfo, &pools[i]); err != nil {
+					return errors.Wrapf(err, "invalid pool spec of placement target %q", target.Name)
+				}
+			}
+		}
+	}
+
+	if err := validateKMS(s.Spec.Security.KMS, s.Spec.Gateway); err != nil {
+		return errors.Wrap(err, "invalid kms spec")
+	}
+	if err := validateAuth(s.Spec.Auth); err != nil {
+		return errors.Wrap(err, "invalid auth spec")
+	}
+	if err := validateServiceSpec(s.Spec.Gateway); err != nil {
+		return errors.Wrap(err, "invalid gateway service spec")
+	}
+	if err := validateAutoscaling(s.Spec.Gateway); err != nil {
+		return errors.Wrap(err, "invalid gateway autoscaling spec")
+	}
+	if err := validateMetrics(s.Spec.Metrics); err != nil {
+		return errors.Wrap(err, "invalid metrics spec")
+	}
+	if err := validateProbes(s.Spec.HealthCheck.Probes); err != nil {
+		return errors.Wrap(err, "invalid health check probes spec")
+	}
+
 	// Fail if we detected an external CephCluster CR and the list of endpoints is empty
 	if r.cephClusterSpec.External.Enable && r.clusterInfo.CephCred.Username != cephclient.AdminUsername {
 		if len(s.Spec.Gateway.ExternalRgwEndpoints) == 0 {
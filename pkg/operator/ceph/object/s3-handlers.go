@@ -18,6 +18,8 @@ package object
 
 import (
 	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -25,14 +27,20 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol"
+	"github.com/aws/aws-sdk-go/private/protocol/restxml"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/pkg/errors"
 )
 
 // S3Agent wraps the s3.S3 structure to allow for wrapper methods
 type S3Agent struct {
 	Client *s3.S3
+	// SNSClient manages the bucket notification topics
+	SNSClient *sns.SNS
 }
 
 func NewS3Agent(accessKey, secretKey, endpoint string, debug bool) (*S3Agent, error) {
@@ -60,21 +68,40 @@ func NewS3Agent(accessKey, secretKey, endpoint string, debug bool) (*S3Agent, er
 	}
 	svc := s3.New(sess)
 	return &S3Agent{
-		Client: svc,
+		Client:    svc,
+		SNSClient: sns.New(sess),
 	}, nil
 }
 
+// NewUserS3Agent creates an S3 agent authenticated with the keys of an object store user
+func NewUserS3Agent(c *Context, userID, endpoint string) (*S3Agent, error) {
+	user, _, err := GetUser(c, userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object user %q", userID)
+	}
+	if user.AccessKey == nil || user.SecretKey == nil {
+		return nil, errors.Errorf("object user %q has no s3 keys", userID)
+	}
+	return NewS3Agent(*user.AccessKey, *user.SecretKey, endpoint, false)
+}
+
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucketNoInfoLogging(name string) error {
-	return s.createBucket(name, false)
+	return s.createBucket(name, "", false, false)
 }
 
 // CreateBucket creates a bucket with the given name
 func (s *S3Agent) CreateBucket(name string) error {
-	return s.createBucket(name, true)
+	return s.createBucket(name, "", true, false)
 }
 
-func (s *S3Agent) createBucket(name string, infoLogging bool) error {
+// CreateBucketInPlacement creates a bucket with the given name in a placement target of the zonegroup, with object
+// lock if enabled. The default placement target of the zonegroup is used if the placement target is empty.
+func (s *S3Agent) CreateBucketInPlacement(name, placementTarget string, objectLock bool) error {
+	return s.createBucket(name, placementTarget, true, objectLock)
+}
+
+func (s *S3Agent) createBucket(name, placementTarget string, infoLogging, objectLock bool) error {
 	if infoLogging {
 		logger.Infof("creating bucket %q", name)
 	} else {
@@ -83,6 +110,16 @@ func (s *S3Agent) createBucket(name string, infoLogging bool) error {
 	bucketInput := &s3.CreateBucketInput{
 		Bucket: &name,
 	}
+	if placementTarget != "" {
+		// rgw reads the placement target after the colon of the location constraint, the zonegroup before the
+		// colon can be omitted
+		bucketInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(":" + placementTarget),
+		}
+	}
+	if objectLock {
+		bucketInput.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
 	_, err := s.Client.CreateBucket(bucketInput)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
@@ -120,6 +157,88 @@ func (s *S3Agent) DeleteBucket(name string) (bool, error) {
 	return true, nil
 }
 
+// PutBucketVersioning sets the versioning state of the bucket, "Enabled" or "Suspended"
+func (s *S3Agent) PutBucketVersioning(bucket, status string) error {
+	_, err := s.Client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{Status: aws.String(status)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set versioning of bucket %q to %q", bucket, status)
+	}
+	return nil
+}
+
+// GetBucketLifecycle returns the lifecycle rules of the bucket, none if the bucket has no lifecycle
+func (s *S3Agent) GetBucketLifecycle(bucket string) ([]*s3.LifecycleRule, error) {
+	output, err := s.Client.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get lifecycle of bucket %q", bucket)
+	}
+	return output.Rules, nil
+}
+
+// PutBucketLifecycle replaces the lifecycle rules of the bucket
+func (s *S3Agent) PutBucketLifecycle(bucket string, rules []*s3.LifecycleRule) error {
+	_, err := s.Client.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set lifecycle of bucket %q", bucket)
+	}
+	return nil
+}
+
+// DeleteBucketLifecycle removes the lifecycle rules of the bucket
+func (s *S3Agent) DeleteBucketLifecycle(bucket string) error {
+	_, err := s.Client.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete lifecycle of bucket %q", bucket)
+	}
+	return nil
+}
+
+// PutObjectLockConfiguration sets the default retention of the objects of a bucket created with object lock, an
+// empty mode removes the default retention
+func (s *S3Agent) PutObjectLockConfiguration(bucket, mode string, days, years int64) error {
+	config := &s3.ObjectLockConfiguration{ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled)}
+	if mode != "" {
+		retention := &s3.DefaultRetention{Mode: aws.String(mode)}
+		if days > 0 {
+			retention.Days = aws.Int64(days)
+		}
+		if years > 0 {
+			retention.Years = aws.Int64(years)
+		}
+		config.Rule = &s3.ObjectLockRule{DefaultRetention: retention}
+	}
+	_, err := s.Client.PutObjectLockConfiguration(&s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		ObjectLockConfiguration: config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set object lock configuration of bucket %q", bucket)
+	}
+	return nil
+}
+
+// GetObjectLockEnabled returns whether object lock was enabled when the bucket was created
+func (s *S3Agent) GetObjectLockEnabled(bucket string) (bool, error) {
+	output, err := s.Client.GetObjectLockConfiguration(&s3.GetObjectLockConfigurationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ObjectLockConfigurationNotFoundError" {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get object lock configuration of bucket %q", bucket)
+	}
+	return output.ObjectLockConfiguration != nil &&
+		aws.StringValue(output.ObjectLockConfiguration.ObjectLockEnabled) == s3.ObjectLockEnabledEnabled, nil
+}
+
 // PutObjectInBucket function puts an object in a bucket using s3 client
 func (s *S3Agent) PutObjectInBucket(bucketname string, body string, key string,
 	contentType string) (bool, error) {
@@ -137,6 +256,48 @@ func (s *S3Agent) PutObjectInBucket(bucketname string, body string, key string,
 	return true, nil
 }
 
+// PutObjectReaderInBucket puts an object in a bucket, the body is read from the reader rather than from memory
+func (s *S3Agent) PutObjectReaderInBucket(bucketname, key string, body io.ReadSeeker, contentType string) error {
+	_, err := s.Client.PutObject(&s3.PutObjectInput{
+		Body:        body,
+		Bucket:      aws.String(bucketname),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put object %q in bucket %q", key, bucketname)
+	}
+	return nil
+}
+
+// PutEncryptedObjectInBucket puts an object in a bucket with server side encryption by the kms key
+func (s *S3Agent) PutEncryptedObjectInBucket(bucketname, body, key, contentType, kmsKeyID string) error {
+	_, err := s.Client.PutObject(&s3.PutObjectInput{
+		Body:                 strings.NewReader(body),
+		Bucket:               &bucketname,
+		Key:                  &key,
+		ContentType:          &contentType,
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms),
+		SSEKMSKeyId:          &kmsKeyID,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put encrypted object %q in bucket %q", key, bucketname)
+	}
+	return nil
+}
+
+// GetObjectEncryption returns the server side encryption and the kms key of an object
+func (s *S3Agent) GetObjectEncryption(bucketname, key string) (string, string, error) {
+	result, err := s.Client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get object %q in bucket %q", key, bucketname)
+	}
+	return aws.StringValue(result.ServerSideEncryption), aws.StringValue(result.SSEKMSKeyId), nil
+}
+
 // GetObjectInBucket function retrieves an object from a bucket using s3 client
 func (s *S3Agent) GetObjectInBucket(bucketname string, key string) (string, error) {
 	result, err := s.Client.GetObject(&s3.GetObjectInput{
@@ -158,6 +319,23 @@ func (s *S3Agent) GetObjectInBucket(bucketname string, key string) (string, erro
 	return buf.String(), nil
 }
 
+// ReadObjectInBucket reads an object of a bucket without keeping it in memory and returns its size
+func (s *S3Agent) ReadObjectInBucket(bucketname, key string) (int64, error) {
+	result, err := s.Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get object %q from bucket %q", key, bucketname)
+	}
+	defer result.Body.Close()
+	size, err := io.Copy(ioutil.Discard, result.Body)
+	if err != nil {
+		return size, errors.Wrapf(err, "failed to read object %q from bucket %q", key, bucketname)
+	}
+	return size, nil
+}
+
 // DeleteObjectInBucket function deletes given bucket using s3 client
 func (s *S3Agent) DeleteObjectInBucket(bucketname string, key string) (bool, error) {
 	_, err := s.Client.DeleteObject(&s3.DeleteObjectInput{
@@ -179,3 +357,140 @@ func (s *S3Agent) DeleteObjectInBucket(bucketname string, key string) (bool, err
 	}
 	return true, nil
 }
+
+// ListObjectsInBucket returns the keys of the objects of a bucket with the given prefix
+func (s *S3Agent) ListObjectsInBucket(bucketname, prefix string) ([]string, error) {
+	keys := []string{}
+	err := s.Client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketname),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			keys = append(keys, aws.StringValue(object.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// PutMultipartObjectInBucket uploads an object of the given size in parts of the given size, the parts are read from
+// the body. The upload is aborted if a part fails.
+func (s *S3Agent) PutMultipartObjectInBucket(bucketname, key string, body io.ReaderAt, size, partSize int64) error {
+	upload, err := s.Client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucketname),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create multipart upload of object %q", key)
+	}
+
+	parts := []*s3.CompletedPart{}
+	for start, number := int64(0), int64(1); start < size; start, number = start+partSize, number+1 {
+		end := start + partSize
+		if end > size {
+			end = size
+		}
+		part, err := s.Client.UploadPart(&s3.UploadPartInput{
+			Bucket:     aws.String(bucketname),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int64(number),
+			Body:       io.NewSectionReader(body, start, end-start),
+		})
+		if err != nil {
+			_, abortErr := s.Client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(bucketname),
+				Key:      aws.String(key),
+				UploadId: upload.UploadId,
+			})
+			if abortErr != nil {
+				logger.Warningf("failed to abort multipart upload of object %q. %v", key, abortErr)
+			}
+			return errors.Wrapf(err, "failed to upload part %d of object %q", number, key)
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: part.ETag, PartNumber: aws.Int64(number)})
+	}
+
+	_, err = s.Client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucketname),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to complete multipart upload of object %q", key)
+	}
+	return nil
+}
+
+// CreateTopic creates or updates the topic with the given attributes and returns its ARN
+func (s *S3Agent) CreateTopic(name string, attributes map[string]string) (string, error) {
+	input := &sns.CreateTopicInput{
+		Name:       aws.String(name),
+		Attributes: aws.StringMap(attributes),
+	}
+	output, err := s.SNSClient.CreateTopic(input)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create topic %q", name)
+	}
+	return aws.StringValue(output.TopicArn), nil
+}
+
+// DeleteTopic deletes the topic with the given ARN, a topic that does not exist is ignored
+func (s *S3Agent) DeleteTopic(arn string) error {
+	_, err := s.SNSClient.DeleteTopic(&sns.DeleteTopicInput{TopicArn: aws.String(arn)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == sns.ErrCodeNotFoundException {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete topic %q", arn)
+	}
+	return nil
+}
+
+// PutBucketNotification adds the notification of the bucket to a topic or updates it.
+// RGW adds the notifications of the configuration to the existing ones of the bucket instead of replacing them.
+func (s *S3Agent) PutBucketNotification(bucket string, notification *s3.TopicConfiguration) error {
+	_, err := s.Client.PutBucketNotificationConfiguration(&s3.PutBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucket),
+		NotificationConfiguration: &s3.NotificationConfiguration{
+			TopicConfigurations: []*s3.TopicConfiguration{notification},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put notification %q of bucket %q", aws.StringValue(notification.Id), bucket)
+	}
+	return nil
+}
+
+// deleteBucketNotificationInput is the input of the RGW extension deleting a single notification of a bucket
+type deleteBucketNotificationInput struct {
+	_      struct{} `type:"structure"`
+	Bucket *string  `location:"uri" locationName:"Bucket" type:"string" required:"true"`
+	ID     *string  `location:"querystring" locationName:"notification" type:"string" required:"true"`
+}
+
+type deleteBucketNotificationOutput struct {
+	_ struct{} `type:"structure"`
+}
+
+// DeleteBucketNotification deletes a notification of the bucket, a notification that does not exist is ignored
+func (s *S3Agent) DeleteBucketNotification(bucket, id string) error {
+	op := &request.Operation{
+		Name:       "DeleteBucketNotification",
+		HTTPMethod: "DELETE",
+		HTTPPath:   "/{Bucket}",
+	}
+	req := s.Client.NewRequest(op, &deleteBucketNotificationInput{Bucket: aws.String(bucket), ID: aws.String(id)}, &deleteBucketNotificationOutput{})
+	req.Handlers.Unmarshal.Swap(restxml.UnmarshalHandler.Name, protocol.UnmarshalDiscardBodyHandler)
+	if err := req.Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete notification %q of bucket %q", id, bucket)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopics(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ = url.ParseQuery(string(body))
+		switch form.Get("Action") {
+		case "CreateTopic":
+			_, _ = w.Write([]byte(`<CreateTopicResponse><CreateTopicResult><TopicArn>arn:aws:sns:my-store::my-topic</TopicArn></CreateTopicResult></CreateTopicResponse>`))
+		case "DeleteTopic":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NotFound</Code></Error></ErrorResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	s3Agent, err := NewS3Agent("access", "secret", server.URL, false)
+	assert.NoError(t, err)
+
+	arn, err := s3Agent.CreateTopic("my-topic", map[string]string{"push-endpoint": "http://sink:8080"})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:sns:my-store::my-topic", arn)
+	assert.Equal(t, "my-topic", form.Get("Name"))
+	assert.Equal(t, "push-endpoint", form.Get("Attributes.entry.1.key"))
+	assert.Equal(t, "http://sink:8080", form.Get("Attributes.entry.1.value"))
+
+	// a topic that does not exist is already deleted
+	err = s3Agent.DeleteTopic(arn)
+	assert.NoError(t, err)
+	assert.Equal(t, arn, form.Get("TopicArn"))
+}
+
+func TestBucketNotifications(t *testing.T) {
+	var method, query, body string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		query = r.URL.RawQuery
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(status)
+		if status == http.StatusNotFound {
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchBucket</Code></Error>`))
+		}
+	}))
+	defer server.Close()
+
+	s3Agent, err := NewS3Agent("access", "secret", server.URL, false)
+	assert.NoError(t, err)
+
+	err = s3Agent.PutBucketNotification("my-bucket", &s3.TopicConfiguration{
+		Id:       aws.String("my-notification"),
+		TopicArn: aws.String("arn:aws:sns:my-store::my-topic"),
+		Events:   aws.StringSlice([]string{"s3:ObjectCreated:*"}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", method)
+	assert.Equal(t, "notification=", query)
+	assert.Contains(t, body, "<Id>my-notification</Id>")
+	assert.Contains(t, body, "<Topic>arn:aws:sns:my-store::my-topic</Topic>")
+	assert.Contains(t, body, "<Event>s3:ObjectCreated:*</Event>")
+
+	err = s3Agent.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", method)
+	assert.Equal(t, "notification=my-notification", query)
+
+	// the notifications of a bucket that does not exist are already deleted
+	status = http.StatusNotFound
+	err = s3Agent.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.NoError(t, err)
+
+	status = http.StatusInternalServerError
+	err = s3Agent.DeleteBucketNotification("my-bucket", "my-notification")
+	assert.Error(t, err)
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	ceph "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+)
+
+// sharedMetadataNamespaces are the RADOS namespaces of the pools of the zone in the shared metadata pool, they are
+// prefixed by the name of the object store
+var sharedMetadataNamespaces = map[string]string{
+	"domain_root":     "meta.root",
+	"control_pool":    "control",
+	"gc_pool":         "log.gc",
+	"lc_pool":         "log.lc",
+	"log_pool":        "log",
+	"intent_log_pool": "log.intent",
+	"usage_log_pool":  "log.usage",
+	"roles_pool":      "meta.roles",
+	"reshard_pool":    "log.reshard",
+	"user_keys_pool":  "meta.users.keys",
+	"user_email_pool": "meta.users.email",
+	"user_swift_pool": "meta.users.swift",
+	"user_uid_pool":   "meta.users.uid",
+	"otp_pool":        "otp",
+	"notif_pool":      "log.notif",
+}
+
+const (
+	// the bucket index and the non-ec pool must be replicated, they are in the metadata pool
+	sharedIndexNamespace     = "buckets.index"
+	sharedDataExtraNamespace = "buckets.non-ec"
+	sharedDataNamespace      = "buckets.data"
+
+	// radosRemoveBatchSize is how many objects are removed by each rados command when purging a RADOS namespace
+	radosRemoveBatchSize = 500
+)
+
+// sharedPoolNamespace returns the pool of the zone of an object store in a shared pool, in the pool:namespace form
+// of the zone config
+func sharedPoolNamespace(pool, storeName, namespace string) string {
+	return fmt.Sprintf("%s:%s.%s", pool, storeName, namespace)
+}
+
+// sharedNamespaces returns the RADOS namespaces of the object store in each shared pool
+func sharedNamespaces(storeName string, shared cephv1.ObjectSharedPoolsSpec) map[string][]string {
+	namespaces := map[string][]string{}
+	for _, namespace := range sharedMetadataNamespaces {
+		namespaces[shared.MetadataPoolName] = append(namespaces[shared.MetadataPoolName], fmt.Sprintf("%s.%s", storeName, namespace))
+	}
+	namespaces[shared.MetadataPoolName] = append(namespaces[shared.MetadataPoolName],
+		fmt.Sprintf("%s.%s", storeName, sharedIndexNamespace),
+		fmt.Sprintf("%s.%s", storeName, sharedDataExtraNamespace))
+	namespaces[shared.DataPoolName] = append(namespaces[shared.DataPoolName], fmt.Sprintf("%s.%s", storeName, sharedDataNamespace))
+	return namespaces
+}
+
+// validateSharedPools validates the shared pools of the object store against the shared pools of the other stores
+func validateSharedPools(store *cephv1.CephObjectStore, others []cephv1.CephObjectStore) error {
+	spec := store.Spec
+	if spec.SharedPools.MetadataPoolName == "" || spec.SharedPools.DataPoolName == "" {
+		return errors.New("shared pools require both a metadata pool name and a data pool name")
+	}
+	if spec.SharedPools.MetadataPoolName == spec.SharedPools.DataPoolName {
+		return errors.New("the shared metadata and data pools must be different pools")
+	}
+	// the pools of a multisite object store are created by its zone
+	if spec.IsMultisite() {
+		return errors.New("shared pools are not supported in a multisite object store")
+	}
+	if len(spec.PlacementTargets) > 0 {
+		return errors.New("placement targets are not supported with shared pools")
+	}
+	if spec.MetadataPool.IsErasureCoded() {
+		return errors.New("the shared metadata pool cannot be erasure coded")
+	}
+
+	// the stores sharing a pool must agree on its role and its settings
+	for _, other := range others {
+		if other.Name == store.Name || !other.Spec.IsSharedPools() {
+			continue
+		}
+		shared := other.Spec.SharedPools
+		if shared.MetadataPoolName == spec.SharedPools.DataPoolName || shared.DataPoolName == spec.SharedPools.MetadataPoolName {
+			return errors.Errorf("the shared pools of object store %q are used with a different role by object store %q", store.Name, other.Name)
+		}
+		if shared.MetadataPoolName == spec.SharedPools.MetadataPoolName && conflictingPools(spec.MetadataPool, other.Spec.MetadataPool) {
+			return errors.Errorf("the settings of shared metadata pool %q conflict with the settings of object store %q", shared.MetadataPoolName, other.Name)
+		}
+		if shared.DataPoolName == spec.SharedPools.DataPoolName && conflictingPools(spec.DataPool, other.Spec.DataPool) {
+			return errors.Errorf("the settings of shared data pool %q conflict with the settings of object store %q", shared.DataPoolName, other.Name)
+		}
+	}
+	return nil
+}
+
+// conflictingPools returns whether two stores define different settings for the same pool, a store may omit the
+// settings of a pool created by another store
+func conflictingPools(pool, other cephv1.PoolSpec) bool {
+	return !emptyPool(pool) && !emptyPool(other) && !reflect.DeepEqual(pool, other)
+}
+
+// createSharedPools creates the shared pools of the object store if their settings are set, they must exist otherwise
+func createSharedPools(context *Context, spec cephv1.ObjectStoreSpec) error {
+	shared := spec.SharedPools
+	for pool, poolSpec := range map[string]cephv1.PoolSpec{shared.MetadataPoolName: spec.MetadataPool, shared.DataPoolName: spec.DataPool} {
+		if emptyPool(poolSpec) {
+			if _, err := ceph.GetPoolDetails(context.Context, context.clusterInfo, pool); err != nil {
+				return errors.Wrapf(err, "shared pool %q is missing", pool)
+			}
+		}
+	}
+
+	if !emptyPool(spec.MetadataPool) {
+		metadataPoolPGs, err := config.GetMonStore(context.Context, context.clusterInfo).Get("mon.", "rgw_rados_pool_pg_num_min")
+		if err != nil {
+			logger.Warningf("failed to adjust the PG count for rgw metadata pools. using the general default. %v", err)
+			metadataPoolPGs = ceph.DefaultPGCount
+		}
+		if err := createPool(context, shared.MetadataPoolName, spec.MetadataPool, metadataPoolPGs, ""); err != nil {
+			return errors.Wrap(err, "failed to create shared metadata pool")
+		}
+	}
+
+	if !emptyPool(spec.DataPool) {
+		ecProfileName := ""
+		if spec.DataPool.IsErasureCoded() {
+			ecProfileName = ceph.GetErasureCodeProfileForPool(shared.DataPoolName)
+			if err := ceph.CreateErasureCodeProfile(context.Context, context.clusterInfo, ecProfileName, spec.DataPool); err != nil {
+				return errors.Wrap(err, "failed to create erasure code profile")
+			}
+		}
+		if err := createPool(context, shared.DataPoolName, spec.DataPool, ceph.DefaultPGCount, ecProfileName); err != nil {
+			return errors.Wrap(err, "failed to create shared data pool")
+		}
+	}
+
+	return nil
+}
+
+// configureSharedPools sets the pools of the zone of the object store to its RADOS namespaces of the shared pools.
+// The period is only committed if the zone changed.
+func configureSharedPools(context *Context, shared cephv1.ObjectSharedPoolsSpec) error {
+	output, err := runAdminCommand(context, "zone", "get")
+	if err != nil {
+		return errors.Wrapf(err, "failed to get zone %q", context.Zone)
+	}
+	zone := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(output), &zone); err != nil {
+		return errors.Wrapf(err, "failed to parse zone %q", context.Zone)
+	}
+
+	changed := false
+	set := func(object map[string]interface{}, key, value string) {
+		if object[key] != value {
+			object[key] = value
+			changed = true
+		}
+	}
+	for key, namespace := range sharedMetadataNamespaces {
+		set(zone, key, sharedPoolNamespace(shared.MetadataPoolName, context.Name, namespace))
+	}
+
+	placementPools, _ := zone["placement_pools"].([]interface{})
+	for _, placementPool := range placementPools {
+		placement, _ := placementPool.(map[string]interface{})
+		if placement["key"] != DefaultPlacementTarget {
+			continue
+		}
+		val, ok := placement["val"].(map[string]interface{})
+		if !ok {
+			return errors.Errorf("invalid %q placement of zone %q", DefaultPlacementTarget, context.Zone)
+		}
+		set(val, "index_pool", sharedPoolNamespace(shared.MetadataPoolName, context.Name, sharedIndexNamespace))
+		set(val, "data_extra_pool", sharedPoolNamespace(shared.MetadataPoolName, context.Name, sharedDataExtraNamespace))
+		storageClasses, ok := val["storage_classes"].(map[string]interface{})
+		if !ok {
+			storageClasses = map[string]interface{}{}
+			val["storage_classes"] = storageClasses
+		}
+		standard, ok := storageClasses[standardStorageClass].(map[string]interface{})
+		if !ok {
+			standard = map[string]interface{}{}
+			storageClasses[standardStorageClass] = standard
+		}
+		set(standard, "data_pool", sharedPoolNamespace(shared.DataPoolName, context.Name, sharedDataNamespace))
+	}
+
+	if !changed {
+		return nil
+	}
+
+	zoneJSON, err := json.Marshal(zone)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize zone %q", context.Zone)
+	}
+	zoneFile, err := ioutil.TempFile("", "")
+	if err != nil {
+		return errors.Wrap(err, "failed to generate temporary file")
+	}
+	defer os.Remove(zoneFile.Name())
+	if _, err := zoneFile.Write(zoneJSON); err != nil {
+		zoneFile.Close()
+		return errors.Wrapf(err, "failed to write zone %q", context.Zone)
+	}
+	zoneFile.Close()
+
+	if output, err := runAdminCommand(context, "zone", "set", fmt.Sprintf("--infile=%s", zoneFile.Name())); err != nil {
+		return errors.Wrapf(err, "failed to set pools of zone %q. %s", context.Zone, output)
+	}
+	if output, err := runAdminCommand(context, "period", "update", "--commit"); err != nil {
+		return errors.Wrapf(err, "failed to update period after setting the shared pools. %s", output)
+	}
+	logger.Infof("object store %q uses shared pools %q and %q", context.Name, shared.MetadataPoolName, shared.DataPoolName)
+	return nil
+}
+
+// deleteSharedPools removes the objects of the RADOS namespaces of the object store from the shared pools, unless
+// they are preserved, and deletes the shared pools created by the object store that are not used by another object
+// store. A shared pool without settings in the spec was created by another store or by the admin and is not deleted.
+func deleteSharedPools(context *Context, spec cephv1.ObjectStoreSpec) error {
+	namespaces := sharedNamespaces(context.Name, spec.SharedPools)
+	if !spec.SharedPools.PreserveRadosNamespaceDataOnDelete {
+		for pool, poolNamespaces := range namespaces {
+			for _, namespace := range poolNamespaces {
+				if err := purgeRadosNamespace(context, pool, namespace); err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		logger.Infof("PreserveRadosNamespaceDataOnDelete is set in object store %s. Objects of its RADOS namespaces not deleted", context.Name)
+	}
+
+	if spec.PreservePoolsOnDelete {
+		logger.Infof("PreservePoolsOnDelete is set in object store %s. Shared pools not deleted", context.Name)
+		return nil
+	}
+
+	usedPools, err := poolsUsedByOtherStores(context)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the pools used by the other object stores")
+	}
+	ownedPools := map[string]cephv1.PoolSpec{spec.SharedPools.MetadataPoolName: spec.MetadataPool, spec.SharedPools.DataPoolName: spec.DataPool}
+	for pool := range namespaces {
+		if emptyPool(ownedPools[pool]) {
+			logger.Infof("not deleting shared pool %q not created by object store %q", pool, context.Name)
+			continue
+		}
+		if usedPools[pool] {
+			logger.Infof("not deleting shared pool %q used by other object stores", pool)
+			continue
+		}
+		if err := ceph.DeletePool(context.Context, context.clusterInfo, pool); err != nil {
+			logger.Warningf("failed to delete pool %q. %v", pool, err)
+		}
+	}
+	if spec.DataPool.IsErasureCoded() && !usedPools[spec.SharedPools.DataPoolName] {
+		ecProfileName := ceph.GetErasureCodeProfileForPool(spec.SharedPools.DataPoolName)
+		if err := ceph.DeleteErasureCodeProfile(context.Context, context.clusterInfo, ecProfileName); err != nil {
+			return errors.Wrapf(err, "failed to delete erasure code profile %s for object store %s", ecProfileName, context.Name)
+		}
+	}
+	return nil
+}
+
+// poolsUsedByOtherStores returns the pools referenced by the zones of the other single site object stores. The realm
+// of the deleted store is already removed.
+func poolsUsedByOtherStores(context *Context) (map[string]bool, error) {
+	stores, err := getObjectStores(context)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := map[string]bool{}
+	for _, store := range stores {
+		if store == context.Name {
+			continue
+		}
+		output, err := RunAdminCommandNoMultisite(context, "zone", "get",
+			fmt.Sprintf("--rgw-realm=%s", store), fmt.Sprintf("--rgw-zonegroup=%s", store), fmt.Sprintf("--rgw-zone=%s", store))
+		if err != nil {
+			// the realm of a multisite object store doesn't have a zone of the same name
+			logger.Debugf("failed to get zone of realm %q. %v", store, err)
+			continue
+		}
+		var zone interface{}
+		if err := json.Unmarshal([]byte(output), &zone); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse zone %q", store)
+		}
+		addZonePools(zone, pools)
+	}
+	return pools, nil
+}
+
+// addZonePools adds the pools of the pool:namespace values of the zone config to the pools
+func addZonePools(value interface{}, pools map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok && (strings.HasSuffix(key, "_pool") || key == "domain_root") {
+				pools[strings.SplitN(s, ":", 2)[0]] = true
+				continue
+			}
+			addZonePools(item, pools)
+		}
+	case []interface{}:
+		for _, item := range v {
+			addZonePools(item, pools)
+		}
+	}
+}
+
+// purgeRadosNamespace removes all the objects of a RADOS namespace of a pool
+func purgeRadosNamespace(context *Context, pool, namespace string) error {
+	args := []string{
+		"--pool", pool,
+		"--namespace", namespace,
+		"--conf", ceph.CephConfFilePath(context.Context.ConfigDir, context.clusterInfo.Namespace),
+	}
+	output, err := context.Context.Executor.ExecuteCommandWithOutput("rados", append(args, "ls")...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list objects of namespace %q of pool %q. %s", namespace, pool, output)
+	}
+
+	objects := []string{}
+	for _, object := range strings.Split(output, "\n") {
+		if object != "" {
+			objects = append(objects, object)
+		}
+	}
+
+	// remove the objects in batches rather than forking a rados command per object
+	removed := 0
+	for len(objects) > 0 {
+		batch := objects
+		if len(batch) > radosRemoveBatchSize {
+			batch = batch[:radosRemoveBatchSize]
+		}
+		objects = objects[len(batch):]
+		if err := context.Context.Executor.ExecuteCommand("rados", append(append(args, "rm"), batch...)...); err != nil {
+			return errors.Wrapf(err, "failed to remove %d objects of namespace %q of pool %q", len(batch), namespace, pool)
+		}
+		removed += len(batch)
+	}
+	if removed > 0 {
+		logger.Infof("removed %d objects of namespace %q of pool %q", removed, namespace, pool)
+	}
+	return nil
+}
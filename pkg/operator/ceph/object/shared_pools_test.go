@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultZoneJSON = `{
+	"id": "5f1cc0ba-0b4b-4ea2-b3f7-a8a93c8c0b4e",
+	"name": "my-store",
+	"domain_root": "my-store.rgw.meta:root",
+	"control_pool": "my-store.rgw.control",
+	"gc_pool": "my-store.rgw.log:gc",
+	"lc_pool": "my-store.rgw.log:lc",
+	"log_pool": "my-store.rgw.log",
+	"intent_log_pool": "my-store.rgw.log:intent",
+	"usage_log_pool": "my-store.rgw.log:usage",
+	"roles_pool": "my-store.rgw.meta:roles",
+	"reshard_pool": "my-store.rgw.log:reshard",
+	"user_keys_pool": "my-store.rgw.meta:users.keys",
+	"user_email_pool": "my-store.rgw.meta:users.email",
+	"user_swift_pool": "my-store.rgw.meta:users.swift",
+	"user_uid_pool": "my-store.rgw.meta:users.uid",
+	"otp_pool": "my-store.rgw.otp",
+	"notif_pool": "my-store.rgw.log:notif",
+	"system_key": {"access_key": "", "secret_key": ""},
+	"placement_pools": [
+		{
+			"key": "default-placement",
+			"val": {
+				"index_pool": "my-store.rgw.buckets.index",
+				"storage_classes": {"STANDARD": {"data_pool": "my-store.rgw.buckets.data"}},
+				"data_extra_pool": "my-store.rgw.buckets.non-ec",
+				"index_type": 0
+			}
+		}
+	],
+	"realm_id": ""
+}`
+
+func sharedStore(name, metadataPool, dataPool string) cephv1.CephObjectStore {
+	return cephv1.CephObjectStore{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec: cephv1.ObjectStoreSpec{
+			SharedPools: cephv1.ObjectSharedPoolsSpec{MetadataPoolName: metadataPool, DataPoolName: dataPool},
+		},
+	}
+}
+
+func TestValidateSharedPools(t *testing.T) {
+	replicated := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	erasureCoded := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+
+	store := sharedStore("store-a", "rgw-meta", "rgw-data")
+	store.Spec.MetadataPool = replicated
+	store.Spec.DataPool = erasureCoded
+	other := sharedStore("store-b", "rgw-meta", "rgw-data")
+	unrelated := cephv1.CephObjectStore{ObjectMeta: metav1.ObjectMeta{Name: "store-c"}, Spec: cephv1.ObjectStoreSpec{DataPool: replicated}}
+	assert.NoError(t, validateSharedPools(&store, []cephv1.CephObjectStore{store, other, unrelated}))
+
+	// the other store defines the same settings
+	other.Spec.MetadataPool = replicated
+	assert.NoError(t, validateSharedPools(&store, []cephv1.CephObjectStore{store, other}))
+
+	// the other store defines different settings
+	other.Spec.DataPool = replicated
+	assert.Error(t, validateSharedPools(&store, []cephv1.CephObjectStore{store, other}))
+
+	// the other store uses the pools with another role
+	swapped := sharedStore("store-b", "rgw-data", "rgw-meta")
+	assert.Error(t, validateSharedPools(&store, []cephv1.CephObjectStore{store, swapped}))
+
+	invalid := []func(s *cephv1.CephObjectStore){
+		func(s *cephv1.CephObjectStore) { s.Spec.SharedPools.DataPoolName = "" },
+		func(s *cephv1.CephObjectStore) { s.Spec.SharedPools.DataPoolName = "rgw-meta" },
+		func(s *cephv1.CephObjectStore) { s.Spec.Zone.Name = "zone-a" },
+		func(s *cephv1.CephObjectStore) {
+			s.Spec.PlacementTargets = []cephv1.ObjectPlacementTargetSpec{{Name: "fast"}}
+		},
+		func(s *cephv1.CephObjectStore) { s.Spec.MetadataPool = erasureCoded },
+	}
+	for i, modify := range invalid {
+		s := store.DeepCopy()
+		modify(s)
+		assert.Error(t, validateSharedPools(s, nil), i)
+	}
+}
+
+func TestConfigureSharedPools(t *testing.T) {
+	zoneJSON := defaultZoneJSON
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, args[0]+" "+args[1])
+			switch {
+			case args[0] == "zone" && args[1] == "get":
+				return zoneJSON, nil
+			case args[0] == "zone" && args[1] == "set":
+				assert.True(t, strings.HasPrefix(args[2], "--infile="))
+				zoneFile, err := ioutil.ReadFile(strings.TrimPrefix(args[2], "--infile="))
+				assert.NoError(t, err)
+				zoneJSON = string(zoneFile)
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "my-store")
+	shared := cephv1.ObjectSharedPoolsSpec{MetadataPoolName: "rgw-meta", DataPoolName: "rgw-data"}
+
+	err := configureSharedPools(objContext, shared)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zone get", "zone set", "period update"}, commands)
+
+	var zone zonePlacementConfig
+	assert.NoError(t, json.Unmarshal([]byte(zoneJSON), &zone))
+	assert.Equal(t, "rgw-meta:my-store.buckets.index", zone.PlacementPools[0].Val.IndexPool)
+	assert.Equal(t, "rgw-meta:my-store.buckets.non-ec", zone.PlacementPools[0].Val.DataExtraPool)
+	assert.Equal(t, "rgw-data:my-store.buckets.data", zone.PlacementPools[0].Val.StorageClasses["STANDARD"].DataPool)
+	pools := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(zoneJSON), &pools))
+	assert.Equal(t, "rgw-meta:my-store.meta.root", pools["domain_root"])
+	assert.Equal(t, "rgw-meta:my-store.log.gc", pools["gc_pool"])
+	// the other settings of the zone are kept
+	assert.Equal(t, "5f1cc0ba-0b4b-4ea2-b3f7-a8a93c8c0b4e", pools["id"])
+
+	// the zone already uses the shared pools
+	commands = []string{}
+	err = configureSharedPools(objContext, shared)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zone get"}, commands)
+}
+
+func TestDeleteSharedPools(t *testing.T) {
+	removedObjects := []string{}
+	deletedPools := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case command == "rados" && args[len(args)-1] == "ls":
+				if args[3] == "my-store.buckets.data" {
+					return "obj1\nobj2\n", nil
+				}
+				return "", nil
+			case args[0] == "realm" && args[1] == "list":
+				return `{"realms":["other-store"]}`, nil
+			case args[0] == "zone" && args[1] == "get":
+				// the other store shares the metadata pool
+				return strings.Replace(strings.Replace(defaultZoneJSON, "my-store.rgw.meta", "rgw-meta", -1), "my-store", "other-store", -1), nil
+			case args[0] == "pool" && args[1] == "stats":
+				return `{"images":{"count":0,"provisioned_bytes":0,"snap_count":0},"trash":{"count":0,"provisioned_bytes":0,"snap_count":0}}`, nil
+			}
+			return "", errors.Errorf("unexpected command %s %q", command, args)
+		},
+		MockExecuteCommand: func(command string, args ...string) error {
+			assert.Equal(t, "rm", args[6])
+			removedObjects = append(removedObjects, args[3]+"/"+strings.Join(args[7:], ","))
+			return nil
+		},
+		MockExecuteCommandWithOutputFile: func(command, outfile string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "pool" {
+				if args[2] == "get" {
+					return `{"pool_id":1}`, nil
+				}
+				if args[2] == "delete" {
+					deletedPools = append(deletedPools, args[3])
+				}
+			}
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "my-store")
+	spec := sharedStore("my-store", "rgw-meta", "rgw-data").Spec
+	spec.MetadataPool = cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+	spec.DataPool = cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}
+
+	// the objects of the store are removed, the pools are preserved
+	spec.PreservePoolsOnDelete = true
+	err := deletePools(objContext, spec, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-store.buckets.data/obj1,obj2"}, removedObjects)
+	assert.Empty(t, deletedPools)
+
+	// the metadata pool is still used by the other store
+	removedObjects = []string{}
+	spec.PreservePoolsOnDelete = false
+	spec.SharedPools.PreserveRadosNamespaceDataOnDelete = true
+	err = deletePools(objContext, spec, false)
+	assert.NoError(t, err)
+	assert.Empty(t, removedObjects)
+	assert.Equal(t, []string{"rgw-data"}, deletedPools)
+
+	// the pools referenced but not created by the store are not deleted, only its namespaces are purged
+	deletedPools = []string{}
+	spec.SharedPools.PreserveRadosNamespaceDataOnDelete = false
+	spec.MetadataPool = cephv1.PoolSpec{}
+	spec.DataPool = cephv1.PoolSpec{}
+	err = deletePools(objContext, spec, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-store.buckets.data/obj1,obj2"}, removedObjects)
+	assert.Empty(t, deletedPools)
+}
@@ -43,6 +43,16 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 		return nil, err
 	}
 	replicas := int32(1)
+	strategy := apps.DeploymentStrategy{
+		Type: apps.RecreateDeploymentStrategyType,
+	}
+	if autoscaling := c.store.Spec.Gateway.Autoscaling; autoscaling != nil {
+		replicas = autoscaling.MinInstances
+		// the autoscaled pods are updated one by one unless they bind the ports of the hosts
+		if !c.clusterSpec.Network.IsHost() {
+			strategy.Type = apps.RollingUpdateDeploymentStrategyType
+		}
+	}
 	d := &apps.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rgwConfig.ResourceName,
@@ -55,9 +65,7 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 			},
 			Template: pod,
 			Replicas: &replicas,
-			Strategy: apps.DeploymentStrategy{
-				Type: apps.RecreateDeploymentStrategyType,
-			},
+			Strategy: strategy,
 		},
 	}
 	k8sutil.AddRookVersionLabelToDeployment(d)
@@ -70,16 +78,18 @@ func (c *clusterConfig) createDeployment(rgwConfig *rgwConfig) (*apps.Deployment
 
 func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec, error) {
 	podSpec := v1.PodSpec{
-		InitContainers: []v1.Container{
-			c.makeChownInitContainer(rgwConfig),
-		},
+		InitContainers: append(
+			[]v1.Container{c.makeChownInitContainer(rgwConfig)},
+			c.kmsInitContainers()...,
+		),
 		Containers: []v1.Container{
 			c.makeDaemonContainer(rgwConfig),
 		},
 		RestartPolicy: v1.RestartPolicyAlways,
-		Volumes: append(
+		Volumes: append(append(
 			controller.DaemonVolumes(c.DataPathMap, rgwConfig.ResourceName),
-			c.mimeTypesVolume(),
+			c.mimeTypesVolume()),
+			append(c.kmsVolumes(), c.authVolumes()...)...,
 		),
 		HostNetwork:       c.clusterSpec.Network.IsHost(),
 		PriorityClassName: c.store.Spec.Gateway.PriorityClassName,
@@ -119,6 +129,20 @@ func (c *clusterConfig) makeRGWPodSpec(rgwConfig *rgwConfig) (v1.PodTemplateSpec
 	}
 	c.store.Spec.Gateway.Annotations.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
 	c.store.Spec.Gateway.Labels.ApplyToObjectMeta(&podTemplateSpec.ObjectMeta)
+	if hash := c.authHash(); hash != "" {
+		// restart the rgw pods when the auth options change in the mon config store
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[authAnnotation] = hash
+	}
+	if c.certHash != "" {
+		// restart the rgw pods when the certificate is renewed since rgw only reads it at startup
+		if podTemplateSpec.ObjectMeta.Annotations == nil {
+			podTemplateSpec.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplateSpec.ObjectMeta.Annotations[certAnnotation] = c.certHash
+	}
 
 	if c.clusterSpec.Network.IsHost() {
 		podTemplateSpec.Spec.DNSPolicy = v1.DNSClusterFirstWithHostNet
@@ -164,11 +188,17 @@ func (c *clusterConfig) makeDaemonContainer(rgwConfig *rgwConfig) v1.Container {
 			controller.DaemonVolumeMounts(c.DataPathMap, rgwConfig.ResourceName),
 			c.mimeTypesVolumeMount(),
 		),
-		Env:             controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image),
+		Env:             append(append(controller.DaemonEnvVars(c.clusterSpec.CephVersion.Image), c.kmsEnvVars()...), c.authEnvVars()...),
 		Resources:       c.store.Spec.Gateway.Resources,
 		LivenessProbe:   c.generateLiveProbe(),
 		SecurityContext: mon.PodSecurityContext(),
 	}
+	if ingress := c.store.Spec.Gateway.Ingress; ingress != nil {
+		// the buckets are also served on the subdomains of the host
+		container.Args = append(container.Args, cephconfig.NewFlag("rgw dns name", ingress.Host))
+	}
+	container.Args = append(append(container.Args, c.kmsFlags()...), c.authFlags()...)
+	container.VolumeMounts = append(append(container.VolumeMounts, c.kmsVolumeMounts()...), c.authVolumeMounts()...)
 
 	// If the liveness probe is enabled
 	configureLivenessProbe(&container, c.store.Spec.HealthCheck)
@@ -266,10 +296,37 @@ func (c *clusterConfig) generateService(cephObjectStore *cephv1.CephObjectStore)
 	}
 	addPort(svc, "http", cephObjectStore.Spec.Gateway.Port, destPort.IntVal)
 	addPort(svc, "https", cephObjectStore.Spec.Gateway.SecurePort, cephObjectStore.Spec.Gateway.SecurePort)
+	applyServiceSpec(svc, cephObjectStore.Spec.Gateway.Service)
 
 	return svc
 }
 
+// applyServiceSpec applies the settings of the spec of the object store to its service
+func applyServiceSpec(svc *v1.Service, spec *cephv1.RGWServiceSpec) {
+	if spec == nil {
+		return
+	}
+	if spec.Type != "" {
+		svc.Spec.Type = spec.Type
+	}
+	if len(spec.Annotations) > 0 {
+		svc.Annotations = map[string]string{}
+		for key, value := range spec.Annotations {
+			svc.Annotations[key] = value
+		}
+	}
+	svc.Spec.LoadBalancerIP = spec.LoadBalancerIP
+	svc.Spec.ExternalTrafficPolicy = spec.ExternalTrafficPolicy
+	for i := range svc.Spec.Ports {
+		switch svc.Spec.Ports[i].Name {
+		case "http":
+			svc.Spec.Ports[i].NodePort = spec.NodePort
+		case "https":
+			svc.Spec.Ports[i].NodePort = spec.SecureNodePort
+		}
+	}
+}
+
 func (c *clusterConfig) generateEndpoint(cephObjectStore *cephv1.CephObjectStore) *v1.Endpoints {
 	labels := getLabels(cephObjectStore.Name, cephObjectStore.Namespace, true)
 
@@ -325,6 +382,10 @@ func (c *clusterConfig) reconcileService(cephObjectStore *cephv1.CephObjectStore
 
 	logger.Infof("ceph object store gateway service running at %s", svc.Spec.ClusterIP)
 
+	if err := c.reconcileIngress(cephObjectStore); err != nil {
+		return "", errors.Wrapf(err, "failed to reconcile object store %q ingress", cephObjectStore.Name)
+	}
+
 	return svc.Spec.ClusterIP, nil
 }
 
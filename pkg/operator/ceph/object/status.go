@@ -59,7 +59,7 @@ func updateStatus(client client.Client, namespacedName types.NamespacedName, sta
 }
 
 // updateStatusBucket updates an object with a given status
-func updateStatusBucket(client client.Client, name types.NamespacedName, phase cephv1.ConditionType, details string) {
+func updateStatusBucket(client client.Client, name types.NamespacedName, phase cephv1.ConditionType, details string, probes []cephv1.ObjectProbeStatus) {
 	objectStore := &cephv1.CephObjectStore{}
 	if err := client.Get(context.TODO(), name, objectStore); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -73,6 +73,7 @@ func updateStatusBucket(client client.Client, name types.NamespacedName, phase c
 		objectStore.Status = &cephv1.ObjectStoreStatus{}
 	}
 	objectStore.Status.BucketStatus = toCustomResourceStatus(objectStore.Status.BucketStatus, details, phase)
+	objectStore.Status.BucketStatus.Probes = probes
 	objectStore.Status.Phase = phase
 	if err := opcontroller.UpdateStatus(client, objectStore); err != nil {
 		logger.Errorf("failed to set object store %q status to %v. %v", name, phase, err)
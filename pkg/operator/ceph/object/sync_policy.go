@@ -0,0 +1,404 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const (
+	syncFlowSymmetrical = "symmetrical"
+	syncFlowDirectional = "directional"
+	syncAll             = "*"
+)
+
+var syncGroupStatuses = map[string]bool{"enabled": true, "allowed": true, "forbidden": true}
+
+// syncPolicyType is the output of `radosgw-admin sync policy get`
+type syncPolicyType struct {
+	Groups []struct {
+		ID       string `json:"id"`
+		Status   string `json:"status"`
+		DataFlow struct {
+			Symmetrical []struct {
+				ID    string   `json:"id"`
+				Zones []string `json:"zones"`
+			} `json:"symmetrical"`
+			Directional []struct {
+				ID         string `json:"id"`
+				SourceZone string `json:"source_zone"`
+				DestZone   string `json:"dest_zone"`
+			} `json:"directional"`
+		} `json:"data_flow"`
+		Pipes []struct {
+			ID     string               `json:"id"`
+			Source syncBucketEntityType `json:"source"`
+			Dest   syncBucketEntityType `json:"dest"`
+			Params struct {
+				Source struct {
+					Filter struct {
+						Prefix string `json:"prefix"`
+					} `json:"filter"`
+				} `json:"source"`
+			} `json:"params"`
+		} `json:"pipes"`
+	} `json:"groups"`
+}
+
+type syncBucketEntityType struct {
+	Bucket string   `json:"bucket"`
+	Zones  []string `json:"zones"`
+}
+
+// ValidateSyncPolicy validates the groups, flows and pipes of a sync policy
+func ValidateSyncPolicy(policy *cephv1.ObjectSyncPolicySpec) error {
+	if policy == nil {
+		return nil
+	}
+	groups := map[string]bool{}
+	for _, group := range policy.Groups {
+		if group.ID == "" {
+			return errors.New("missing sync group id")
+		}
+		key := group.Bucket + "/" + group.ID
+		if groups[key] {
+			return errors.Errorf("duplicate sync group %q", group.ID)
+		}
+		groups[key] = true
+		if !syncGroupStatuses[group.Status] {
+			return errors.Errorf("invalid status %q of sync group %q, expected enabled, allowed or forbidden", group.Status, group.ID)
+		}
+
+		flows := map[string]bool{}
+		for _, flow := range group.Flows {
+			if flow.ID == "" || flows[flow.ID] {
+				return errors.Errorf("missing or duplicate flow id in sync group %q", group.ID)
+			}
+			flows[flow.ID] = true
+			switch flow.Type {
+			case syncFlowSymmetrical:
+				if len(flow.Zones) < 2 {
+					return errors.Errorf("symmetrical flow %q of sync group %q requires at least two zones", flow.ID, group.ID)
+				}
+			case syncFlowDirectional:
+				if flow.SourceZone == "" || flow.DestZone == "" {
+					return errors.Errorf("directional flow %q of sync group %q requires a source zone and a destination zone", flow.ID, group.ID)
+				}
+			default:
+				return errors.Errorf("invalid type %q of flow %q of sync group %q, expected symmetrical or directional", flow.Type, flow.ID, group.ID)
+			}
+		}
+
+		pipes := map[string]bool{}
+		for _, pipe := range group.Pipes {
+			if pipe.ID == "" || pipes[pipe.ID] {
+				return errors.Errorf("missing or duplicate pipe id in sync group %q", group.ID)
+			}
+			pipes[pipe.ID] = true
+		}
+	}
+	return nil
+}
+
+// ReconcileSyncPolicy applies the sync policy groups to the zone group of the context and to the buckets of the groups.
+// The groups that are not in the policy are removed from the zone group and from the buckets of the previous effective
+// policy. The period is committed if the policy of the zone group changed. It returns the effective policy. When the
+// policy was removed from the spec, the groups of the previous policy are removed and no effective policy is returned.
+func ReconcileSyncPolicy(objContext *Context, policy *cephv1.ObjectSyncPolicySpec, previous []cephv1.ObjectSyncGroupSpec) ([]cephv1.ObjectSyncGroupSpec, error) {
+	removed := policy == nil
+	if removed {
+		if len(previous) == 0 {
+			return nil, nil
+		}
+		policy = &cephv1.ObjectSyncPolicySpec{}
+	}
+
+	// the sync policy is changed in the master zone of the zone group
+	masterZone, err := getMasterZone(objContext)
+	if err != nil {
+		return nil, err
+	}
+	objContext.Zone = masterZone
+
+	// the zone group policy is first, followed by the buckets of the policy and of the previous policy
+	buckets := []string{""}
+	desired := map[string][]cephv1.ObjectSyncGroupSpec{"": nil}
+	for _, group := range policy.Groups {
+		if _, ok := desired[group.Bucket]; !ok {
+			buckets = append(buckets, group.Bucket)
+		}
+		desired[group.Bucket] = append(desired[group.Bucket], normalizeSyncGroup(group))
+	}
+	for _, group := range previous {
+		if _, ok := desired[group.Bucket]; !ok {
+			buckets = append(buckets, group.Bucket)
+			desired[group.Bucket] = nil
+		}
+	}
+
+	effective := []cephv1.ObjectSyncGroupSpec{}
+	for _, bucket := range buckets {
+		changed, err := reconcileSyncGroups(objContext, bucket, desired[bucket])
+		if err != nil {
+			return nil, err
+		}
+		if bucket == "" && changed {
+			if output, err := runAdminCommand(objContext, "period", "update", "--commit"); err != nil {
+				return nil, errors.Wrapf(err, "failed to update period after changing the sync policy of zone group %q. %s", objContext.ZoneGroup, output)
+			}
+			logger.Infof("updated sync policy of zone group %q", objContext.ZoneGroup)
+		}
+
+		groups, err := getSyncPolicy(objContext, bucket)
+		if err != nil {
+			return nil, err
+		}
+		effective = append(effective, groups...)
+	}
+	if removed {
+		logger.Infof("removed sync policy of zone group %q", objContext.ZoneGroup)
+		return nil, nil
+	}
+	return effective, nil
+}
+
+// reconcileSyncGroups applies the groups to the zone group, or to the bucket if set, and returns whether the
+// policy changed
+func reconcileSyncGroups(objContext *Context, bucket string, desired []cephv1.ObjectSyncGroupSpec) (bool, error) {
+	groups, err := getSyncPolicy(objContext, bucket)
+	if err != nil {
+		return false, err
+	}
+	current := map[string]cephv1.ObjectSyncGroupSpec{}
+	for _, group := range groups {
+		current[group.ID] = group
+	}
+
+	changed := false
+	run := func(args ...string) error {
+		if bucket != "" {
+			args = append(args, fmt.Sprintf("--bucket=%s", bucket))
+		}
+		if output, err := runAdminCommand(objContext, args...); err != nil {
+			return errors.Wrapf(err, "failed to run %q. %s", strings.Join(args, " "), output)
+		}
+		changed = true
+		return nil
+	}
+
+	for _, group := range desired {
+		existing, ok := current[group.ID]
+		delete(current, group.ID)
+		if ok && reflect.DeepEqual(existing, group) {
+			continue
+		}
+		groupArg := fmt.Sprintf("--group-id=%s", group.ID)
+		if !ok {
+			if err := run("sync", "group", "create", groupArg, fmt.Sprintf("--status=%s", group.Status)); err != nil {
+				return false, err
+			}
+		} else if existing.Status != group.Status {
+			if err := run("sync", "group", "modify", groupArg, fmt.Sprintf("--status=%s", group.Status)); err != nil {
+				return false, err
+			}
+		}
+
+		// the flows and pipes that changed are removed and created again
+		existingFlows := map[string]cephv1.ObjectSyncFlowSpec{}
+		for _, flow := range existing.Flows {
+			existingFlows[flow.ID] = flow
+		}
+		for _, flow := range group.Flows {
+			existingFlow, ok := existingFlows[flow.ID]
+			delete(existingFlows, flow.ID)
+			if ok && reflect.DeepEqual(existingFlow, flow) {
+				continue
+			}
+			if ok {
+				if err := run(syncFlowArgs("remove", groupArg, existingFlow)...); err != nil {
+					return false, err
+				}
+			}
+			if err := run(syncFlowArgs("create", groupArg, flow)...); err != nil {
+				return false, err
+			}
+		}
+		for _, flow := range existingFlows {
+			if err := run(syncFlowArgs("remove", groupArg, flow)...); err != nil {
+				return false, err
+			}
+		}
+
+		existingPipes := map[string]cephv1.ObjectSyncPipeSpec{}
+		for _, pipe := range existing.Pipes {
+			existingPipes[pipe.ID] = pipe
+		}
+		for _, pipe := range group.Pipes {
+			existingPipe, ok := existingPipes[pipe.ID]
+			delete(existingPipes, pipe.ID)
+			if ok && reflect.DeepEqual(existingPipe, pipe) {
+				continue
+			}
+			pipeArg := fmt.Sprintf("--pipe-id=%s", pipe.ID)
+			if ok {
+				if err := run("sync", "group", "pipe", "remove", groupArg, pipeArg); err != nil {
+					return false, err
+				}
+			}
+			args := []string{"sync", "group", "pipe", "create", groupArg, pipeArg,
+				fmt.Sprintf("--source-zones=%s", strings.Join(pipe.SourceZones, ",")),
+				fmt.Sprintf("--source-bucket=%s", pipe.SourceBucket),
+				fmt.Sprintf("--dest-zones=%s", strings.Join(pipe.DestZones, ",")),
+				fmt.Sprintf("--dest-bucket=%s", pipe.DestBucket),
+			}
+			if pipe.Prefix != "" {
+				args = append(args, fmt.Sprintf("--prefix=%s", pipe.Prefix))
+			}
+			if err := run(args...); err != nil {
+				return false, err
+			}
+		}
+		for id := range existingPipes {
+			if err := run("sync", "group", "pipe", "remove", groupArg, fmt.Sprintf("--pipe-id=%s", id)); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	for id := range current {
+		if err := run("sync", "group", "remove", fmt.Sprintf("--group-id=%s", id)); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+func syncFlowArgs(action, groupArg string, flow cephv1.ObjectSyncFlowSpec) []string {
+	args := []string{"sync", "group", "flow", action, groupArg, fmt.Sprintf("--flow-id=%s", flow.ID), fmt.Sprintf("--flow-type=%s", flow.Type)}
+	if flow.Type == syncFlowSymmetrical {
+		// a symmetrical flow is removed entirely when no zones are given
+		if action == "create" {
+			args = append(args, fmt.Sprintf("--zones=%s", strings.Join(flow.Zones, ",")))
+		}
+		return args
+	}
+	return append(args, fmt.Sprintf("--source-zone=%s", flow.SourceZone), fmt.Sprintf("--dest-zone=%s", flow.DestZone))
+}
+
+// getSyncPolicy returns the sync policy groups of the zone group, or of the bucket if set
+func getSyncPolicy(objContext *Context, bucket string) ([]cephv1.ObjectSyncGroupSpec, error) {
+	args := []string{"sync", "policy", "get"}
+	if bucket != "" {
+		args = append(args, fmt.Sprintf("--bucket=%s", bucket))
+	}
+	output, err := runAdminCommand(objContext, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sync policy of %s. %s", syncPolicyScope(objContext, bucket), output)
+	}
+	var policy syncPolicyType
+	if err := json.Unmarshal([]byte(output), &policy); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse sync policy of %s", syncPolicyScope(objContext, bucket))
+	}
+
+	groups := []cephv1.ObjectSyncGroupSpec{}
+	for _, g := range policy.Groups {
+		group := cephv1.ObjectSyncGroupSpec{ID: g.ID, Bucket: bucket, Status: g.Status}
+		for _, flow := range g.DataFlow.Symmetrical {
+			group.Flows = append(group.Flows, cephv1.ObjectSyncFlowSpec{ID: flow.ID, Type: syncFlowSymmetrical, Zones: flow.Zones})
+		}
+		for _, flow := range g.DataFlow.Directional {
+			group.Flows = append(group.Flows, cephv1.ObjectSyncFlowSpec{ID: flow.ID, Type: syncFlowDirectional, SourceZone: flow.SourceZone, DestZone: flow.DestZone})
+		}
+		for _, pipe := range g.Pipes {
+			group.Pipes = append(group.Pipes, cephv1.ObjectSyncPipeSpec{
+				ID:           pipe.ID,
+				SourceZones:  pipe.Source.Zones,
+				SourceBucket: pipe.Source.Bucket,
+				DestZones:    pipe.Dest.Zones,
+				DestBucket:   pipe.Dest.Bucket,
+				Prefix:       pipe.Params.Source.Filter.Prefix,
+			})
+		}
+		groups = append(groups, normalizeSyncGroup(group))
+	}
+	return groups, nil
+}
+
+// normalizeSyncGroup sets the defaults of a sync group and sorts its flows and pipes to compare it with the policy
+// reported by ceph
+func normalizeSyncGroup(group cephv1.ObjectSyncGroupSpec) cephv1.ObjectSyncGroupSpec {
+	normalized := *group.DeepCopy()
+	for i := range normalized.Flows {
+		sort.Strings(normalized.Flows[i].Zones)
+	}
+	for i := range normalized.Pipes {
+		pipe := &normalized.Pipes[i]
+		pipe.SourceZones = normalizeSyncEntities(pipe.SourceZones)
+		pipe.DestZones = normalizeSyncEntities(pipe.DestZones)
+		if pipe.SourceBucket == "" {
+			pipe.SourceBucket = syncAll
+		}
+		if pipe.DestBucket == "" {
+			pipe.DestBucket = syncAll
+		}
+	}
+	sort.Slice(normalized.Flows, func(i, j int) bool { return normalized.Flows[i].ID < normalized.Flows[j].ID })
+	sort.Slice(normalized.Pipes, func(i, j int) bool { return normalized.Pipes[i].ID < normalized.Pipes[j].ID })
+	return normalized
+}
+
+func normalizeSyncEntities(zones []string) []string {
+	if len(zones) == 0 {
+		return []string{syncAll}
+	}
+	sorted := append([]string{}, zones...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func syncPolicyScope(objContext *Context, bucket string) string {
+	if bucket != "" {
+		return fmt.Sprintf("bucket %q", bucket)
+	}
+	return fmt.Sprintf("zone group %q", objContext.ZoneGroup)
+}
+
+// getMasterZone returns the name of the master zone of the zone group of the context
+func getMasterZone(objContext *Context) (string, error) {
+	output, err := RunAdminCommandNoMultisite(objContext, "zonegroup", "get",
+		fmt.Sprintf("--rgw-realm=%s", objContext.Realm), fmt.Sprintf("--rgw-zonegroup=%s", objContext.ZoneGroup))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get zone group %q", objContext.ZoneGroup)
+	}
+	zoneGroup, err := DecodeZoneGroupConfig(output)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse zone group %q", objContext.ZoneGroup)
+	}
+	for _, zone := range zoneGroup.Zones {
+		if zone.ID == zoneGroup.MasterZoneID {
+			return zone.Name, nil
+		}
+	}
+	return "", errors.Errorf("zone group %q has no master zone yet", objContext.ZoneGroup)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	syncZoneGroupJSON = `{
+	"name": "zonegroup-a",
+	"is_master": "true",
+	"master_zone": "6cb39d2c-3005-49da-9be3-c1a92a97d28a",
+	"zones": [
+		{"id": "6cb39d2c-3005-49da-9be3-c1a92a97d28a", "name": "zone-a", "endpoints": []},
+		{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53", "name": "zone-b", "endpoints": []}
+	]
+}`
+	syncGroupJSON = `{
+		"id": "group1",
+		"data_flow": {
+			"symmetrical": [{"id": "flow1", "zones": ["zone-a", "zone-b"]}]
+		},
+		"pipes": [
+			{
+				"id": "pipe1",
+				"source": {"bucket": "*", "zones": ["*"]},
+				"dest": {"bucket": "*", "zones": ["*"]},
+				"params": {"source": {"filter": {"prefix": "prod-", "tags": []}}, "dest": {}, "priority": 0, "mode": "system", "user": ""}
+			}
+		],
+		"status": "allowed"
+	}`
+	manualSyncGroupJSON = `{"id": "manual", "data_flow": {}, "pipes": [], "status": "forbidden"}`
+)
+
+func syncPolicySpec() *cephv1.ObjectSyncPolicySpec {
+	return &cephv1.ObjectSyncPolicySpec{
+		Groups: []cephv1.ObjectSyncGroupSpec{
+			{
+				ID:     "group1",
+				Status: "allowed",
+				Flows:  []cephv1.ObjectSyncFlowSpec{{ID: "flow1", Type: "symmetrical", Zones: []string{"zone-b", "zone-a"}}},
+				Pipes:  []cephv1.ObjectSyncPipeSpec{{ID: "pipe1", Prefix: "prod-"}},
+			},
+			{
+				ID:     "group2",
+				Bucket: "bucket1",
+				Status: "enabled",
+				Flows:  []cephv1.ObjectSyncFlowSpec{{ID: "flow2", Type: "directional", SourceZone: "zone-a", DestZone: "zone-b"}},
+			},
+		},
+	}
+}
+
+func TestValidateSyncPolicy(t *testing.T) {
+	assert.NoError(t, ValidateSyncPolicy(nil))
+	assert.NoError(t, ValidateSyncPolicy(syncPolicySpec()))
+
+	invalid := []func(policy *cephv1.ObjectSyncPolicySpec){
+		func(policy *cephv1.ObjectSyncPolicySpec) { policy.Groups[0].ID = "" },
+		func(policy *cephv1.ObjectSyncPolicySpec) { policy.Groups[0].Status = "on" },
+		func(policy *cephv1.ObjectSyncPolicySpec) {
+			policy.Groups[1].ID = "group1"
+			policy.Groups[1].Bucket = ""
+		},
+		func(policy *cephv1.ObjectSyncPolicySpec) { policy.Groups[0].Flows[0].Zones = []string{"zone-a"} },
+		func(policy *cephv1.ObjectSyncPolicySpec) { policy.Groups[0].Flows[0].Type = "both" },
+		func(policy *cephv1.ObjectSyncPolicySpec) { policy.Groups[1].Flows[0].DestZone = "" },
+		func(policy *cephv1.ObjectSyncPolicySpec) {
+			policy.Groups[0].Pipes = append(policy.Groups[0].Pipes, cephv1.ObjectSyncPipeSpec{ID: "pipe1"})
+		},
+	}
+	for i, modify := range invalid {
+		policy := syncPolicySpec()
+		modify(policy)
+		assert.Error(t, ValidateSyncPolicy(policy), i)
+	}
+}
+
+func TestReconcileSyncPolicy(t *testing.T) {
+	policies := map[string]string{"": `{"groups": [` + syncGroupJSON + `, ` + manualSyncGroupJSON + `]}`}
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return syncZoneGroupJSON, nil
+			case args[0] == "sync" && args[1] == "policy":
+				bucket := ""
+				if strings.HasPrefix(args[3], "--bucket=") {
+					bucket = strings.TrimPrefix(args[3], "--bucket=")
+				}
+				if policy, ok := policies[bucket]; ok {
+					return policy, nil
+				}
+				return `{"groups": []}`, nil
+			}
+			commands = append(commands, strings.Join(args, " "))
+			return "", nil
+		},
+	}
+	objContext := NewContext(&clusterd.Context{Executor: executor}, &client.ClusterInfo{Namespace: "mycluster"}, "zonegroup-a")
+	objContext.Realm = "realm-a"
+	objContext.ZoneGroup = "zonegroup-a"
+
+	// the manual group is removed, the bucket group is created
+	_, err := ReconcileSyncPolicy(objContext, syncPolicySpec(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "zone-a", objContext.Zone)
+	assert.Len(t, commands, 4)
+	assert.Contains(t, commands[0], "sync group remove --group-id=manual")
+	assert.Contains(t, commands[1], "period update --commit")
+	assert.Contains(t, commands[2], "sync group create --group-id=group2 --status=enabled --bucket=bucket1")
+	assert.Contains(t, commands[3], "sync group flow create --group-id=group2 --flow-id=flow2 --flow-type=directional --source-zone=zone-a --dest-zone=zone-b --bucket=bucket1")
+
+	// the policy is applied, the effective policy is reported
+	policies[""] = `{"groups": [` + syncGroupJSON + `]}`
+	policies["bucket1"] = `{"groups": [{"id": "group2", "data_flow": {"directional": [{"id": "flow2", "source_zone": "zone-a", "dest_zone": "zone-b"}]}, "pipes": [], "status": "enabled"}]}`
+	commands = []string{}
+	effective, err := ReconcileSyncPolicy(objContext, syncPolicySpec(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+	assert.Equal(t, []cephv1.ObjectSyncGroupSpec{
+		{
+			ID:     "group1",
+			Status: "allowed",
+			Flows:  []cephv1.ObjectSyncFlowSpec{{ID: "flow1", Type: "symmetrical", Zones: []string{"zone-a", "zone-b"}}},
+			Pipes:  []cephv1.ObjectSyncPipeSpec{{ID: "pipe1", SourceZones: []string{"*"}, SourceBucket: "*", DestZones: []string{"*"}, DestBucket: "*", Prefix: "prod-"}},
+		},
+		{
+			ID:     "group2",
+			Bucket: "bucket1",
+			Status: "enabled",
+			Flows:  []cephv1.ObjectSyncFlowSpec{{ID: "flow2", Type: "directional", SourceZone: "zone-a", DestZone: "zone-b"}},
+		},
+	}, effective)
+
+	// the pipe changed and the bucket group is removed from the spec
+	policy := syncPolicySpec()
+	policy.Groups = policy.Groups[:1]
+	policy.Groups[0].Pipes[0].DestZones = []string{"zone-b"}
+	_, err = ReconcileSyncPolicy(objContext, policy, effective)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 4)
+	assert.Contains(t, commands[0], "sync group pipe remove --group-id=group1 --pipe-id=pipe1")
+	assert.Contains(t, commands[1], "sync group pipe create --group-id=group1 --pipe-id=pipe1 --source-zones=* --source-bucket=* --dest-zones=zone-b --dest-bucket=* --prefix=prod-")
+	assert.Contains(t, commands[2], "period update --commit")
+	assert.Contains(t, commands[3], "sync group remove --group-id=group2 --bucket=bucket1")
+
+	// the policy is removed from the spec, the previous groups are removed
+	commands = []string{}
+	effective, err = ReconcileSyncPolicy(objContext, nil, effective)
+	assert.NoError(t, err)
+	assert.Nil(t, effective)
+	assert.Len(t, commands, 3)
+	assert.Contains(t, commands[0], "sync group remove --group-id=group1")
+	assert.Contains(t, commands[1], "period update --commit")
+	assert.Contains(t, commands[2], "sync group remove --group-id=group2 --bucket=bucket1")
+
+	// nothing to do without a previous policy
+	commands = []string{}
+	_, err = ReconcileSyncPolicy(objContext, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+}
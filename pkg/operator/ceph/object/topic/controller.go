@@ -0,0 +1,456 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topic manages the topics receiving the bucket notifications of an object store.
+package topic
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-bucket-topic-controller"
+	// topicUserName is the object store user creating the topics, the topics are shared by all the users of the store
+	topicUserName = "rook-ceph-internal-topic-user"
+	// credentialsUserKey and credentialsPasswordKey are the keys of the secret holding the credentials of an endpoint
+	credentialsUserKey     = "user"
+	credentialsPasswordKey = "password"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var cephBucketTopicKind = reflect.TypeOf(cephv1.CephBucketTopic{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       cephBucketTopicKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileBucketTopic reconciles a CephBucketTopic object
+type ReconcileBucketTopic struct {
+	client             client.Client
+	scheme             *runtime.Scheme
+	context            *clusterd.Context
+	clusterInfo        *cephclient.ClusterInfo
+	credentialsWatches *credentialsWatches
+}
+
+// Add creates a new CephBucketTopic Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context) error {
+	return add(mgr, newReconciler(mgr, context))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context) reconcile.Reconciler {
+	// Add the cephv1 scheme to the manager scheme so that the controller knows about it
+	mgrScheme := mgr.GetScheme()
+	if err := cephv1.AddToScheme(mgr.GetScheme()); err != nil {
+		panic(err)
+	}
+
+	return &ReconcileBucketTopic{
+		client:             mgr.GetClient(),
+		scheme:             mgrScheme,
+		context:            context,
+		credentialsWatches: newCredentialsWatches(mgr.GetClient(), context.Clientset),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephBucketTopic CRD object
+	err = c.Watch(&source.Kind{Type: &cephv1.CephBucketTopic{TypeMeta: controllerTypeMeta}}, &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// The credentials secrets of the endpoints are watched by the reconciler as they are referenced by the topics
+	if reconciler, ok := r.(*ReconcileBucketTopic); ok {
+		reconciler.credentialsWatches.controller = c
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephBucketTopic object and makes changes based on the state read
+// and what is in the CephBucketTopic.Spec
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileBucketTopic) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime loggin interface
+	reconcileResponse, err := r.reconcile(request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %v", err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.Result, error) {
+	// Fetch the CephBucketTopic instance
+	topic := &cephv1.CephBucketTopic{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, topic)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			if err := r.credentialsWatches.update(request.Namespace); err != nil {
+				logger.Warningf("failed to update the watches of the credentials secrets. %v", err)
+			}
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get CephBucketTopic")
+	}
+
+	// Watch the credentials secret of the endpoint so the topic is updated when the credentials change
+	if err := r.credentialsWatches.update(request.Namespace); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to watch the credentials secrets")
+	}
+
+	// The CR was just created, initializing status fields
+	if topic.Status == nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, "", "")
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	_, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.client, r.context, request.NamespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		if !topic.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.client, topic)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.client, topic)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = mon.LoadClusterInfo(r.context, request.NamespacedName.Namespace)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+
+	s3Agent, err := r.topicS3Agent(topic)
+	if err != nil {
+		if !topic.GetDeletionTimestamp().IsZero() {
+			// The object store is gone, so are its topics
+			err = opcontroller.RemoveFinalizer(r.client, topic)
+			if err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		logger.Debugf("object store %q not ready for topic %q, retrying in %q. %v",
+			topic.Spec.ObjectStoreName, request.NamespacedName, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", err.Error())
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// DELETE: the CR was deleted
+	if !topic.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("deleting topic %q", topic.Name)
+		if topic.Status != nil && topic.Status.ARN != "" {
+			if err := s3Agent.DeleteTopic(topic.Status.ARN); err != nil {
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete topic %q", topic.Name)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.client, topic)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	// validate the topic settings
+	if err := validateTopic(topic); err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", err.Error())
+		return reconcile.Result{}, errors.Wrapf(err, "invalid topic CR %q spec", topic.Name)
+	}
+
+	// CREATE/UPDATE TOPIC
+	attributes, err := r.topicAttributes(topic)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", err.Error())
+		return reconcile.Result{}, errors.Wrapf(err, "failed to generate attributes of topic %q", topic.Name)
+	}
+	arn, err := s3Agent.CreateTopic(topic.Name, attributes)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, "", err.Error())
+		return opcontroller.ImmediateRetryResult, err
+	}
+	logger.Infof("topic %q created with arn %q", topic.Name, arn)
+
+	// Set Ready status, we are done reconciling
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, arn, "")
+
+	// Return and do not requeue
+	logger.Debug("done reconciling")
+	return reconcile.Result{}, nil
+}
+
+// topicS3Agent returns an S3 agent of the object store of the topic, authenticated with the user creating the topics
+func (r *ReconcileBucketTopic) topicS3Agent(topic *cephv1.CephBucketTopic) (*object.S3Agent, error) {
+	store := &cephv1.CephObjectStore{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: topic.Spec.ObjectStoreName, Namespace: topic.Namespace}, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object store %q", topic.Spec.ObjectStoreName)
+	}
+	if store.Status == nil || store.Status.Info["endpoint"] == "" {
+		return nil, errors.Errorf("object store %q has no endpoint yet", store.Name)
+	}
+
+	objContext, err := object.NewMultisiteContext(r.context, r.clusterInfo, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to set multisite context of object store %q", store.Name)
+	}
+
+	displayName := topicUserName
+	_, rgwerr, err := object.CreateUser(objContext, object.ObjectUser{UserID: topicUserName, DisplayName: &displayName})
+	if err != nil && rgwerr != object.ErrorCodeFileExists {
+		return nil, errors.Wrapf(err, "failed to create object user %q", topicUserName)
+	}
+
+	return object.NewUserS3Agent(objContext, topicUserName, store.Status.Info["endpoint"])
+}
+
+// topicAttributes returns the attributes of the topic understood by rgw, the credentials of the endpoint are added to
+// its uri
+func (r *ReconcileBucketTopic) topicAttributes(topic *cephv1.CephBucketTopic) (map[string]string, error) {
+	attributes := map[string]string{
+		"persistent": strconv.FormatBool(topic.Spec.Persistent),
+	}
+	if topic.Spec.OpaqueData != "" {
+		attributes["OpaqueData"] = topic.Spec.OpaqueData
+	}
+
+	endpoint := topic.Spec.Endpoint
+	switch {
+	case endpoint.HTTP != nil:
+		attributes["push-endpoint"] = endpoint.HTTP.URI
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.HTTP.DisableVerifySSL)
+		if endpoint.HTTP.SendCloudEvents {
+			attributes["cloudevents"] = "true"
+		}
+
+	case endpoint.AMQP != nil:
+		uri, err := r.uriWithCredentials(topic.Namespace, endpoint.AMQP.URI, endpoint.AMQP.CredentialsSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		attributes["push-endpoint"] = uri
+		attributes["amqp-exchange"] = endpoint.AMQP.Exchange
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.AMQP.DisableVerifySSL)
+		if endpoint.AMQP.AckLevel != "" {
+			attributes["amqp-ack-level"] = endpoint.AMQP.AckLevel
+		}
+
+	case endpoint.Kafka != nil:
+		uri, err := r.uriWithCredentials(topic.Namespace, endpoint.Kafka.URI, endpoint.Kafka.CredentialsSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		attributes["push-endpoint"] = uri
+		attributes["use-ssl"] = strconv.FormatBool(endpoint.Kafka.UseSSL)
+		attributes["verify-ssl"] = strconv.FormatBool(!endpoint.Kafka.DisableVerifySSL)
+		if endpoint.Kafka.AckLevel != "" {
+			attributes["kafka-ack-level"] = endpoint.Kafka.AckLevel
+		}
+	}
+
+	return attributes, nil
+}
+
+// uriWithCredentials adds the user and password of the secret to the uri of an endpoint
+func (r *ReconcileBucketTopic) uriWithCredentials(namespace, uri string, secretRef *corev1.LocalObjectReference) (string, error) {
+	if secretRef == nil {
+		return uri, nil
+	}
+
+	secret, err := r.context.Clientset.CoreV1().Secrets(namespace).Get(secretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get endpoint credentials secret %q", secretRef.Name)
+	}
+	user, ok := secret.Data[credentialsUserKey]
+	if !ok {
+		return "", errors.Errorf("endpoint credentials secret %q has no %q key", secretRef.Name, credentialsUserKey)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse endpoint uri %q", uri)
+	}
+	if password, ok := secret.Data[credentialsPasswordKey]; ok {
+		u.User = url.UserPassword(string(user), string(password))
+	} else {
+		u.User = url.User(string(user))
+	}
+
+	return u.String(), nil
+}
+
+// validateTopic validates the topic arguments
+func validateTopic(topic *cephv1.CephBucketTopic) error {
+	if topic.Spec.ObjectStoreName == "" {
+		return errors.New("missing object store name")
+	}
+
+	endpoint := topic.Spec.Endpoint
+	endpoints := 0
+	if endpoint.HTTP != nil {
+		endpoints++
+		if err := validateURI(endpoint.HTTP.URI, "http", "https"); err != nil {
+			return err
+		}
+	}
+	if endpoint.AMQP != nil {
+		endpoints++
+		if err := validateURI(endpoint.AMQP.URI, "amqp", "amqps"); err != nil {
+			return err
+		}
+		if endpoint.AMQP.Exchange == "" {
+			return errors.New("missing amqp exchange")
+		}
+		// the credentials are sent in the uri, they must not be sent in clear text
+		if endpoint.AMQP.CredentialsSecretRef != nil {
+			if err := validateURI(endpoint.AMQP.URI, "amqps"); err != nil {
+				return errors.Wrap(err, "amqp credentials require an amqps uri")
+			}
+		}
+		if err := validateAckLevel(endpoint.AMQP.AckLevel, "none", "broker", "routable"); err != nil {
+			return err
+		}
+	}
+	if endpoint.Kafka != nil {
+		endpoints++
+		if err := validateURI(endpoint.Kafka.URI, "kafka"); err != nil {
+			return err
+		}
+		if err := validateAckLevel(endpoint.Kafka.AckLevel, "none", "broker"); err != nil {
+			return err
+		}
+		if endpoint.Kafka.CredentialsSecretRef != nil && !endpoint.Kafka.UseSSL {
+			return errors.New("kafka credentials require useSSL")
+		}
+	}
+	if endpoints != 1 {
+		return errors.Errorf("exactly one endpoint must be specified, found %d", endpoints)
+	}
+
+	return nil
+}
+
+func validateURI(uri string, schemes ...string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errors.Wrapf(err, "invalid endpoint uri %q", uri)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme && u.Host != "" {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid endpoint uri %q, expected a %v uri", uri, schemes)
+}
+
+func validateAckLevel(ackLevel string, levels ...string) error {
+	if ackLevel == "" {
+		return nil
+	}
+	for _, level := range levels {
+		if ackLevel == level {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid ack level %q, expected one of %v", ackLevel, levels)
+}
+
+// updateStatus updates a topic with a given status, the arn is kept if empty
+func updateStatus(client client.Client, name types.NamespacedName, status, arn, details string) {
+	topic := &cephv1.CephBucketTopic{}
+	if err := client.Get(context.TODO(), name, topic); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBucketTopic resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve topic %q to update status to %q. %v", name, status, err)
+		return
+	}
+
+	if topic.Status == nil {
+		topic.Status = &cephv1.BucketTopicStatus{}
+	}
+
+	topic.Status.Phase = status
+	if arn != "" {
+		topic.Status.ARN = arn
+	}
+	topic.Status.Details = details
+	if err := opcontroller.UpdateStatus(client, topic); err != nil {
+		logger.Errorf("failed to set topic %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("topic %q status updated to %q", name, status)
+}
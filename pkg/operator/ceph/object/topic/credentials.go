@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topic
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// credentialsSecretName returns the name of the secret holding the credentials of the endpoint of a topic, if any
+func credentialsSecretName(topic *cephv1.CephBucketTopic) string {
+	endpoint := topic.Spec.Endpoint
+	switch {
+	case endpoint.AMQP != nil && endpoint.AMQP.CredentialsSecretRef != nil:
+		return endpoint.AMQP.CredentialsSecretRef.Name
+	case endpoint.Kafka != nil && endpoint.Kafka.CredentialsSecretRef != nil:
+		return endpoint.Kafka.CredentialsSecretRef.Name
+	}
+	return ""
+}
+
+// credentialsWatches watches the credentials secrets referenced by the topics so the topics are updated with the new
+// credentials. The secrets are created by the user and not owned by the topics, each secret has its own informer
+// restricted to its name so that the other secrets of the cluster are not cached.
+type credentialsWatches struct {
+	mutex      sync.Mutex
+	controller controller.Controller
+	client     client.Client
+	clientset  kubernetes.Interface
+	// stopChans stops the informers of the watched secrets
+	stopChans map[types.NamespacedName]chan struct{}
+}
+
+func newCredentialsWatches(client client.Client, clientset kubernetes.Interface) *credentialsWatches {
+	return &credentialsWatches{
+		client:    client,
+		clientset: clientset,
+		stopChans: map[types.NamespacedName]chan struct{}{},
+	}
+}
+
+// update watches the credentials secrets referenced by the topics of the namespace and stops watching the secrets
+// that are not referenced anymore
+func (w *credentialsWatches) update(namespace string) error {
+	if w == nil || w.controller == nil {
+		return nil
+	}
+	topics := &cephv1.CephBucketTopicList{}
+	if err := w.client.List(context.TODO(), topics, client.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "failed to list topics in namespace %q", namespace)
+	}
+	referenced := map[types.NamespacedName]bool{}
+	for i := range topics.Items {
+		topic := &topics.Items[i]
+		if name := credentialsSecretName(topic); name != "" && topic.GetDeletionTimestamp().IsZero() {
+			referenced[types.NamespacedName{Name: name, Namespace: namespace}] = true
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for secret, stopChan := range w.stopChans {
+		if secret.Namespace == namespace && !referenced[secret] {
+			logger.Debugf("stopping the watch of credentials secret %q", secret)
+			close(stopChan)
+			delete(w.stopChans, secret)
+		}
+	}
+	for secret := range referenced {
+		if _, ok := w.stopChans[secret]; ok {
+			continue
+		}
+		stopChan := make(chan struct{})
+		informer := w.newSecretInformer(secret)
+		err := w.controller.Watch(&source.Informer{Informer: informer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(func(obj handler.MapObject) []reconcile.Request {
+				return credentialsSecretRequests(w.client, obj.Meta.GetNamespace(), obj.Meta.GetName())
+			}),
+		}, credentialsSecretPredicate())
+		if err != nil {
+			return errors.Wrapf(err, "failed to watch credentials secret %q", secret)
+		}
+		go informer.Run(stopChan)
+		w.stopChans[secret] = stopChan
+		logger.Debugf("watching credentials secret %q", secret)
+	}
+	return nil
+}
+
+// newSecretInformer returns an informer of a single secret
+func (w *credentialsWatches) newSecretInformer(secret types.NamespacedName) cache.SharedIndexInformer {
+	selector := fields.OneTermEqualSelector("metadata.name", secret.Name).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return w.clientset.CoreV1().Secrets(secret.Namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return w.clientset.CoreV1().Secrets(secret.Namespace).Watch(options)
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &corev1.Secret{}, 0, cache.Indexers{})
+}
+
+// credentialsSecretRequests returns the requests of the topics whose endpoint uses the credentials of a secret
+func credentialsSecretRequests(c client.Client, namespace, secretName string) []reconcile.Request {
+	topics := &cephv1.CephBucketTopicList{}
+	if err := c.List(context.TODO(), topics, client.InNamespace(namespace)); err != nil {
+		logger.Errorf("failed to list topics of credentials secret %q. %v", secretName, err)
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for i := range topics.Items {
+		topic := &topics.Items[i]
+		if credentialsSecretName(topic) == secretName {
+			logger.Infof("credentials secret %q of topic %q changed, reconciling", secretName, topic.Name)
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: topic.Name, Namespace: namespace}})
+		}
+	}
+	return requests
+}
+
+// credentialsSecretPredicate reconciles the topics when a secret is created or its content changes
+func credentialsSecretPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topic
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// fakeController records the sources of the watches
+type fakeController struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *fakeController) Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
+func amqpTopic(name, secretName string) *cephv1.CephBucketTopic {
+	return &cephv1.CephBucketTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+		Spec: cephv1.BucketTopicSpec{
+			ObjectStoreName: "my-store",
+			Endpoint: cephv1.TopicEndpointSpec{
+				AMQP: &cephv1.AMQPEndpointSpec{
+					URI:                  "amqps://rabbitmq.default.svc:5671",
+					Exchange:             "ex",
+					CredentialsSecretRef: &corev1.LocalObjectReference{Name: secretName},
+				},
+			},
+		},
+	}
+}
+
+func TestCredentialsWatches(t *testing.T) {
+	topic := amqpTopic("my-topic", "amqp-credentials")
+	other := amqpTopic("other-topic", "amqp-credentials")
+	noCredentials := amqpTopic("no-credentials", "")
+	noCredentials.Spec.Endpoint.AMQP.CredentialsSecretRef = nil
+	cl := fakeclient.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{topic, other, noCredentials}...)
+	c := &fakeController{}
+	w := newCredentialsWatches(cl, fake.NewSimpleClientset())
+
+	// nothing is watched before the controller is set
+	assert.NoError(t, w.update("rook-ceph"))
+	assert.Empty(t, w.stopChans)
+
+	// the secret referenced by both topics is watched once
+	w.controller = c
+	assert.NoError(t, w.update("rook-ceph"))
+	assert.NoError(t, w.update("rook-ceph"))
+	assert.Len(t, c.sources, 1)
+	secret := types.NamespacedName{Name: "amqp-credentials", Namespace: "rook-ceph"}
+	assert.Contains(t, w.stopChans, secret)
+
+	// both topics are reconciled when the secret changes
+	requests := credentialsSecretRequests(cl, "rook-ceph", "amqp-credentials")
+	assert.Len(t, requests, 2)
+	assert.Empty(t, credentialsSecretRequests(cl, "rook-ceph", "other-secret"))
+
+	// the secret is watched until no topic references it
+	assert.NoError(t, cl.Delete(context.TODO(), topic))
+	assert.NoError(t, w.update("rook-ceph"))
+	assert.Contains(t, w.stopChans, secret)
+	assert.NoError(t, cl.Delete(context.TODO(), other))
+	assert.NoError(t, w.update("rook-ceph"))
+	assert.Empty(t, w.stopChans)
+}
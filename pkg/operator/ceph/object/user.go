@@ -43,6 +43,27 @@ type ObjectUser struct {
 	AccessKey   *string `json:"accessKey"`
 	SecretKey   *string `json:"secretKey"`
 	SystemUser  bool    `json:"systemuser"`
+	// Keys are all the s3 keys of the user, AccessKey and SecretKey are the first one
+	Keys []ObjectUserKey `json:"keys,omitempty"`
+	// Caps are the admin capabilities of the user by type
+	Caps map[string]string `json:"caps,omitempty"`
+	// SubUsers are the permissions of the subusers of the user by subuser id
+	SubUsers map[string]string `json:"subUsers,omitempty"`
+	// SwiftKeys are the swift secret keys by subuser id
+	SwiftKeys map[string]string `json:"swiftKeys,omitempty"`
+}
+
+// ObjectUserKey is an s3 key of an object store user
+type ObjectUserKey struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// ObjectUserStats is the usage of an object store user
+type ObjectUserStats struct {
+	Buckets int
+	Size    int64
+	Objects int64
 }
 
 // ListUsers lists the object pool users.
@@ -68,6 +89,19 @@ type rgwUserInfo struct {
 		AccessKey string `json:"access_key"`
 		SecretKey string `json:"secret_key"`
 	}
+	SwiftKeys []struct {
+		User      string `json:"user"`
+		SecretKey string `json:"secret_key"`
+	} `json:"swift_keys"`
+	SubUsers []struct {
+		ID          string `json:"id"`
+		Permissions string `json:"permissions"`
+	} `json:"subusers"`
+	Caps []struct {
+		Type string `json:"type"`
+		Perm string `json:"perm"`
+	} `json:"caps"`
+	UserQuota rgwQuota `json:"user_quota"`
 }
 
 func decodeUser(data string) (*ObjectUser, int, error) {
@@ -85,6 +119,22 @@ func decodeUser(data string) (*ObjectUser, int, error) {
 	} else {
 		return nil, RGWErrorBadData, errors.New("AccessKey and SecretKey are missing")
 	}
+	for _, k := range user.Keys {
+		rookUser.Keys = append(rookUser.Keys, ObjectUserKey{AccessKey: k.AccessKey, SecretKey: k.SecretKey})
+	}
+
+	rookUser.Caps = map[string]string{}
+	for _, c := range user.Caps {
+		rookUser.Caps[c.Type] = c.Perm
+	}
+	rookUser.SubUsers = map[string]string{}
+	for _, u := range user.SubUsers {
+		rookUser.SubUsers[u.ID] = u.Permissions
+	}
+	rookUser.SwiftKeys = map[string]string{}
+	for _, k := range user.SwiftKeys {
+		rookUser.SwiftKeys[k.User] = k.SecretKey
+	}
 
 	return &rookUser, RGWErrorNone, nil
 }
@@ -257,3 +307,129 @@ func SetQuotaUserMaxSize(c *Context, id string, maxsize string) (string, error)
 	}
 	return result, err
 }
+
+// SetQuota sets and enables the quota of a user or of each of its buckets, the scope is "user" or "bucket". A
+// negative limit is unlimited.
+func SetQuota(c *Context, id, scope string, maxSize, maxObjects int64) error {
+	logger.Debugf("setting %s quota of user %q to max size %d and max objects %d", scope, id, maxSize, maxObjects)
+	args := []string{"--quota-scope", scope, "--max-size", strconv.FormatInt(maxSize, 10), "--max-objects", strconv.FormatInt(maxObjects, 10)}
+	if _, err := setUserQuota(c, id, args); err != nil {
+		return errors.Wrapf(err, "failed to set %s quota of user %q", scope, id)
+	}
+	if _, err := runAdminCommand(c, "quota", "enable", "--quota-scope", scope, "--uid", id); err != nil {
+		return errors.Wrapf(err, "failed to enable %s quota of user %q", scope, id)
+	}
+	return nil
+}
+
+// DisableQuota disables the quota of a user or of each of its buckets
+func DisableQuota(c *Context, id, scope string) error {
+	logger.Debugf("disabling %s quota of user %q", scope, id)
+	if _, err := runAdminCommand(c, "quota", "disable", "--quota-scope", scope, "--uid", id); err != nil {
+		return errors.Wrapf(err, "failed to disable %s quota of user %q", scope, id)
+	}
+	return nil
+}
+
+// AddUserCaps adds admin capabilities to a user, e.g. "users=read;buckets=*"
+func AddUserCaps(c *Context, id, caps string) error {
+	logger.Infof("adding caps %q to user %q", caps, id)
+	if _, err := runAdminCommand(c, "caps", "add", "--uid", id, "--caps", caps); err != nil {
+		return errors.Wrapf(err, "failed to add caps %q to user %q", caps, id)
+	}
+	return nil
+}
+
+// RemoveUserCaps removes admin capabilities from a user
+func RemoveUserCaps(c *Context, id, caps string) error {
+	logger.Infof("removing caps %q from user %q", caps, id)
+	if _, err := runAdminCommand(c, "caps", "rm", "--uid", id, "--caps", caps); err != nil {
+		return errors.Wrapf(err, "failed to remove caps %q from user %q", caps, id)
+	}
+	return nil
+}
+
+// CreateSubUser creates a swift subuser of a user with a generated secret, the access is read, write, readwrite or full
+func CreateSubUser(c *Context, id, subUser, access string) error {
+	logger.Infof("creating subuser %q of user %q", subUser, id)
+	_, err := runAdminCommand(c, "subuser", "create", "--uid", id, "--subuser", subUser, "--access", access, "--key-type", "swift", "--gen-secret")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// ModifySubUser changes the access of a subuser
+func ModifySubUser(c *Context, id, subUser, access string) error {
+	logger.Infof("setting access of subuser %q of user %q to %q", subUser, id, access)
+	if _, err := runAdminCommand(c, "subuser", "modify", "--uid", id, "--subuser", subUser, "--access", access); err != nil {
+		return errors.Wrapf(err, "failed to modify subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// DeleteSubUser removes a subuser and its keys
+func DeleteSubUser(c *Context, id, subUser string) error {
+	logger.Infof("removing subuser %q of user %q", subUser, id)
+	if _, err := runAdminCommand(c, "subuser", "rm", "--uid", id, "--subuser", subUser, "--purge-keys"); err != nil {
+		return errors.Wrapf(err, "failed to remove subuser %q of user %q", subUser, id)
+	}
+	return nil
+}
+
+// CreateUserKey generates a new s3 key for the user and returns the user with all its keys
+func CreateUserKey(c *Context, id string) (*ObjectUser, error) {
+	logger.Infof("creating a new s3 key for user %q", id)
+	result, err := runAdminCommand(c, "key", "create", "--uid", id, "--key-type", "s3", "--gen-access-key", "--gen-secret")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create s3 key of user %q", id)
+	}
+	user, _, err := decodeUser(result)
+	return user, err
+}
+
+// DeleteUserKey removes an s3 key of the user
+func DeleteUserKey(c *Context, id, accessKey string) error {
+	logger.Infof("removing s3 key %q of user %q", accessKey, id)
+	if _, err := runAdminCommand(c, "key", "rm", "--uid", id, "--key-type", "s3", "--access-key", accessKey); err != nil {
+		return errors.Wrapf(err, "failed to remove s3 key %q of user %q", accessKey, id)
+	}
+	return nil
+}
+
+// GetUserStats returns the usage of the user
+func GetUserStats(c *Context, id string) (*ObjectUserStats, error) {
+	result, err := runAdminCommand(c, "user", "stats", "--uid", id, "--sync-stats")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get stats of user %q", id)
+	}
+	// the fields of the stats were renamed in octopus
+	var info struct {
+		Stats struct {
+			Size         int64 `json:"size"`
+			NumObjects   int64 `json:"num_objects"`
+			TotalBytes   int64 `json:"total_bytes"`
+			TotalEntries int64 `json:"total_entries"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(result), &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal stats of user %q. %s", id, result)
+	}
+	stats := &ObjectUserStats{Size: info.Stats.Size, Objects: info.Stats.NumObjects}
+	if stats.Size == 0 && stats.Objects == 0 {
+		stats.Size = info.Stats.TotalBytes
+		stats.Objects = info.Stats.TotalEntries
+	}
+
+	result, err = runAdminCommand(c, "bucket", "list", "--uid", id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list buckets of user %q", id)
+	}
+	var buckets []string
+	if err := json.Unmarshal([]byte(result), &buckets); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal buckets of user %q. %s", id, result)
+	}
+	stats.Buckets = len(buckets)
+
+	return stats, nil
+}
@@ -69,6 +69,7 @@ type ReconcileObjectStoreUser struct {
 	context         *clusterd.Context
 	objContext      *object.Context
 	userConfig      object.ObjectUser
+	objectUser      *object.ObjectUser
 	cephClusterSpec *cephv1.ClusterSpec
 	clusterInfo     *cephclient.ClusterInfo
 }
@@ -149,7 +150,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 
 	// The CR was just created, initializing status fields
 	if cephObjectStoreUser.Status == nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.Created)
+		updateStatus(r.client, request.NamespacedName, k8sutil.Created, "")
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -202,7 +203,7 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 		}
 		logger.Debugf("ObjectStore resource not ready in namespace %q, retrying in %q. %v",
 			request.NamespacedName.Namespace, opcontroller.WaitForRequeueIfCephClusterNotReady.RequeueAfter.String(), err)
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
 		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
 	}
 
@@ -231,67 +232,85 @@ func (r *ReconcileObjectStoreUser) reconcile(request reconcile.Request) (reconci
 	// validate the user settings
 	err = r.validateUser(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
 		return reconcile.Result{}, errors.Wrapf(err, "invalid pool CR %q spec", cephObjectStoreUser.Name)
 	}
 
 	// CREATE/UPDATE CEPH USER
-	reconcileResponse, err = r.reconcileCephUser(cephObjectStoreUser)
+	created, err := r.createorUpdateCephUser(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
-		return reconcileResponse, err
+		err = errors.Wrapf(err, "failed to create/update object store user %q", cephObjectStoreUser.Name)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// QUOTAS, CAPS AND SUBUSERS
+	err = r.reconcileUserSettings(cephObjectStoreUser)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
+		return reconcile.Result{}, err
+	}
+
+	// ROTATE KEYS
+	requeueAfter, err := r.reconcileKeyRotation(cephObjectStoreUser, created)
+	if err != nil {
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
+		return reconcile.Result{}, err
 	}
 
 	// CREATE/UPDATE KUBERNETES SECRET
 	reconcileResponse, err = r.reconcileCephUserSecret(cephObjectStoreUser)
 	if err != nil {
-		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus)
+		updateStatus(r.client, request.NamespacedName, k8sutil.ReconcileFailedStatus, err.Error())
 		return reconcileResponse, err
 	}
 
+	// The usage is only informative, don't fail the reconcile if it is not available
+	if err := r.updateUsage(cephObjectStoreUser); err != nil {
+		logger.Warningf("failed to get usage of object store user %q. %v", cephObjectStoreUser.Name, err)
+	}
+
 	// Set Ready status, we are done reconciling
-	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
+	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus, "")
 
-	// Return and do not requeue
+	// Requeue to refresh the usage and to remove the previous key at the end of the rotation grace period. The
+	// user is reconciled again each time, its unchanged settings are only logged at debug level.
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
-}
-
-func (r *ReconcileObjectStoreUser) reconcileCephUser(cephObjectStoreUser *cephv1.CephObjectStoreUser) (reconcile.Result, error) {
-	err := r.createorUpdateCephUser(cephObjectStoreUser)
-	if err != nil {
-		return reconcile.Result{}, errors.Wrapf(err, "failed to create/update object store user %q", cephObjectStoreUser.Name)
+	if requeueAfter == 0 || requeueAfter > usageRefreshInterval {
+		requeueAfter = usageRefreshInterval
 	}
-
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectStoreUser) error {
-	logger.Infof("creating ceph object user %q in namespace %q", u.Name, u.Namespace)
+// createorUpdateCephUser creates the ceph user or updates it, it returns whether the user was created
+func (r *ReconcileObjectStoreUser) createorUpdateCephUser(u *cephv1.CephObjectStoreUser) (bool, error) {
+	logger.Debugf("creating ceph object user %q in namespace %q", u.Name, u.Namespace)
 	user, rgwerr, err := object.CreateUser(r.objContext, r.userConfig)
 	if err != nil {
 		if rgwerr == object.ErrorCodeFileExists {
 			objectUser, _, err := object.UpdateUser(r.objContext, r.userConfig)
 			if err != nil {
-				return errors.Wrapf(err, "failed to get details from ceph object user %q", objectUser.UserID)
+				return false, errors.Wrapf(err, "failed to get details from ceph object user %q", objectUser.UserID)
 			}
 
 			// Set access and secret key
 			r.userConfig.AccessKey = objectUser.AccessKey
 			r.userConfig.SecretKey = objectUser.SecretKey
+			r.objectUser = objectUser
 			logger.Debugf("ceph object user %q updated with display name %q", u.Name, *objectUser.DisplayName)
 
-			return nil
+			return false, nil
 		}
-		return errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
+		return false, errors.Wrapf(err, "failed to create ceph object user %q. error code %d", u.Name, rgwerr)
 	}
 
 	// Set access and secret key
 	r.userConfig.AccessKey = user.AccessKey
 	r.userConfig.SecretKey = user.SecretKey
+	r.objectUser = user
 
 	logger.Infof("created ceph object user %q", u.Name)
-	return nil
+	return true, nil
 }
 
 func (r *ReconcileObjectStoreUser) initializeObjectStoreContext(u *cephv1.CephObjectStoreUser) error {
@@ -348,6 +367,12 @@ func (r *ReconcileObjectStoreUser) generateCephUserSecret(u *cephv1.CephObjectSt
 		"SecretKey": *r.userConfig.SecretKey,
 		"Endpoint":  r.objContext.Endpoint,
 	}
+	// The swift keys of the subusers
+	for _, subUser := range u.Spec.SubUsers {
+		if key, ok := r.objectUser.SwiftKeys[subUserID(u.Name, subUser.Name)]; ok {
+			secrets[swiftKeyPrefix+subUser.Name] = key
+		}
+	}
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      generateCephUserSecretName(u),
@@ -381,7 +406,7 @@ func (r *ReconcileObjectStoreUser) reconcileCephUserSecret(cephObjectStoreUser *
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph object user %q secret", secret.Name)
 	}
 
-	logger.Infof("created ceph object user secret %q", secret.Name)
+	logger.Debugf("created ceph object user secret %q", secret.Name)
 	return reconcile.Result{}, nil
 }
 
@@ -427,7 +452,7 @@ func (r *ReconcileObjectStoreUser) getObjectStore(storeName string) (*cephv1.Cep
 
 	for _, store := range objectStores.Items {
 		if store.Name == storeName {
-			logger.Infof("CephObjectStore %q found", storeName)
+			logger.Debugf("CephObjectStore %q found", storeName)
 			return &store, nil
 		}
 	}
@@ -480,7 +505,7 @@ func (r *ReconcileObjectStoreUser) validateUser(u *cephv1.CephObjectStoreUser) e
 			return errors.New("missing store")
 		}
 	}
-	return nil
+	return validateUserSettings(u)
 }
 
 func labelsForRgw(name string) map[string]string {
@@ -488,7 +513,7 @@ func labelsForRgw(name string) map[string]string {
 }
 
 // updateStatus updates an object with a given status
-func updateStatus(client client.Client, name types.NamespacedName, status string) {
+func updateStatus(client client.Client, name types.NamespacedName, status, details string) {
 	user := &cephv1.CephObjectStoreUser{}
 	if err := client.Get(context.TODO(), name, user); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -503,6 +528,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 
 	user.Status.Phase = status
+	user.Status.Details = details
 	if user.Status.Phase == k8sutil.ReadyStatus {
 		user.Status.Info = generateStatusInfo(user)
 	}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// keyRotationAnnotation rotates the s3 keys of the user when its value changes to a non-empty value
+	keyRotationAnnotation = opcontroller.ObjectUserKeyRotationAnnotation
+	// defaultKeyRotationGracePeriod is how long the previous key remains valid after a rotation if not set in the spec
+	defaultKeyRotationGracePeriod = 24 * time.Hour
+)
+
+// keyRotationGracePeriod returns the grace period of the key rotation of the user
+func keyRotationGracePeriod(u *cephv1.CephObjectStoreUser) (time.Duration, error) {
+	if u.Spec.KeyRotationGracePeriod == "" {
+		return defaultKeyRotationGracePeriod, nil
+	}
+	gracePeriod, err := time.ParseDuration(u.Spec.KeyRotationGracePeriod)
+	if err != nil || gracePeriod < 0 {
+		return 0, errors.Errorf("invalid keyRotationGracePeriod %q", u.Spec.KeyRotationGracePeriod)
+	}
+	return gracePeriod, nil
+}
+
+// reconcileKeyRotation creates a new s3 key for the user when the rotation annotation changes and removes the
+// previous key at the end of the grace period. The active key is stored in the user config to be written in the user
+// secret. It returns how long until the previous key must be removed.
+func (r *ReconcileObjectStoreUser) reconcileKeyRotation(u *cephv1.CephObjectStoreUser, created bool) (time.Duration, error) {
+	nsName := types.NamespacedName{Name: u.Name, Namespace: u.Namespace}
+	rotation := &cephv1.ObjectUserKeyRotationStatus{}
+	if u.Status != nil && u.Status.KeyRotation != nil {
+		rotation = u.Status.KeyRotation.DeepCopy()
+	}
+	previous := rotation.DeepCopy()
+	annotation := u.Annotations[keyRotationAnnotation]
+
+	if created {
+		// the keys of a new user are fresh
+		rotation = &cephv1.ObjectUserKeyRotationStatus{LastRotation: annotation}
+	} else if annotation != "" && annotation != rotation.LastRotation {
+		// removing the annotation is not a rotation request
+		if err := r.rotateKeys(u, nsName, rotation, annotation); err != nil {
+			return 0, err
+		}
+		// persist the rotation right away so the new key is not lost if the reconcile fails later on
+		if err := updateStatusKeyRotation(r.client, nsName, rotation); err != nil {
+			return 0, err
+		}
+		previous = rotation.DeepCopy()
+	}
+
+	var requeueAfter time.Duration
+	if rotation.RetiredAccessKey != "" {
+		retireTime, err := time.Parse(time.RFC3339, rotation.RetireTime)
+		if err == nil && time.Now().Before(retireTime) {
+			requeueAfter = time.Until(retireTime)
+		} else {
+			if err := r.retireKey(u.Name, rotation.RetiredAccessKey); err != nil {
+				return 0, err
+			}
+			rotation.RetiredAccessKey = ""
+			rotation.RetireTime = ""
+		}
+	}
+
+	key := activeKey(r.objectUser, rotation.AccessKey)
+	if key != nil {
+		rotation.AccessKey = key.AccessKey
+		r.userConfig.AccessKey = &key.AccessKey
+		r.userConfig.SecretKey = &key.SecretKey
+	}
+
+	if !reflect.DeepEqual(rotation, previous) {
+		if err := updateStatusKeyRotation(r.client, nsName, rotation); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// rotateKeys creates a new s3 key for the user, the active key is retired at the end of the grace period. The rotation
+// is recorded as pending before the key is created, so that a retried rotation reuses the key of an interrupted one.
+func (r *ReconcileObjectStoreUser) rotateKeys(u *cephv1.CephObjectStoreUser, nsName types.NamespacedName, rotation *cephv1.ObjectUserKeyRotationStatus, annotation string) error {
+	gracePeriod, err := keyRotationGracePeriod(u)
+	if err != nil {
+		return err
+	}
+
+	// a user keeps at most two keys, a key still in its grace period is removed by a new rotation
+	if rotation.RetiredAccessKey != "" {
+		if err := r.retireKey(u.Name, rotation.RetiredAccessKey); err != nil {
+			return err
+		}
+	}
+	previousKey := activeKey(r.objectUser, rotation.AccessKey)
+
+	var newKey *object.ObjectUserKey
+	if rotation.PendingRotation == annotation {
+		newKey = pendingKey(r.objectUser, previousKey)
+	}
+	if newKey == nil {
+		rotation.PendingRotation = annotation
+		if err := updateStatusKeyRotation(r.client, nsName, rotation); err != nil {
+			return err
+		}
+		user, err := object.CreateUserKey(r.objContext, u.Name)
+		if err != nil {
+			return err
+		}
+		newKey = newUserKey(r.objectUser, user)
+		if newKey == nil {
+			return errors.Errorf("failed to find the new s3 key of user %q", u.Name)
+		}
+		r.objectUser = user
+	}
+	logger.Infof("rotated s3 keys of object store user %q", u.Name)
+
+	now := time.Now().UTC()
+	rotation.AccessKey = newKey.AccessKey
+	rotation.LastRotation = annotation
+	rotation.RotationTime = now.Format(time.RFC3339)
+	rotation.RetiredAccessKey = ""
+	rotation.RetireTime = ""
+	rotation.PendingRotation = ""
+	if previousKey != nil {
+		rotation.RetiredAccessKey = previousKey.AccessKey
+		rotation.RetireTime = now.Add(gracePeriod).Format(time.RFC3339)
+	}
+	return nil
+}
+
+// retireKey removes the s3 key of the user if it still exists
+func (r *ReconcileObjectStoreUser) retireKey(id, accessKey string) error {
+	for _, key := range r.objectUser.Keys {
+		if key.AccessKey != accessKey {
+			continue
+		}
+		if err := object.DeleteUserKey(r.objContext, id, accessKey); err != nil {
+			return err
+		}
+		break
+	}
+
+	keys := []object.ObjectUserKey{}
+	for _, key := range r.objectUser.Keys {
+		if key.AccessKey != accessKey {
+			keys = append(keys, key)
+		}
+	}
+	r.objectUser.Keys = keys
+	return nil
+}
+
+// activeKey returns the s3 key of the user with the access key, or its first key if not found
+func activeKey(user *object.ObjectUser, accessKey string) *object.ObjectUserKey {
+	if len(user.Keys) == 0 {
+		return nil
+	}
+	for i, key := range user.Keys {
+		if key.AccessKey == accessKey {
+			return &user.Keys[i]
+		}
+	}
+	return &user.Keys[0]
+}
+
+// pendingKey returns the s3 key created by an interrupted rotation, the key of the user other than the active key
+func pendingKey(user *object.ObjectUser, activeKey *object.ObjectUserKey) *object.ObjectUserKey {
+	if activeKey == nil {
+		return nil
+	}
+	for i, key := range user.Keys {
+		if key.AccessKey != activeKey.AccessKey {
+			return &user.Keys[i]
+		}
+	}
+	return nil
+}
+
+// newUserKey returns the s3 key of the user that did not exist before
+func newUserKey(before, after *object.ObjectUser) *object.ObjectUserKey {
+	existing := map[string]bool{}
+	for _, key := range before.Keys {
+		existing[key.AccessKey] = true
+	}
+	for i, key := range after.Keys {
+		if !existing[key.AccessKey] {
+			return &after.Keys[i]
+		}
+	}
+	return nil
+}
+
+// updateStatusKeyRotation updates the key rotation of the user status, the rotation is retried if it cannot be recorded
+func updateStatusKeyRotation(client client.Client, name types.NamespacedName, rotation *cephv1.ObjectUserKeyRotationStatus) error {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := client.Get(context.TODO(), name, user); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectStoreUser resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve object store user %q to update key rotation", name)
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+
+	user.Status.KeyRotation = rotation.DeepCopy()
+	if err := opcontroller.UpdateStatus(client, user); err != nil {
+		return errors.Wrapf(err, "failed to set object store user %q key rotation", name)
+	}
+	logger.Debugf("object store user %q key rotation updated", name)
+	return nil
+}
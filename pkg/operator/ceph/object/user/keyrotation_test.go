@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const userRotatedJSON = `{
+	"user_id": "my-user",
+	"display_name": "my-user",
+	"keys": [
+		{"user": "my-user", "access_key": "EOE7FYCNOBZJ5VFV909G", "secret_key": "qmIqpWm8HxCzmynCrD6U6vKWi4hnDBndOnmxXNsV"},
+		{"user": "my-user", "access_key": "NEWACCESSKEY", "secret_key": "NEWSECRETKEY"}
+	]
+}`
+
+func TestReconcileKeyRotation(t *testing.T) {
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store, KeyRotationGracePeriod: "1h"},
+	}
+	s := runtime.NewScheme()
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectStoreUser{})
+	nsName := types.NamespacedName{Name: name, Namespace: namespace}
+
+	commands := []string{}
+	r := newSettingsReconciler(&commands, func(args ...string) string {
+		if args[0] == "key" && args[1] == "create" {
+			return userRotatedJSON
+		}
+		return ""
+	})
+	r.client = fake.NewFakeClientWithScheme(s, u)
+	firstKey := object.ObjectUserKey{AccessKey: "EOE7FYCNOBZJ5VFV909G", SecretKey: "qmIqpWm8HxCzmynCrD6U6vKWi4hnDBndOnmxXNsV"}
+	r.objectUser = &object.ObjectUser{Keys: []object.ObjectUserKey{firstKey}}
+
+	// a new user is not rotated
+	u.Annotations = map[string]string{keyRotationAnnotation: "1"}
+	requeueAfter, err := r.reconcileKeyRotation(u, true)
+	assert.NoError(t, err)
+	assert.Zero(t, requeueAfter)
+	assert.Empty(t, commands)
+	assert.Equal(t, firstKey.AccessKey, *r.userConfig.AccessKey)
+	err = r.client.Get(context.TODO(), nsName, u)
+	assert.NoError(t, err)
+	assert.Equal(t, &cephv1.ObjectUserKeyRotationStatus{AccessKey: firstKey.AccessKey, LastRotation: "1"}, u.Status.KeyRotation)
+
+	// the annotation did not change
+	requeueAfter, err = r.reconcileKeyRotation(u, false)
+	assert.NoError(t, err)
+	assert.Zero(t, requeueAfter)
+	assert.Empty(t, commands)
+
+	// rotate the keys, the previous key is kept during the grace period
+	u.Annotations[keyRotationAnnotation] = "2"
+	requeueAfter, err = r.reconcileKeyRotation(u, false)
+	assert.NoError(t, err)
+	assert.True(t, requeueAfter > 59*time.Minute && requeueAfter <= time.Hour, requeueAfter)
+	assert.Equal(t, []string{"key create --uid my-user --key-type s3 --gen-access-key --gen-secret"}, trimCommands(commands))
+	assert.Equal(t, "NEWACCESSKEY", *r.userConfig.AccessKey)
+	assert.Equal(t, "NEWSECRETKEY", *r.userConfig.SecretKey)
+	err = r.client.Get(context.TODO(), nsName, u)
+	assert.NoError(t, err)
+	assert.Equal(t, "NEWACCESSKEY", u.Status.KeyRotation.AccessKey)
+	assert.Equal(t, "2", u.Status.KeyRotation.LastRotation)
+	assert.Equal(t, firstKey.AccessKey, u.Status.KeyRotation.RetiredAccessKey)
+	assert.Empty(t, u.Status.KeyRotation.PendingRotation)
+
+	// the previous key is removed at the end of the grace period
+	commands = []string{}
+	u.Status.KeyRotation.RetireTime = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	requeueAfter, err = r.reconcileKeyRotation(u, false)
+	assert.NoError(t, err)
+	assert.Zero(t, requeueAfter)
+	assert.Equal(t, []string{"key rm --uid my-user --key-type s3 --access-key EOE7FYCNOBZJ5VFV909G"}, trimCommands(commands))
+	assert.Equal(t, "NEWACCESSKEY", *r.userConfig.AccessKey)
+	updated := &cephv1.CephObjectStoreUser{}
+	err = r.client.Get(context.TODO(), nsName, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "NEWACCESSKEY", updated.Status.KeyRotation.AccessKey)
+	assert.Empty(t, updated.Status.KeyRotation.RetiredAccessKey)
+	assert.Empty(t, updated.Status.KeyRotation.RetireTime)
+
+	// removing the annotation does not rotate the keys
+	commands = []string{}
+	u = updated
+	delete(u.Annotations, keyRotationAnnotation)
+	_, err = r.reconcileKeyRotation(u, false)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+
+	// a retried rotation reuses the key created by the interrupted rotation
+	commands = []string{}
+	secondKey := object.ObjectUserKey{AccessKey: "NEWACCESSKEY", SecretKey: "NEWSECRETKEY"}
+	r.objectUser = &object.ObjectUser{Keys: []object.ObjectUserKey{secondKey, firstKey}}
+	u.Annotations = map[string]string{keyRotationAnnotation: "3"}
+	u.Status.KeyRotation.PendingRotation = "3"
+	_, err = r.reconcileKeyRotation(u, false)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+	assert.Equal(t, firstKey.AccessKey, *r.userConfig.AccessKey)
+	updated = &cephv1.CephObjectStoreUser{}
+	err = r.client.Get(context.TODO(), nsName, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, firstKey.AccessKey, updated.Status.KeyRotation.AccessKey)
+	assert.Equal(t, "NEWACCESSKEY", updated.Status.KeyRotation.RetiredAccessKey)
+	assert.Equal(t, "3", updated.Status.KeyRotation.LastRotation)
+	assert.Empty(t, updated.Status.KeyRotation.PendingRotation)
+}
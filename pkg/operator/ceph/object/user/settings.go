@@ -0,0 +1,265 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// usageRefreshInterval is how often the usage of the user is refreshed in its status
+	usageRefreshInterval = 5 * time.Minute
+	// swiftKeyPrefix is the prefix of the keys of the swift secret keys of the subusers in the user secret
+	swiftKeyPrefix = "SwiftKey-"
+
+	quotaScopeUser   = "user"
+	quotaScopeBucket = "bucket"
+	// capAll is how rgw reports a "read, write" capability
+	capAll = "*"
+)
+
+// subUserAccess maps the access of a subuser in the spec to the permissions reported by rgw
+var subUserAccess = map[string]string{
+	"read":      "read",
+	"write":     "write",
+	"readwrite": "read-write",
+	"full":      "full-control",
+}
+
+var subUserNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// reconcileUserSettings applies the max buckets, the quotas, the caps and the subusers of the spec to the user
+func (r *ReconcileObjectStoreUser) reconcileUserSettings(u *cephv1.CephObjectStoreUser) error {
+	if u.Spec.MaxBuckets != nil {
+		if _, err := object.SetQuotaUserBucketMax(r.objContext, u.Name, *u.Spec.MaxBuckets); err != nil {
+			return errors.Wrapf(err, "failed to set max buckets of user %q", u.Name)
+		}
+	}
+	if u.Spec.Quotas != nil {
+		if err := r.reconcileQuota(u.Name, quotaScopeUser, u.Spec.Quotas); err != nil {
+			return err
+		}
+	}
+	if u.Spec.BucketQuotas != nil {
+		if err := r.reconcileQuota(u.Name, quotaScopeBucket, u.Spec.BucketQuotas); err != nil {
+			return err
+		}
+	}
+	if u.Spec.Capabilities != nil {
+		if err := r.reconcileCaps(u.Name, u.Spec.Capabilities); err != nil {
+			return err
+		}
+	}
+	return r.reconcileSubUsers(u.Name, u.Spec.SubUsers)
+}
+
+// reconcileQuota sets the quota of the user or of its buckets, a quota without limit is disabled
+func (r *ReconcileObjectStoreUser) reconcileQuota(id, scope string, quota *cephv1.ObjectQuotaSpec) error {
+	if quota.MaxSize == nil && quota.MaxObjects == nil {
+		return object.DisableQuota(r.objContext, id, scope)
+	}
+
+	maxSize, maxObjects := int64(-1), int64(-1)
+	if quota.MaxSize != nil {
+		maxSize = quota.MaxSize.Value()
+	}
+	if quota.MaxObjects != nil {
+		maxObjects = *quota.MaxObjects
+	}
+	return object.SetQuota(r.objContext, id, scope, maxSize, maxObjects)
+}
+
+// reconcileCaps sets the caps of the user, an empty capability is removed
+func (r *ReconcileObjectStoreUser) reconcileCaps(id string, caps *cephv1.ObjectUserCapSpec) error {
+	desired := map[string]string{
+		"users":    caps.User,
+		"buckets":  caps.Bucket,
+		"metadata": caps.Metadata,
+		"usage":    caps.Usage,
+	}
+	for capType, perm := range desired {
+		current := normalizeCap(r.objectUser.Caps[capType])
+		perm = normalizeCap(perm)
+		if current == perm {
+			continue
+		}
+		if current != "" {
+			if err := object.RemoveUserCaps(r.objContext, id, fmt.Sprintf("%s=%s", capType, current)); err != nil {
+				return err
+			}
+		}
+		if perm != "" {
+			if err := object.AddUserCaps(r.objContext, id, fmt.Sprintf("%s=%s", capType, perm)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeCap returns a capability as rgw reports it
+func normalizeCap(perm string) string {
+	switch strings.Replace(perm, " ", "", -1) {
+	case "read,write", "write,read", capAll:
+		return capAll
+	}
+	return strings.TrimSpace(perm)
+}
+
+// reconcileSubUsers creates the subusers of the spec, updates their access and removes the other subusers
+func (r *ReconcileObjectStoreUser) reconcileSubUsers(id string, subUsers []cephv1.ObjectUserSubUserSpec) error {
+	changed := false
+	desired := map[string]bool{}
+	for _, subUser := range subUsers {
+		subID := subUserID(id, subUser.Name)
+		desired[subID] = true
+		perm, ok := r.objectUser.SubUsers[subID]
+		if !ok {
+			if err := object.CreateSubUser(r.objContext, id, subID, subUser.Access); err != nil {
+				return err
+			}
+			changed = true
+			continue
+		}
+		if perm != subUserAccess[subUser.Access] {
+			if err := object.ModifySubUser(r.objContext, id, subID, subUser.Access); err != nil {
+				return err
+			}
+		}
+	}
+	for subID := range r.objectUser.SubUsers {
+		if desired[subID] {
+			continue
+		}
+		if err := object.DeleteSubUser(r.objContext, id, subID); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	// refresh the swift keys of the subusers
+	user, _, err := object.GetUser(r.objContext, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get user %q", id)
+	}
+	r.objectUser.SubUsers = user.SubUsers
+	r.objectUser.SwiftKeys = user.SwiftKeys
+	return nil
+}
+
+func subUserID(id, name string) string {
+	return fmt.Sprintf("%s:%s", id, name)
+}
+
+// validateUserSettings validates the quotas, the caps, the subusers and the key rotation settings of the user
+func validateUserSettings(u *cephv1.CephObjectStoreUser) error {
+	if u.Spec.MaxBuckets != nil && *u.Spec.MaxBuckets < 0 {
+		return errors.New("maxBuckets cannot be negative")
+	}
+	for _, quota := range []*cephv1.ObjectQuotaSpec{u.Spec.Quotas, u.Spec.BucketQuotas} {
+		if quota == nil {
+			continue
+		}
+		if quota.MaxSize != nil && quota.MaxSize.Value() < 0 {
+			return errors.Errorf("invalid quota max size %q", quota.MaxSize.String())
+		}
+		if quota.MaxObjects != nil && *quota.MaxObjects < 0 {
+			return errors.Errorf("invalid quota max objects %d", *quota.MaxObjects)
+		}
+	}
+	if caps := u.Spec.Capabilities; caps != nil {
+		for _, perm := range []string{caps.User, caps.Bucket, caps.Metadata, caps.Usage} {
+			switch normalizeCap(perm) {
+			case "", "read", "write", capAll:
+			default:
+				return errors.Errorf("invalid capability %q, expected \"*\", \"read\", \"write\" or \"read, write\"", perm)
+			}
+		}
+	}
+	names := map[string]bool{}
+	for _, subUser := range u.Spec.SubUsers {
+		if !subUserNameRegex.MatchString(subUser.Name) {
+			return errors.Errorf("invalid subuser name %q", subUser.Name)
+		}
+		if names[subUser.Name] {
+			return errors.Errorf("duplicate subuser %q", subUser.Name)
+		}
+		names[subUser.Name] = true
+		if _, ok := subUserAccess[subUser.Access]; !ok {
+			return errors.Errorf("invalid access %q of subuser %q, expected read, write, readwrite or full", subUser.Access, subUser.Name)
+		}
+	}
+	if _, err := keyRotationGracePeriod(u); err != nil {
+		return err
+	}
+	return nil
+}
+
+// updateUsage refreshes the usage of the user in its status
+func (r *ReconcileObjectStoreUser) updateUsage(u *cephv1.CephObjectStoreUser) error {
+	stats, err := object.GetUserStats(r.objContext, u.Name)
+	if err != nil {
+		return err
+	}
+
+	usage := &cephv1.ObjectUserUsageStatus{
+		Buckets:     stats.Buckets,
+		SizeBytes:   stats.Size,
+		Objects:     stats.Objects,
+		LastChecked: time.Now().UTC().Format(time.RFC3339),
+	}
+	updateStatusUsage(r.client, types.NamespacedName{Name: u.Name, Namespace: u.Namespace}, usage)
+	return nil
+}
+
+// updateStatusUsage updates the usage of the user status
+func updateStatusUsage(client client.Client, name types.NamespacedName, usage *cephv1.ObjectUserUsageStatus) {
+	user := &cephv1.CephObjectStoreUser{}
+	if err := client.Get(context.TODO(), name, user); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectStoreUser resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object store user %q to update usage. %v", name, err)
+		return
+	}
+	if user.Status == nil {
+		user.Status = &cephv1.ObjectStoreUserStatus{}
+	}
+
+	user.Status.Usage = usage
+	if err := opcontroller.UpdateStatus(client, user); err != nil {
+		logger.Errorf("failed to set object store user %q usage. %v", name, err)
+		return
+	}
+	logger.Debugf("object store user %q usage updated", name)
+}
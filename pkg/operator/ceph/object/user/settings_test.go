@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectuser
+
+import (
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newSettingsReconciler returns a reconciler recording the radosgw-admin commands it runs
+func newSettingsReconciler(commands *[]string, output func(args ...string) string) *ReconcileObjectStoreUser {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			*commands = append(*commands, strings.Join(args, " "))
+			return output(args...), nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor}
+	return &ReconcileObjectStoreUser{
+		context:    c,
+		objContext: object.NewContext(c, &client.ClusterInfo{Namespace: namespace}, store),
+	}
+}
+
+func TestValidateUserSettings(t *testing.T) {
+	u := &cephv1.CephObjectStoreUser{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cephv1.ObjectStoreUserSpec{Store: store},
+	}
+	assert.NoError(t, validateUserSettings(u))
+
+	maxSize := resource.MustParse("10Gi")
+	maxObjects := int64(1000)
+	maxBuckets := 10
+	u.Spec.MaxBuckets = &maxBuckets
+	u.Spec.Quotas = &cephv1.ObjectQuotaSpec{MaxSize: &maxSize, MaxObjects: &maxObjects}
+	u.Spec.BucketQuotas = &cephv1.ObjectQuotaSpec{}
+	u.Spec.Capabilities = &cephv1.ObjectUserCapSpec{User: "read", Bucket: "read, write", Usage: "*"}
+	u.Spec.SubUsers = []cephv1.ObjectUserSubUserSpec{{Name: "swift", Access: "full"}, {Name: "reader", Access: "read"}}
+	u.Spec.KeyRotationGracePeriod = "1h"
+	assert.NoError(t, validateUserSettings(u))
+
+	invalid := []func(u *cephv1.CephObjectStoreUser){
+		func(u *cephv1.CephObjectStoreUser) { negative := -1; u.Spec.MaxBuckets = &negative },
+		func(u *cephv1.CephObjectStoreUser) { negative := int64(-1); u.Spec.BucketQuotas.MaxObjects = &negative },
+		func(u *cephv1.CephObjectStoreUser) { u.Spec.Capabilities.Metadata = "admin" },
+		func(u *cephv1.CephObjectStoreUser) { u.Spec.SubUsers[0].Name = "my:subuser" },
+		func(u *cephv1.CephObjectStoreUser) { u.Spec.SubUsers[1].Name = "swift" },
+		func(u *cephv1.CephObjectStoreUser) { u.Spec.SubUsers[0].Access = "read-write" },
+		func(u *cephv1.CephObjectStoreUser) { u.Spec.KeyRotationGracePeriod = "1 day" },
+	}
+	for i, modify := range invalid {
+		user := u.DeepCopy()
+		modify(user)
+		assert.Error(t, validateUserSettings(user), i)
+	}
+}
+
+func TestReconcileCaps(t *testing.T) {
+	commands := []string{}
+	r := newSettingsReconciler(&commands, func(args ...string) string { return "" })
+	r.objectUser = &object.ObjectUser{Caps: map[string]string{"users": "*", "buckets": "read", "usage": "read"}}
+
+	caps := &cephv1.ObjectUserCapSpec{User: "read, write", Bucket: "write", Metadata: "read"}
+	err := r.reconcileCaps(name, caps)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"caps rm --uid my-user --caps buckets=read",
+		"caps add --uid my-user --caps buckets=write",
+		"caps add --uid my-user --caps metadata=read",
+		"caps rm --uid my-user --caps usage=read",
+	}, trimCommands(commands))
+}
+
+func TestReconcileSubUsers(t *testing.T) {
+	commands := []string{}
+	r := newSettingsReconciler(&commands, func(args ...string) string { return userCreateJSON })
+	r.objectUser = &object.ObjectUser{SubUsers: map[string]string{"my-user:swift": "read", "my-user:old": "full-control"}}
+
+	subUsers := []cephv1.ObjectUserSubUserSpec{{Name: "swift", Access: "full"}, {Name: "new", Access: "readwrite"}}
+	err := r.reconcileSubUsers(name, subUsers)
+	assert.NoError(t, err)
+	commands = trimCommands(commands)
+	assert.Len(t, commands, 4)
+	assert.Contains(t, commands, "subuser modify --uid my-user --subuser my-user:swift --access full")
+	assert.Contains(t, commands, "subuser create --uid my-user --subuser my-user:new --access readwrite --key-type swift --gen-secret")
+	assert.Contains(t, commands, "subuser rm --uid my-user --subuser my-user:old --purge-keys")
+	// the user is refreshed to get the swift keys of the new subuser
+	assert.Equal(t, "user info --uid my-user", commands[3])
+
+	// nothing to do
+	commands = []string{}
+	r.objectUser = &object.ObjectUser{SubUsers: map[string]string{"my-user:swift": "full-control"}}
+	err = r.reconcileSubUsers(name, subUsers[:1])
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+}
+
+// trimCommands removes the global flags of the radosgw-admin commands
+func trimCommands(commands []string) []string {
+	trimmed := []string{}
+	for _, command := range commands {
+		args := []string{}
+		for _, arg := range strings.Split(command, " ") {
+			if strings.HasPrefix(arg, "--cluster=") || strings.HasPrefix(arg, "--conf=") || strings.HasPrefix(arg, "--name=") || strings.HasPrefix(arg, "--keyring=") {
+				continue
+			}
+			args = append(args, arg)
+		}
+		trimmed = append(trimmed, strings.Join(args, " "))
+	}
+	return trimmed
+}
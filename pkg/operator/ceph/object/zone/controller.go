@@ -54,6 +54,9 @@ const (
 
 var waitForRequeueIfObjectZoneGroupNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
 
+// syncStatusRefreshInterval is how often the sync status of the zone is refreshed in its status
+var syncStatusRefreshInterval = time.Minute
+
 var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
 
 var cephObjectZoneKind = reflect.TypeOf(cephv1.CephObjectZone{}).Name()
@@ -195,12 +198,95 @@ func (r *ReconcileObjectZone) reconcile(request reconcile.Request) (reconcile.Re
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone", err)
 	}
 
+	objContext := object.NewContext(r.context, r.clusterInfo, cephObjectZone.Name)
+	objContext.Realm = realmName
+	objContext.ZoneGroup = cephObjectZone.Spec.ZoneGroup
+	objContext.Zone = cephObjectZone.Name
+
+	// Promote the zone to master if requested
+	if err := r.reconcileMasterPromotion(objContext, cephObjectZone); err != nil {
+		return r.setFailedStatus(request.NamespacedName, "failed to promote ceph zone", err)
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
-	// Return and do not requeue
+	// Report the replication status, it is refreshed periodically
+	r.reportMultisiteStatus(objContext, request.NamespacedName)
+
 	logger.Debug("zone done reconciling")
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: syncStatusRefreshInterval}, nil
+}
+
+// reconcileMasterPromotion promotes the zone to master when master is set in its spec. The zone is only promoted once:
+// if another zone of the zone group is promoted later, for example by the operator of another cluster, the zone is not
+// promoted again until master is unset and set again. Otherwise two zones setting master would promote each other at
+// every reconcile.
+func (r *ReconcileObjectZone) reconcileMasterPromotion(objContext *object.Context, zone *cephv1.CephObjectZone) error {
+	name := types.NamespacedName{Name: zone.Name, Namespace: zone.Namespace}
+	promoted := zone.Status != nil && zone.Status.MasterPromoted
+	if !zone.Spec.Master {
+		if promoted {
+			return updateStatusMasterPromoted(r.client, name, false)
+		}
+		return nil
+	}
+	if promoted {
+		logger.Debugf("zone %q was already promoted to master", name)
+		return nil
+	}
+
+	promoted, err := object.PromoteZone(objContext)
+	if err != nil {
+		return err
+	}
+	if promoted {
+		logger.Infof("restart the rgw deployments of the object stores of zone %q if they don't use the new period", name)
+	}
+	return updateStatusMasterPromoted(r.client, name, true)
+}
+
+// reportMultisiteStatus updates the master and sync status of the zone, a failure to get them is not fatal
+func (r *ReconcileObjectZone) reportMultisiteStatus(objContext *object.Context, name types.NamespacedName) {
+	zoneIsMaster, zoneGroupIsMaster, err := object.GetZoneMasterStatus(objContext)
+	if err != nil {
+		logger.Warningf("failed to get master status of zone %q. %v", name, err)
+		return
+	}
+	syncStatus, err := object.GetZoneSyncStatus(objContext)
+	if err != nil {
+		logger.Warningf("failed to get sync status of zone %q. %v", name, err)
+		return
+	}
+	if len(syncStatus.Metadata.Errors) > 0 {
+		logger.Warningf("metadata sync errors in zone %q. %v", name, syncStatus.Metadata.Errors)
+	}
+	for _, data := range syncStatus.Data {
+		if len(data.Errors) > 0 {
+			logger.Warningf("data sync errors from zone %q in zone %q. %v", data.Source, name, data.Errors)
+		}
+	}
+
+	objectZone := &cephv1.CephObjectZone{}
+	if err := r.client.Get(context.TODO(), name, objectZone); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephObjectZone resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve object zone %q to update sync status. %v", name, err)
+		return
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
+	}
+	objectZone.Status.Master = zoneIsMaster
+	objectZone.Status.MasterZoneGroup = zoneGroupIsMaster
+	objectZone.Status.SyncStatus = syncStatus
+	if err := opcontroller.UpdateStatus(r.client, objectZone); err != nil {
+		logger.Errorf("failed to update sync status of object zone %q. %v", name, err)
+		return
+	}
+	logger.Debugf("object zone %q sync status updated", name)
 }
 
 func (r *ReconcileObjectZone) createCephZone(zone *cephv1.CephObjectZone, realmName string) (reconcile.Result, error) {
@@ -231,6 +317,12 @@ func (r *ReconcileObjectZone) createCephZone(zone *cephv1.CephObjectZone, realmN
 	_, err = object.RunAdminCommandNoMultisite(objContext, "zone", "get", realmArg, zoneGroupArg, zoneArg)
 	if err == nil {
 		logger.Debugf("ceph zone %q already exists, new zone and pools will not be created", zone.Name)
+		// the tier type of a zone cannot be changed once it has data
+		for _, z := range zoneGroupJson.Zones {
+			if z.Name == zone.Name && (z.TierType == object.ArchiveTierType) != zone.Spec.Archive {
+				return reconcile.Result{}, errors.Errorf("the archive setting of existing ceph zone %q cannot be changed", zone.Name)
+			}
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -241,6 +333,9 @@ func (r *ReconcileObjectZone) createCephZone(zone *cephv1.CephObjectZone, realmN
 		if zoneGroupJson.MasterZoneID == "" {
 			zoneIsMaster = true
 		}
+		if zoneIsMaster && zone.Spec.Archive {
+			return reconcile.Result{}, errors.Errorf("archive zone %q cannot be the master zone of zone group %q, the master zone must be created first", zone.Name, zone.Spec.ZoneGroup)
+		}
 
 		err = r.createPoolsAndZone(objContext, zone, realmName, zoneIsMaster)
 		if err != nil {
@@ -260,7 +355,7 @@ func (r *ReconcileObjectZone) createPoolsAndZone(objContext *object.Context, zon
 	zoneGroupArg := fmt.Sprintf("--rgw-zonegroup=%s", zone.Spec.ZoneGroup)
 	zoneArg := fmt.Sprintf("--rgw-zone=%s", zone.Name)
 
-	err := object.CreatePools(objContext, zone.Spec.MetadataPool, zone.Spec.DataPool)
+	err := object.CreatePools(objContext, zone.Spec.MetadataPool, zone.Spec.DataPool, nil)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create pools for zone %v", zone.Name)
 	}
@@ -276,6 +371,9 @@ func (r *ReconcileObjectZone) createPoolsAndZone(objContext *object.Context, zon
 		// master zone does not exist yet for zone group
 		args = append(args, "--master")
 	}
+	if zone.Spec.Archive {
+		args = append(args, fmt.Sprintf("--tier-type=%s", object.ArchiveTierType))
+	}
 
 	output, err := object.RunAdminCommandNoMultisite(objContext, args...)
 	if err != nil {
@@ -330,6 +428,20 @@ func (r *ReconcileObjectZone) validateZoneCR(z *cephv1.CephObjectZone) error {
 	if z.Spec.ZoneGroup == "" {
 		return errors.New("missing zonegroup")
 	}
+	if z.Spec.Archive && z.Spec.Master {
+		return errors.New("an archive zone cannot be the master zone")
+	}
+	if z.Spec.Master {
+		zones := &cephv1.CephObjectZoneList{}
+		if err := r.client.List(context.TODO(), zones, client.InNamespace(z.Namespace)); err != nil {
+			return errors.Wrap(err, "failed to list the object zones")
+		}
+		for _, other := range zones.Items {
+			if other.Name != z.Name && other.Spec.ZoneGroup == z.Spec.ZoneGroup && other.Spec.Master && other.DeletionTimestamp == nil {
+				return errors.Errorf("zones %q and %q of zone group %q both set master", z.Name, other.Name, z.Spec.ZoneGroup)
+			}
+		}
+	}
 	if err := pool.ValidatePoolSpec(r.context, r.clusterInfo, &z.Spec.MetadataPool); err != nil {
 		return errors.Wrap(err, "invalid metadata pool spec")
 	}
@@ -356,7 +468,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectZone.Status == nil {
-		objectZone.Status = &cephv1.Status{}
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
 	}
 
 	objectZone.Status.Phase = status
@@ -366,3 +478,19 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 	}
 	logger.Debugf("object zone %q status updated to %q", name, status)
 }
+
+// updateStatusMasterPromoted records whether the zone was promoted to master since master was set in its spec
+func updateStatusMasterPromoted(client client.Client, name types.NamespacedName, promoted bool) error {
+	objectZone := &cephv1.CephObjectZone{}
+	if err := client.Get(context.TODO(), name, objectZone); err != nil {
+		return errors.Wrapf(err, "failed to retrieve object zone %q to record its promotion", name)
+	}
+	if objectZone.Status == nil {
+		objectZone.Status = &cephv1.ObjectZoneStatus{}
+	}
+	objectZone.Status.MasterPromoted = promoted
+	if err := opcontroller.UpdateStatus(client, objectZone); err != nil {
+		return errors.Wrapf(err, "failed to record the promotion of object zone %q", name)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/object"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
@@ -358,3 +359,90 @@ func TestCephObjectZoneController(t *testing.T) {
 	err = r.client.Get(context.TODO(), req.NamespacedName, objectZone)
 	assert.NoError(t, err)
 }
+
+func TestValidateArchiveZone(t *testing.T) {
+	r := &ReconcileObjectZone{context: &clusterd.Context{Executor: &exectest.MockExecutor{}}}
+	z := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-b", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a", Archive: true, Master: true},
+	}
+	err := r.validateZoneCR(z)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "archive zone")
+
+	z.Spec.Master = false
+	err = r.validateZoneCR(z)
+	assert.NoError(t, err)
+}
+
+func TestValidateMasterZone(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZone{}, &cephv1.CephObjectZoneList{})
+	zone := func(name, zoneGroup string, master bool) *cephv1.CephObjectZone {
+		return &cephv1.CephObjectZone{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			Spec:       cephv1.ObjectZoneSpec{ZoneGroup: zoneGroup, Master: master},
+		}
+	}
+	cl := fake.NewFakeClientWithScheme(s, zone("zone-a", "zonegroup-a", true), zone("zone-b", "zonegroup-b", true))
+	r := &ReconcileObjectZone{client: cl, context: &clusterd.Context{Executor: &exectest.MockExecutor{}}}
+
+	assert.NoError(t, r.validateZoneCR(zone("zone-c", "zonegroup-a", false)))
+	assert.NoError(t, r.validateZoneCR(zone("zone-a", "zonegroup-a", true)))
+	err := r.validateZoneCR(zone("zone-c", "zonegroup-a", true))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `zones "zone-c" and "zone-a" of zone group "zonegroup-a" both set master`)
+}
+
+func TestReconcileMasterPromotion(t *testing.T) {
+	masterZoneID := "6cb39d2c-3005-49da-9be3-c1a92a97d28a"
+	commands := []string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "zonegroup" && args[1] == "get":
+				return `{"master_zone": "` + masterZoneID + `", "is_master": "true"}`, nil
+			case args[0] == "zone" && args[1] == "get":
+				return `{"id": "b1abbebb-e8ae-4c3b-880e-b009728bad53"}`, nil
+			}
+			commands = append(commands, args[0]+" "+args[1])
+			return "", nil
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephObjectZone{}, &cephv1.CephObjectZoneList{})
+	objectZone := &cephv1.CephObjectZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "zone-b", Namespace: "rook-ceph"},
+		Spec:       cephv1.ObjectZoneSpec{ZoneGroup: "zonegroup-a", Master: true},
+	}
+	cl := fake.NewFakeClientWithScheme(s, objectZone)
+	c := &clusterd.Context{Executor: executor}
+	r := &ReconcileObjectZone{client: cl, context: c}
+	objContext := object.NewContext(c, cephclient.AdminClusterInfo("rook"), "zone-b")
+	objContext.Realm = "realm-a"
+	objContext.ZoneGroup = "zonegroup-a"
+	objContext.Zone = "zone-b"
+	name := types.NamespacedName{Name: "zone-b", Namespace: "rook-ceph"}
+
+	// the zone is promoted and the promotion is recorded
+	assert.NoError(t, r.reconcileMasterPromotion(objContext, objectZone))
+	assert.Equal(t, []string{"zone modify", "period update"}, commands)
+	objectZone = &cephv1.CephObjectZone{}
+	assert.NoError(t, cl.Get(context.TODO(), name, objectZone))
+	assert.True(t, objectZone.Status.MasterPromoted)
+
+	// another zone was promoted since then, the zone is not promoted again
+	commands = []string{}
+	assert.NoError(t, r.reconcileMasterPromotion(objContext, objectZone))
+	assert.Empty(t, commands)
+
+	// the zone is promoted again once master is unset and set
+	objectZone.Spec.Master = false
+	assert.NoError(t, r.reconcileMasterPromotion(objContext, objectZone))
+	objectZone = &cephv1.CephObjectZone{}
+	assert.NoError(t, cl.Get(context.TODO(), name, objectZone))
+	assert.False(t, objectZone.Status.MasterPromoted)
+	objectZone.Spec.Master = true
+	assert.NoError(t, r.reconcileMasterPromotion(objContext, objectZone))
+	assert.Equal(t, []string{"zone modify", "period update"}, commands)
+}
@@ -193,6 +193,13 @@ func (r *ReconcileObjectZoneGroup) reconcile(request reconcile.Request) (reconci
 		return r.setFailedStatus(request.NamespacedName, "failed to create ceph zone group", err)
 	}
 
+	// Apply the sync policy of the zone group, or remove the previous policy if it was removed from the spec
+	if cephObjectZoneGroup.Spec.SyncPolicy != nil || (cephObjectZoneGroup.Status != nil && len(cephObjectZoneGroup.Status.SyncPolicy) > 0) {
+		if err := r.reconcileSyncPolicy(cephObjectZoneGroup); err != nil {
+			return r.setFailedStatus(request.NamespacedName, "failed to apply sync policy", err)
+		}
+	}
+
 	// Set Ready status, we are done reconciling
 	updateStatus(r.client, request.NamespacedName, k8sutil.ReadyStatus)
 
@@ -292,6 +299,35 @@ func (r *ReconcileObjectZoneGroup) reconcileCephRealm(zoneGroup *cephv1.CephObje
 	return reconcile.Result{}, nil
 }
 
+func (r *ReconcileObjectZoneGroup) reconcileSyncPolicy(zoneGroup *cephv1.CephObjectZoneGroup) error {
+	objContext := object.NewContext(r.context, r.clusterInfo, zoneGroup.Name)
+	objContext.Realm = zoneGroup.Spec.Realm
+	objContext.ZoneGroup = zoneGroup.Name
+
+	var previous []cephv1.ObjectSyncGroupSpec
+	if zoneGroup.Status != nil {
+		previous = zoneGroup.Status.SyncPolicy
+	}
+	effective, err := object.ReconcileSyncPolicy(objContext, zoneGroup.Spec.SyncPolicy, previous)
+	if err != nil {
+		return err
+	}
+
+	name := types.NamespacedName{Name: zoneGroup.Name, Namespace: zoneGroup.Namespace}
+	objectZoneGroup := &cephv1.CephObjectZoneGroup{}
+	if err := r.client.Get(context.TODO(), name, objectZoneGroup); err != nil {
+		return errors.Wrapf(err, "failed to retrieve object zone group %q to update sync policy status", name)
+	}
+	if objectZoneGroup.Status == nil {
+		objectZoneGroup.Status = &cephv1.ObjectZoneGroupStatus{}
+	}
+	objectZoneGroup.Status.SyncPolicy = effective
+	if err := opcontroller.UpdateStatus(r.client, objectZoneGroup); err != nil {
+		return errors.Wrapf(err, "failed to update sync policy status of object zone group %q", name)
+	}
+	return nil
+}
+
 func (r *ReconcileObjectZoneGroup) setFailedStatus(name types.NamespacedName, errMessage string, err error) (reconcile.Result, error) {
 	updateStatus(r.client, name, k8sutil.ReconcileFailedStatus)
 	return reconcile.Result{}, errors.Wrapf(err, "%s", errMessage)
@@ -309,7 +345,7 @@ func updateStatus(client client.Client, name types.NamespacedName, status string
 		return
 	}
 	if objectZoneGroup.Status == nil {
-		objectZoneGroup.Status = &cephv1.Status{}
+		objectZoneGroup.Status = &cephv1.ObjectZoneGroupStatus{}
 	}
 
 	objectZoneGroup.Status.Phase = status
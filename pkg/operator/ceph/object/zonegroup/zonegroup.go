@@ -18,8 +18,10 @@ package zonegroup
 
 import (
 	"encoding/json"
+
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/object"
 )
 
 type masterZoneGroupType struct {
@@ -47,5 +49,8 @@ func validateZoneGroup(u *cephv1.CephObjectZoneGroup) error {
 	if u.Spec.Realm == "" {
 		return errors.New("missing realm")
 	}
+	if err := object.ValidateSyncPolicy(u.Spec.SyncPolicy); err != nil {
+		return errors.Wrap(err, "invalid sync policy")
+	}
 	return nil
 }
@@ -259,7 +259,26 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 
 	// ADD PEERS
 	logger.Debug("reconciling create rbd mirror peer configuration")
+	mirroringRoleResponse := reconcile.Result{}
 	if cephBlockPool.Spec.Mirroring.Enabled {
+		// Snapshot based mirroring is only available since Octopus
+		if cephVersion.IsAtLeastOctopus() {
+			err = cephclient.ReconcileSnapshotSchedules(r.context, clusterInfo, cephBlockPool.Name, cephBlockPool.Spec.Mirroring.SnapshotSchedules)
+			if err != nil {
+				updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+				return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to reconcile snapshot schedules for pool %q.", cephBlockPool.GetName())
+			}
+		} else if len(cephBlockPool.Spec.Mirroring.SnapshotSchedules) > 0 {
+			logger.Warningf("snapshot schedules for pool %q are ignored, they require ceph octopus or newer", cephBlockPool.GetName())
+		}
+
+		// Promote or demote the mirrored images if the desired role changed
+		mirroringRoleResponse, err = r.reconcileMirroringRole(cephBlockPool, request.NamespacedName)
+		if err != nil {
+			updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure, nil)
+			return mirroringRoleResponse, errors.Wrapf(err, "failed to reconcile mirroring role for pool %q.", cephBlockPool.GetName())
+		}
+
 		// Always create a bootstrap peer token in case another cluster wants to add us as a peer
 		reconcileResponse, err = r.createBootstrapPeerSecret(cephBlockPool, request.NamespacedName)
 		if err != nil {
@@ -275,7 +294,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			if r.blockPoolChannels[cephBlockPool.Name].monitoringRunning {
 				logger.Debug("external rgw endpoint monitoring go routine already running!")
 			} else {
-				checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephBlockPool.Spec.StatusCheck, cephBlockPool.Name)
+				checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &cephBlockPool.Spec.StatusCheck, cephBlockPool.Name, cephBlockPool.Spec.Mirroring.SnapshotSchedules)
 				go checker.checkMirroring(r.blockPoolChannels[cephBlockPool.Name].stopChan)
 			}
 		}
@@ -289,9 +308,9 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		updateStatus(r.client, request.NamespacedName, cephv1.ConditionReady, nil)
 	}
 
-	// Return and do not requeue
+	// Return and only requeue while the peers did not acknowledge a mirroring role change
 	logger.Debug("done reconciling")
-	return reconcile.Result{}, nil
+	return mirroringRoleResponse, nil
 }
 
 func (r *ReconcileCephBlockPool) reconcileCreatePool(clusterInfo *cephclient.ClusterInfo, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
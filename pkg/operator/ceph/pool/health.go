@@ -38,18 +38,27 @@ type mirrorChecker struct {
 	namespacedName  types.NamespacedName
 	healthCheckSpec *cephv1.MirrorHealthCheckSpec
 	poolName        string
+	// snapshotSchedules are the desired snapshot schedules, the status is only reported when some are configured
+	snapshotSchedules []cephv1.SnapshotScheduleSpec
+}
+
+// snapshotScheduleStatus groups the snapshot schedules of a pool with their current status
+type snapshotScheduleStatus struct {
+	schedules []cephclient.SnapshotSchedule
+	status    *cephclient.SnapshotScheduleStatus
 }
 
 // newMirrorChecker creates a new HealthChecker object
-func newMirrorChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, healthCheckSpec *cephv1.MirrorHealthCheckSpec, poolName string) *mirrorChecker {
+func newMirrorChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, healthCheckSpec *cephv1.MirrorHealthCheckSpec, poolName string, snapshotSchedules []cephv1.SnapshotScheduleSpec) *mirrorChecker {
 	c := &mirrorChecker{
-		context:         context,
-		interval:        defaultHealthCheckInterval,
-		clusterInfo:     clusterInfo,
-		namespacedName:  namespacedName,
-		client:          client,
-		healthCheckSpec: healthCheckSpec,
-		poolName:        poolName,
+		context:           context,
+		interval:          defaultHealthCheckInterval,
+		clusterInfo:       clusterInfo,
+		namespacedName:    namespacedName,
+		client:            client,
+		healthCheckSpec:   healthCheckSpec,
+		poolName:          poolName,
+		snapshotSchedules: snapshotSchedules,
 	}
 
 	// allow overriding the check interval
@@ -106,5 +115,34 @@ func (c *mirrorChecker) checkMirroringHealth() error {
 	// On success
 	c.updateStatusMirroring(mirrorStatus, mirrorInfo, "")
 
+	// Check snapshot schedule status, the error is already reported in the status
+	snapSchedStatus, err := c.checkSnapshotSchedules()
+	if err != nil {
+		logger.Debugf("failed to check snapshot schedules status for ceph block pool %q. %v", c.namespacedName.Name, err)
+		return nil
+	}
+	c.updateStatusSnapshotSchedules(snapSchedStatus, "")
+
 	return nil
 }
+
+// checkSnapshotSchedules returns the snapshot schedules and their status, nil if the pool is not snapshot mirrored
+func (c *mirrorChecker) checkSnapshotSchedules() (*snapshotScheduleStatus, error) {
+	if len(c.snapshotSchedules) == 0 {
+		return nil, nil
+	}
+
+	schedules, err := cephclient.ListSnapshotSchedulesRecursively(c.context, c.clusterInfo, c.poolName)
+	if err != nil {
+		c.updateStatusSnapshotSchedules(nil, err.Error())
+		return nil, err
+	}
+
+	scheduleStatus, err := cephclient.GetSnapshotScheduleStatus(c.context, c.clusterInfo, c.poolName)
+	if err != nil {
+		c.updateStatusSnapshotSchedules(nil, err.Error())
+		return nil, err
+	}
+
+	return &snapshotScheduleStatus{schedules: schedules, status: scheduleStatus}, nil
+}
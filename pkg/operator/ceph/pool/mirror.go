@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// peerAcknowledgementTimeout is how long the peers have to acknowledge a promotion or a demotion before an error is reported
+var peerAcknowledgementTimeout = 2 * time.Minute
+
+// waitForPeerAcknowledgement is how often the peers acknowledgement is checked after a promotion or a demotion
+var waitForPeerAcknowledgement = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+// reconcileMirroringRole promotes or demotes the mirrored images of the pool when the desired role changes
+// The role is recorded in the status as soon as it is applied, the peers acknowledgement is then checked on
+// the next reconciles instead of blocking the reconcile
+func (r *ReconcileCephBlockPool) reconcileMirroringRole(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName) (reconcile.Result, error) {
+	desiredRole := cephBlockPool.Spec.Mirroring.Role
+	if desiredRole == "" {
+		return reconcile.Result{}, nil
+	}
+
+	var currentStatus *cephv1.MirroringStatusSpec
+	if cephBlockPool.Status != nil {
+		currentStatus = cephBlockPool.Status.MirroringStatus
+	}
+
+	promote := desiredRole == cephv1.MirroringRolePrimary
+	lastTransition := time.Now()
+	if currentStatus == nil || currentStatus.Role != desiredRole {
+		if promote {
+			err := cephclient.PromotePoolImages(r.context, r.clusterInfo, cephBlockPool.Name, cephBlockPool.Spec.Mirroring.Force)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to promote pool %q", cephBlockPool.Name)
+			}
+		} else {
+			err := cephclient.DemotePoolImages(r.context, r.clusterInfo, cephBlockPool.Name)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to demote pool %q", cephBlockPool.Name)
+			}
+		}
+		// Record the role right away so it is not applied again while waiting for the peers
+		if err := updateStatusMirroringRole(r.client, namespacedName, desiredRole); err != nil {
+			return opcontroller.ImmediateRetryResult, err
+		}
+	} else if !currentStatus.PendingAcknowledgement {
+		logger.Debugf("pool %q mirrored images are already %q", cephBlockPool.Name, desiredRole)
+		return reconcile.Result{}, nil
+	} else if t, err := time.Parse(time.RFC3339, currentStatus.LastRoleTransition); err == nil {
+		lastTransition = t
+	}
+
+	status, err := cephclient.GetPoolMirroringImagesStatus(r.context, r.clusterInfo, cephBlockPool.Name)
+	if err != nil {
+		logger.Errorf("failed to get mirroring images status for pool %q while waiting for peer acknowledgement. %v", cephBlockPool.Name, err)
+		return waitForPeerAcknowledgement, nil
+	}
+
+	// On a forced promotion the peer is most likely unreachable so there is no point waiting for it
+	if !(promote && cephBlockPool.Spec.Mirroring.Force) && !cephclient.PeersAcknowledged(status, promote) {
+		if time.Since(lastTransition) > peerAcknowledgementTimeout {
+			return waitForPeerAcknowledgement, errors.Errorf("timeout waiting for the peers of pool %q to acknowledge the %q role", cephBlockPool.Name, desiredRole)
+		}
+		logger.Infof("waiting for the peers of pool %q to acknowledge the %q role", cephBlockPool.Name, desiredRole)
+		return waitForPeerAcknowledgement, nil
+	}
+
+	logger.Infof("pool %q mirrored images are now %q", cephBlockPool.Name, desiredRole)
+	if err := updateStatusMirroringAcknowledged(r.client, namespacedName, splitBrainImages(status)); err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// splitBrainImages returns the name of the images in split-brain
+func splitBrainImages(status *cephclient.PoolMirroringStatus) []string {
+	images := []string{}
+	if status == nil {
+		return images
+	}
+	for i := range status.Images {
+		if status.Images[i].IsSplitBrain() {
+			images = append(images, status.Images[i].Name)
+		}
+	}
+	return images
+}
+
+// updateStatusMirroringRole records a mirroring role transition in the pool status, pending the peers acknowledgement
+func updateStatusMirroringRole(client client.Client, poolName types.NamespacedName, role cephv1.MirroringRole) error {
+	return updateMirroringStatus(client, poolName, func(status *cephv1.MirroringStatusSpec) {
+		status.Role = role
+		status.LastRoleTransition = time.Now().UTC().Format(time.RFC3339)
+		status.PendingAcknowledgement = true
+		status.SplitBrainImages = nil
+	})
+}
+
+// updateStatusMirroringAcknowledged records the peers acknowledgement of the last role transition in the pool status
+func updateStatusMirroringAcknowledged(client client.Client, poolName types.NamespacedName, splitBrainImages []string) error {
+	return updateMirroringStatus(client, poolName, func(status *cephv1.MirroringStatusSpec) {
+		status.PendingAcknowledgement = false
+		status.SplitBrainImages = splitBrainImages
+	})
+}
+
+func updateMirroringStatus(client client.Client, poolName types.NamespacedName, update func(status *cephv1.MirroringStatusSpec)) error {
+	pool := &cephv1.CephBlockPool{}
+	err := client.Get(context.TODO(), poolName, pool)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve pool %q to update mirroring role", poolName)
+	}
+
+	if pool.Status == nil {
+		pool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	if pool.Status.MirroringStatus == nil {
+		pool.Status.MirroringStatus = &cephv1.MirroringStatusSpec{}
+	}
+
+	update(pool.Status.MirroringStatus)
+	if err := opcontroller.UpdateStatus(client, pool); err != nil {
+		return errors.Wrapf(err, "failed to set pool %q mirroring role to %q", pool.Name, pool.Status.MirroringStatus.Role)
+	}
+	logger.Debugf("pool %q mirroring role updated to %q", poolName, pool.Status.MirroringStatus.Role)
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMirroringRole(t *testing.T) {
+	pool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Spec: cephv1.PoolSpec{
+			Mirroring: cephv1.MirroringSpec{Enabled: true, Mode: "image", Role: cephv1.MirroringRolePrimary},
+		},
+	}
+	namespacedName := types.NamespacedName{Name: pool.Name, Namespace: pool.Namespace}
+
+	promotions := 0
+	peerState := "up+stopped"
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" {
+				switch args[2] {
+				case "promote":
+					promotions++
+					return "", nil
+				case "status":
+					return `{"summary":{"health":"OK"},"images":[{"name":"image-1","state":"up+stopped","peer_sites":[{"site_name":"site-b","state":"` + peerState + `"}]}]}`, nil
+				}
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBlockPool{})
+	cl := fake.NewFakeClientWithScheme(s, pool)
+	r := &ReconcileCephBlockPool{
+		client:      cl,
+		scheme:      s,
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminClusterInfo("mycluster"),
+	}
+
+	// the images are promoted and the role is recorded without waiting for the peer
+	res, err := r.reconcileMirroringRole(pool, namespacedName)
+	assert.NoError(t, err)
+	assert.Equal(t, waitForPeerAcknowledgement, res)
+	assert.Equal(t, 1, promotions)
+	assert.NoError(t, cl.Get(context.TODO(), namespacedName, pool))
+	assert.Equal(t, cephv1.MirroringRolePrimary, pool.Status.MirroringStatus.Role)
+	assert.True(t, pool.Status.MirroringStatus.PendingAcknowledgement)
+
+	// the images are not promoted again while the peer did not acknowledge the promotion
+	res, err = r.reconcileMirroringRole(pool, namespacedName)
+	assert.NoError(t, err)
+	assert.Equal(t, waitForPeerAcknowledgement, res)
+	assert.Equal(t, 1, promotions)
+
+	// an error is reported after the timeout, still without promoting again
+	pool.Status.MirroringStatus.LastRoleTransition = time.Now().Add(-2 * peerAcknowledgementTimeout).UTC().Format(time.RFC3339)
+	_, err = r.reconcileMirroringRole(pool, namespacedName)
+	assert.Error(t, err)
+	assert.Equal(t, 1, promotions)
+
+	// the peer acknowledged the promotion
+	peerState = "up+replaying"
+	res, err = r.reconcileMirroringRole(pool, namespacedName)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	pool = &cephv1.CephBlockPool{}
+	assert.NoError(t, cl.Get(context.TODO(), namespacedName, pool))
+	assert.False(t, pool.Status.MirroringStatus.PendingAcknowledgement)
+	assert.Equal(t, 1, promotions)
+
+	// nothing to do once the role is applied
+	res, err = r.reconcileMirroringRole(pool, namespacedName)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, 1, promotions)
+}
@@ -19,6 +19,7 @@ package pool
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -78,6 +79,94 @@ func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephclient.PoolMirro
 	logger.Debugf("ceph block pool %q mirroring status updated", c.namespacedName.Name)
 }
 
+// updateStatusSnapshotSchedules updates the snapshot schedules status of a pool
+func (c *mirrorChecker) updateStatusSnapshotSchedules(snapSchedStatus *snapshotScheduleStatus, details string) {
+	// Nothing to report if the pool has no snapshot schedules
+	if snapSchedStatus == nil && details == "" {
+		return
+	}
+
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(context.TODO(), c.namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update snapshot schedules status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+	blockPool.Status.SnapshotScheduleStatus = toCustomResourceSnapshotScheduleStatus(blockPool.Status.SnapshotScheduleStatus, snapSchedStatus, details)
+	if err := opcontroller.UpdateStatus(c.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q snapshot schedules status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("ceph block pool %q snapshot schedules status updated", c.namespacedName.Name)
+}
+
+func toCustomResourceSnapshotScheduleStatus(currentStatus *cephv1.SnapshotScheduleStatusSpec, snapSchedStatus *snapshotScheduleStatus, details string) *cephv1.SnapshotScheduleStatusSpec {
+	snapshotScheduleStatusSpec := &cephv1.SnapshotScheduleStatusSpec{}
+	now := time.Now().UTC()
+
+	// snapSchedStatus will be nil in case of an error to fetch it
+	if snapSchedStatus != nil {
+		snapshotScheduleStatusSpec.LastChecked = now.Format(time.RFC3339)
+
+		for _, schedule := range snapSchedStatus.schedules {
+			s := cephv1.SnapshotSchedulesSpec{Pool: schedule.Pool, Namespace: schedule.Namespace, Image: schedule.Image}
+			for _, item := range schedule.Items {
+				s.Items = append(s.Items, cephv1.SnapshotScheduleSpec{Interval: item.Interval, StartTime: item.StartTime})
+			}
+			snapshotScheduleStatusSpec.SnapshotSchedules = append(snapshotScheduleStatusSpec.SnapshotSchedules, s)
+		}
+
+		for _, image := range snapSchedStatus.status.ScheduledImages {
+			scheduledImage := cephv1.ScheduledImageSpec{Image: image.Image, NextSnapshotTime: image.ScheduleTime}
+			// The next snapshot time moves forward once a snapshot has been taken, so the previous
+			// next snapshot time becomes the last snapshot time
+			if previous := findScheduledImage(currentStatus, image.Image); previous != nil {
+				scheduledImage.LastSnapshotTime = previous.LastSnapshotTime
+				if previous.NextSnapshotTime != "" && previous.NextSnapshotTime != image.ScheduleTime {
+					scheduledImage.LastSnapshotTime = previous.NextSnapshotTime
+				}
+			}
+			snapshotScheduleStatusSpec.ScheduledImages = append(snapshotScheduleStatusSpec.ScheduledImages, scheduledImage)
+		}
+	} else if currentStatus != nil {
+		// Keep the last known state on error
+		snapshotScheduleStatusSpec.SnapshotSchedules = currentStatus.SnapshotSchedules
+		snapshotScheduleStatusSpec.ScheduledImages = currentStatus.ScheduledImages
+		snapshotScheduleStatusSpec.LastChecked = currentStatus.LastChecked
+	}
+
+	// Always display the details, typically an error
+	snapshotScheduleStatusSpec.Details = details
+
+	if currentStatus != nil {
+		snapshotScheduleStatusSpec.LastChanged = currentStatus.LastChanged
+		if !reflect.DeepEqual(currentStatus.SnapshotSchedules, snapshotScheduleStatusSpec.SnapshotSchedules) || currentStatus.Details != details {
+			snapshotScheduleStatusSpec.LastChanged = now.Format(time.RFC3339)
+		}
+	}
+
+	return snapshotScheduleStatusSpec
+}
+
+func findScheduledImage(status *cephv1.SnapshotScheduleStatusSpec, image string) *cephv1.ScheduledImageSpec {
+	if status == nil {
+		return nil
+	}
+	for i := range status.ScheduledImages {
+		if status.ScheduledImages[i].Image == image {
+			return &status.ScheduledImages[i]
+		}
+	}
+	return nil
+}
+
 func toCustomResourceStatus(currentStatus *cephv1.MirroringStatusSpec, mirroringStatus *cephclient.PoolMirroringStatus, currentInfo *cephv1.MirroringInfoSpec, mirroringInfo *cephclient.PoolMirroringInfo, details string) (*cephv1.MirroringStatusSpec, *cephv1.MirroringInfoSpec) {
 	mirroringStatusSpec := &cephv1.MirroringStatusSpec{}
 	mirroringInfoSpec := &cephv1.MirroringInfoSpec{}
@@ -96,6 +185,11 @@ func toCustomResourceStatus(currentStatus *cephv1.MirroringStatusSpec, mirroring
 
 	if currentStatus != nil {
 		mirroringStatusSpec.LastChanged = currentStatus.LastChanged
+		// The role transition is recorded by the controller, keep it
+		mirroringStatusSpec.Role = currentStatus.Role
+		mirroringStatusSpec.LastRoleTransition = currentStatus.LastRoleTransition
+		mirroringStatusSpec.PendingAcknowledgement = currentStatus.PendingAcknowledgement
+		mirroringStatusSpec.SplitBrainImages = currentStatus.SplitBrainImages
 	}
 
 	// mirroringInfo will be nil in case of an error to fetch it
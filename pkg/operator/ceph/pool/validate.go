@@ -18,6 +18,8 @@ limitations under the License.
 package pool
 
 import (
+	"regexp"
+
 	"github.com/rook/rook/pkg/daemon/ceph/client"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 
@@ -26,6 +28,8 @@ import (
 	"github.com/rook/rook/pkg/clusterd"
 )
 
+var snapshotScheduleIntervalRegex = regexp.MustCompile(`^[0-9]+[mhd]$`)
+
 // ValidatePool Validate the pool arguments
 func ValidatePool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, p *cephv1.CephBlockPool) error {
 	if p.Name == "" {
@@ -130,6 +134,28 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *client.ClusterInfo
 		default:
 			return errors.Errorf("unrecognized mirroring mode %q. only 'image and 'pool' are supported", p.Mirroring.Mode)
 		}
+
+		// Snapshot based mirroring only applies to images mirrored in "image" mode
+		if len(p.Mirroring.SnapshotSchedules) > 0 && p.Mirroring.Mode != "image" {
+			return errors.Errorf("mirroring snapshot schedules require mirroring mode 'image', got %q", p.Mirroring.Mode)
+		}
+
+		switch p.Mirroring.Role {
+		case "", cephv1.MirroringRolePrimary, cephv1.MirroringRoleSecondary:
+			break
+		default:
+			return errors.Errorf("unrecognized mirroring role %q. only 'primary' and 'secondary' are supported", p.Mirroring.Role)
+		}
+
+		if p.Mirroring.Force && p.Mirroring.Role != cephv1.MirroringRolePrimary {
+			return errors.New("mirroring force option is only supported with the 'primary' role")
+		}
+
+		for _, schedule := range p.Mirroring.SnapshotSchedules {
+			if !snapshotScheduleIntervalRegex.MatchString(schedule.Interval) {
+				return errors.Errorf("invalid mirroring snapshot schedule interval %q. must be a number followed by 'm', 'h' or 'd'", schedule.Interval)
+			}
+		}
 	}
 
 	return nil
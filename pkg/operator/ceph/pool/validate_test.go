@@ -138,6 +138,43 @@ func TestValidatePool(t *testing.T) {
 	p.Spec.CompressionMode = "passive"
 	err = ValidatePool(context, clusterInfo, &p)
 	assert.Nil(t, err)
+
+	// succeed with snapshot schedules on an image mirrored pool
+	p.Spec.Mirroring.Enabled = true
+	p.Spec.Mirroring.Mode = "image"
+	p.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{Interval: "1h"}, {Namespace: "ns", Interval: "30m", StartTime: "14:00:00-05:00"}}
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.NoError(t, err)
+
+	// fail with an invalid snapshot schedule interval
+	p.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{Interval: "1w"}}
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
+
+	// succeed with a forced promotion
+	p.Spec.Mirroring.SnapshotSchedules = nil
+	p.Spec.Mirroring.Role = cephv1.MirroringRolePrimary
+	p.Spec.Mirroring.Force = true
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.NoError(t, err)
+
+	// fail with a forced demotion
+	p.Spec.Mirroring.Role = cephv1.MirroringRoleSecondary
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
+
+	// fail with an unknown role
+	p.Spec.Mirroring.Role = "leader"
+	p.Spec.Mirroring.Force = false
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
+	p.Spec.Mirroring.Role = ""
+
+	// fail with snapshot schedules on a pool mirrored pool
+	p.Spec.Mirroring.Mode = "pool"
+	p.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{Interval: "1d"}}
+	err = ValidatePool(context, clusterInfo, &p)
+	assert.Error(t, err)
 }
 
 func TestValidateCrushProperties(t *testing.T) {
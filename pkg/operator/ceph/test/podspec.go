@@ -73,8 +73,12 @@ func (ps *PodSpecTester) AssertVolumesMeetCephRequirements(
 		// #nosec because of the word `Secret`
 		keyringSecretName = "rook-ceph-mons-keyring"
 	}
+	if daemonType == config.FilesystemMirrorType {
+		// #nosec because of the word `Secret`
+		keyringSecretName = "rook-ceph-fs-mirror-keyring"
+	}
 	requiredVols := []string{"rook-config-override", keyringSecretName}
-	if daemonType != config.RbdMirrorType {
+	if daemonType != config.RbdMirrorType && daemonType != config.FilesystemMirrorType {
 		requiredVols = append(requiredVols, "ceph-daemon-data")
 	}
 	vols := []string{}
@@ -119,7 +123,7 @@ func (ps *PodSpecTester) AssertRestartPolicyAlways() {
 // Ceph daemons.
 func (ps *PodSpecTester) AssertChownContainer(daemonType string) {
 	switch daemonType {
-	case config.MonType, config.MgrType, config.OsdType, config.MdsType, config.RgwType, config.RbdMirrorType:
+	case config.MonType, config.MgrType, config.OsdType, config.MdsType, config.RgwType, config.RbdMirrorType, config.FilesystemMirrorType:
 		assert.True(ps.t, containerExists("chown-container-data-dir", ps.spec))
 	}
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"fmt"
+
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CreateOrUpdateHorizontalPodAutoscaler creates a horizontal pod autoscaler or updates the autoscaler declaratively if
+// it already exists.
+func CreateOrUpdateHorizontalPodAutoscaler(clientset kubernetes.Interface, namespace string, hpaDefinition *autoscalingv2beta1.HorizontalPodAutoscaler) (*autoscalingv2beta1.HorizontalPodAutoscaler, error) {
+	name := hpaDefinition.Name
+	logger.Debugf("creating horizontal pod autoscaler %q", name)
+	hpa, err := clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(namespace).Create(hpaDefinition)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create horizontal pod autoscaler %q. %v", name, err)
+		}
+		hpa, err = clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(namespace).Update(hpaDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update horizontal pod autoscaler %q. %v", name, err)
+		}
+	}
+
+	return hpa, err
+}
+
+// DeleteHorizontalPodAutoscaler deletes a horizontal pod autoscaler and returns the error if any
+func DeleteHorizontalPodAutoscaler(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.AutoscalingV2beta1().HorizontalPodAutoscalers(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8sutil
+
+import (
+	"fmt"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IngressSupported returns whether the cluster serves the networking.k8s.io/v1beta1 ingresses, from K8s 1.14
+func IngressSupported(clientset kubernetes.Interface) bool {
+	k8sVersion, err := GetK8SVersion(clientset)
+	if err != nil {
+		logger.Warningf("failed to get the k8s version, ingresses are not supported. %v", err)
+		return false
+	}
+	return k8sVersion.AtLeast(version.MustParseSemantic("v1.14.0"))
+}
+
+// CreateOrUpdateIngress creates an ingress or updates the ingress declaratively if it already exists.
+func CreateOrUpdateIngress(clientset kubernetes.Interface, namespace string, ingressDefinition *networkingv1beta1.Ingress) (*networkingv1beta1.Ingress, error) {
+	name := ingressDefinition.Name
+	logger.Debugf("creating ingress %q", name)
+	ingress, err := clientset.NetworkingV1beta1().Ingresses(namespace).Create(ingressDefinition)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create ingress %q. %v", name, err)
+		}
+		ingress, err = clientset.NetworkingV1beta1().Ingresses(namespace).Update(ingressDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update ingress %q. %v", name, err)
+		}
+	}
+
+	return ingress, err
+}
+
+// DeleteIngress deletes an ingress and returns the error if any
+func DeleteIngress(clientset kubernetes.Interface, namespace, name string) error {
+	err := clientset.NetworkingV1beta1().Ingresses(namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -57,11 +57,34 @@ func UpdateService(
 	}
 	// ClusterIP is immutable for k8s services and cannot be left empty in k8s v1 API
 	serviceDefinition.Spec.ClusterIP = existing.Spec.ClusterIP
+	// Keep the node ports allocated by k8s unless they are set explicitly
+	preserveNodePorts(existing, serviceDefinition)
 	// ResourceVersion required to update services in k8s v1 API to prevent race conditions
 	serviceDefinition.ResourceVersion = existing.ResourceVersion
 	return clientset.CoreV1().Services(namespace).Update(serviceDefinition)
 }
 
+func preserveNodePorts(existing, serviceDefinition *v1.Service) {
+	if serviceDefinition.Spec.Type != v1.ServiceTypeNodePort && serviceDefinition.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return
+	}
+	for i, port := range serviceDefinition.Spec.Ports {
+		if port.NodePort != 0 {
+			continue
+		}
+		for _, existingPort := range existing.Spec.Ports {
+			if existingPort.Name == port.Name {
+				serviceDefinition.Spec.Ports[i].NodePort = existingPort.NodePort
+			}
+		}
+	}
+	if serviceDefinition.Spec.Type == v1.ServiceTypeLoadBalancer &&
+		serviceDefinition.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeLocal &&
+		serviceDefinition.Spec.HealthCheckNodePort == 0 {
+		serviceDefinition.Spec.HealthCheckNodePort = existing.Spec.HealthCheckNodePort
+	}
+}
+
 // DeleteService deletes a Service and returns the error if any
 func DeleteService(clientset kubernetes.Interface, namespace, name string) error {
 	err := clientset.CoreV1().Services(namespace).Delete(name, &metav1.DeleteOptions{})
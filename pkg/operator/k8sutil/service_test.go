@@ -22,6 +22,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestParseServiceType(t *testing.T) {
@@ -35,3 +37,34 @@ func TestParseServiceType(t *testing.T) {
 		assert.Equal(t, v1.ServiceType(""), ParseServiceType(serviceType))
 	}
 }
+
+func TestUpdateServiceNodePorts(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "rgw", Namespace: "ns"},
+		Spec: v1.ServiceSpec{
+			Type:  v1.ServiceTypeNodePort,
+			Ports: []v1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}, {Name: "https", Port: 443, NodePort: 30443}},
+		},
+	}
+	_, err := clientset.CoreV1().Services("ns").Create(service)
+	assert.NoError(t, err)
+
+	// the allocated node ports are kept unless they are set
+	update := service.DeepCopy()
+	update.Spec.Ports[0].NodePort = 0
+	update.Spec.Ports[1].NodePort = 31443
+	s, err := UpdateService(clientset, "ns", update)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(30080), s.Spec.Ports[0].NodePort)
+	assert.Equal(t, int32(31443), s.Spec.Ports[1].NodePort)
+
+	// a ClusterIP service has no node ports
+	update = service.DeepCopy()
+	update.Spec.Type = v1.ServiceTypeClusterIP
+	update.Spec.Ports[0].NodePort = 0
+	update.Spec.Ports[1].NodePort = 0
+	s, err = UpdateService(clientset, "ns", update)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), s.Spec.Ports[0].NodePort)
+}
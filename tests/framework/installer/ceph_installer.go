@@ -605,11 +605,16 @@ func (h *CephInstaller) UninstallRookFromMultipleNS(systemNamespace string, name
 		"cephobjectzones.ceph.rook.io",
 		"cephfilesystems.ceph.rook.io",
 		"cephnfses.ceph.rook.io",
+		"cephnfsexports.ceph.rook.io",
 		"cephclients.ceph.rook.io",
 		"volumes.rook.io",
 		"objectbuckets.objectbucket.io",
 		"objectbucketclaims.objectbucket.io",
-		"cephrbdmirrors.ceph.rook.io")
+		"cephrbdmirrors.ceph.rook.io",
+		"cephfilesystemmirrors.ceph.rook.io",
+		"cephfilesystemsubvolumegroups.ceph.rook.io",
+		"cephbuckettopics.ceph.rook.io",
+		"cephbucketnotifications.ceph.rook.io")
 	checkError(h.T(), err, "cannot delete CRDs")
 
 	if h.useHelm {
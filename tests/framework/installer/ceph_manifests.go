@@ -323,6 +323,25 @@ spec:
                   type: integer
                 activeStandby:
                   type: boolean
+                autoscaling:
+                  properties:
+                    enabled:
+                      type: boolean
+                    minActiveCount:
+                      minimum: 1
+                      maximum: 10
+                      type: integer
+                    maxActiveCount:
+                      minimum: 1
+                      maximum: 10
+                      type: integer
+                    targetRequestRate:
+                      minimum: 0
+                      type: integer
+                    scaleOnCachePressure:
+                      type: boolean
+                    interval:
+                      type: string
                 annotations: {}
                 placement: {}
                 resources: {}
@@ -366,6 +385,9 @@ spec:
               type: array
               items:
                 properties:
+                  name:
+                    type: string
+                    pattern: ^[a-zA-Z0-9][a-zA-Z0-9._-]*$
                   failureDomain:
                     type: string
                   crushRoot:
@@ -404,6 +426,49 @@ spec:
                     type: object
             preservePoolsOnDelete:
               type: boolean
+            mirroring:
+              properties:
+                enabled:
+                  type: boolean
+                peers:
+                  properties:
+                    secretNames:
+                      type: array
+                directories:
+                  type: array
+                  items:
+                    type: string
+                statusCheck:
+                  properties:
+                    mirror:
+                      properties:
+                        disabled:
+                          type: boolean
+                        interval:
+                          type: string
+            snapshotSchedules:
+              type: array
+              items:
+                properties:
+                  path:
+                    type: string
+                    pattern: ^/
+                  interval:
+                    type: string
+                    pattern: ^[0-9]+[hdwMy]$
+                  startTime:
+                    type: string
+                  retention:
+                    properties:
+                      hourly:
+                        type: integer
+                        minimum: 0
+                      daily:
+                        type: integer
+                        minimum: 0
+                      weekly:
+                        type: integer
+                        minimum: 0
   additionalPrinterColumns:
     - name: ActiveMDS
       type: string
@@ -453,6 +518,95 @@ spec:
 ---
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
+metadata:
+  name: cephnfsexports.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephNFSExport
+    listKind: CephNFSExportList
+    plural: cephnfsexports
+    singular: cephnfsexport
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            nfsName:
+              type: string
+            exportID:
+              type: integer
+              minimum: 1
+              maximum: 65535
+            filesystemName:
+              type: string
+            path:
+              type: string
+            subVolume:
+              type: string
+            subVolumeGroup:
+              type: string
+            pseudoPath:
+              type: string
+            accessType:
+              type: string
+              enum:
+              - RW
+              - RO
+              - None
+            squash:
+              type: string
+              enum:
+              - None
+              - Root
+              - RootId
+              - All
+            clients:
+              type: array
+              items:
+                properties:
+                  addresses:
+                    type: array
+                    items:
+                      type: string
+                  accessType:
+                    type: string
+                    enum:
+                    - RW
+                    - RO
+                    - None
+                  squash:
+                    type: string
+                    enum:
+                    - None
+                    - Root
+                    - RootId
+                    - All
+            user:
+              type: string
+              pattern: ^nfs-export\.[A-Za-z0-9._-]+$
+  additionalPrinterColumns:
+    - name: NFS
+      type: string
+      description: Name of the CephNFS serving the export
+      JSONPath: .spec.nfsName
+    - name: Pseudo
+      type: string
+      description: Path of the export in the NFSv4 pseudo filesystem
+      JSONPath: .spec.pseudoPath
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
 metadata:
   name: cephobjectstores.ceph.rook.io
 spec:
@@ -487,6 +641,53 @@ spec:
                 annotations: {}
                 placement: {}
                 resources: {}
+                service:
+                  properties:
+                    type:
+                      type: string
+                      enum:
+                      - ClusterIP
+                      - NodePort
+                      - LoadBalancer
+                    annotations: {}
+                    loadBalancerIP:
+                      type: string
+                    externalTrafficPolicy:
+                      type: string
+                      enum:
+                      - Cluster
+                      - Local
+                    nodePort:
+                      type: integer
+                      minimum: 0
+                      maximum: 65535
+                    secureNodePort:
+                      type: integer
+                      minimum: 0
+                      maximum: 65535
+                ingress:
+                  properties:
+                    host:
+                      type: string
+                    tlsSecretName:
+                      type: string
+                    annotations: {}
+                autoscaling:
+                  properties:
+                    minInstances:
+                      type: integer
+                      minimum: 1
+                    maxInstances:
+                      type: integer
+                      minimum: 1
+                    targetCPUUtilizationPercentage:
+                      type: integer
+                      minimum: 1
+                    targetRequestsPerSecond:
+                      type: integer
+                      minimum: 1
+                    requestsPerSecondMetric:
+                      type: string
             metadataPool:
               properties:
                 failureDomain:
@@ -551,8 +752,112 @@ spec:
                   - force
                 parameters:
                   type: object
+            placementTargets:
+              type: array
+              items:
+                properties:
+                  name:
+                    type: string
+                  metadataPool: {}
+                  dataPool: {}
+                  storageClasses:
+                    type: array
+                    items:
+                      properties:
+                        name:
+                          type: string
+                        dataPool: {}
+            sharedPools:
+              properties:
+                metadataPoolName:
+                  type: string
+                dataPoolName:
+                  type: string
+                preserveRadosNamespaceDataOnDelete:
+                  type: boolean
+            security:
+              properties:
+                kms:
+                  properties:
+                    vault:
+                      properties:
+                        address:
+                          type: string
+                        secretEngine:
+                          type: string
+                          enum:
+                          - kv
+                          - transit
+                        prefix:
+                          type: string
+                        namespace:
+                          type: string
+                        tokenSecretName:
+                          type: string
+                    testingKeysSecretName:
+                      type: string
+                    healthCheckKeyID:
+                      type: string
+                    allowInsecureEncryption:
+                      type: boolean
+            auth:
+              properties:
+                keystone:
+                  properties:
+                    url:
+                      type: string
+                    adminUser:
+                      type: string
+                    adminProject:
+                      type: string
+                    adminDomain:
+                      type: string
+                    passwordSecretName:
+                      type: string
+                    acceptedRoles:
+                      type: array
+                      items:
+                        type: string
+                    implicitTenants:
+                      type: boolean
+                    enableS3:
+                      type: boolean
+                ldap:
+                  properties:
+                    uri:
+                      type: string
+                    bindDN:
+                      type: string
+                    passwordSecretName:
+                      type: string
+                    searchDN:
+                      type: string
+                    uidAttribute:
+                      type: string
+                    searchFilter:
+                      type: string
+                sts:
+                  properties:
+                    keySecretName:
+                      type: string
+                    webIdentity:
+                      properties:
+                        introspectionURL:
+                          type: string
+                        clientID:
+                          type: string
+                        clientSecretName:
+                          type: string
             preservePoolsOnDelete:
               type: boolean
+            metrics:
+              properties:
+                enabled:
+                  type: boolean
+                interval:
+                  type: string
+                serviceMonitor:
+                  type: boolean
             healthCheck:
               properties:
                 bucket:
@@ -561,6 +866,39 @@ spec:
                       type: boolean
                     interval:
                       type: string
+                probes:
+                  properties:
+                    objectSizes:
+                      type: array
+                      items:
+                        type: string
+                    multipartSize:
+                      type: string
+                    samples:
+                      type: integer
+                      minimum: 0
+                      maximum: 10000
+                    latencyThresholds:
+                      type: array
+                      items:
+                        properties:
+                          operation:
+                            type: string
+                            enum:
+                            - put
+                            - get
+                            - list
+                            - delete
+                            - multipart
+                          percentile:
+                            type: integer
+                            minimum: 0
+                            maximum: 100
+                          latency:
+                            type: string
+                        required:
+                        - operation
+                        - latency
   subresources:
   subresources:
     status: {}
@@ -704,6 +1042,24 @@ spec:
                   enum:
                   - image
                   - pool
+                snapshotSchedules:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      namespace:
+                        type: string
+                      interval:
+                        type: string
+                      startTime:
+                        type: string
+                role:
+                  type: string
+                  enum:
+                  - primary
+                  - secondary
+                force:
+                  type: boolean
   subresources:
     status: {}
 ---
@@ -824,6 +1180,236 @@ spec:
               properties:
                 secretNames:
                   type: array
+            statusCheck:
+              properties:
+                mirror:
+                  properties:
+                    disabled:
+                      type: boolean
+                    interval:
+                      type: string
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephfilesystemmirrors.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephFilesystemMirror
+    listKind: CephFilesystemMirrorList
+    plural: cephfilesystemmirrors
+    singular: cephfilesystemmirror
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            annotations: {}
+            labels: {}
+            placement: {}
+            resources: {}
+            priorityClassName:
+              type: string
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephfilesystemsubvolumegroups.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephFilesystemSubVolumeGroup
+    listKind: CephFilesystemSubVolumeGroupList
+    plural: cephfilesystemsubvolumegroups
+    singular: cephfilesystemsubvolumegroup
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            filesystemName:
+              type: string
+            quota: {}
+            dataPoolName:
+              type: string
+            mode:
+              type: string
+              pattern: ^0?[0-7]{3}$
+            exportPin:
+              type: integer
+              minimum: -1
+  additionalPrinterColumns:
+    - name: Filesystem
+      type: string
+      description: Name of the CephFilesystem of the group
+      JSONPath: .spec.filesystemName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephbuckettopics.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephBucketTopic
+    listKind: CephBucketTopicList
+    plural: cephbuckettopics
+    singular: cephbuckettopic
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            objectStoreName:
+              type: string
+            opaqueData:
+              type: string
+            persistent:
+              type: boolean
+            endpoint:
+              properties:
+                http:
+                  properties:
+                    uri:
+                      type: string
+                    disableVerifySSL:
+                      type: boolean
+                    sendCloudEvents:
+                      type: boolean
+                amqp:
+                  properties:
+                    uri:
+                      type: string
+                    exchange:
+                      type: string
+                    ackLevel:
+                      type: string
+                      enum:
+                        - ""
+                        - none
+                        - broker
+                        - routable
+                    disableVerifySSL:
+                      type: boolean
+                    credentialsSecretRef:
+                      properties:
+                        name:
+                          type: string
+                kafka:
+                  properties:
+                    uri:
+                      type: string
+                    useSSL:
+                      type: boolean
+                    disableVerifySSL:
+                      type: boolean
+                    ackLevel:
+                      type: string
+                      enum:
+                        - ""
+                        - none
+                        - broker
+                    credentialsSecretRef:
+                      properties:
+                        name:
+                          type: string
+  additionalPrinterColumns:
+    - name: ObjectStore
+      type: string
+      description: Name of the CephObjectStore of the topic
+      JSONPath: .spec.objectStoreName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: ARN
+      type: string
+      JSONPath: .status.arn
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
+  subresources:
+    status: {}
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cephbucketnotifications.ceph.rook.io
+spec:
+  group: ceph.rook.io
+  names:
+    kind: CephBucketNotification
+    listKind: CephBucketNotificationList
+    plural: cephbucketnotifications
+    singular: cephbucketnotification
+  scope: Namespaced
+  version: v1
+  validation:
+    openAPIV3Schema:
+      properties:
+        spec:
+          properties:
+            topic:
+              type: string
+            objectBucketClaim:
+              properties:
+                name:
+                  type: string
+                namespace:
+                  type: string
+            bucketName:
+              type: string
+            events:
+              type: array
+              items:
+                type: string
+                pattern: ^s3:.+
+            filter:
+              properties:
+                keyFilters:
+                  type: array
+                  items:
+                    properties:
+                      name:
+                        type: string
+                        enum:
+                          - prefix
+                          - suffix
+                          - regex
+                      value:
+                        type: string
+  additionalPrinterColumns:
+    - name: Topic
+      type: string
+      description: Name of the CephBucketTopic receiving the notifications
+      JSONPath: .spec.topic
+    - name: Bucket
+      type: string
+      JSONPath: .status.bucketName
+    - name: Phase
+      type: string
+      JSONPath: .status.phase
+    - name: Age
+      type: date
+      JSONPath: .metadata.creationTimestamp
   subresources:
     status: {}`
 }
@@ -988,6 +1574,28 @@ rules:
   - create
   - update
   - delete
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - delete
+- apiGroups:
+  - autoscaling
+  resources:
+  - horizontalpodautoscalers
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - delete
 - apiGroups:
   - apps
   - extensions
@@ -1047,6 +1655,14 @@ rules:
   - get
   - list
   - watch
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - get
+  - list
+  - watch
 - apiGroups:
   - batch
   resources:
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	rgw "github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/tests/framework/clients"
+	"github.com/rook/rook/tests/framework/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ldapUser     = "alice"
+	ldapPassword = "alice-password"
+	ldapBucket   = "ldap-bucket"
+)
+
+// runObjectLDAPAuthTest deploys an OpenLDAP server with a user and an object store authenticating its S3 users with
+// the server, then reads and writes a bucket with the LDAP token of the user
+func runObjectLDAPAuthTest(helper *clients.TestClient, k8sh *utils.K8sHelper, s suite.Suite, namespace string) {
+	storeName := "ldapstore"
+	logger.Infof("Object Storage LDAP Integration Test - Create an LDAP server and an object store authenticating with it")
+
+	logger.Infof("Step 1 : Create the LDAP server")
+	require.NoError(s.T(), k8sh.ResourceOperation("apply", ldapServerManifest(namespace)))
+	require.NoError(s.T(), k8sh.WaitForLabeledPodsToRun("app=openldap", namespace))
+	defer func() {
+		assert.NoError(s.T(), k8sh.ResourceOperation("delete", ldapServerManifest(namespace)))
+	}()
+
+	logger.Infof("Step 2 : Create the object store with LDAP auth")
+	require.NoError(s.T(), k8sh.ResourceOperation("apply", ldapObjectStoreManifest(namespace, storeName)))
+	require.NoError(s.T(), k8sh.WaitForLabeledPodsToRunWithRetries(fmt.Sprintf("rook_object_store=%s", storeName), namespace, 40))
+	require.NoError(s.T(), k8sh.CreateExternalRGWService(namespace, storeName))
+	defer func() {
+		assert.NoError(s.T(), helper.ObjectClient.Delete(namespace, storeName))
+	}()
+
+	logger.Infof("Step 3 : Read and write a bucket with the LDAP token")
+	token := fmt.Sprintf(`{"RGW_TOKEN":{"version":1,"type":"ldap","id":"%s","key":"%s"}}`, ldapUser, ldapPassword)
+	s3endpoint, err := helper.ObjectClient.GetEndPointUrl(namespace, storeName)
+	require.NoError(s.T(), err)
+	s3client, err := rgw.NewS3Agent(base64.StdEncoding.EncodeToString([]byte(token)), "unused", s3endpoint, true)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s3client.CreateBucket(ldapBucket))
+	_, err = s3client.PutObjectInBucket(ldapBucket, ObjBody, ObjectKey1, contentType)
+	require.NoError(s.T(), err)
+	read, err := s3client.GetObjectInBucket(ldapBucket, ObjectKey1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), ObjBody, read)
+	_, err = s3client.DeleteObjectInBucket(ldapBucket, ObjectKey1)
+	assert.NoError(s.T(), err)
+	_, err = s3client.DeleteBucket(ldapBucket)
+	assert.NoError(s.T(), err)
+
+	logger.Infof("Step 4 : A wrong password is rejected")
+	token = fmt.Sprintf(`{"RGW_TOKEN":{"version":1,"type":"ldap","id":"%s","key":"wrong"}}`, ldapUser)
+	s3client, err = rgw.NewS3Agent(base64.StdEncoding.EncodeToString([]byte(token)), "unused", s3endpoint, true)
+	require.NoError(s.T(), err)
+	assert.Error(s.T(), s3client.CreateBucket(ldapBucket))
+}
+
+func ldapServerManifest(namespace string) string {
+	return `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: openldap-users
+  namespace: ` + namespace + `
+data:
+  users.ldif: |
+    dn: ou=users,dc=rook,dc=io
+    objectClass: organizationalUnit
+    ou: users
+
+    dn: uid=` + ldapUser + `,ou=users,dc=rook,dc=io
+    objectClass: inetOrgPerson
+    uid: ` + ldapUser + `
+    cn: ` + ldapUser + `
+    sn: ` + ldapUser + `
+    userPassword: ` + ldapPassword + `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: rgw-ldap-password
+  namespace: ` + namespace + `
+stringData:
+  password: admin-password
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: openldap
+  namespace: ` + namespace + `
+  labels:
+    app: openldap
+spec:
+  containers:
+  - name: openldap
+    image: osixia/openldap:1.4.0
+    args: ["--copy-service"]
+    env:
+    - name: LDAP_DOMAIN
+      value: rook.io
+    - name: LDAP_ADMIN_PASSWORD
+      value: admin-password
+    - name: LDAP_TLS
+      value: "false"
+    ports:
+    - containerPort: 389
+    volumeMounts:
+    - name: users
+      mountPath: /container/service/slapd/assets/config/bootstrap/ldif/custom
+  volumes:
+  - name: users
+    configMap:
+      name: openldap-users
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: openldap
+  namespace: ` + namespace + `
+spec:
+  selector:
+    app: openldap
+  ports:
+  - port: 389
+`
+}
+
+func ldapObjectStoreManifest(namespace, name string) string {
+	return `apiVersion: ceph.rook.io/v1
+kind: CephObjectStore
+metadata:
+  name: ` + name + `
+  namespace: ` + namespace + `
+spec:
+  metadataPool:
+    replicated:
+      size: 1
+      requireSafeReplicaSize: false
+  dataPool:
+    replicated:
+      size: 1
+      requireSafeReplicaSize: false
+  gateway:
+    type: s3
+    port: 80
+    instances: 1
+  auth:
+    ldap:
+      uri: ldap://openldap.` + namespace + `.svc:389
+      bindDN: cn=admin,dc=rook,dc=io
+      passwordSecretName: rgw-ldap-password
+      searchDN: ou=users,dc=rook,dc=io
+`
+}